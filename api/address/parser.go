@@ -0,0 +1,465 @@
+// Package address splits free-text Canadian addresses into their components. It is shared by the
+// LMIA, non-compliant employer, report and boycott datasets so they all read addresses the same way.
+package address
+
+import (
+	"math"
+	"regexp"
+	"strings"
+)
+
+// Components are the parts of a parsed address. Fields that could not be found are left empty.
+type Components struct {
+	Unit            string  `json:"unit,omitempty"`
+	StreetNumber    string  `json:"street_number,omitempty"`
+	Street          string  `json:"street,omitempty"`           // Street as written, e.g. "Main Street West", "rue Principale"
+	StreetType      string  `json:"street_type,omitempty"`      // Canada Post abbreviation, e.g. "ST", "RUE"
+	StreetDirection string  `json:"street_direction,omitempty"` // e.g. "W", "NE"
+	POBox           string  `json:"po_box,omitempty"`
+	RuralRoute      string  `json:"rural_route,omitempty"`
+	Municipality    string  `json:"municipality,omitempty"`
+	ProvinceCode    string  `json:"province_code,omitempty"`
+	PostalCode      string  `json:"postal_code,omitempty"` // Formatted as A1A 1A1
+	Confidence      float64 `json:"confidence"`            // 0 to 1, how much of the address was recognized
+}
+
+// Addresses parsed with less confidence than this are kept as written by Standardize
+const minStandardizeConfidence = 0.6
+
+var (
+	// Canadian postal codes never use D, F, I, O, Q or U, and W and Z are not used as the first letter
+	postalCodeRegex = regexp.MustCompile(`(?i)([ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z])[\s-]?(\d[ABCEGHJ-NPRSTV-Z]\d)`)
+
+	countrySuffixRegex = regexp.MustCompile(`(?i)[\s,]*\bCanada\s*$`)
+
+	// Street types that run into the next word, e.g. "123 Main StreetToronto"
+	gluedStreetTypeRegex = regexp.MustCompile(`\b((?i:street|avenue|road|drive|boulevard|crescent|court|place|lane|way|highway|rue|chemin|st|ave|rd|dr|blvd|cres))([A-Z][a-z]{2,})`)
+
+	poBoxRegex      = regexp.MustCompile(`(?i)\b(?:P\.?\s?O\.?\s?Box|Post\s+Office\s+Box|C\.?\s?P\.|Case\s+postale|Box)\s*#?\s*(\d+)\b`)
+	ruralRouteRegex = regexp.MustCompile(`(?i)\b(?:R\.?\s?R\.?|Rural\s+Route|Route\s+rurale)\s*#?\s*(\d+)\b`)
+	stationRegex    = regexp.MustCompile(`(?i)\b(?:Stn|Station|Succ|Succursale)\.?\s+[\w-]+|\b(?:Site|Comp)\s+\d+\b`)
+
+	unitRegex         = regexp.MustCompile(`(?i)(?:^|[\s,])(?:unit|suite|apt|apartment|app|appt|appartement|bureau|local|room|rm)\.?\s*#?\s*([A-Z]?\d+[A-Z]?|[A-Z])\b`)
+	unitHashRegex     = regexp.MustCompile(`#\s*([A-Za-z0-9-]+)`)
+	unitCivicRegex    = regexp.MustCompile(`^([A-Za-z0-9]+)\s*-\s*(\d+[A-Za-z]?)\s+`)
+	streetNumberRegex = regexp.MustCompile(`^(\d+[A-Za-z]?(?:\s+1/2)?)\s+`)
+	civicOnlyRegex    = regexp.MustCompile(`^(?:[A-Za-z0-9]+-)?\d+[A-Za-z]?$`)
+)
+
+// Parse splits a free-text address such as "5-123 Main St W, Toronto, ON M5V 1A1" or
+// "123, rue Principale, Montréal (Québec) H2X 1Y4" into its components
+func Parse(raw string) Components {
+	var c Components
+
+	text := Clean(raw)
+	if text == "" {
+		return c
+	}
+
+	// Postal code, the last one wins since it is normally at the end
+	if matches := postalCodeRegex.FindAllStringSubmatchIndex(text, -1); len(matches) > 0 {
+		m := matches[len(matches)-1]
+		c.PostalCode = strings.ToUpper(text[m[2]:m[3]] + " " + text[m[4]:m[5]])
+		text = text[:m[0]] + " " + text[m[1]:]
+	}
+	text = trimSeparators(text)
+
+	if code, rest, ok := cutProvinceSuffix(text); ok {
+		c.ProvinceCode = code
+		text = trimSeparators(rest)
+	}
+
+	parts := splitParts(text)
+	guessedMunicipality := false
+
+	var streetLine string
+	if len(parts) >= 2 && looksLikeMunicipality(parts[len(parts)-1]) {
+		c.Municipality = parts[len(parts)-1]
+		streetLine = strings.Join(parts[:len(parts)-1], ", ")
+	} else {
+		streetLine = strings.Join(parts, ", ")
+	}
+
+	streetLine = c.extractDelivery(streetLine)
+	streetLine = c.extractUnit(streetLine)
+	streetLine = pickStreetPart(streetLine)
+	streetLine = c.extractStreetNumber(streetLine)
+
+	if len(parts) == 1 {
+		streetLine, c.Municipality = splitMunicipality(streetLine, c.StreetNumber != "")
+		guessedMunicipality = streetLine != "" && c.Municipality != ""
+	}
+
+	c.setStreet(streetLine)
+	c.scoreConfidence(guessedMunicipality)
+
+	return c
+}
+
+// Clean tidies whitespace and punctuation, drops a trailing "Canada" and separates street types
+// that run into the next word. It keeps the address in a single line.
+func Clean(raw string) string {
+	text := strings.Join(strings.Fields(raw), " ")
+	if text == "" {
+		return ""
+	}
+
+	// "Montréal (Québec)" is the same as "Montréal, Québec"
+	text = strings.NewReplacer("(", ", ", ")", ", ", ";", ",").Replace(text)
+	text = countrySuffixRegex.ReplaceAllString(text, "")
+	text = gluedStreetTypeRegex.ReplaceAllString(text, "$1 $2")
+
+	return trimSeparators(strings.Join(strings.Fields(text), " "))
+}
+
+// Format writes the components back as a single line in Canada Post order,
+// e.g. "5-123 Main St W, Toronto, ON M5V 1A1"
+func (c Components) Format() string {
+	var lines []string
+
+	var street []string
+	if c.POBox != "" {
+		street = append(street, "PO Box "+c.POBox)
+	}
+	if c.RuralRoute != "" {
+		street = append(street, "RR "+c.RuralRoute)
+	}
+	civic := c.StreetNumber
+	if c.Unit != "" {
+		if civic != "" {
+			civic = c.Unit + "-" + civic
+		} else {
+			street = append(street, "Unit "+c.Unit)
+		}
+	}
+	if civic != "" || c.Street != "" {
+		street = append(street, strings.TrimSpace(civic+" "+c.Street))
+	}
+	if len(street) > 0 {
+		lines = append(lines, strings.Join(street, " "))
+	}
+
+	if c.Municipality != "" {
+		lines = append(lines, c.Municipality)
+	}
+	if region := strings.TrimSpace(c.ProvinceCode + " " + c.PostalCode); region != "" {
+		lines = append(lines, region)
+	}
+
+	return strings.Join(lines, ", ")
+}
+
+// Standardize rewrites an address in Canada Post order when most of it was recognized, and
+// otherwise only tidies it with Clean so nothing is lost from addresses the parser doesn't understand
+func Standardize(raw string) string {
+	if c := Parse(raw); c.Confidence >= minStandardizeConfidence {
+		return c.Format()
+	}
+	return Clean(raw)
+}
+
+// ParseLocation reads a job or statistics location such as "Toronto, ON" or "Abbotsford (BC)"
+// and returns the municipality and the province code
+func ParseLocation(location string) (municipality, provinceCode string) {
+	c := Parse(location)
+	return c.Municipality, c.ProvinceCode
+}
+
+// ExtractPostalCode returns the postal code in an address formatted as A1A 1A1, or "" when there is none
+func ExtractPostalCode(text string) string {
+	matches := postalCodeRegex.FindAllStringSubmatch(text, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+	m := matches[len(matches)-1]
+	return strings.ToUpper(m[1] + " " + m[2])
+}
+
+// ProvinceCode returns the two letter code for a province or territory name or abbreviation,
+// in English or French
+func ProvinceCode(province string) (string, bool) {
+	code, ok := provinceAliases[strings.ToLower(strings.TrimSpace(province))]
+	return code, ok
+}
+
+// ProvinceName returns the English name of a province or territory code
+func ProvinceName(code string) string {
+	return provinceNames[strings.ToUpper(code)]
+}
+
+// ProvinceForPostalCode returns the province a postal code is assigned to. Codes starting with X
+// are shared by the Northwest Territories and Nunavut and return "".
+func ProvinceForPostalCode(postalCode string) string {
+	postalCode = strings.TrimSpace(postalCode)
+	if postalCode == "" {
+		return ""
+	}
+	return postalCodeProvinces[strings.ToUpper(postalCode[:1])[0]]
+}
+
+// Normalize reduces an address to a lower case key for caching and de-duplication
+func Normalize(text string) string {
+	normalized := strings.Join(strings.Fields(strings.ToLower(text)), " ")
+	normalized = strings.NewReplacer(",", "", ".", "").Replace(normalized)
+	return strings.Join(strings.Fields(normalized), " ")
+}
+
+// cutProvinceSuffix removes the province at the end of the text, preferring the longest spelling
+func cutProvinceSuffix(text string) (code, rest string, ok bool) {
+	bestLength := 0
+	for alias, aliasCode := range provinceAliases {
+		if len(alias) <= bestLength || len(alias) > len(text) {
+			continue
+		}
+		start := len(text) - len(alias)
+		if !strings.EqualFold(text[start:], alias) {
+			continue
+		}
+		// Must be a whole word, "Toronto" does not end with the province "ON"
+		if start > 0 && text[start-1] != ' ' && text[start-1] != ',' {
+			continue
+		}
+		code, rest, bestLength = aliasCode, text[:start], len(alias)
+	}
+	return code, rest, bestLength > 0
+}
+
+// splitParts splits on commas and joins a civic number written on its own, as in
+// "123, rue Principale", back onto its street
+func splitParts(text string) []string {
+	var parts []string
+	pendingNumber := ""
+	for _, part := range strings.Split(text, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		if pendingNumber != "" {
+			part = pendingNumber + " " + part
+			pendingNumber = ""
+		}
+		if civicOnlyRegex.MatchString(part) {
+			pendingNumber = part
+			continue
+		}
+		parts = append(parts, part)
+	}
+	if pendingNumber != "" {
+		parts = append(parts, pendingNumber)
+	}
+	return parts
+}
+
+// looksLikeMunicipality reports whether the last comma separated part can be the municipality
+// rather than a trailing civic number, unit or box, as in "123 Main St, Unit 5"
+func looksLikeMunicipality(part string) bool {
+	if part == "" || hasDigit(part[:1]) || strings.HasPrefix(part, "#") {
+		return false
+	}
+	for _, pattern := range []*regexp.Regexp{unitRegex, poBoxRegex, ruralRouteRegex} {
+		if loc := pattern.FindStringIndex(part); loc != nil && loc[0] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// pickStreetPart keeps the part of the street line with the civic number when there are several,
+// e.g. a building or district name written next to the street
+func pickStreetPart(line string) string {
+	var parts []string
+	for _, part := range strings.Split(line, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	for _, part := range parts {
+		if hasDigit(part) {
+			return part
+		}
+	}
+	return parts[0]
+}
+
+// extractDelivery removes post office boxes and rural routes along with their station, site and
+// compartment, which are not needed to locate the address
+func (c *Components) extractDelivery(line string) string {
+	if match := poBoxRegex.FindStringSubmatch(line); match != nil {
+		c.POBox = match[1]
+		line = poBoxRegex.ReplaceAllString(line, " ")
+	}
+	if match := ruralRouteRegex.FindStringSubmatch(line); match != nil {
+		c.RuralRoute = match[1]
+		line = ruralRouteRegex.ReplaceAllString(line, " ")
+	}
+	if c.POBox != "" || c.RuralRoute != "" {
+		line = stationRegex.ReplaceAllString(line, " ")
+	}
+	return trimSeparators(strings.Join(strings.Fields(line), " "))
+}
+
+// extractUnit removes the unit, written as "Unit 5", "Suite 200", "bureau 100", "#12" or "5-123"
+func (c *Components) extractUnit(line string) string {
+	if match := unitRegex.FindStringSubmatchIndex(line); match != nil {
+		c.Unit = strings.ToUpper(line[match[2]:match[3]])
+		line = line[:match[0]] + " " + line[match[1]:]
+	} else if match := unitHashRegex.FindStringSubmatchIndex(line); match != nil {
+		c.Unit = strings.ToUpper(line[match[2]:match[3]])
+		line = line[:match[0]] + " " + line[match[1]:]
+	}
+	line = trimSeparators(strings.Join(strings.Fields(line), " "))
+
+	if c.Unit == "" {
+		match := unitCivicRegex.FindStringSubmatch(line + " ")
+		if match != nil && (hasDigit(match[1]) || len(match[1]) == 1) {
+			c.Unit = strings.ToUpper(match[1])
+			c.StreetNumber = strings.ToUpper(match[2])
+			line = strings.TrimSpace(line[len(match[0])-1:])
+		}
+	}
+	return line
+}
+
+// extractStreetNumber removes the civic number at the start of the street
+func (c *Components) extractStreetNumber(line string) string {
+	if c.StreetNumber != "" {
+		return line
+	}
+	if match := streetNumberRegex.FindStringSubmatch(line + " "); match != nil {
+		c.StreetNumber = strings.ToUpper(match[1])
+		line = strings.TrimSpace(line[len(match[0])-1:])
+	}
+	return trimSeparators(line)
+}
+
+// splitMunicipality separates the municipality from a street written without commas, e.g.
+// "Main St W Toronto". Without a civic number or street type the whole text is the municipality.
+func splitMunicipality(line string, hasNumber bool) (street, municipality string) {
+	tokens := strings.Fields(line)
+	if len(tokens) == 0 {
+		return "", ""
+	}
+
+	// English street types follow the name, the municipality starts after the type and direction
+	for i := 1; i < len(tokens); i++ {
+		word := tokenKey(tokens[i])
+		if _, ok := streetTypes[word]; !ok || ambiguousStreetTypes[word] && !hasNumber {
+			continue
+		}
+		end := i + 1
+		// "Queen's Park Cres" has two types in a row
+		if ambiguousStreetTypes[word] && end < len(tokens) {
+			if _, ok := streetTypes[tokenKey(tokens[end])]; ok {
+				end++
+			}
+		}
+		if end < len(tokens) {
+			if _, ok := streetDirections[tokenKey(tokens[end])]; ok {
+				end++
+			}
+		}
+		return strings.Join(tokens[:end], " "), strings.Join(tokens[end:], " ")
+	}
+
+	// French street types come first, guess that the last word is the municipality
+	if frenchStreetTypes[tokenKey(tokens[0])] {
+		if len(tokens) >= 3 {
+			return strings.Join(tokens[:len(tokens)-1], " "), tokens[len(tokens)-1]
+		}
+		return line, ""
+	}
+
+	if hasNumber {
+		if len(tokens) >= 2 {
+			return strings.Join(tokens[:len(tokens)-1], " "), tokens[len(tokens)-1]
+		}
+		return line, ""
+	}
+
+	return "", line
+}
+
+// setStreet records the street and reads its type and direction
+func (c *Components) setStreet(street string) {
+	c.Street = trimSeparators(street)
+	tokens := strings.Fields(c.Street)
+	if len(tokens) == 0 {
+		return
+	}
+
+	last := len(tokens) - 1
+	if len(tokens) > 1 {
+		if direction, ok := streetDirections[tokenKey(tokens[last])]; ok {
+			c.StreetDirection = direction
+			last--
+		}
+	}
+
+	if len(tokens) > 1 && frenchStreetTypes[tokenKey(tokens[0])] {
+		c.StreetType = streetTypes[tokenKey(tokens[0])]
+		return
+	}
+	if last > 0 {
+		if streetType, ok := streetTypes[tokenKey(tokens[last])]; ok {
+			c.StreetType = streetType
+			return
+		}
+	}
+}
+
+// scoreConfidence rates how completely the address was recognized
+func (c *Components) scoreConfidence(guessedMunicipality bool) {
+	score := 0.0
+
+	if c.PostalCode != "" {
+		score += 0.3
+	}
+
+	postalProvince := ProvinceForPostalCode(c.PostalCode)
+	switch {
+	case c.ProvinceCode != "" && postalProvince != "" && postalProvince != c.ProvinceCode:
+		// The postal code belongs to another province, one of them is wrong
+	case c.ProvinceCode != "":
+		score += 0.2
+	case postalProvince != "":
+		c.ProvinceCode = postalProvince
+		score += 0.1
+	}
+
+	if c.Municipality != "" {
+		score += 0.2
+		if guessedMunicipality {
+			score -= 0.1
+		}
+	}
+
+	switch {
+	case c.POBox != "" || c.RuralRoute != "":
+		score += 0.3
+	case c.StreetNumber != "" && c.Street != "":
+		score += 0.2
+		if c.StreetType != "" {
+			score += 0.1
+		}
+	case c.Street != "":
+		score += 0.1
+	}
+
+	c.Confidence = math.Round(math.Max(0, math.Min(1, score))*100) / 100
+}
+
+// tokenKey is the lower case form of a word used to look it up in the tables
+func tokenKey(token string) string {
+	return strings.TrimRight(strings.ToLower(token), ".,")
+}
+
+func trimSeparators(text string) string {
+	return strings.Trim(text, " ,-")
+}
+
+func hasDigit(text string) bool {
+	return strings.ContainsAny(text, "0123456789")
+}
@@ -0,0 +1,141 @@
+package address
+
+// Province and territory codes with their English names
+var provinceNames = map[string]string{
+	"AB": "Alberta",
+	"BC": "British Columbia",
+	"MB": "Manitoba",
+	"NB": "New Brunswick",
+	"NL": "Newfoundland and Labrador",
+	"NS": "Nova Scotia",
+	"NT": "Northwest Territories",
+	"NU": "Nunavut",
+	"ON": "Ontario",
+	"PE": "Prince Edward Island",
+	"QC": "Quebec",
+	"SK": "Saskatchewan",
+	"YT": "Yukon",
+}
+
+// Spellings of provinces found in the datasets, lower case, mapped to their codes
+var provinceAliases = map[string]string{
+	"ab": "AB", "alberta": "AB", "alta": "AB", "alta.": "AB",
+	"bc": "BC", "b.c.": "BC", "british columbia": "BC", "colombie-britannique": "BC",
+	"mb": "MB", "manitoba": "MB", "man": "MB", "man.": "MB",
+	"nb": "NB", "n.b.": "NB", "new brunswick": "NB", "nouveau-brunswick": "NB",
+	"nl": "NL", "newfoundland and labrador": "NL", "newfoundland": "NL", "nfld": "NL", "nfld.": "NL",
+	"terre-neuve-et-labrador": "NL",
+	"ns":                      "NS", "n.s.": "NS", "nova scotia": "NS", "nouvelle-écosse": "NS", "nouvelle-ecosse": "NS",
+	"nt": "NT", "nwt": "NT", "n.w.t.": "NT", "northwest territories": "NT", "territoires du nord-ouest": "NT",
+	"nu": "NU", "nunavut": "NU",
+	"on": "ON", "ont": "ON", "ont.": "ON", "ontario": "ON",
+	"pe": "PE", "pei": "PE", "p.e.i.": "PE", "prince edward island": "PE",
+	"île-du-prince-édouard": "PE", "ile-du-prince-edouard": "PE",
+	"qc": "QC", "que": "QC", "que.": "QC", "qué": "QC", "qué.": "QC", "pq": "QC", "quebec": "QC", "québec": "QC",
+	"sk": "SK", "sask": "SK", "sask.": "SK", "saskatchewan": "SK",
+	"yt": "YT", "yk": "YT", "yukon": "YT", "yukon territory": "YT",
+}
+
+// First letter of a postal code to the province it is assigned to. X is shared by the
+// Northwest Territories and Nunavut, so it is left out.
+var postalCodeProvinces = map[byte]string{
+	'A': "NL",
+	'B': "NS",
+	'C': "PE",
+	'E': "NB",
+	'G': "QC", 'H': "QC", 'J': "QC",
+	'K': "ON", 'L': "ON", 'M': "ON", 'N': "ON", 'P': "ON",
+	'R': "MB",
+	'S': "SK",
+	'T': "AB",
+	'V': "BC",
+	'Y': "YT",
+}
+
+// Street types, lower case, mapped to their Canada Post abbreviation
+var streetTypes = map[string]string{
+	// English, written after the street name
+	"street": "ST", "st": "ST",
+	"avenue": "AVE", "ave": "AVE", "av": "AVE",
+	"road": "RD", "rd": "RD",
+	"drive": "DR", "dr": "DR",
+	"boulevard": "BLVD", "blvd": "BLVD", "boul": "BLVD", "bd": "BLVD",
+	"crescent": "CRES", "cres": "CRES", "cr": "CRES",
+	"court": "CRT", "crt": "CRT", "ct": "CRT",
+	"place": "PL", "pl": "PL",
+	"lane": "LANE", "ln": "LANE",
+	"way":     "WAY",
+	"highway": "HWY", "hwy": "HWY",
+	"parkway": "PKY", "pky": "PKY", "pkwy": "PKY",
+	"terrace": "TERR", "terr": "TERR",
+	"trail": "TRAIL", "trl": "TRAIL",
+	"circle": "CIR", "cir": "CIR",
+	"close":  "CLOSE",
+	"gate":   "GATE",
+	"square": "SQ", "sq": "SQ",
+	"line":       "LINE",
+	"concession": "CONC", "conc": "CONC",
+	"sideroad": "SDRD", "sdrd": "SDRD",
+	"grove":   "GROVE",
+	"heights": "HTS", "hts": "HTS",
+	"gardens": "GDNS", "gdns": "GDNS",
+	"mews": "MEWS",
+	"park": "PK", "pk": "PK",
+	"point": "PT", "pt": "PT",
+	"ridge":   "RIDGE",
+	"row":     "ROW",
+	"path":    "PATH",
+	"green":   "GREEN",
+	"landing": "LANDNG",
+	"bay":     "BAY",
+	"common":  "COMMON",
+	"view":    "VIEW",
+	"hill":    "HILL",
+	"freeway": "FWY", "fwy": "FWY",
+	"expressway": "EXPY", "expy": "EXPY",
+
+	// French, written before the street name
+	"rue":    "RUE",
+	"chemin": "CH", "ch": "CH",
+	"route": "RTE", "rte": "RTE",
+	"rang":   "RANG",
+	"montée": "MONTÉE", "montee": "MONTÉE",
+	"côte": "CÔTE", "cote": "CÔTE",
+	"allée": "ALLÉE", "allee": "ALLÉE",
+	"impasse": "IMP", "imp": "IMP",
+	"promenade": "PROM", "prom": "PROM",
+	"croissant": "CROIS", "crois": "CROIS",
+	"terrasse": "TSSE", "tsse": "TSSE",
+	"carré": "CAR", "carre": "CAR",
+	"ruelle":    "RLE",
+	"autoroute": "AUT", "aut": "AUT",
+}
+
+// Street types that come before the name in French addresses, e.g. "rue Principale". Avenue,
+// boulevard and place are used both ways.
+var frenchStreetTypes = map[string]bool{
+	"rue": true, "chemin": true, "ch": true, "route": true, "rte": true, "rang": true,
+	"montée": true, "montee": true, "côte": true, "cote": true, "allée": true, "allee": true,
+	"impasse": true, "imp": true, "promenade": true, "prom": true, "croissant": true, "crois": true,
+	"terrasse": true, "tsse": true, "carré": true, "carre": true, "ruelle": true, "autoroute": true, "aut": true,
+	"avenue": true, "av": true, "boulevard": true, "boul": true, "bd": true, "place": true,
+}
+
+// Street types that are also common words in municipality names, e.g. "Thunder Bay". They are
+// only trusted when the address also has a civic number.
+var ambiguousStreetTypes = map[string]bool{
+	"bay": true, "park": true, "point": true, "hill": true, "ridge": true, "grove": true,
+	"view": true, "green": true, "gate": true, "landing": true, "heights": true, "gardens": true,
+	"common": true, "line": true, "row": true, "path": true, "close": true, "mews": true,
+}
+
+// Street directions in English and French mapped to their abbreviation
+var streetDirections = map[string]string{
+	"n": "N", "north": "N", "nord": "N",
+	"s": "S", "south": "S", "sud": "S",
+	"e": "E", "east": "E", "est": "E",
+	"w": "W", "west": "W", "o": "W", "ouest": "W",
+	"ne": "NE", "northeast": "NE", "nw": "NW", "northwest": "NW",
+	"se": "SE", "southeast": "SE", "sw": "SW", "southwest": "SW",
+	"nord-est": "NE", "nord-ouest": "NW", "sud-est": "SE", "sud-ouest": "SW",
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"canada-hires/container"
+	"canada-hires/db"
+	"canada-hires/services"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Warn("Could not load .env file", "error", err)
+	}
+
+	filePath := flag.String("file", os.Getenv("POSTAL_CODE_CENTROIDS_PATH"), "Postal code or FSA centroid file (CSV or GeoNames CA.txt)")
+	flag.Parse()
+
+	if *filePath == "" {
+		fmt.Println("Usage: postal_code_import -file <path>, or set POSTAL_CODE_CENTROIDS_PATH")
+		os.Exit(1)
+	}
+
+	// Initialize database
+	database := db.InitDB()
+	defer database.Close()
+
+	// Test database connection
+	if err := database.Ping(); err != nil {
+		log.Fatal("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	// Create container and get geocoding service
+	cn, err := container.New()
+	if err != nil {
+		log.Fatal("Failed to create container", "error", err)
+		os.Exit(1)
+	}
+
+	var geocodingService services.PostalCodeGeocodingService
+	if err := cn.Invoke(func(s services.PostalCodeGeocodingService) {
+		geocodingService = s
+	}); err != nil {
+		log.Fatal("Failed to get geocoding service", "error", err)
+		os.Exit(1)
+	}
+
+	imported, err := geocodingService.ImportPostalCodeCentroids(*filePath)
+	if err != nil {
+		log.Error("Postal code centroid import failed", "error", err)
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d postal code centroids\n", imported)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"canada-hires/container"
+	"canada-hires/db"
+	"canada-hires/services"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load environment variables
+	if err := godotenv.Load(); err != nil {
+		log.Warn("Could not load .env file", "error", err)
+	}
+
+	filePath := flag.String("file", os.Getenv("ELECTORAL_DISTRICTS_PATH"), "Electoral district boundary file (GeoJSON or shapefile in longitude and latitude)")
+	flag.Parse()
+
+	if *filePath == "" {
+		fmt.Println("Usage: riding_import -file <path>, or set ELECTORAL_DISTRICTS_PATH")
+		os.Exit(1)
+	}
+
+	// Initialize database
+	database := db.InitDB()
+	defer database.Close()
+
+	// Test database connection
+	if err := database.Ping(); err != nil {
+		log.Fatal("Failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+
+	// Create container and get electoral district service
+	cn, err := container.New()
+	if err != nil {
+		log.Fatal("Failed to create container", "error", err)
+		os.Exit(1)
+	}
+
+	var districtService services.ElectoralDistrictService
+	if err := cn.Invoke(func(s services.ElectoralDistrictService) {
+		districtService = s
+	}); err != nil {
+		log.Fatal("Failed to get electoral district service", "error", err)
+		os.Exit(1)
+	}
+
+	imported, err := districtService.ImportBoundaries(*filePath)
+	if err != nil {
+		log.Error("Electoral district import failed", "error", err)
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Imported %d electoral districts\n", imported)
+}
@@ -69,6 +69,10 @@ func registerProviders(c *dig.Container) error {
 		return err
 	}
 
+	if err := c.Provide(NewBoycottCampaignRepository); err != nil {
+		return err
+	}
+
 	if err := c.Provide(NewPostalCodeRepository); err != nil {
 		return err
 	}
@@ -81,6 +85,50 @@ func registerProviders(c *dig.Container) error {
 		return err
 	}
 
+	if err := c.Provide(NewPrevailingWageRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewJobLMIAMatchRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewAddressRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewSpatialRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewElectoralDistrictRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewGeocodingReviewRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportAttachmentRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportVoteRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportRevisionRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewUserTierRepository); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewAPITokenRepository); err != nil {
+		return err
+	}
+
 	// Service providers
 	if err := c.Provide(NewEmailService); err != nil {
 		return err
@@ -146,6 +194,10 @@ func registerProviders(c *dig.Container) error {
 		return err
 	}
 
+	if err := c.Provide(NewBoycottCampaignService); err != nil {
+		return err
+	}
+
 	if err := c.Provide(NewPostalCodeService); err != nil {
 		return err
 	}
@@ -162,6 +214,54 @@ func registerProviders(c *dig.Container) error {
 		return err
 	}
 
+	if err := c.Provide(NewWageService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewLMIAMatchService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewNonCompliantMatchService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewAddressService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewSpatialService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewElectoralDistrictService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewGeocodingReviewService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportAttachmentService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportVoteService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewVerificationTierService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewVerificationTierCronService); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewAPITokenService); err != nil {
+		return err
+	}
+
 	// Controller providers
 	if err := c.Provide(NewAuthController); err != nil {
 		return err
@@ -196,10 +296,46 @@ func registerProviders(c *dig.Container) error {
 		return err
 	}
 
+	if err := c.Provide(NewBoycottCampaignController); err != nil {
+		return err
+	}
+
 	if err := c.Provide(NewNonCompliantController); err != nil {
 		return err
 	}
 
+	if err := c.Provide(NewAddressController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewSpatialController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewElectoralDistrictController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewGeocodingReviewController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportAttachmentController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewReportVoteController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewVerificationTierController); err != nil {
+		return err
+	}
+
+	if err := c.Provide(NewAPITokenController); err != nil {
+		return err
+	}
+
 	// Middleware providers
 	if err := c.Provide(NewAuthMiddleware); err != nil {
 		return err
@@ -254,13 +390,13 @@ func NewAuthService(userRepo repos.UserRepository, tokenRepo repos.LoginTokenRep
 }
 
 // NewBusinessService creates a new business service
-func NewBusinessService(repo repos.BusinessRepository) services.BusinessService {
-	return services.NewBusinessService(repo)
+func NewBusinessService(repo repos.BusinessRepository, reportService services.ReportService, emailService services.EmailService) services.BusinessService {
+	return services.NewBusinessService(repo, reportService, emailService)
 }
 
 // NewReportService creates a new report service
-func NewReportService(repo repos.ReportRepository) services.ReportService {
-	return services.NewReportService(repo)
+func NewReportService(repo repos.ReportRepository, nonCompliantMatchService services.NonCompliantMatchService, addressService services.AddressService, businessRepo repos.BusinessRepository, revisionRepo repos.ReportRevisionRepository) services.ReportService {
+	return services.NewReportService(repo, nonCompliantMatchService, addressService, businessRepo, revisionRepo)
 }
 
 // NewAuthController creates a new auth controller
@@ -287,8 +423,8 @@ func NewUserController(userService services.UserService) controllers.UserControl
 }
 
 // NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(authService services.AuthService, userService services.UserService) func(http.Handler) http.Handler {
-	authMW := middleware.NewAuthMiddleware(authService, userService)
+func NewAuthMiddleware(authService services.AuthService, userService services.UserService, apiTokenService services.APITokenService) func(http.Handler) http.Handler {
+	authMW := middleware.NewAuthMiddleware(authService, userService, apiTokenService)
 	return authMW.Middleware
 }
 
@@ -298,8 +434,8 @@ func NewLMIARepository(database db.Database) repos.LMIARepository {
 }
 
 // NewLMIAService creates a new LMIA service
-func NewLMIAService(repo repos.LMIARepository, geocodingService services.PostalCodeGeocodingService, postalCodeService services.PostalCodeService) services.LMIAService {
-	return services.NewLMIAService(repo, geocodingService, postalCodeService)
+func NewLMIAService(repo repos.LMIARepository, geocodingService services.PostalCodeGeocodingService, postalCodeService services.PostalCodeService, addressService services.AddressService) services.LMIAService {
+	return services.NewLMIAService(repo, geocodingService, postalCodeService, addressService)
 }
 
 // NewCronService creates a new cron service
@@ -338,8 +474,8 @@ func NewJobService(repo repos.JobBankRepository, redditService services.RedditSe
 }
 
 // NewJobController creates a new Job controller
-func NewJobController(repo repos.JobBankRepository, jobService services.JobService, redditService services.RedditService, scraperCronService *services.ScraperCronService, geminiService *services.GeminiService) *controllers.JobController {
-	return controllers.NewJobController(repo, jobService, redditService, scraperCronService, geminiService)
+func NewJobController(repo repos.JobBankRepository, jobService services.JobService, redditService services.RedditService, scraperCronService *services.ScraperCronService, geminiService *services.GeminiService, wageService services.WageService, lmiaMatchService services.LMIAMatchService) *controllers.JobController {
+	return controllers.NewJobController(repo, jobService, redditService, scraperCronService, geminiService, wageService, lmiaMatchService)
 }
 
 // NewScraperJobRepository creates a new scraper job repository
@@ -348,9 +484,9 @@ func NewScraperJobRepository(database db.Database) repos.ScraperJobRepository {
 }
 
 // NewScraperCronService creates a new scraper cron service
-func NewScraperCronService(scraperService services.ScraperService, scraperJobRepo repos.ScraperJobRepository, statisticsService services.LMIAStatisticsService) *services.ScraperCronService {
+func NewScraperCronService(scraperService services.ScraperService, scraperJobRepo repos.ScraperJobRepository, statisticsService services.LMIAStatisticsService, wageService services.WageService, lmiaMatchService services.LMIAMatchService, nonCompliantMatchService services.NonCompliantMatchService, spatialService services.SpatialService, electoralDistrictService services.ElectoralDistrictService) *services.ScraperCronService {
 	logger := log.Default()
-	return services.NewScraperCronService(logger, scraperService, scraperJobRepo, statisticsService)
+	return services.NewScraperCronService(logger, scraperService, scraperJobRepo, statisticsService, wageService, lmiaMatchService, nonCompliantMatchService, spatialService, electoralDistrictService)
 }
 
 // NewRedditService creates a new Reddit service
@@ -360,9 +496,9 @@ func NewRedditService(jobRepo repos.JobBankRepository, subredditRepo repos.Subre
 }
 
 // NewScraperService creates a new scraper service
-func NewScraperService(jobRepo repos.JobBankRepository) services.ScraperService {
+func NewScraperService(jobRepo repos.JobBankRepository, jobBankService services.JobBankService) services.ScraperService {
 	logger := log.Default()
-	return services.NewScraperService(jobRepo, logger)
+	return services.NewScraperService(jobRepo, jobBankService, logger)
 }
 
 // NewSubredditRepository creates a new subreddit repository
@@ -406,8 +542,8 @@ func NewBoycottRepository(database db.Database) repos.BoycottRepository {
 }
 
 // NewBoycottService creates a new boycott service
-func NewBoycottService(repo repos.BoycottRepository) services.BoycottService {
-	return services.NewBoycottService(repo)
+func NewBoycottService(repo repos.BoycottRepository, addressService services.AddressService) services.BoycottService {
+	return services.NewBoycottService(repo, addressService)
 }
 
 // NewBoycottController creates a new boycott controller
@@ -431,8 +567,8 @@ func NewAddressGeocodingCacheRepository(database db.Database) repos.AddressGeoco
 }
 
 // NewPostalCodeGeocodingService creates a new postal code geocoding service
-func NewPostalCodeGeocodingService(postalCodeRepo repos.PostalCodeRepository, postalCodeService services.PostalCodeService, addressCacheRepo repos.AddressGeocodingCacheRepository) services.PostalCodeGeocodingService {
-	return services.NewPostalCodeGeocodingService(postalCodeRepo, postalCodeService, addressCacheRepo)
+func NewPostalCodeGeocodingService(postalCodeRepo repos.PostalCodeRepository, postalCodeService services.PostalCodeService, addressCacheRepo repos.AddressGeocodingCacheRepository, reviewRepo repos.GeocodingReviewRepository) services.PostalCodeGeocodingService {
+	return services.NewPostalCodeGeocodingService(postalCodeRepo, postalCodeService, addressCacheRepo, reviewRepo)
 }
 
 // NewNonCompliantRepository creates a new non-compliant repository
@@ -441,19 +577,190 @@ func NewNonCompliantRepository(database db.Database) repos.NonCompliantRepositor
 }
 
 // NewNonCompliantService creates a new non-compliant service
-func NewNonCompliantService(repo repos.NonCompliantRepository, postalCodeService services.PostalCodeService, geocodingService services.PostalCodeGeocodingService) services.NonCompliantService {
+func NewNonCompliantService(repo repos.NonCompliantRepository, postalCodeService services.PostalCodeService, geocodingService services.PostalCodeGeocodingService, addressService services.AddressService) services.NonCompliantService {
 	logger := log.Default()
-	return services.NewNonCompliantService(repo, logger, postalCodeService, geocodingService)
+	return services.NewNonCompliantService(repo, logger, postalCodeService, geocodingService, addressService)
 }
 
 // NewNonCompliantController creates a new non-compliant controller
-func NewNonCompliantController(service services.NonCompliantService) *controllers.NonCompliantController {
+func NewNonCompliantController(service services.NonCompliantService, matchService services.NonCompliantMatchService) *controllers.NonCompliantController {
 	logger := log.Default()
-	return controllers.NewNonCompliantController(service, logger)
+	return controllers.NewNonCompliantController(service, matchService, logger)
 }
 
 // NewNonCompliantCronService creates a new non-compliant cron service
-func NewNonCompliantCronService(nonCompliantService services.NonCompliantService, scraperJobRepo repos.ScraperJobRepository) *services.NonCompliantCronService {
+func NewNonCompliantCronService(nonCompliantService services.NonCompliantService, matchService services.NonCompliantMatchService, scraperJobRepo repos.ScraperJobRepository) *services.NonCompliantCronService {
 	logger := log.Default()
-	return services.NewNonCompliantCronService(logger, nonCompliantService, scraperJobRepo)
+	return services.NewNonCompliantCronService(logger, nonCompliantService, matchService, scraperJobRepo)
+}
+
+// NewPrevailingWageRepository creates a new prevailing wage repository
+func NewPrevailingWageRepository(database db.Database) repos.PrevailingWageRepository {
+	return repos.NewPrevailingWageRepository(database.GetDB())
+}
+
+// NewWageService creates a new prevailing wage service
+func NewWageService(wageRepo repos.PrevailingWageRepository, jobBankRepo repos.JobBankRepository) services.WageService {
+	return services.NewWageService(wageRepo, jobBankRepo)
+}
+
+// NewJobLMIAMatchRepository creates a new job posting to LMIA approval match repository
+func NewJobLMIAMatchRepository(database db.Database) repos.JobLMIAMatchRepository {
+	return repos.NewJobLMIAMatchRepository(database.GetDB())
+}
+
+// NewLMIAMatchService creates a new LMIA approval matching service
+func NewLMIAMatchService(matchRepo repos.JobLMIAMatchRepository, jobBankRepo repos.JobBankRepository, postalCodeService services.PostalCodeService) services.LMIAMatchService {
+	return services.NewLMIAMatchService(matchRepo, jobBankRepo, postalCodeService)
+}
+
+// NewNonCompliantMatchService creates a new service linking non-compliant employers to postings and LMIA approvals
+func NewNonCompliantMatchService(nonCompliantRepo repos.NonCompliantRepository, jobBankRepo repos.JobBankRepository) services.NonCompliantMatchService {
+	return services.NewNonCompliantMatchService(nonCompliantRepo, jobBankRepo)
+}
+
+// NewAddressRepository creates a new parsed address repository
+func NewAddressRepository(database db.Database) repos.AddressRepository {
+	return repos.NewAddressRepository(database.GetDB())
+}
+
+// NewAddressService creates a new service that parses and stores addresses
+func NewAddressService(repo repos.AddressRepository) services.AddressService {
+	return services.NewAddressService(repo)
+}
+
+// NewAddressController creates a new parsed address controller
+func NewAddressController(service services.AddressService) controllers.AddressController {
+	return controllers.NewAddressController(service)
+}
+
+// NewSpatialRepository creates a new repository for radius and bounding box queries
+func NewSpatialRepository(database db.Database) repos.SpatialRepository {
+	return repos.NewSpatialRepository(database.GetDB())
+}
+
+// NewSpatialService creates a new service for location based searches
+func NewSpatialService(repo repos.SpatialRepository, geocodingService services.PostalCodeGeocodingService) services.SpatialService {
+	return services.NewSpatialService(repo, geocodingService)
+}
+
+// NewSpatialController creates a new spatial search controller
+func NewSpatialController(service services.SpatialService) controllers.SpatialController {
+	return controllers.NewSpatialController(service)
+}
+
+// NewElectoralDistrictRepository creates a new federal electoral district repository
+func NewElectoralDistrictRepository(database db.Database) repos.ElectoralDistrictRepository {
+	return repos.NewElectoralDistrictRepository(database.GetDB())
+}
+
+// NewElectoralDistrictService creates a new service for electoral district boundaries and summaries
+func NewElectoralDistrictService(repo repos.ElectoralDistrictRepository, geocodingService services.PostalCodeGeocodingService) services.ElectoralDistrictService {
+	return services.NewElectoralDistrictService(repo, geocodingService)
+}
+
+// NewElectoralDistrictController creates a new electoral district controller
+func NewElectoralDistrictController(service services.ElectoralDistrictService) controllers.ElectoralDistrictController {
+	return controllers.NewElectoralDistrictController(service)
+}
+
+// NewGeocodingReviewRepository creates a new repository for the geocoding review queue
+func NewGeocodingReviewRepository(database db.Database) repos.GeocodingReviewRepository {
+	return repos.NewGeocodingReviewRepository(database.GetDB())
+}
+
+// NewGeocodingReviewService creates a new service for reviewing geocoding results
+func NewGeocodingReviewService(repo repos.GeocodingReviewRepository) services.GeocodingReviewService {
+	return services.NewGeocodingReviewService(repo)
+}
+
+// NewGeocodingReviewController creates a new geocoding review controller
+func NewGeocodingReviewController(service services.GeocodingReviewService) controllers.GeocodingReviewController {
+	return controllers.NewGeocodingReviewController(service)
+}
+
+// NewReportAttachmentRepository creates a new report attachment repository
+func NewReportAttachmentRepository(database db.Database) repos.ReportAttachmentRepository {
+	return repos.NewReportAttachmentRepository(database.GetDB())
+}
+
+// NewReportAttachmentService creates a new service for report evidence files
+func NewReportAttachmentService(repo repos.ReportAttachmentRepository, reportService services.ReportService) services.ReportAttachmentService {
+	return services.NewReportAttachmentService(repo, reportService)
+}
+
+// NewReportAttachmentController creates a new report attachment controller
+func NewReportAttachmentController(service services.ReportAttachmentService) controllers.ReportAttachmentController {
+	return controllers.NewReportAttachmentController(service)
+}
+
+// NewReportVoteRepository creates a new report vote repository
+func NewReportVoteRepository(database db.Database) repos.ReportVoteRepository {
+	return repos.NewReportVoteRepository(database.GetDB())
+}
+
+// NewReportVoteService creates a new service for community votes on reports
+func NewReportVoteService(repo repos.ReportVoteRepository, reportService services.ReportService, userRepo repos.UserRepository) services.ReportVoteService {
+	return services.NewReportVoteService(repo, reportService, userRepo)
+}
+
+// NewReportVoteController creates a new report vote controller
+func NewReportVoteController(service services.ReportVoteService) controllers.ReportVoteController {
+	return controllers.NewReportVoteController(service)
+}
+
+// NewReportRevisionRepository creates a new report revision repository
+func NewReportRevisionRepository(database db.Database) repos.ReportRevisionRepository {
+	return repos.NewReportRevisionRepository(database.GetDB())
+}
+
+// NewBoycottCampaignRepository creates a new boycott campaign repository
+func NewBoycottCampaignRepository(database db.Database) repos.BoycottCampaignRepository {
+	return repos.NewBoycottCampaignRepository(database.GetDB())
+}
+
+// NewBoycottCampaignService creates a new boycott campaign service
+func NewBoycottCampaignService(repo repos.BoycottCampaignRepository) services.BoycottCampaignService {
+	return services.NewBoycottCampaignService(repo)
+}
+
+// NewBoycottCampaignController creates a new boycott campaign controller
+func NewBoycottCampaignController(service services.BoycottCampaignService) controllers.BoycottCampaignController {
+	return controllers.NewBoycottCampaignController(service)
+}
+
+// NewUserTierRepository creates a new user verification tier repository
+func NewUserTierRepository(database db.Database) repos.UserTierRepository {
+	return repos.NewUserTierRepository(database.GetDB())
+}
+
+// NewVerificationTierService creates a new verification tier service
+func NewVerificationTierService(repo repos.UserTierRepository) services.VerificationTierService {
+	return services.NewVerificationTierService(repo)
+}
+
+// NewVerificationTierCronService creates a new cron service for the scheduled tier evaluation
+func NewVerificationTierCronService(tierService services.VerificationTierService, scraperJobRepo repos.ScraperJobRepository) *services.VerificationTierCronService {
+	logger := log.Default()
+	return services.NewVerificationTierCronService(logger, tierService, scraperJobRepo)
+}
+
+// NewVerificationTierController creates a new verification tier controller
+func NewVerificationTierController(service services.VerificationTierService) controllers.VerificationTierController {
+	return controllers.NewVerificationTierController(service)
+}
+
+// NewAPITokenRepository creates a new API token repository
+func NewAPITokenRepository(database db.Database) repos.APITokenRepository {
+	return repos.NewAPITokenRepository(database.GetDB())
+}
+
+// NewAPITokenService creates a new API token service
+func NewAPITokenService(repo repos.APITokenRepository, userRepo repos.UserRepository) services.APITokenService {
+	return services.NewAPITokenService(repo, userRepo)
+}
+
+// NewAPITokenController creates a new API token controller
+func NewAPITokenController(service services.APITokenService) controllers.APITokenController {
+	return controllers.NewAPITokenController(service)
 }
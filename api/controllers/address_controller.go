@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type AddressController interface {
+	// Public endpoints
+	ParseAddress(w http.ResponseWriter, r *http.Request)
+	GetParsedAddress(w http.ResponseWriter, r *http.Request)
+
+	// Admin endpoints
+	ParseAllAddresses(w http.ResponseWriter, r *http.Request)
+	GetParseSummary(w http.ResponseWriter, r *http.Request)
+}
+
+type addressController struct {
+	service services.AddressService
+}
+
+func NewAddressController(service services.AddressService) AddressController {
+	return &addressController{service: service}
+}
+
+// ParseAddress splits the address in the "q" query parameter into its components without storing it
+func (c *addressController) ParseAddress(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, "q parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	components := c.service.Parse(query)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"address":    query,
+		"components": components,
+		"formatted":  components.Format(),
+	})
+}
+
+// GetParsedAddress returns a stored parsed address by ID
+func (c *addressController) GetParsedAddress(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "address_id")
+	if id == "" {
+		http.Error(w, "address_id is required", http.StatusBadRequest)
+		return
+	}
+
+	parsed, err := c.service.GetParsedAddress(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Address not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get parsed address", "error", err, "address_id", id)
+		http.Error(w, "Failed to get parsed address", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(parsed)
+}
+
+// ParseAllAddresses parses the unparsed addresses of every dataset in the background. A single
+// dataset can be chosen with the "source" query parameter.
+func (c *addressController) ParseAllAddresses(w http.ResponseWriter, r *http.Request) {
+	source := r.URL.Query().Get("source")
+	if source != "" && !slices.Contains(models.AddressSources, source) {
+		http.Error(w, "Invalid source (expected one of "+strings.Join(models.AddressSources, ", ")+")", http.StatusBadRequest)
+		return
+	}
+
+	go func() {
+		var err error
+		if source != "" {
+			_, err = c.service.ParseSourceAddresses(source)
+		} else {
+			_, err = c.service.ParseAllAddresses()
+		}
+		if err != nil {
+			log.Error("Address parsing failed", "source", source, "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Address parsing started in background",
+		"source":  source,
+	})
+}
+
+// GetParseSummary returns how many addresses of each dataset are parsed and how confidently
+func (c *addressController) GetParseSummary(w http.ResponseWriter, r *http.Request) {
+	summaries, err := c.service.GetParseSummary()
+	if err != nil {
+		log.Error("Failed to get address parse summary", "error", err)
+		http.Error(w, "Failed to get address parse summary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": summaries,
+	})
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type APITokenController interface {
+	CreateToken(w http.ResponseWriter, r *http.Request)
+	GetMyTokens(w http.ResponseWriter, r *http.Request)
+	RevokeToken(w http.ResponseWriter, r *http.Request)
+}
+
+type apiTokenController struct {
+	service services.APITokenService
+}
+
+func NewAPITokenController(service services.APITokenService) APITokenController {
+	return &apiTokenController{service: service}
+}
+
+// CreateToken creates an API token for the current user. The response is the only time the
+// token is shown.
+func (c *apiTokenController) CreateToken(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	var req services.CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	token, err := c.service.CreateToken(user, &req)
+	if err != nil {
+		writeAPITokenError(w, err, "Failed to create API token")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(token)
+}
+
+// GetMyTokens lists the current user's API tokens without the tokens themselves
+func (c *apiTokenController) GetMyTokens(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := c.service.GetUserTokens(user.ID)
+	if err != nil {
+		writeAPITokenError(w, err, "Failed to get API tokens")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  tokens,
+		"count": len(tokens),
+	})
+}
+
+// RevokeToken revokes one of the current user's API tokens
+func (c *apiTokenController) RevokeToken(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.service.RevokeToken(user.ID, chi.URLParam(r, "token_id")); err != nil {
+		writeAPITokenError(w, err, "Failed to revoke API token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAPITokenError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "API token not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidAPIToken):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrAPITokenScopeNotAllowed):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Error(message, "error", err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,227 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type BoycottCampaignController interface {
+	// Public routes
+	ListCampaigns(w http.ResponseWriter, r *http.Request)
+	GetCampaign(w http.ResponseWriter, r *http.Request)
+	GetCampaignStats(w http.ResponseWriter, r *http.Request)
+
+	// Protected routes (auth required)
+	CreateCampaign(w http.ResponseWriter, r *http.Request)
+	CloseCampaign(w http.ResponseWriter, r *http.Request)
+	Pledge(w http.ResponseWriter, r *http.Request)
+	WithdrawPledge(w http.ResponseWriter, r *http.Request)
+	GetMyCampaigns(w http.ResponseWriter, r *http.Request)
+}
+
+type boycottCampaignController struct {
+	service services.BoycottCampaignService
+}
+
+func NewBoycottCampaignController(service services.BoycottCampaignService) BoycottCampaignController {
+	return &boycottCampaignController{service: service}
+}
+
+type CreateCampaignRequest struct {
+	Title       string                    `json:"title"`
+	Description string                    `json:"description"`
+	TargetType  string                    `json:"target_type"`
+	BrandName   *string                   `json:"brand_name"`
+	Targets     []services.CampaignTarget `json:"targets"`
+	GoalPledges int                       `json:"goal_pledges"`
+	EndsAt      time.Time                 `json:"ends_at"`
+}
+
+// ListCampaigns returns campaigns, newest first. The "status" query parameter is active or ended.
+func (c *boycottCampaignController) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	campaigns, total, err := c.service.ListCampaigns(r.URL.Query().Get("status"), helpers.GetUserFromContext(r.Context()), limit, offset)
+	if err != nil {
+		writeCampaignError(w, err, "Failed to get boycott campaigns")
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*models.BoycottCampaign{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   campaigns,
+		"count":  len(campaigns),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func (c *boycottCampaignController) GetCampaign(w http.ResponseWriter, r *http.Request) {
+	campaign, err := c.service.GetCampaign(chi.URLParam(r, "id"), helpers.GetUserFromContext(r.Context()))
+	if err != nil {
+		writeCampaignError(w, err, "Failed to get boycott campaign")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// GetCampaignStats returns a campaign's progress, milestones and daily pledges
+func (c *boycottCampaignController) GetCampaignStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.service.GetCampaignStats(chi.URLParam(r, "id"))
+	if err != nil {
+		writeCampaignError(w, err, "Failed to get boycott campaign stats")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+func (c *boycottCampaignController) CreateCampaign(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateCampaignRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	campaign, err := c.service.CreateCampaign(&services.CreateCampaignRequest{
+		Creator:     user,
+		Title:       req.Title,
+		Description: req.Description,
+		TargetType:  req.TargetType,
+		BrandName:   req.BrandName,
+		Targets:     req.Targets,
+		GoalPledges: req.GoalPledges,
+		EndsAt:      req.EndsAt,
+	})
+	if err != nil {
+		writeCampaignError(w, err, "Failed to create boycott campaign")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// CloseCampaign stops a campaign from taking pledges
+func (c *boycottCampaignController) CloseCampaign(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	campaign, err := c.service.CloseCampaign(chi.URLParam(r, "id"), user)
+	if err != nil {
+		writeCampaignError(w, err, "Failed to close boycott campaign")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// Pledge adds the user's pledge to a campaign, along with any milestones it reached
+func (c *boycottCampaignController) Pledge(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	campaign, reached, err := c.service.Pledge(chi.URLParam(r, "id"), user)
+	if err != nil {
+		writeCampaignError(w, err, "Failed to pledge to boycott campaign")
+		return
+	}
+	if reached == nil {
+		reached = []*models.BoycottCampaignMilestone{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"campaign":           campaign,
+		"milestones_reached": reached,
+	})
+}
+
+func (c *boycottCampaignController) WithdrawPledge(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	campaign, err := c.service.WithdrawPledge(chi.URLParam(r, "id"), user)
+	if err != nil {
+		writeCampaignError(w, err, "Failed to withdraw boycott campaign pledge")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(campaign)
+}
+
+// GetMyCampaigns returns the campaigns the user pledges to
+func (c *boycottCampaignController) GetMyCampaigns(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	limit, offset := getPaginationParams(r)
+	campaigns, err := c.service.GetUserCampaigns(user.ID, limit, offset)
+	if err != nil {
+		writeCampaignError(w, err, "Failed to get pledged boycott campaigns")
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*models.BoycottCampaign{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  campaigns,
+		"count": len(campaigns),
+	})
+}
+
+// writeCampaignError maps boycott campaign service errors to responses
+func writeCampaignError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Boycott campaign not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidCampaign):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrCampaignNotAllowed):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrCampaignClosed):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error(message, "error", err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
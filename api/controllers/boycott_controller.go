@@ -2,8 +2,10 @@ package controllers
 
 import (
 	"canada-hires/helpers"
+	"canada-hires/models"
 	"canada-hires/services"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -18,6 +20,9 @@ type BoycottController interface {
 	// Public routes
 	GetTopBoycotted(w http.ResponseWriter, r *http.Request)
 	GetBoycottStats(w http.ResponseWriter, r *http.Request)
+	GetTrending(w http.ResponseWriter, r *http.Request)
+	GetActivity(w http.ResponseWriter, r *http.Request)
+	GetRegionalStats(w http.ResponseWriter, r *http.Request)
 }
 
 type boycottController struct {
@@ -203,3 +208,80 @@ func (c *boycottController) GetBoycottStats(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+// GetTrending returns the businesses gaining the most boycotts this week
+func (c *boycottController) GetTrending(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	trending, err := c.service.GetTrendingBoycotts(limit)
+	if err != nil {
+		log.Error("Failed to get trending boycotts", "error", err)
+		http.Error(w, "Failed to get trending boycotts", http.StatusInternalServerError)
+		return
+	}
+	if trending == nil {
+		trending = []*models.TrendingBoycott{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  trending,
+		"count": len(trending),
+	})
+}
+
+// GetActivity returns new boycotts and cancellations over time. The optional business_name and
+// business_address parameters narrow it to one business, interval is day or week and days is the
+// period covered.
+func (c *boycottController) GetActivity(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	interval := query.Get("interval")
+	days, _ := strconv.Atoi(query.Get("days"))
+
+	points, err := c.service.GetBoycottActivity(query.Get("business_name"), query.Get("business_address"), interval, days)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTrendQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("Failed to get boycott activity", "error", err)
+		http.Error(w, "Failed to get boycott activity", http.StatusInternalServerError)
+		return
+	}
+	if points == nil {
+		points = []*models.BoycottActivityPoint{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  points,
+		"count": len(points),
+	})
+}
+
+// GetRegionalStats rolls up boycotts by province, or by municipality with group_by=municipality,
+// optionally within one province
+func (c *boycottController) GetRegionalStats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	days, _ := strconv.Atoi(query.Get("days"))
+
+	stats, err := c.service.GetRegionalBoycotts(query.Get("group_by"), query.Get("province"), days)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTrendQuery) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("Failed to get regional boycott stats", "error", err)
+		http.Error(w, "Failed to get regional boycott stats", http.StatusInternalServerError)
+		return
+	}
+	if stats == nil {
+		stats = []*models.BoycottRegionStats{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  stats,
+		"count": len(stats),
+	})
+}
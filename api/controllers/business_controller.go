@@ -1,8 +1,19 @@
 package controllers
 
 import (
+	"canada-hires/helpers"
+	"canada-hires/models"
 	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
 )
 
 type BusinessController interface {
@@ -10,9 +21,28 @@ type BusinessController interface {
 	CreateBusiness(w http.ResponseWriter, r *http.Request)
 	GetBusiness(w http.ResponseWriter, r *http.Request)
 	UpdateBusiness(w http.ResponseWriter, r *http.Request)
+
+	// Public routes
+	ConfirmClaimPage(w http.ResponseWriter, r *http.Request)
+	VerifyClaim(w http.ResponseWriter, r *http.Request)
+	GetStatement(w http.ResponseWriter, r *http.Request)
+
+	// Protected routes (auth required)
+	StartClaim(w http.ResponseWriter, r *http.Request)
+	GetMyClaims(w http.ResponseWriter, r *http.Request)
+	SetStatement(w http.ResponseWriter, r *http.Request)
+	ReplyToReport(w http.ResponseWriter, r *http.Request)
+
+	// Moderation routes (moderator or admin required)
+	GetReplyQueue(w http.ResponseWriter, r *http.Request)
+	PublishReply(w http.ResponseWriter, r *http.Request)
+	RejectReply(w http.ResponseWriter, r *http.Request)
+	GetClaimQueue(w http.ResponseWriter, r *http.Request)
+	ApproveClaim(w http.ResponseWriter, r *http.Request)
+	RevokeClaim(w http.ResponseWriter, r *http.Request)
 }
 
-type businessController struct{
+type businessController struct {
 	service services.BusinessService
 }
 
@@ -20,7 +50,344 @@ func NewBusinessController(service services.BusinessService) BusinessController
 	return &businessController{service: service}
 }
 
-func (c *businessController) GetBusinesses(w http.ResponseWriter, r *http.Request) {}
+func (c *businessController) GetBusinesses(w http.ResponseWriter, r *http.Request)  {}
 func (c *businessController) CreateBusiness(w http.ResponseWriter, r *http.Request) {}
 func (c *businessController) GetBusiness(w http.ResponseWriter, r *http.Request)    {}
 func (c *businessController) UpdateBusiness(w http.ResponseWriter, r *http.Request) {}
+
+type startClaimRequest struct {
+	BusinessName    string `json:"business_name"`
+	BusinessAddress string `json:"business_address"`
+	Email           string `json:"email"`
+}
+
+// businessReplyRequest is the body of report responses and business statements
+type businessReplyRequest struct {
+	Body string `json:"body"`
+}
+
+// replyModerationRequest is the optional body of reply moderation and claim revocation requests
+type replyModerationRequest struct {
+	Notes *string `json:"notes"`
+}
+
+// StartClaim emails a verification link to an address at the business's domain
+func (c *businessController) StartClaim(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req startClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	claim, err := c.service.StartClaim(&services.StartClaimRequest{
+		User:            user,
+		BusinessName:    req.BusinessName,
+		BusinessAddress: req.BusinessAddress,
+		Email:           req.Email,
+	})
+	if err != nil {
+		writeBusinessError(w, err, "Failed to start business claim")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(claim)
+}
+
+// claimConfirmPage is shown for the emailed link. Confirming submits a POST to the same URL, so
+// mail scanners that open links don't confirm claims.
+const claimConfirmPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<title>Confirm your business - JobWatch Canada</title>
+</head>
+<body>
+	<h1>Confirm your business</h1>
+	<p>Confirm that you asked to represent this business on JobWatch Canada. A moderator reviews the claim before you can respond to reports.</p>
+	<form method="post">
+		<button type="submit">Confirm</button>
+	</form>
+</body>
+</html>`
+
+// ConfirmClaimPage shows the page of the emailed verification link, it doesn't change the claim
+func (c *businessController) ConfirmClaimPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	// The token is in the URL, don't send it to other sites
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; form-action 'self'")
+	w.Write([]byte(claimConfirmPage))
+}
+
+// VerifyClaim confirms the claim of the emailed link, submitted from ConfirmClaimPage, and
+// redirects to the frontend. The claim then awaits a moderator's review.
+func (c *businessController) VerifyClaim(w http.ResponseWriter, r *http.Request) {
+	redirect := os.Getenv("FRONTEND_URL") + "/business/claim"
+
+	claim, err := c.service.VerifyClaim(chi.URLParam(r, "token"))
+	if err != nil {
+		reason := "verification_failed"
+		if errors.Is(err, services.ErrBusinessAlreadyClaimed) {
+			reason = "already_claimed"
+		} else if errors.Is(err, services.ErrClaimAwaitingReview) {
+			reason = "awaiting_review"
+		} else if !errors.Is(err, services.ErrInvalidClaimToken) {
+			log.Error("Failed to verify business claim", "error", err)
+		}
+		http.Redirect(w, r, redirect+"?error="+reason, http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, redirect+"?verified="+url.QueryEscape(claim.ID), http.StatusSeeOther)
+}
+
+// GetMyClaims returns the user's business claims
+func (c *businessController) GetMyClaims(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := c.service.GetUserClaims(user.ID)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to get business claims")
+		return
+	}
+	if claims == nil {
+		claims = []*models.BusinessClaim{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  claims,
+		"count": len(claims),
+	})
+}
+
+// SetStatement posts or replaces the general statement of a verified business
+func (c *businessController) SetStatement(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req businessReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := c.service.SetStatement(user, chi.URLParam(r, "claim_id"), req.Body)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to save business statement")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// GetStatement returns the published statement of a business, by name and address
+func (c *businessController) GetStatement(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	businessAddress := r.URL.Query().Get("address")
+	if name == "" || businessAddress == "" {
+		http.Error(w, "name and address are required", http.StatusBadRequest)
+		return
+	}
+
+	statement, err := c.service.GetStatement(name, businessAddress)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Business statement not found", http.StatusNotFound)
+			return
+		}
+		writeBusinessError(w, err, "Failed to get business statement")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(statement.Public())
+}
+
+// ReplyToReport posts or replaces the business's response to a report about it
+func (c *businessController) ReplyToReport(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req businessReplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	reply, err := c.service.ReplyToReport(user, chi.URLParam(r, "report_id"), req.Body)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to save business reply")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// GetReplyQueue returns business replies in a status, pending by default
+func (c *businessController) GetReplyQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	replies, total, err := c.service.GetReplyQueue(r.URL.Query().Get("status"), limit, offset)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to get business reply queue")
+		return
+	}
+	if replies == nil {
+		replies = []*models.BusinessReply{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   replies,
+		"count":  len(replies),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// PublishReply shows a business reply publicly
+func (c *businessController) PublishReply(w http.ResponseWriter, r *http.Request) {
+	c.moderateReply(w, r, "publish")
+}
+
+// RejectReply keeps a business reply hidden
+func (c *businessController) RejectReply(w http.ResponseWriter, r *http.Request) {
+	c.moderateReply(w, r, "reject")
+}
+
+func (c *businessController) moderateReply(w http.ResponseWriter, r *http.Request, action string) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, ok := decodeReplyModeration(w, r)
+	if !ok {
+		return
+	}
+
+	reply, err := c.service.ModerateReply(chi.URLParam(r, "reply_id"), action, user.ID, req.Notes)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to moderate business reply")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(reply)
+}
+
+// GetClaimQueue returns the business claims awaiting review
+func (c *businessController) GetClaimQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	claims, total, err := c.service.GetClaimQueue(limit, offset)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to get business claim queue")
+		return
+	}
+	if claims == nil {
+		claims = []*models.BusinessClaim{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   claims,
+		"count":  len(claims),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ApproveClaim verifies a business claim after its email domain was checked against the business
+func (c *businessController) ApproveClaim(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	claim, err := c.service.ApproveClaim(chi.URLParam(r, "claim_id"), user.ID)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to approve business claim")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claim)
+}
+
+// RevokeClaim withdraws a business claim, hiding its replies
+func (c *businessController) RevokeClaim(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	req, ok := decodeReplyModeration(w, r)
+	if !ok {
+		return
+	}
+
+	claim, err := c.service.RevokeClaim(chi.URLParam(r, "claim_id"), user.ID, req.Notes)
+	if err != nil {
+		writeBusinessError(w, err, "Failed to revoke business claim")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claim)
+}
+
+// decodeReplyModeration reads the optional notes of a moderation request
+func decodeReplyModeration(w http.ResponseWriter, r *http.Request) (*replyModerationRequest, bool) {
+	var req replyModerationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+// writeBusinessError maps business service errors to responses
+func writeBusinessError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrInvalidClaim), errors.Is(err, services.ErrInvalidBusinessReply):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, services.ErrBusinessAlreadyClaimed), errors.Is(err, services.ErrClaimAwaitingReview):
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errors.Is(err, services.ErrNotBusinessOwner), errors.Is(err, services.ErrClaimNotEligible):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	default:
+		log.Error(message, "error", err)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
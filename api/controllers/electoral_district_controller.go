@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"canada-hires/address"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type ElectoralDistrictController interface {
+	// Public endpoints
+	ListDistricts(w http.ResponseWriter, r *http.Request)
+	GetDistrict(w http.ResponseWriter, r *http.Request)
+	GetSummary(w http.ResponseWriter, r *http.Request)
+	LookupPostalCode(w http.ResponseWriter, r *http.Request)
+
+	// Admin endpoints
+	ImportBoundaries(w http.ResponseWriter, r *http.Request)
+	AssignDistricts(w http.ResponseWriter, r *http.Request)
+}
+
+type electoralDistrictController struct {
+	service services.ElectoralDistrictService
+}
+
+func NewElectoralDistrictController(service services.ElectoralDistrictService) ElectoralDistrictController {
+	return &electoralDistrictController{service: service}
+}
+
+// ListDistricts returns every federal electoral district, or those of the province given in the
+// "province" query parameter
+func (c *electoralDistrictController) ListDistricts(w http.ResponseWriter, r *http.Request) {
+	provinceCode := ""
+	if province := r.URL.Query().Get("province"); province != "" {
+		code, ok := address.ProvinceCode(province)
+		if !ok {
+			http.Error(w, "Invalid province parameter", http.StatusBadRequest)
+			return
+		}
+		provinceCode = code
+	}
+
+	districts, err := c.service.ListDistricts(provinceCode)
+	if err != nil {
+		log.Error("Failed to list electoral districts", "error", err)
+		http.Error(w, "Failed to list electoral districts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  districts,
+		"count": len(districts),
+	})
+}
+
+// GetDistrict returns a single district by its number
+func (c *electoralDistrictController) GetDistrict(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "district_id")
+
+	district, err := c.service.GetDistrict(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Electoral district not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get electoral district", "error", err, "district_id", id)
+		http.Error(w, "Failed to get electoral district", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(district)
+}
+
+// GetSummary returns the LMIA approvals, non-compliant employers and job postings located in a
+// district, for all years or the one in the "year" query parameter
+func (c *electoralDistrictController) GetSummary(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "district_id")
+
+	year := 0
+	if yearStr := r.URL.Query().Get("year"); yearStr != "" {
+		parsedYear, err := strconv.Atoi(yearStr)
+		if err != nil || parsedYear < 2000 || parsedYear > time.Now().Year() {
+			http.Error(w, "Invalid year parameter", http.StatusBadRequest)
+			return
+		}
+		year = parsedYear
+	}
+
+	summary, err := c.service.GetSummary(id, year)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Electoral district not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get electoral district summary", "error", err, "district_id", id)
+		http.Error(w, "Failed to get electoral district summary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(summary)
+}
+
+// LookupPostalCode returns the district of the postal code in the "postal_code" query parameter
+func (c *electoralDistrictController) LookupPostalCode(w http.ResponseWriter, r *http.Request) {
+	postalCode := address.ExtractPostalCode(strings.TrimSpace(r.URL.Query().Get("postal_code")))
+	if postalCode == "" {
+		http.Error(w, "A valid postal_code parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	district, err := c.service.LookupPostalCode(postalCode)
+	if err != nil {
+		if errors.Is(err, services.ErrDistrictNotFound) {
+			http.Error(w, "No electoral district found for postal code", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to look up postal code electoral district", "error", err, "postal_code", postalCode)
+		http.Error(w, "Failed to look up electoral district", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"postal_code": postalCode,
+		"district":    district,
+	})
+}
+
+// ImportBoundaries loads the district boundaries from ELECTORAL_DISTRICTS_PATH and assigns every
+// geocoded point to its district, in the background
+func (c *electoralDistrictController) ImportBoundaries(w http.ResponseWriter, r *http.Request) {
+	filePath := os.Getenv("ELECTORAL_DISTRICTS_PATH")
+	if filePath == "" {
+		http.Error(w, "ELECTORAL_DISTRICTS_PATH is not configured", http.StatusBadRequest)
+		return
+	}
+
+	go func() {
+		if _, err := c.service.ImportBoundaries(filePath); err != nil {
+			log.Error("Electoral district import failed", "file_path", filePath, "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":   "Electoral district import started in background",
+		"file_path": filePath,
+	})
+}
+
+// AssignDistricts assigns geocoded points that have no district yet, in the background
+func (c *electoralDistrictController) AssignDistricts(w http.ResponseWriter, r *http.Request) {
+	go func() {
+		if _, err := c.service.AssignDistricts(); err != nil {
+			log.Error("Electoral district assignment failed", "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Electoral district assignment started in background",
+	})
+}
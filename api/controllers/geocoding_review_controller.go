@@ -0,0 +1,247 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+// Page size of the geocoding review queue
+const (
+	defaultGeocodingReviewLimit = 50
+	maxGeocodingReviewLimit     = 200
+)
+
+type GeocodingReviewController interface {
+	// Admin endpoints
+	ListReviews(w http.ResponseWriter, r *http.Request)
+	GetSummary(w http.ResponseWriter, r *http.Request)
+	GetReview(w http.ResponseWriter, r *http.Request)
+	AcceptReview(w http.ResponseWriter, r *http.Request)
+	RejectReview(w http.ResponseWriter, r *http.Request)
+	PinReview(w http.ResponseWriter, r *http.Request)
+}
+
+type geocodingReviewController struct {
+	service services.GeocodingReviewService
+}
+
+func NewGeocodingReviewController(service services.GeocodingReviewService) GeocodingReviewController {
+	return &geocodingReviewController{service: service}
+}
+
+// reviewDecisionRequest is the body of accept, reject and pin requests. Coordinates are only
+// used to pin.
+type reviewDecisionRequest struct {
+	Latitude  *float64 `json:"latitude"`
+	Longitude *float64 `json:"longitude"`
+	Notes     *string  `json:"notes"`
+}
+
+// ListReviews returns the review queue, oldest first. Query parameters:
+//   - status: pending (default), accepted, rejected, pinned or all
+//   - reason: low_confidence, province_mismatch or postal_code_mismatch
+//   - target_type: postal_code or address
+//   - limit, offset: paging
+func (c *geocodingReviewController) ListReviews(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := models.GeocodingReviewFilter{
+		Status:     models.GeocodingReviewPending,
+		Reason:     query.Get("reason"),
+		TargetType: query.Get("target_type"),
+		Limit:      defaultGeocodingReviewLimit,
+	}
+
+	if status := query.Get("status"); status == "all" {
+		filter.Status = ""
+	} else if status != "" {
+		if !slices.Contains(models.GeocodingReviewStatuses, status) {
+			http.Error(w, "Invalid status parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Status = status
+	}
+
+	switch filter.Reason {
+	case "", models.GeocodingReviewLowConfidence, models.GeocodingReviewProvinceMismatch, models.GeocodingReviewPostalCodeMismatch:
+	default:
+		http.Error(w, "Invalid reason parameter", http.StatusBadRequest)
+		return
+	}
+
+	switch filter.TargetType {
+	case "", models.GeocodingTargetPostalCode, models.GeocodingTargetAddress:
+	default:
+		http.Error(w, "Invalid target_type parameter", http.StatusBadRequest)
+		return
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > maxGeocodingReviewLimit {
+			http.Error(w, "Invalid limit parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			http.Error(w, "Invalid offset parameter", http.StatusBadRequest)
+			return
+		}
+		filter.Offset = offset
+	}
+
+	reviews, total, err := c.service.ListReviews(filter)
+	if err != nil {
+		log.Error("Failed to list geocoding reviews", "error", err)
+		http.Error(w, "Failed to list geocoding reviews", http.StatusInternalServerError)
+		return
+	}
+
+	if reviews == nil {
+		reviews = []*models.GeocodingReview{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   reviews,
+		"count":  len(reviews),
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	})
+}
+
+// GetSummary returns the number of reviews in each status
+func (c *geocodingReviewController) GetSummary(w http.ResponseWriter, r *http.Request) {
+	counts, err := c.service.GetStatusCounts()
+	if err != nil {
+		log.Error("Failed to get geocoding review summary", "error", err)
+		http.Error(w, "Failed to get geocoding review summary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(counts)
+}
+
+// GetReview returns a single review
+func (c *geocodingReviewController) GetReview(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseReviewID(w, r)
+	if !ok {
+		return
+	}
+
+	review, err := c.service.GetReview(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Geocoding review not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get geocoding review", "error", err, "review_id", id)
+		http.Error(w, "Failed to get geocoding review", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// AcceptReview confirms the reviewed result and stores it as a manual override
+func (c *geocodingReviewController) AcceptReview(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, func(id int, reviewerID string, req *reviewDecisionRequest) (*models.GeocodingReview, error) {
+		return c.service.AcceptReview(id, reviewerID, req.Notes)
+	})
+}
+
+// RejectReview discards the reviewed result
+func (c *geocodingReviewController) RejectReview(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, func(id int, reviewerID string, req *reviewDecisionRequest) (*models.GeocodingReview, error) {
+		return c.service.RejectReview(id, reviewerID, req.Notes)
+	})
+}
+
+// PinReview stores the latitude and longitude of the request body as a manual override
+func (c *geocodingReviewController) PinReview(w http.ResponseWriter, r *http.Request) {
+	c.decide(w, r, func(id int, reviewerID string, req *reviewDecisionRequest) (*models.GeocodingReview, error) {
+		if req.Latitude == nil || req.Longitude == nil {
+			return nil, errMissingPin
+		}
+		return c.service.PinReview(id, reviewerID, *req.Latitude, *req.Longitude, req.Notes)
+	})
+}
+
+var errMissingPin = errors.New("latitude and longitude are required")
+
+// decide parses a decision request, applies it and writes the resolved review
+func (c *geocodingReviewController) decide(w http.ResponseWriter, r *http.Request, apply func(int, string, *reviewDecisionRequest) (*models.GeocodingReview, error)) {
+	id, ok := parseReviewID(w, r)
+	if !ok {
+		return
+	}
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// The body is optional when accepting or rejecting
+	var req reviewDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Notes != nil {
+		notes := strings.TrimSpace(*req.Notes)
+		req.Notes = &notes
+		if notes == "" {
+			req.Notes = nil
+		}
+	}
+
+	review, err := apply(id, user.ID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			http.Error(w, "Geocoding review not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrReviewResolved):
+			http.Error(w, "Geocoding review was already resolved", http.StatusConflict)
+		case errors.Is(err, errMissingPin):
+			http.Error(w, "latitude and longitude are required", http.StatusBadRequest)
+		case errors.Is(err, services.ErrInvalidPin):
+			http.Error(w, "Coordinates must be in Canada", http.StatusBadRequest)
+		default:
+			log.Error("Failed to resolve geocoding review", "error", err, "review_id", id)
+			http.Error(w, "Failed to resolve geocoding review", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+func parseReviewID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "review_id"))
+	if err != nil || id <= 0 {
+		http.Error(w, "Invalid review ID", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -23,15 +24,19 @@ type JobController struct {
 	redditService      services.RedditService
 	scraperCronService *services.ScraperCronService
 	geminiService      *services.GeminiService
+	wageService        services.WageService
+	lmiaMatchService   services.LMIAMatchService
 }
 
-func NewJobController(jobBankRepo repos.JobBankRepository, jobService services.JobService, redditService services.RedditService, scraperCronService *services.ScraperCronService, geminiService *services.GeminiService) *JobController {
+func NewJobController(jobBankRepo repos.JobBankRepository, jobService services.JobService, redditService services.RedditService, scraperCronService *services.ScraperCronService, geminiService *services.GeminiService, wageService services.WageService, lmiaMatchService services.LMIAMatchService) *JobController {
 	return &JobController{
 		jobBankRepo:        jobBankRepo,
 		jobService:         jobService,
 		redditService:      redditService,
 		scraperCronService: scraperCronService,
 		geminiService:      geminiService,
+		wageService:        wageService,
+		lmiaMatchService:   lmiaMatchService,
 	}
 }
 
@@ -145,6 +150,14 @@ func (jc *JobController) GetJobPostings(w http.ResponseWriter, r *http.Request)
 	province := r.URL.Query().Get("province")
 	title := r.URL.Query().Get("title")
 	salaryMinStr := r.URL.Query().Get("salary_min")
+	salaryHourlyMinStr := r.URL.Query().Get("salary_hourly_min")
+	salaryAnnualMinStr := r.URL.Query().Get("salary_annual_min")
+	belowMedianStr := r.URL.Query().Get("below_median")
+	maxPercentOfMedianStr := r.URL.Query().Get("max_percent_of_median")
+	nocCode := r.URL.Query().Get("noc_code")
+	minRepostsStr := r.URL.Query().Get("min_reposts")
+	repeatLMIAUserStr := r.URL.Query().Get("repeat_lmia_user")
+	nonCompliantStr := r.URL.Query().Get("non_compliant_employer")
 	sortBy := r.URL.Query().Get("sort_by")
 	sortOrder := r.URL.Query().Get("sort_order")
 	limitStr := r.URL.Query().Get("limit")
@@ -174,11 +187,68 @@ func (jc *JobController) GetJobPostings(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	// Parse salary filter
-	var salaryMin *float64
-	if salaryMinStr != "" {
+	// Parse salary filters. Both compare against normalized salaries so hourly and
+	// yearly postings can be filtered together.
+	var salaryHourlyMin, salaryAnnualMin *float64
+	if salaryHourlyMinStr != "" {
+		if parsedSalary, err := strconv.ParseFloat(salaryHourlyMinStr, 64); err == nil {
+			salaryHourlyMin = &parsedSalary
+		}
+	}
+	if salaryAnnualMinStr != "" {
+		if parsedSalary, err := strconv.ParseFloat(salaryAnnualMinStr, 64); err == nil {
+			salaryAnnualMin = &parsedSalary
+		}
+	}
+
+	// Legacy salary_min doesn't say which period it is in, so treat small values
+	// as an hourly wage and anything larger as an annual salary
+	if salaryMinStr != "" && salaryHourlyMin == nil && salaryAnnualMin == nil {
 		if parsedSalary, err := strconv.ParseFloat(salaryMinStr, 64); err == nil {
-			salaryMin = &parsedSalary
+			if parsedSalary < 1000 {
+				salaryHourlyMin = &parsedSalary
+			} else {
+				salaryAnnualMin = &parsedSalary
+			}
+		}
+	}
+
+	// Parse prevailing wage filters
+	var belowMedian *bool
+	if belowMedianStr != "" {
+		if parsed, err := strconv.ParseBool(belowMedianStr); err == nil {
+			belowMedian = &parsed
+		}
+	}
+
+	var maxPercentOfMedian *float64
+	if maxPercentOfMedianStr != "" {
+		if parsed, err := strconv.ParseFloat(maxPercentOfMedianStr, 64); err == nil {
+			maxPercentOfMedian = &parsed
+		}
+	}
+
+	// Parse repost filter
+	minReposts := 0
+	if minRepostsStr != "" {
+		if parsed, err := strconv.Atoi(minRepostsStr); err == nil && parsed >= 0 {
+			minReposts = parsed
+		}
+	}
+
+	// Parse LMIA approval history filter
+	var repeatLMIAUser *bool
+	if repeatLMIAUserStr != "" {
+		if parsed, err := strconv.ParseBool(repeatLMIAUserStr); err == nil {
+			repeatLMIAUser = &parsed
+		}
+	}
+
+	// Parse non-compliant employer filter
+	var nonCompliant *bool
+	if nonCompliantStr != "" {
+		if parsed, err := strconv.ParseBool(nonCompliantStr); err == nil {
+			nonCompliant = &parsed
 		}
 	}
 
@@ -192,17 +262,24 @@ func (jc *JobController) GetJobPostings(w http.ResponseWriter, r *http.Request)
 
 	// Create filter parameters
 	filters := map[string]interface{}{
-		"search":     search,
-		"employer":   employer,
-		"city":       city,
-		"province":   province,
-		"title":      title,
-		"salary_min": salaryMin,
-		"days":       days,
-		"sort_by":    sortBy,
-		"sort_order": sortOrder,
-		"limit":      limit,
-		"offset":     offset,
+		"search":                    search,
+		"employer":                  employer,
+		"city":                      city,
+		"province":                  province,
+		"title":                     title,
+		"salary_hourly_min":         salaryHourlyMin,
+		"salary_annual_min":         salaryAnnualMin,
+		"below_median_wage":         belowMedian,
+		"max_percent_of_median":     maxPercentOfMedian,
+		"noc_code":                  nocCode,
+		"min_repost_count":          minReposts,
+		"is_repeat_lmia_user":       repeatLMIAUser,
+		"is_non_compliant_employer": nonCompliant,
+		"days":                      days,
+		"sort_by":                   sortBy,
+		"sort_order":                sortOrder,
+		"limit":                     limit,
+		"offset":                    offset,
 	}
 
 	jobs, totalCount, err := jc.jobBankRepo.SearchJobPostingsAdvanced(filters)
@@ -257,6 +334,133 @@ func (jc *JobController) GetJobStats(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(stats)
 }
 
+// GetRepostedJobs lists posting families that have been reposted the most
+func (jc *JobController) GetRepostedJobs(w http.ResponseWriter, r *http.Request) {
+	minReposts := 1
+	if minRepostsStr := r.URL.Query().Get("min_reposts"); minRepostsStr != "" {
+		if parsed, err := strconv.Atoi(minRepostsStr); err == nil && parsed >= 0 {
+			minReposts = parsed
+		}
+	}
+
+	limit := 50
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 500 {
+			limit = parsedLimit
+		}
+	}
+
+	families, err := jc.jobBankRepo.GetMostRepostedFamilies(minReposts, limit)
+	if err != nil {
+		log.Error("Failed to retrieve reposted jobs", "error", err)
+		http.Error(w, "Failed to retrieve reposted jobs", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"families": families,
+		"count":    len(families),
+	})
+}
+
+// GetPostingFamily returns a posting family with every posting seen in it
+func (jc *JobController) GetPostingFamily(w http.ResponseWriter, r *http.Request) {
+	familyID := chi.URLParam(r, "family_id")
+	if familyID == "" {
+		http.Error(w, "Family ID is required", http.StatusBadRequest)
+		return
+	}
+
+	family, err := jc.jobBankRepo.GetPostingFamilyByID(familyID)
+	if err != nil {
+		log.Error("Failed to retrieve posting family", "family_id", familyID, "error", err)
+		http.Error(w, "Posting family not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"family":       family,
+		"repost_count": family.RepostCount(),
+	})
+}
+
+// RebuildPostingFamilies recomputes repost fingerprints and families for all stored postings
+func (jc *JobController) RebuildPostingFamilies(w http.ResponseWriter, r *http.Request) {
+	log.Info("Manual posting family rebuild requested")
+
+	processed, err := jc.jobBankRepo.RebuildPostingFamilies()
+	if err != nil {
+		log.Error("Failed to rebuild posting families", "error", err)
+		http.Error(w, "Failed to rebuild posting families: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":            "Posting families rebuilt successfully",
+		"postings_processed": processed,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetJobLMIAApprovals returns the employer's historical LMIA approvals matched to a job posting
+func (jc *JobController) GetJobLMIAApprovals(w http.ResponseWriter, r *http.Request) {
+	jobID := chi.URLParam(r, "job_id")
+	if jobID == "" {
+		http.Error(w, "Job ID is required", http.StatusBadRequest)
+		return
+	}
+
+	job, err := jc.jobBankRepo.GetJobPostingByID(jobID)
+	if err != nil {
+		log.Error("Failed to get job posting", "job_id", jobID, "error", err)
+		http.Error(w, "Job not found", http.StatusNotFound)
+		return
+	}
+
+	approvals, err := jc.lmiaMatchService.GetApprovalHistory(jobID)
+	if err != nil {
+		log.Error("Failed to get LMIA approvals for job", "job_id", jobID, "error", err)
+		http.Error(w, "Failed to get LMIA approvals", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"job_id":               job.ID,
+		"employer":             job.Employer,
+		"match_confidence":     job.LMIAMatchConfidence,
+		"historical_approvals": job.LMIAHistoricalApprovals,
+		"historical_positions": job.LMIAHistoricalPositions,
+		"approval_periods":     job.LMIAApprovalPeriods,
+		"is_repeat_lmia_user":  job.IsRepeatLMIAUser,
+		"approvals":            approvals,
+	})
+}
+
+// TriggerLMIAMatching links all job postings to LMIA approval records
+func (jc *JobController) TriggerLMIAMatching(w http.ResponseWriter, r *http.Request) {
+	log.Info("Manual LMIA approval matching requested")
+
+	matched, err := jc.lmiaMatchService.MatchAllJobPostings()
+	if err != nil {
+		log.Error("Failed to match job postings to LMIA approvals", "error", err)
+		http.Error(w, "Failed to match job postings to LMIA approvals: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":          "LMIA approval matching completed",
+		"postings_matched": matched,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // GetScrapingRuns retrieves recent scraping runs
 func (jc *JobController) GetScrapingRuns(w http.ResponseWriter, r *http.Request) {
 	// For now, just get the latest run
@@ -699,6 +903,60 @@ func (jc *JobController) TriggerStatisticsAggregation(w http.ResponseWriter, r *
 	json.NewEncoder(w).Encode(response)
 }
 
+// ImportPrevailingWages loads the wage table from WAGE_TABLE_CSV_PATH and re-annotates job postings
+func (jc *JobController) ImportPrevailingWages(w http.ResponseWriter, r *http.Request) {
+	filePath := os.Getenv("WAGE_TABLE_CSV_PATH")
+	if filePath == "" {
+		http.Error(w, "WAGE_TABLE_CSV_PATH is not configured", http.StatusBadRequest)
+		return
+	}
+
+	log.Info("Manual prevailing wage import requested", "file_path", filePath)
+
+	imported, err := jc.wageService.ImportWageTableFromCSV(filePath)
+	if err != nil {
+		log.Error("Failed to import prevailing wages", "error", err)
+		http.Error(w, "Failed to import prevailing wages: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	annotated, err := jc.wageService.AnnotateAllJobPostings()
+	if err != nil {
+		log.Error("Failed to annotate job postings with prevailing wages", "error", err)
+		http.Error(w, "Failed to annotate job postings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":           "Prevailing wages imported successfully",
+		"wages_imported":    imported,
+		"postings_compared": annotated,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// TriggerWageComparison recomputes the prevailing wage comparison for all job postings
+func (jc *JobController) TriggerWageComparison(w http.ResponseWriter, r *http.Request) {
+	log.Info("Manual prevailing wage comparison requested")
+
+	annotated, err := jc.wageService.AnnotateAllJobPostings()
+	if err != nil {
+		log.Error("Failed to run prevailing wage comparison", "error", err)
+		http.Error(w, "Failed to run prevailing wage comparison: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":           "Prevailing wage comparison completed",
+		"postings_compared": annotated,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
 // GenerateRedditPostContent generates Reddit post content for a single job
 func (jc *JobController) GenerateRedditPostContent(w http.ResponseWriter, r *http.Request) {
 	jobID := chi.URLParam(r, "job_id")
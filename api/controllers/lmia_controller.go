@@ -6,6 +6,7 @@ import (
 	"canada-hires/services"
 	"encoding/json"
 	"net/http"
+	"os"
 	"strconv"
 	"time"
 
@@ -326,6 +327,31 @@ func (c *LMIAController) ProcessUnprocessedResources(w http.ResponseWriter, r *h
 	})
 }
 
+// ImportPostalCodeCentroids loads postal code and FSA centroids from POSTAL_CODE_CENTROIDS_PATH so
+// geocoding can run without a network provider
+func (c *LMIAController) ImportPostalCodeCentroids(w http.ResponseWriter, r *http.Request) {
+	filePath := os.Getenv("POSTAL_CODE_CENTROIDS_PATH")
+	if filePath == "" {
+		http.Error(w, "POSTAL_CODE_CENTROIDS_PATH is not configured", http.StatusBadRequest)
+		return
+	}
+
+	log.Info("Manual postal code centroid import requested", "file_path", filePath)
+
+	imported, err := c.lmiaService.GetGeocodingService().ImportPostalCodeCentroids(filePath)
+	if err != nil {
+		log.Error("Failed to import postal code centroids", "error", err)
+		http.Error(w, "Failed to import postal code centroids: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message":            "Postal code centroids imported successfully",
+		"centroids_imported": imported,
+	})
+}
+
 // TriggerGeocoding triggers the batch geocoding process for unprocessed employers
 func (c *LMIAController) TriggerGeocoding(w http.ResponseWriter, r *http.Request) {
 	log.Info("Triggering batch geocoding process")
@@ -17,6 +17,8 @@ type LMIAStatisticsController interface {
 	GetMonthlyTrends(w http.ResponseWriter, r *http.Request)
 	GetTrendsSummary(w http.ResponseWriter, r *http.Request)
 	GetRegionalStats(w http.ResponseWriter, r *http.Request)
+	GetWageComparison(w http.ResponseWriter, r *http.Request)
+	GetRepeatEmployers(w http.ResponseWriter, r *http.Request)
 
 	// Admin endpoints (for manual operations)
 	BackfillHistoricalStatistics(w http.ResponseWriter, r *http.Request)
@@ -246,6 +248,59 @@ func (c *lmiaStatisticsController) GetRegionalStats(w http.ResponseWriter, r *ht
 	json.NewEncoder(w).Encode(regionalStats)
 }
 
+// GetWageComparison returns how offered wages compare to the prevailing median for a given timeframe
+func (c *lmiaStatisticsController) GetWageComparison(w http.ResponseWriter, r *http.Request) {
+	timeframe := r.URL.Query().Get("timeframe")
+	if timeframe == "" {
+		timeframe = "year"
+	}
+
+	now := time.Now()
+	var startDate time.Time
+
+	switch timeframe {
+	case "week":
+		startDate = now.AddDate(0, 0, -7)
+	case "month":
+		startDate = now.AddDate(0, -1, 0)
+	case "quarter":
+		startDate = now.AddDate(0, -3, 0)
+	case "year":
+		startDate = now.AddDate(-1, 0, 0)
+	default:
+		http.Error(w, "Invalid timeframe. Must be: week, month, quarter, or year", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := c.service.GetWageComparisonByTimeframe(startDate, now)
+	if err != nil {
+		log.Error("Failed to get wage comparison statistics", "error", err, "timeframe", timeframe)
+		http.Error(w, "Failed to get wage comparison statistics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":      stats,
+		"timeframe": timeframe,
+	})
+}
+
+// GetRepeatEmployers returns the share of current postings from employers with LMIA approvals and from repeat LMIA users
+func (c *lmiaStatisticsController) GetRepeatEmployers(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.service.GetLMIAMatchStats()
+	if err != nil {
+		log.Error("Failed to get repeat LMIA employer statistics", "error", err)
+		http.Error(w, "Failed to get repeat LMIA employer statistics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data": stats,
+	})
+}
+
 // RunDailyAggregation manually runs the daily aggregation job (admin only)
 func (c *lmiaStatisticsController) RunDailyAggregation(w http.ResponseWriter, r *http.Request) {
 	log.Info("Manually running daily aggregation job")
@@ -1,24 +1,30 @@
 package controllers
 
 import (
+	"canada-hires/models"
 	"canada-hires/services"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/go-chi/chi/v5"
 )
 
 type NonCompliantController struct {
-	service services.NonCompliantService
-	logger  *log.Logger
+	service      services.NonCompliantService
+	matchService services.NonCompliantMatchService
+	logger       *log.Logger
 }
 
-func NewNonCompliantController(service services.NonCompliantService, logger *log.Logger) *NonCompliantController {
+func NewNonCompliantController(service services.NonCompliantService, matchService services.NonCompliantMatchService, logger *log.Logger) *NonCompliantController {
 	return &NonCompliantController{
-		service: service,
-		logger:  logger,
+		service:      service,
+		matchService: matchService,
+		logger:       logger,
 	}
 }
 
@@ -282,7 +288,283 @@ func (c *NonCompliantController) TriggerNonCompliantAddressGeocode(w http.Respon
 	json.NewEncoder(w).Encode(response)
 }
 
-// SetupNonCompliantRoutes sets up all routes for non-compliant employers
+// GetNonCompliantChanges handles GET /api/non-compliant/changes
+func (c *NonCompliantController) GetNonCompliantChanges(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit := 50 // default
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = parsedLimit
+		}
+	}
+
+	offset := 0 // default
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	filters := models.NonCompliantChangeFilters{
+		ChangeType: query.Get("change_type"),
+		FieldName:  query.Get("field"),
+	}
+
+	switch filters.ChangeType {
+	case "", models.NonCompliantChangeListed, models.NonCompliantChangeUpdated,
+		models.NonCompliantChangeDelisted, models.NonCompliantChangeRelisted:
+	default:
+		http.Error(w, "Invalid change_type, expected listed, updated, delisted or relisted", http.StatusBadRequest)
+		return
+	}
+
+	if sinceStr := query.Get("since"); sinceStr != "" {
+		since, err := time.Parse("2006-01-02", sinceStr)
+		if err != nil {
+			http.Error(w, "Invalid since date, expected YYYY-MM-DD", http.StatusBadRequest)
+			return
+		}
+		filters.Since = &since
+	}
+
+	changes, err := c.service.GetNonCompliantChanges(filters, limit, offset)
+	if err != nil {
+		c.logger.Error("Failed to get non-compliant changes", "error", err)
+		http.Error(w, "Failed to retrieve non-compliant changes", http.StatusInternalServerError)
+		return
+	}
+
+	totalCount, err := c.service.GetNonCompliantChangesCount(filters)
+	if err != nil {
+		c.logger.Error("Failed to get non-compliant changes count", "error", err)
+		http.Error(w, "Failed to retrieve changes count", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"changes":     changes,
+		"total_count": totalCount,
+		"limit":       limit,
+		"offset":      offset,
+		"has_more":    offset+limit < totalCount,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetNonCompliantEmployerHistory handles GET /api/non-compliant/employers/{employer_id}/history
+func (c *NonCompliantController) GetNonCompliantEmployerHistory(w http.ResponseWriter, r *http.Request) {
+	employerID := chi.URLParam(r, "employer_id")
+	if employerID == "" {
+		http.Error(w, "Employer ID is required", http.StatusBadRequest)
+		return
+	}
+
+	history, err := c.service.GetNonCompliantEmployerHistory(employerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Employer not found", http.StatusNotFound)
+			return
+		}
+		c.logger.Error("Failed to get non-compliant employer history", "error", err, "employer_id", employerID)
+		http.Error(w, "Failed to retrieve employer history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(history)
+}
+
+// GetStillHiringEmployers handles GET /api/non-compliant/still-hiring
+func (c *NonCompliantController) GetStillHiringEmployers(w http.ResponseWriter, r *http.Request) {
+	limit := 25 // default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	offset := 0 // default
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsedOffset, err := strconv.Atoi(offsetStr); err == nil && parsedOffset >= 0 {
+			offset = parsedOffset
+		}
+	}
+
+	employers, err := c.matchService.GetStillHiringEmployers(limit, offset)
+	if err != nil {
+		c.logger.Error("Failed to get still hiring employers", "error", err)
+		http.Error(w, "Failed to retrieve still hiring employers", http.StatusInternalServerError)
+		return
+	}
+
+	totalCount, err := c.matchService.GetStillHiringCount()
+	if err != nil {
+		c.logger.Error("Failed to get still hiring employers count", "error", err)
+		http.Error(w, "Failed to retrieve still hiring employers count", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"employers":   employers,
+		"total_count": totalCount,
+		"limit":       limit,
+		"offset":      offset,
+		"has_more":    offset+limit < totalCount,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// TriggerStillHiringMatching handles POST /api/admin/non-compliant/still-hiring/match
+func (c *NonCompliantController) TriggerStillHiringMatching(w http.ResponseWriter, r *http.Request) {
+	c.logger.Info("Non-compliant job posting matching triggered via API")
+
+	flagged, err := c.matchService.MatchAllJobPostings()
+	if err != nil {
+		c.logger.Error("Failed to match job postings to non-compliant employers", "error", err)
+		http.Error(w, "Failed to match job postings to non-compliant employers", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message":          "Non-compliant employer matching completed successfully",
+		"postings_flagged": flagged,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPenaltyStats handles GET /api/non-compliant/statistics
+func (c *NonCompliantController) GetPenaltyStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := c.service.GetPenaltyStats()
+	if err != nil {
+		c.logger.Error("Failed to get non-compliant penalty statistics", "error", err)
+		http.Error(w, "Failed to retrieve penalty statistics", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// GetLargestPenalties handles GET /api/non-compliant/statistics/largest-penalties
+func (c *NonCompliantController) GetLargestPenalties(w http.ResponseWriter, r *http.Request) {
+	limit := 25 // default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	employers, err := c.service.GetLargestPenalties(limit)
+	if err != nil {
+		c.logger.Error("Failed to get largest penalties", "error", err)
+		http.Error(w, "Failed to retrieve largest penalties", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"employers": employers,
+		"count":     len(employers),
+		"limit":     limit,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetRepeatOffenders handles GET /api/non-compliant/statistics/repeat-offenders
+func (c *NonCompliantController) GetRepeatOffenders(w http.ResponseWriter, r *http.Request) {
+	limit := 25 // default
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
+			limit = parsedLimit
+		}
+	}
+
+	minListings := 2 // default
+	if minListingsStr := r.URL.Query().Get("min_listings"); minListingsStr != "" {
+		if parsed, err := strconv.Atoi(minListingsStr); err == nil && parsed >= 2 {
+			minListings = parsed
+		}
+	}
+
+	offenders, err := c.service.GetRepeatOffenders(minListings, limit)
+	if err != nil {
+		c.logger.Error("Failed to get repeat offenders", "error", err)
+		http.Error(w, "Failed to retrieve repeat offenders", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"offenders":    offenders,
+		"count":        len(offenders),
+		"min_listings": minListings,
+		"limit":        limit,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPenaltyTrends handles GET /api/non-compliant/statistics/trends
+func (c *NonCompliantController) GetPenaltyTrends(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = "month"
+	}
+	if period != "month" && period != "year" {
+		http.Error(w, "Invalid period, expected month or year", http.StatusBadRequest)
+		return
+	}
+
+	trends, err := c.service.GetPenaltyTrends(period)
+	if err != nil {
+		c.logger.Error("Failed to get penalty trends", "error", err)
+		http.Error(w, "Failed to retrieve penalty trends", http.StatusInternalServerError)
+		return
+	}
+
+	response := map[string]interface{}{
+		"period": period,
+		"trends": trends,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// ImportNonCompliantPage handles POST /api/admin/non-compliant/import
+// The request body is the HTML of a saved copy of the non-compliant employers page.
+func (c *NonCompliantController) ImportNonCompliantPage(w http.ResponseWriter, r *http.Request) {
+	c.logger.Info("Non-compliant page import triggered via API")
+
+	body := http.MaxBytesReader(w, r.Body, 50<<20)
+	defer body.Close()
+
+	job, err := c.service.ImportNonCompliantPage(body)
+	if err != nil {
+		c.logger.Error("Failed to import non-compliant page", "error", err)
+		http.Error(w, "Failed to import non-compliant page", http.StatusBadRequest)
+		return
+	}
+
+	response := map[string]interface{}{
+		"message": "Non-compliant employers page imported successfully",
+		"job":     job,
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetupNonCompliantRoutes sets up the public routes for non-compliant employers. Admin routes are
+// only registered by router.NonCompliantRoutes, behind authentication and RequireAdmin.
 func (c *NonCompliantController) SetupNonCompliantRoutes(r chi.Router) {
 	// Public API routes
 	r.Route("/api/non-compliant", func(r chi.Router) {
@@ -291,13 +573,12 @@ func (c *NonCompliantController) SetupNonCompliantRoutes(r chi.Router) {
 		r.Get("/locations", c.GetNonCompliantLocations)
 		r.Get("/employers/postal-code/{postal_code}", c.GetNonCompliantEmployersByPostalCode)
 		r.Get("/employers/coordinates/{lat}/{lng}", c.GetNonCompliantEmployersByCoordinates)
-	})
-
-	// Admin routes (these should have authentication middleware in production)
-	r.Route("/api/admin/non-compliant", func(r chi.Router) {
-		r.Post("/scrape", c.TriggerNonCompliantScraper)
-		r.Get("/status", c.GetNonCompliantScrapingStatus)
-		r.Post("/geocode", c.TriggerNonCompliantGeocoding)
-		r.Post("/address-geocode", c.TriggerNonCompliantAddressGeocode)
+		r.Get("/employers/{employer_id}/history", c.GetNonCompliantEmployerHistory)
+		r.Get("/changes", c.GetNonCompliantChanges)
+		r.Get("/still-hiring", c.GetStillHiringEmployers)
+		r.Get("/statistics", c.GetPenaltyStats)
+		r.Get("/statistics/largest-penalties", c.GetLargestPenalties)
+		r.Get("/statistics/repeat-offenders", c.GetRepeatOffenders)
+		r.Get("/statistics/trends", c.GetPenaltyTrends)
 	})
 }
\ No newline at end of file
@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+// Room for the multipart boundaries and headers on top of the file size limit
+const multipartOverhead = 64 << 10
+
+type ReportAttachmentController interface {
+	// Public routes, private attachments are only listed for their author and moderators
+	GetAttachments(w http.ResponseWriter, r *http.Request)
+	DownloadAttachment(w http.ResponseWriter, r *http.Request)
+
+	// Protected routes (report author or moderator)
+	UploadAttachment(w http.ResponseWriter, r *http.Request)
+	DeleteAttachment(w http.ResponseWriter, r *http.Request)
+
+	// Moderation routes (moderator or admin required)
+	ApproveAttachment(w http.ResponseWriter, r *http.Request)
+	HideAttachment(w http.ResponseWriter, r *http.Request)
+}
+
+type reportAttachmentController struct {
+	service services.ReportAttachmentService
+}
+
+func NewReportAttachmentController(service services.ReportAttachmentService) ReportAttachmentController {
+	return &reportAttachmentController{service: service}
+}
+
+// GetAttachments lists the attachments of a report the user can see
+func (c *reportAttachmentController) GetAttachments(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	attachments, err := c.service.GetAttachments(reportID, helpers.GetUserFromContext(r.Context()))
+	if err != nil {
+		writeAttachmentError(w, err, "Failed to get attachments", reportID)
+		return
+	}
+	if attachments == nil {
+		attachments = []*models.ReportAttachment{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  attachments,
+		"count": len(attachments),
+	})
+}
+
+// DownloadAttachment streams an attachment. Files are always served as downloads so a browser
+// never renders them in the site's origin.
+func (c *reportAttachmentController) DownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	attachment, content, err := c.service.OpenAttachment(reportID, chi.URLParam(r, "attachment_id"), helpers.GetUserFromContext(r.Context()))
+	if err != nil {
+		writeAttachmentError(w, err, "Failed to get attachment", reportID)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", attachment.FileName))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+	w.Header().Set("ETag", `"`+attachment.ChecksumSHA256+`"`)
+	// Private files must not be kept by shared caches
+	if attachment.Visibility == models.AttachmentPublic {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	} else {
+		w.Header().Set("Cache-Control", "private, no-store")
+	}
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Warn("Failed to send attachment", "error", err, "attachment_id", attachment.ID)
+	}
+}
+
+// UploadAttachment attaches the file in the "file" field of a multipart form to a report
+func (c *reportAttachmentController) UploadAttachment(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	maxBytes := c.service.MaxBytes()
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+multipartOverhead)
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, fmt.Sprintf("File is larger than %d bytes", maxBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "A file is required in the \"file\" field", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	attachment, err := c.service.UploadAttachment(&services.UploadAttachmentRequest{
+		ReportID: reportID,
+		Uploader: user,
+		FileName: header.Filename,
+		Data:     data,
+	})
+	if err != nil {
+		writeAttachmentError(w, err, "Failed to upload attachment", reportID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// DeleteAttachment removes an attachment and its file
+func (c *reportAttachmentController) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.service.DeleteAttachment(reportID, chi.URLParam(r, "attachment_id"), user); err != nil {
+		writeAttachmentError(w, err, "Failed to delete attachment", reportID)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ApproveAttachment makes an attachment visible to everyone who can see the report
+func (c *reportAttachmentController) ApproveAttachment(w http.ResponseWriter, r *http.Request) {
+	c.setVisibility(w, r, models.AttachmentPublic)
+}
+
+// HideAttachment makes an attachment private again
+func (c *reportAttachmentController) HideAttachment(w http.ResponseWriter, r *http.Request) {
+	c.setVisibility(w, r, models.AttachmentPrivate)
+}
+
+func (c *reportAttachmentController) setVisibility(w http.ResponseWriter, r *http.Request, visibility string) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	attachment, err := c.service.SetVisibility(reportID, chi.URLParam(r, "attachment_id"), visibility, user.ID)
+	if err != nil {
+		writeAttachmentError(w, err, "Failed to update attachment", reportID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(attachment)
+}
+
+// writeAttachmentError maps attachment service errors to responses
+func writeAttachmentError(w http.ResponseWriter, err error, message, reportID string) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.Error(w, "Report or attachment not found", http.StatusNotFound)
+	case errors.Is(err, services.ErrAttachmentForbidden):
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errors.Is(err, services.ErrAttachmentTooLarge):
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+	case errors.Is(err, services.ErrUnsupportedAttachment):
+		http.Error(w, "Only JPEG, PNG and PDF files are accepted", http.StatusUnsupportedMediaType)
+	case errors.Is(err, services.ErrTooManyAttachments), errors.Is(err, services.ErrDuplicateAttachment):
+		http.Error(w, err.Error(), http.StatusConflict)
+	default:
+		log.Error(message, "error", err, "report_id", reportID)
+		http.Error(w, message, http.StatusInternalServerError)
+	}
+}
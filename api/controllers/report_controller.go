@@ -3,8 +3,11 @@ package controllers
 import (
 	"canada-hires/dto"
 	"canada-hires/helpers"
+	"canada-hires/models"
 	"canada-hires/services"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -24,6 +27,19 @@ type ReportController interface {
 	GetUserReports(w http.ResponseWriter, r *http.Request)
 	UpdateReport(w http.ResponseWriter, r *http.Request)
 	DeleteReport(w http.ResponseWriter, r *http.Request)
+
+	// Moderation routes (moderator or admin required)
+	GetModerationQueue(w http.ResponseWriter, r *http.Request)
+	GetModerationSummary(w http.ResponseWriter, r *http.Request)
+	PublishReport(w http.ResponseWriter, r *http.Request)
+	RejectReport(w http.ResponseWriter, r *http.Request)
+	FlagReport(w http.ResponseWriter, r *http.Request)
+	BulkModerateReports(w http.ResponseWriter, r *http.Request)
+	GetReportHistory(w http.ResponseWriter, r *http.Request)
+
+	// Admin routes
+	GetDeletedReports(w http.ResponseWriter, r *http.Request)
+	RestoreReport(w http.ResponseWriter, r *http.Request)
 }
 
 type reportController struct {
@@ -59,18 +75,29 @@ func (c *reportController) CreateReport(w http.ResponseWriter, r *http.Request)
 		TFWRatio:        req.TFWRatio,
 		AdditionalNotes: req.AdditionalNotes,
 		IPAddress:       &clientIP,
+		AuthorTier:      user.VerificationTier,
+		AuthorRole:      user.Role,
 	}
 
-	report, err := c.service.CreateReport(serviceReq)
+	report, merged, err := c.service.CreateReport(serviceReq)
 	if err != nil {
+		if errors.Is(err, services.ErrDuplicateReport) {
+			http.Error(w, "You already reported this business recently, edit your earlier report instead", http.StatusConflict)
+			return
+		}
 		log.Error("Failed to create report", "error", err, "user_id", user.ID)
 		http.Error(w, "Failed to create report: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// A report merged into the user's earlier report on the same business isn't a new resource
 	response := dto.ToReportResponse(report)
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	if merged {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
 	json.NewEncoder(w).Encode(response)
 }
 
@@ -81,7 +108,8 @@ func (c *reportController) GetReportByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	report, err := c.service.GetReportByID(id)
+	// Reports that aren't published yet are only shown to their author and to moderators
+	report, err := c.service.GetVisibleReport(id, helpers.GetUserFromContext(r.Context()))
 	if err != nil {
 		log.Error("Failed to get report", "error", err, "report_id", id)
 		http.Error(w, "Report not found", http.StatusNotFound)
@@ -230,7 +258,8 @@ func (c *reportController) UpdateReport(w http.ResponseWriter, r *http.Request)
 	existingReport.ConfidenceLevel = req.ConfidenceLevel
 	existingReport.AdditionalNotes = req.AdditionalNotes
 
-	err = c.service.UpdateReport(existingReport)
+	clientIP := helpers.GetClientIP(r)
+	err = c.service.UpdateReport(existingReport, user, &clientIP)
 	if err != nil {
 		log.Error("Failed to update report", "error", err, "report_id", id, "user_id", user.ID)
 		http.Error(w, "Failed to update report: "+err.Error(), http.StatusBadRequest)
@@ -255,9 +284,15 @@ func (c *reportController) DeleteReport(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err := c.service.DeleteReport(id, user.ID, user.IsAdmin())
+	// Deleted reports keep their attachments, in case an admin restores them
+	clientIP := helpers.GetClientIP(r)
+	err := c.service.DeleteReport(id, user.ID, user.IsAdmin(), &clientIP)
 	if err != nil {
 		log.Error("Failed to delete report", "error", err, "report_id", id, "user_id", user.ID)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Report not found", http.StatusNotFound)
+			return
+		}
 		if strings.Contains(err.Error(), "unauthorized") {
 			http.Error(w, err.Error(), http.StatusForbidden)
 			return
@@ -307,6 +342,272 @@ func (c *reportController) GetReportsGrouped(w http.ResponseWriter, r *http.Requ
 	})
 }
 
+// GetModerationQueue returns the reports waiting for moderation, oldest first. The "status" query
+// parameter takes a comma separated list of statuses, pending and flagged by default.
+func (c *reportController) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	var statuses []string
+	if statusParam := r.URL.Query().Get("status"); statusParam != "" {
+		for _, status := range strings.Split(statusParam, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+
+	reports, total, err := c.service.GetModerationQueue(statuses, limit, offset)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidModeration) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("Failed to get moderation queue", "error", err)
+		http.Error(w, "Failed to get moderation queue", http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]*dto.ReportModerationResponse, len(reports))
+	for i, report := range reports {
+		data[i] = dto.ToReportModerationResponse(report)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   data,
+		"count":  len(data),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetModerationSummary returns the number of reports in each status and the rejection reasons
+// moderators can choose from
+func (c *reportController) GetModerationSummary(w http.ResponseWriter, r *http.Request) {
+	counts, err := c.service.GetModerationSummary()
+	if err != nil {
+		log.Error("Failed to get moderation summary", "error", err)
+		http.Error(w, "Failed to get moderation summary", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status_counts":     counts,
+		"rejection_reasons": models.RejectionReasons,
+	})
+}
+
+// PublishReport makes a report public
+func (c *reportController) PublishReport(w http.ResponseWriter, r *http.Request) {
+	c.moderateReport(w, r, "publish")
+}
+
+// RejectReport rejects a report, the body must give a rejection_reason
+func (c *reportController) RejectReport(w http.ResponseWriter, r *http.Request) {
+	c.moderateReport(w, r, "reject")
+}
+
+// FlagReport hides a report until a moderator looks at it again
+func (c *reportController) FlagReport(w http.ResponseWriter, r *http.Request) {
+	c.moderateReport(w, r, "flag")
+}
+
+func (c *reportController) moderateReport(w http.ResponseWriter, r *http.Request, action string) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Report ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	// The body is optional when publishing or flagging
+	var req dto.ReportModerationDecision
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if _, err := c.service.GetReportByID(id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Report not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get report", "error", err, "report_id", id)
+		http.Error(w, "Failed to moderate report", http.StatusInternalServerError)
+		return
+	}
+
+	clientIP := helpers.GetClientIP(r)
+	_, err := c.service.ModerateReports(&services.ModerateReportsRequest{
+		ReportIDs:       []string{id},
+		Action:          action,
+		ModeratorID:     user.ID,
+		RejectionReason: req.RejectionReason,
+		Notes:           req.Notes,
+		IPAddress:       &clientIP,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidModeration) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("Failed to moderate report", "error", err, "report_id", id, "action", action)
+		http.Error(w, "Failed to moderate report", http.StatusInternalServerError)
+		return
+	}
+
+	report, err := c.service.GetReportByID(id)
+	if err != nil {
+		log.Error("Failed to get moderated report", "error", err, "report_id", id)
+		http.Error(w, "Failed to get moderated report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.ToReportModerationResponse(report))
+}
+
+// BulkModerateReports applies one action to several reports
+func (c *reportController) BulkModerateReports(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req dto.ModerateReportsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	clientIP := helpers.GetClientIP(r)
+	updated, err := c.service.ModerateReports(&services.ModerateReportsRequest{
+		ReportIDs:       req.ReportIDs,
+		Action:          req.Action,
+		ModeratorID:     user.ID,
+		RejectionReason: req.RejectionReason,
+		Notes:           req.Notes,
+		IPAddress:       &clientIP,
+	})
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidModeration) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		log.Error("Failed to moderate reports", "error", err, "action", req.Action, "user_id", user.ID)
+		http.Error(w, "Failed to moderate reports", http.StatusInternalServerError)
+		return
+	}
+
+	if updated == nil {
+		updated = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"action":      req.Action,
+		"requested":   len(req.ReportIDs),
+		"updated":     len(updated),
+		"updated_ids": updated,
+	})
+}
+
+// GetReportHistory returns every recorded change to a report, oldest first. Deleted reports keep
+// their history.
+func (c *reportController) GetReportHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Report ID is required", http.StatusBadRequest)
+		return
+	}
+
+	revisions, err := c.service.GetReportHistory(id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Report not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get report history", "error", err, "report_id", id)
+		http.Error(w, "Failed to get report history", http.StatusInternalServerError)
+		return
+	}
+	if revisions == nil {
+		revisions = []*models.ReportRevision{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  revisions,
+		"count": len(revisions),
+	})
+}
+
+// GetDeletedReports returns deleted reports, most recently deleted first
+func (c *reportController) GetDeletedReports(w http.ResponseWriter, r *http.Request) {
+	limit, offset := getPaginationParams(r)
+
+	reports, total, err := c.service.GetDeletedReports(limit, offset)
+	if err != nil {
+		log.Error("Failed to get deleted reports", "error", err)
+		http.Error(w, "Failed to get deleted reports", http.StatusInternalServerError)
+		return
+	}
+
+	data := make([]*dto.ReportModerationResponse, len(reports))
+	for i, report := range reports {
+		data[i] = dto.ToReportModerationResponse(report)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":   data,
+		"count":  len(data),
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// RestoreReport brings back a deleted report
+func (c *reportController) RestoreReport(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if id == "" {
+		http.Error(w, "Report ID is required", http.StatusBadRequest)
+		return
+	}
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	clientIP := helpers.GetClientIP(r)
+	report, err := c.service.RestoreReport(id, user.ID, &clientIP)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Deleted report not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to restore report", "error", err, "report_id", id, "user_id", user.ID)
+		http.Error(w, "Failed to restore report", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(dto.ToReportModerationResponse(report))
+}
+
 // Helper functions
 func getPaginationParams(r *http.Request) (limit, offset int) {
 	limit = 50 // default
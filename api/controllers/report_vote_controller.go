@@ -0,0 +1,155 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type ReportVoteController interface {
+	// Protected routes (auth required)
+	CastVote(w http.ResponseWriter, r *http.Request)
+	GetMyVote(w http.ResponseWriter, r *http.Request)
+	WithdrawVote(w http.ResponseWriter, r *http.Request)
+
+	// Moderation routes (moderator or admin required)
+	GetReportVotes(w http.ResponseWriter, r *http.Request)
+}
+
+type reportVoteController struct {
+	service services.ReportVoteService
+}
+
+func NewReportVoteController(service services.ReportVoteService) ReportVoteController {
+	return &reportVoteController{service: service}
+}
+
+// castVoteRequest is the body of a vote
+type castVoteRequest struct {
+	VoteType   string  `json:"vote_type"`   // corroborate or flag
+	FlagReason *string `json:"flag_reason"` // Required to flag: inaccurate, personal_information or spam
+	Comment    *string `json:"comment"`
+}
+
+// CastVote corroborates or flags a report, replacing the user's earlier vote on it
+func (c *reportVoteController) CastVote(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req castVoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := c.service.CastVote(&services.CastVoteRequest{
+		ReportID:   reportID,
+		Voter:      user,
+		VoteType:   req.VoteType,
+		FlagReason: req.FlagReason,
+		Comment:    req.Comment,
+		IPAddress:  helpers.GetClientIP(r),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			http.Error(w, "Report not found", http.StatusNotFound)
+		case errors.Is(err, services.ErrOwnReport):
+			http.Error(w, err.Error(), http.StatusForbidden)
+		case errors.Is(err, services.ErrInvalidVote):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			log.Error("Failed to vote on report", "error", err, "report_id", reportID, "user_id", user.ID)
+			http.Error(w, "Failed to vote on report", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetMyVote returns the user's vote on a report
+func (c *reportVoteController) GetMyVote(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	vote, err := c.service.GetUserVote(reportID, user)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Vote not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get report vote", "error", err, "report_id", reportID, "user_id", user.ID)
+		http.Error(w, "Failed to get report vote", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(vote)
+}
+
+// WithdrawVote removes the user's vote on a report
+func (c *reportVoteController) WithdrawVote(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := c.service.WithdrawVote(reportID, user); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Vote not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to withdraw report vote", "error", err, "report_id", reportID, "user_id", user.ID)
+		http.Error(w, "Failed to withdraw report vote", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetReportVotes returns every vote on a report, including the ones that weren't counted
+func (c *reportVoteController) GetReportVotes(w http.ResponseWriter, r *http.Request) {
+	reportID := chi.URLParam(r, "id")
+
+	votes, err := c.service.GetReportVotes(reportID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "Report not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get report votes", "error", err, "report_id", reportID)
+		http.Error(w, "Failed to get report votes", http.StatusInternalServerError)
+		return
+	}
+	if votes == nil {
+		votes = []*models.ReportVote{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":  votes,
+		"count": len(votes),
+	})
+}
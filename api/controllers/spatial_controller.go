@@ -0,0 +1,269 @@
+package controllers
+
+import (
+	"canada-hires/models"
+	"canada-hires/services"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Limits of spatial queries
+const (
+	defaultSpatialRadiusKm = 10
+	maxSpatialRadiusKm     = 100
+	defaultSpatialLimit    = 100
+	maxSpatialLimit        = 500
+)
+
+type SpatialController interface {
+	// Public endpoints
+	Near(w http.ResponseWriter, r *http.Request)
+	Within(w http.ResponseWriter, r *http.Request)
+	Clusters(w http.ResponseWriter, r *http.Request)
+
+	// Admin endpoints
+	GeocodeLocations(w http.ResponseWriter, r *http.Request)
+}
+
+type spatialController struct {
+	service services.SpatialService
+}
+
+func NewSpatialController(service services.SpatialService) SpatialController {
+	return &spatialController{service: service}
+}
+
+// Near returns the records within a radius of a point, nearest first. Query parameters:
+//   - lat, lng: the point
+//   - radius: the radius in kilometres, 10 by default
+//   - datasets: a comma separated list of datasets, all by default
+//   - limit: the maximum number of records of each dataset
+func (c *spatialController) Near(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	latitude, err := parseCoordinate(query.Get("lat"), 90)
+	if err != nil {
+		http.Error(w, "Invalid lat parameter", http.StatusBadRequest)
+		return
+	}
+	longitude, err := parseCoordinate(query.Get("lng"), 180)
+	if err != nil {
+		http.Error(w, "Invalid lng parameter", http.StatusBadRequest)
+		return
+	}
+
+	radiusKm := float64(defaultSpatialRadiusKm)
+	if radiusStr := query.Get("radius"); radiusStr != "" {
+		radiusKm, err = strconv.ParseFloat(radiusStr, 64)
+		if err != nil || radiusKm <= 0 || radiusKm > maxSpatialRadiusKm {
+			http.Error(w, fmt.Sprintf("Invalid radius (expected kilometres between 0 and %d)", maxSpatialRadiusKm), http.StatusBadRequest)
+			return
+		}
+	}
+
+	datasets, limit, ok := parseSpatialOptions(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := c.service.Near(latitude, longitude, radiusKm*1000, datasets, limit)
+	if err != nil {
+		log.Error("Failed to search nearby records", "error", err, "lat", latitude, "lng", longitude, "radius_km", radiusKm)
+		http.Error(w, "Failed to search nearby records", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Within returns the records inside a map viewport given as bbox=minLng,minLat,maxLng,maxLat,
+// nearest to its center first. Takes the same datasets and limit parameters as Near.
+func (c *spatialController) Within(w http.ResponseWriter, r *http.Request) {
+	bbox, err := parseBoundingBox(r.URL.Query().Get("bbox"))
+	if err != nil {
+		http.Error(w, "Invalid bbox parameter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	datasets, limit, ok := parseSpatialOptions(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := c.service.Within(*bbox, datasets, limit)
+	if err != nil {
+		log.Error("Failed to search records in bounding box", "error", err, "bbox", bbox)
+		http.Error(w, "Failed to search records in bounding box", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// Clusters returns the points of a map layer inside a viewport grouped into clusters for a zoom
+// level, as a GeoJSON FeatureCollection. Query parameters:
+//   - layer: lmia, non_compliant or job_postings
+//   - zoom: the web map zoom level, 0 to 20
+//   - bbox: the viewport as minLng,minLat,maxLng,maxLat
+//   - year: LMIA approvals or non-compliance decisions of a single year
+//   - quarter: LMIA approvals of a single quarter, e.g. Q1
+//   - days: job postings posted in the last number of days
+func (c *spatialController) Clusters(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	layer := query.Get("layer")
+	if !slices.Contains(models.MapLayers, layer) {
+		http.Error(w, "Invalid layer (expected one of "+strings.Join(models.MapLayers, ", ")+")", http.StatusBadRequest)
+		return
+	}
+
+	zoom, err := strconv.Atoi(query.Get("zoom"))
+	if err != nil || zoom < 0 || zoom > services.MaxMapZoom {
+		http.Error(w, fmt.Sprintf("Invalid zoom (expected 0 to %d)", services.MaxMapZoom), http.StatusBadRequest)
+		return
+	}
+
+	bbox, err := parseBoundingBox(query.Get("bbox"))
+	if err != nil {
+		http.Error(w, "Invalid bbox parameter: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	clusterQuery := models.MapClusterQuery{
+		Layer:   layer,
+		Zoom:    zoom,
+		BBox:    *bbox,
+		Quarter: query.Get("quarter"),
+	}
+
+	if yearStr := query.Get("year"); yearStr != "" {
+		clusterQuery.Year, err = strconv.Atoi(yearStr)
+		if err != nil || clusterQuery.Year < 2000 || clusterQuery.Year > time.Now().Year() {
+			http.Error(w, "Invalid year parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	if daysStr := query.Get("days"); daysStr != "" {
+		clusterQuery.Days, err = strconv.Atoi(daysStr)
+		if err != nil || clusterQuery.Days <= 0 {
+			http.Error(w, "Invalid days parameter", http.StatusBadRequest)
+			return
+		}
+	}
+
+	collection, err := c.service.ClusterMapLayer(clusterQuery)
+	if err != nil {
+		log.Error("Failed to cluster map layer", "error", err, "layer", layer, "zoom", zoom, "bbox", bbox)
+		http.Error(w, "Failed to cluster map layer", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/geo+json")
+	json.NewEncoder(w).Encode(collection)
+}
+
+// GeocodeLocations geocodes the job posting locations and report addresses that can't be placed
+// on the map yet, in the background
+func (c *spatialController) GeocodeLocations(w http.ResponseWriter, r *http.Request) {
+	go func() {
+		if _, err := c.service.GeocodeJobPostings(); err != nil {
+			log.Error("Job posting geocoding failed", "error", err)
+		}
+		if _, err := c.service.GeocodeReports(); err != nil {
+			log.Error("Report geocoding failed", "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Location geocoding started in background",
+	})
+}
+
+// parseSpatialOptions reads the datasets and limit parameters, writing a 400 response when they
+// are invalid
+func parseSpatialOptions(w http.ResponseWriter, r *http.Request) (datasets []string, limit int, ok bool) {
+	query := r.URL.Query()
+
+	datasets = models.SpatialDatasets
+	if datasetsStr := query.Get("datasets"); datasetsStr != "" {
+		datasets = nil
+		for _, dataset := range strings.Split(datasetsStr, ",") {
+			dataset = strings.TrimSpace(dataset)
+			if !slices.Contains(models.SpatialDatasets, dataset) {
+				http.Error(w, "Invalid dataset (expected one of "+strings.Join(models.SpatialDatasets, ", ")+")", http.StatusBadRequest)
+				return nil, 0, false
+			}
+			datasets = append(datasets, dataset)
+		}
+	}
+
+	limit = defaultSpatialLimit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsedLimit, err := strconv.Atoi(limitStr)
+		if err != nil || parsedLimit <= 0 || parsedLimit > maxSpatialLimit {
+			http.Error(w, fmt.Sprintf("Invalid limit (expected 1 to %d)", maxSpatialLimit), http.StatusBadRequest)
+			return nil, 0, false
+		}
+		limit = parsedLimit
+	}
+
+	return datasets, limit, true
+}
+
+// parseCoordinate parses a latitude or longitude no larger than max degrees either way
+func parseCoordinate(value string, max float64) (float64, error) {
+	coordinate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0, err
+	}
+	if coordinate < -max || coordinate > max {
+		return 0, fmt.Errorf("coordinate out of range: %f", coordinate)
+	}
+	return coordinate, nil
+}
+
+// parseBoundingBox parses a bounding box in the minLng,minLat,maxLng,maxLat order used by GeoJSON
+// and most map libraries
+func parseBoundingBox(value string) (*models.BoundingBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("expected minLng,minLat,maxLng,maxLat")
+	}
+
+	var corners [4]float64
+	for i, part := range parts {
+		max := float64(180)
+		if i%2 == 1 {
+			max = 90
+		}
+		corner, err := parseCoordinate(part, max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinate %q", part)
+		}
+		corners[i] = corner
+	}
+
+	bbox := &models.BoundingBox{
+		MinLongitude: corners[0],
+		MinLatitude:  corners[1],
+		MaxLongitude: corners[2],
+		MaxLatitude:  corners[3],
+	}
+	if bbox.MinLatitude >= bbox.MaxLatitude || bbox.MinLongitude >= bbox.MaxLongitude {
+		return nil, fmt.Errorf("minimum corner must be south west of the maximum corner")
+	}
+
+	return bbox, nil
+}
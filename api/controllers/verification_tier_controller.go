@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"canada-hires/helpers"
+	"canada-hires/models"
+	"canada-hires/services"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/charmbracelet/log"
+	"github.com/go-chi/chi/v5"
+)
+
+type VerificationTierController interface {
+	// User endpoints
+	GetMyTier(w http.ResponseWriter, r *http.Request)
+
+	// Admin endpoints
+	GetUserTier(w http.ResponseWriter, r *http.Request)
+	EvaluateUser(w http.ResponseWriter, r *http.Request)
+	EvaluateAll(w http.ResponseWriter, r *http.Request)
+}
+
+type verificationTierController struct {
+	service services.VerificationTierService
+}
+
+func NewVerificationTierController(service services.VerificationTierService) VerificationTierController {
+	return &verificationTierController{service: service}
+}
+
+// tierStatusResponse is the tier a user qualifies for with their past tier changes
+type tierStatusResponse struct {
+	*models.TierEvaluation
+	History []*models.UserTierChange `json:"history"`
+}
+
+// GetMyTier returns the current user's tier, what the next tier still needs and their tier changes
+func (c *verificationTierController) GetMyTier(w http.ResponseWriter, r *http.Request) {
+	user := helpers.GetUserFromContext(r.Context())
+	if user == nil {
+		http.Error(w, "User not found", http.StatusUnauthorized)
+		return
+	}
+
+	c.writeTierStatus(w, user.ID)
+}
+
+// GetUserTier returns the tier status of any user
+func (c *verificationTierController) GetUserTier(w http.ResponseWriter, r *http.Request) {
+	c.writeTierStatus(w, chi.URLParam(r, "user_id"))
+}
+
+// EvaluateUser computes and applies the tier of one user right away
+func (c *verificationTierController) EvaluateUser(w http.ResponseWriter, r *http.Request) {
+	evaluation, err := c.service.EvaluateUser(chi.URLParam(r, "user_id"))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to evaluate verification tier", "error", err)
+		http.Error(w, "Failed to evaluate verification tier", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(evaluation)
+}
+
+// EvaluateAll computes and applies the tier of every user in the background, as the scheduled
+// evaluation does
+func (c *verificationTierController) EvaluateAll(w http.ResponseWriter, r *http.Request) {
+	go func() {
+		if _, err := c.service.EvaluateAll(); err != nil {
+			log.Error("Verification tier evaluation failed", "error", err)
+		}
+	}()
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"message": "Verification tier evaluation started in background",
+	})
+}
+
+func (c *verificationTierController) writeTierStatus(w http.ResponseWriter, userID string) {
+	evaluation, err := c.service.GetTierStatus(userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		log.Error("Failed to get verification tier", "error", err)
+		http.Error(w, "Failed to get verification tier", http.StatusInternalServerError)
+		return
+	}
+
+	history, err := c.service.GetTierHistory(userID)
+	if err != nil {
+		log.Error("Failed to get verification tier history", "error", err)
+		http.Error(w, "Failed to get verification tier history", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tierStatusResponse{TierEvaluation: evaluation, History: history})
+}
@@ -24,16 +24,51 @@ type UpdateReportRequest struct {
 }
 
 type ReportResponse struct {
-	ID              string    `json:"id"`
-	UserID          string    `json:"user_id"`
-	BusinessName    string    `json:"business_name"`
-	BusinessAddress string    `json:"business_address"`
-	ReportSource    string    `json:"report_source"`
-	ConfidenceLevel *int      `json:"confidence_level"` // Deprecated: use TFWRatio
-	TFWRatio        *string   `json:"tfw_ratio"`
-	AdditionalNotes *string   `json:"additional_notes"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	BusinessName    string     `json:"business_name"`
+	BusinessAddress string     `json:"business_address"`
+	ReportSource    string     `json:"report_source"`
+	ConfidenceLevel *int       `json:"confidence_level"` // Deprecated: use TFWRatio
+	TFWRatio        *string    `json:"tfw_ratio"`
+	AdditionalNotes *string    `json:"additional_notes"`
+	Status          string     `json:"status"`
+	RejectionReason *string    `json:"rejection_reason,omitempty"`
+	PublishedAt     *time.Time `json:"published_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	CorroborationCount int `json:"corroboration_count"`
+
+	// Published response of the verified business
+	BusinessReply *models.PublicBusinessReply `json:"business_reply,omitempty"`
+}
+
+// ReportModerationResponse adds the moderation details shown to moderators
+type ReportModerationResponse struct {
+	*ReportResponse
+	ModeratedBy     *string    `json:"moderated_by"`
+	ModeratedAt     *time.Time `json:"moderated_at"`
+	ModerationNotes *string    `json:"moderation_notes"`
+	FlagCount       int        `json:"flag_count"`
+
+	// Set on deleted reports
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *string    `json:"deleted_by,omitempty"`
+}
+
+// ModerateReportsRequest publishes, rejects or flags one or more reports
+type ModerateReportsRequest struct {
+	ReportIDs       []string `json:"report_ids" validate:"required,min=1,max=100"`
+	Action          string   `json:"action" validate:"required,oneof=publish reject flag"`
+	RejectionReason *string  `json:"rejection_reason"` // Required to reject
+	Notes           *string  `json:"notes" validate:"omitempty,max=1000"`
+}
+
+// ReportModerationDecision is the body of single report moderation requests
+type ReportModerationDecision struct {
+	RejectionReason *string `json:"rejection_reason"` // Required to reject
+	Notes           *string `json:"notes" validate:"omitempty,max=1000"`
 }
 
 type ReportListResponse struct {
@@ -59,8 +94,14 @@ func ToReportResponse(report *models.Report) *ReportResponse {
 		ConfidenceLevel: report.ConfidenceLevel,
 		TFWRatio:        report.TFWRatio,
 		AdditionalNotes: report.AdditionalNotes,
+		Status:          report.Status,
+		RejectionReason: report.RejectionReason,
+		PublishedAt:     report.PublishedAt,
 		CreatedAt:       report.CreatedAt,
 		UpdatedAt:       report.UpdatedAt,
+
+		CorroborationCount: report.CorroborationCount,
+		BusinessReply:      report.BusinessReply,
 	}
 }
 
@@ -78,4 +119,17 @@ func ToReportListResponse(reports []*models.Report, limit, offset int) *ReportLi
 			Offset: offset,
 		},
 	}
-}
\ No newline at end of file
+}
+
+// ToReportModerationResponse converts a report to the DTO shown to moderators
+func ToReportModerationResponse(report *models.Report) *ReportModerationResponse {
+	return &ReportModerationResponse{
+		ReportResponse:  ToReportResponse(report),
+		ModeratedBy:     report.ModeratedBy,
+		ModeratedAt:     report.ModeratedAt,
+		ModerationNotes: report.ModerationNotes,
+		FlagCount:       report.FlagCount,
+		DeletedAt:       report.DeletedAt,
+		DeletedBy:       report.DeletedBy,
+	}
+}
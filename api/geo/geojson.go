@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type geoJSONFeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   *struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	} `json:"geometry"`
+}
+
+// ReadBoundaries reads the boundaries of a GeoJSON file (.geojson or .json) or of an ESRI
+// shapefile (.shp, with its .dbf next to it). Coordinates must be longitudes and latitudes.
+func ReadBoundaries(path string) ([]*Boundary, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".shp":
+		return ReadShapefile(path)
+	case ".geojson", ".json":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open boundary file: %w", err)
+		}
+		defer file.Close()
+		return ReadGeoJSON(file)
+	default:
+		return nil, fmt.Errorf("unsupported boundary file %s (expected .geojson, .json or .shp)", filepath.Base(path))
+	}
+}
+
+// ReadGeoJSON reads the Polygon and MultiPolygon features of a GeoJSON FeatureCollection. Other
+// geometries are skipped.
+func ReadGeoJSON(r io.Reader) ([]*Boundary, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var collection geoJSONFeatureCollection
+	if err := decoder.Decode(&collection); err != nil {
+		return nil, fmt.Errorf("failed to decode GeoJSON: %w", err)
+	}
+	if collection.Type != "FeatureCollection" {
+		return nil, fmt.Errorf("expected a GeoJSON FeatureCollection, got %q", collection.Type)
+	}
+
+	var boundaries []*Boundary
+	for i, feature := range collection.Features {
+		if feature.Geometry == nil {
+			continue
+		}
+
+		var shape MultiPolygon
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var coordinates [][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil {
+				return nil, fmt.Errorf("invalid polygon in feature %d: %w", i, err)
+			}
+			shape = MultiPolygon{toPolygon(coordinates)}
+		case "MultiPolygon":
+			var coordinates [][][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &coordinates); err != nil {
+				return nil, fmt.Errorf("invalid multipolygon in feature %d: %w", i, err)
+			}
+			for _, polygon := range coordinates {
+				shape = append(shape, toPolygon(polygon))
+			}
+		default:
+			continue
+		}
+
+		if !shape.Valid() {
+			return nil, fmt.Errorf("feature %d is not in longitude and latitude, reproject the file to WGS 84 (EPSG:4326) first", i)
+		}
+
+		properties := make(map[string]string, len(feature.Properties))
+		for key, value := range feature.Properties {
+			if value != nil {
+				properties[key] = strings.TrimSpace(fmt.Sprint(value))
+			}
+		}
+
+		boundaries = append(boundaries, &Boundary{Properties: properties, Shape: shape})
+	}
+
+	return boundaries, nil
+}
+
+// toPolygon drops any elevation from the positions
+func toPolygon(coordinates [][][]float64) Polygon {
+	polygon := make(Polygon, 0, len(coordinates))
+	for _, line := range coordinates {
+		ring := make(Ring, 0, len(line))
+		for _, position := range line {
+			if len(position) >= 2 {
+				ring = append(ring, Position{position[0], position[1]})
+			}
+		}
+		polygon = append(polygon, ring)
+	}
+	return polygon
+}
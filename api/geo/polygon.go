@@ -0,0 +1,141 @@
+// Package geo reads boundary files, such as federal electoral districts, and finds the boundary a
+// point falls in. Positions are longitude, latitude pairs in degrees, as in GeoJSON.
+package geo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Position is a longitude, latitude pair
+type Position [2]float64
+
+// Ring is a closed line of positions
+type Ring []Position
+
+// Polygon is an outer ring followed by the rings of its holes
+type Polygon []Ring
+
+// MultiPolygon is a shape made of one or more polygons, e.g. a district with islands
+type MultiPolygon []Polygon
+
+// Bounds is the bounding box of a shape
+type Bounds struct {
+	MinLongitude float64 `json:"min_longitude"`
+	MinLatitude  float64 `json:"min_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+}
+
+// Contains reports whether a point is inside the box
+func (b Bounds) Contains(latitude, longitude float64) bool {
+	return latitude >= b.MinLatitude && latitude <= b.MaxLatitude &&
+		longitude >= b.MinLongitude && longitude <= b.MaxLongitude
+}
+
+// Bounds returns the bounding box of the shape
+func (m MultiPolygon) Bounds() Bounds {
+	b := Bounds{MinLongitude: 180, MinLatitude: 90, MaxLongitude: -180, MaxLatitude: -90}
+	for _, polygon := range m {
+		for _, ring := range polygon {
+			for _, p := range ring {
+				b.MinLongitude = min(b.MinLongitude, p[0])
+				b.MaxLongitude = max(b.MaxLongitude, p[0])
+				b.MinLatitude = min(b.MinLatitude, p[1])
+				b.MaxLatitude = max(b.MaxLatitude, p[1])
+			}
+		}
+	}
+	return b
+}
+
+// Contains reports whether a point is inside one of the polygons and outside its holes
+func (m MultiPolygon) Contains(latitude, longitude float64) bool {
+	for _, polygon := range m {
+		if len(polygon) == 0 || !polygon[0].contains(latitude, longitude) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if hole.contains(latitude, longitude) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether every position is a longitude and latitude in degrees. Boundary files in a
+// projected coordinate system, e.g. Statistics Canada Lambert, have coordinates in metres.
+func (m MultiPolygon) Valid() bool {
+	if len(m) == 0 {
+		return false
+	}
+	b := m.Bounds()
+	return b.MinLongitude >= -180 && b.MaxLongitude <= 180 && b.MinLatitude >= -90 && b.MaxLatitude <= 90
+}
+
+// contains tests a point against the ring by casting a ray east and counting the edges it crosses
+func (r Ring) contains(latitude, longitude float64) bool {
+	inside := false
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		a, b := r[i], r[j]
+		if (a[1] > latitude) != (b[1] > latitude) &&
+			longitude < (b[0]-a[0])*(latitude-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// signedArea is positive for counter-clockwise rings and negative for clockwise ones
+func (r Ring) signedArea() float64 {
+	area := 0.0
+	for i, j := 0, len(r)-1; i < len(r); j, i = i, i+1 {
+		area += r[j][0]*r[i][1] - r[i][0]*r[j][1]
+	}
+	return area / 2
+}
+
+// Value stores the shape as GeoJSON MultiPolygon coordinates
+func (m MultiPolygon) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *MultiPolygon) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*m = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return fmt.Errorf("cannot scan %T into a MultiPolygon", value)
+	}
+}
+
+// Boundary is a shape read from a boundary file with its attributes
+type Boundary struct {
+	Properties map[string]string
+	Shape      MultiPolygon
+}
+
+// Property returns the first of the named attributes that is set, ignoring case
+func (b *Boundary) Property(names ...string) string {
+	for _, name := range names {
+		for key, value := range b.Properties {
+			if value != "" && strings.EqualFold(key, name) {
+				return value
+			}
+		}
+	}
+	return ""
+}
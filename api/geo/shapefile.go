@@ -0,0 +1,257 @@
+package geo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Shape types of polygon records. The Z and M variants carry extra values after the points,
+// which are ignored.
+const (
+	shapeNull     = 0
+	shapePolygon  = 5
+	shapePolygonZ = 15
+	shapePolygonM = 25
+)
+
+// ReadShapefile reads the polygons of an ESRI shapefile and their attributes from the .dbf file
+// with the same name. Shapefiles in a projected coordinate system, like the Statistics Canada
+// and Elections Canada boundary files, are rejected and must be reprojected to longitude and
+// latitude first, e.g. with ogr2ogr -t_srs EPSG:4326.
+func ReadShapefile(shpPath string) ([]*Boundary, error) {
+	basePath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath))
+
+	// The .prj file, when present, says whether coordinates are geographic or projected
+	if prj, err := os.ReadFile(basePath + ".prj"); err == nil && strings.HasPrefix(strings.TrimSpace(string(prj)), "PROJCS") {
+		return nil, fmt.Errorf("shapefile uses a projected coordinate system, reproject it to WGS 84 (EPSG:4326) first")
+	}
+
+	shapes, err := readShapes(shpPath)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := readDBF(basePath + ".dbf")
+	if err != nil {
+		return nil, err
+	}
+	if len(records) != len(shapes) {
+		return nil, fmt.Errorf("shapefile has %d shapes but %d attribute records", len(shapes), len(records))
+	}
+
+	var boundaries []*Boundary
+	for i, shape := range shapes {
+		if records[i] == nil || len(shape) == 0 {
+			continue
+		}
+		if !shape.Valid() {
+			return nil, fmt.Errorf("shape %d is not in longitude and latitude, reproject the file to WGS 84 (EPSG:4326) first", i)
+		}
+		boundaries = append(boundaries, &Boundary{Properties: records[i], Shape: shape})
+	}
+
+	return boundaries, nil
+}
+
+// readShapes reads every record of a .shp file. Null shapes are returned as nil to keep the
+// records aligned with the .dbf file.
+func readShapes(path string) ([]MultiPolygon, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shapefile: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, 100)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("failed to read shapefile header: %w", err)
+	}
+	if binary.BigEndian.Uint32(header[0:4]) != 9994 {
+		return nil, fmt.Errorf("not a shapefile")
+	}
+
+	var shapes []MultiPolygon
+	recordHeader := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(reader, recordHeader); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read shapefile record: %w", err)
+		}
+
+		// Content length is counted in 16-bit words
+		content := make([]byte, int(binary.BigEndian.Uint32(recordHeader[4:8]))*2)
+		if _, err := io.ReadFull(reader, content); err != nil {
+			return nil, fmt.Errorf("failed to read shapefile record %d: %w", len(shapes)+1, err)
+		}
+
+		shape, err := parsePolygonRecord(content)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shapefile record %d: %w", len(shapes)+1, err)
+		}
+		shapes = append(shapes, shape)
+	}
+
+	return shapes, nil
+}
+
+// parsePolygonRecord reads the rings of a polygon record and groups them into polygons. Outer rings
+// run clockwise and holes counter-clockwise, each hole belonging to the outer ring around it.
+func parsePolygonRecord(content []byte) (MultiPolygon, error) {
+	if len(content) < 4 {
+		return nil, fmt.Errorf("record is too short")
+	}
+
+	shapeType := binary.LittleEndian.Uint32(content[0:4])
+	switch shapeType {
+	case shapeNull:
+		return nil, nil
+	case shapePolygon, shapePolygonZ, shapePolygonM:
+	default:
+		return nil, fmt.Errorf("unsupported shape type %d, expected polygons", shapeType)
+	}
+
+	// Shape type, bounding box, part count and point count
+	if len(content) < 44 {
+		return nil, fmt.Errorf("record is too short")
+	}
+	numParts := int(binary.LittleEndian.Uint32(content[36:40]))
+	numPoints := int(binary.LittleEndian.Uint32(content[40:44]))
+	pointsOffset := 44 + numParts*4
+	if numParts <= 0 || numPoints < 0 || len(content) < pointsOffset+numPoints*16 {
+		return nil, fmt.Errorf("record is truncated")
+	}
+
+	parts := make([]int, numParts+1)
+	for i := 0; i < numParts; i++ {
+		parts[i] = int(binary.LittleEndian.Uint32(content[44+i*4:]))
+	}
+	parts[numParts] = numPoints
+
+	var outers []Ring
+	var holes []Ring
+	for i := 0; i < numParts; i++ {
+		if parts[i] < 0 || parts[i] > parts[i+1] {
+			return nil, fmt.Errorf("invalid part index")
+		}
+		ring := make(Ring, 0, parts[i+1]-parts[i])
+		for p := parts[i]; p < parts[i+1]; p++ {
+			offset := pointsOffset + p*16
+			ring = append(ring, Position{
+				math.Float64frombits(binary.LittleEndian.Uint64(content[offset:])),
+				math.Float64frombits(binary.LittleEndian.Uint64(content[offset+8:])),
+			})
+		}
+		if ring.signedArea() > 0 {
+			holes = append(holes, ring)
+		} else {
+			outers = append(outers, ring)
+		}
+	}
+
+	shape := make(MultiPolygon, 0, len(outers))
+	for _, outer := range outers {
+		shape = append(shape, Polygon{outer})
+	}
+	for _, hole := range holes {
+		placed := false
+		for i := range shape {
+			if len(hole) > 0 && shape[i][0].contains(hole[0][1], hole[0][0]) {
+				shape[i] = append(shape[i], hole)
+				placed = true
+				break
+			}
+		}
+		// A counter-clockwise ring outside every outer ring was written with the wrong winding
+		if !placed {
+			shape = append(shape, Polygon{hole})
+		}
+	}
+
+	return shape, nil
+}
+
+// readDBF reads the attribute table of a shapefile. Deleted records are returned as nil. Text
+// that isn't UTF-8 is read as Latin-1, the usual encoding of older dBase files.
+func readDBF(path string) ([]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open shapefile attributes: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, 32)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, fmt.Errorf("failed to read dBase header: %w", err)
+	}
+	numRecords := int(binary.LittleEndian.Uint32(header[4:8]))
+	headerLength := int(binary.LittleEndian.Uint16(header[8:10]))
+	recordLength := int(binary.LittleEndian.Uint16(header[10:12]))
+	if headerLength < 32 || recordLength < 1 {
+		return nil, fmt.Errorf("invalid dBase header")
+	}
+
+	// Field descriptors of 32 bytes each follow the header, up to a 0x0D terminator
+	descriptors := make([]byte, headerLength-32)
+	if _, err := io.ReadFull(reader, descriptors); err != nil {
+		return nil, fmt.Errorf("failed to read dBase fields: %w", err)
+	}
+
+	type field struct {
+		name   string
+		length int
+	}
+	var fields []field
+	for offset := 0; offset+32 <= len(descriptors) && descriptors[offset] != 0x0D; offset += 32 {
+		name := string(descriptors[offset : offset+11])
+		if end := strings.IndexByte(name, 0); end >= 0 {
+			name = name[:end]
+		}
+		fields = append(fields, field{name: name, length: int(descriptors[offset+16])})
+	}
+
+	records := make([]map[string]string, 0, numRecords)
+	record := make([]byte, recordLength)
+	for i := 0; i < numRecords; i++ {
+		if _, err := io.ReadFull(reader, record); err != nil {
+			return nil, fmt.Errorf("failed to read dBase record %d: %w", i+1, err)
+		}
+		if record[0] == '*' {
+			records = append(records, nil)
+			continue
+		}
+
+		values := make(map[string]string, len(fields))
+		offset := 1
+		for _, f := range fields {
+			if offset+f.length > len(record) {
+				break
+			}
+			values[f.name] = strings.TrimSpace(decodeDBFText(record[offset : offset+f.length]))
+			offset += f.length
+		}
+		records = append(records, values)
+	}
+
+	return records, nil
+}
+
+func decodeDBFText(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
@@ -17,6 +17,9 @@ const ContextKey contextKey = "user"
 
 var UserContextKey = contextKey("user")
 
+// APITokenContextKey is the key of the API token a request was authenticated with
+const APITokenContextKey contextKey = "api_token"
+
 // GetUserFromContext retrieves the user from the request context
 // Returns nil if no user is found or context value is not a valid user
 func GetUserFromContext(ctx context.Context) *models.User {
@@ -33,6 +36,17 @@ func GetUserFromContext(ctx context.Context) *models.User {
 	return user
 }
 
+// GetAPITokenFromContext retrieves the API token the request was authenticated with
+// Returns nil for requests authenticated with a session cookie or not authenticated
+func GetAPITokenFromContext(ctx context.Context) *models.APIToken {
+	token, ok := ctx.Value(APITokenContextKey).(*models.APIToken)
+	if !ok {
+		return nil
+	}
+
+	return token
+}
+
 func IsDev() bool {
 	if os.Getenv("ENV") == "development" {
 		return true
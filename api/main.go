@@ -88,6 +88,17 @@ func main() {
 		log.Fatal("Failed to start non-compliant scraper cron service", "error", err)
 	}
 
+	err = cn.Invoke(func(verificationTierCronService *services.VerificationTierCronService) {
+		go func() {
+			if err := verificationTierCronService.Start(ctx); err != nil {
+				log.Error("Verification tier cron service error", "error", err)
+			}
+		}()
+	})
+	if err != nil {
+		log.Fatal("Failed to start verification tier cron service", "error", err)
+	}
+
 	// Setup graceful shutdown
 	server := &http.Server{
 		Addr:    ":8000",
@@ -2,10 +2,12 @@ package middleware
 
 import (
 	"canada-hires/helpers"
+	"canada-hires/models"
 	"canada-hires/services"
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -14,23 +16,47 @@ import (
 	"github.com/charmbracelet/log"
 )
 
-// AuthMiddleware creates a middleware that extracts the user from the cookie
-// and attaches it to the request context
+// AuthMiddleware creates a middleware that extracts the user from the cookie or from an
+// "Authorization: Bearer" API token and attaches it to the request context
 
 type AuthMiddleware struct {
-	authService services.AuthService
-	userService services.UserService
+	authService     services.AuthService
+	userService     services.UserService
+	apiTokenService services.APITokenService
 }
 
-func NewAuthMiddleware(authService services.AuthService, userService services.UserService) *AuthMiddleware {
+func NewAuthMiddleware(authService services.AuthService, userService services.UserService, apiTokenService services.APITokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		authService: authService,
-		userService: userService,
+		authService:     authService,
+		userService:     userService,
+		apiTokenService: apiTokenService,
 	}
 }
 
+// scopeCheckedKey marks requests whose API token passed a RequireScope check
+type scopeCheckedKey struct{}
+
 func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// API tokens take precedence over the cookie, an invalid token is refused rather than
+		// treated as an anonymous request so scripts notice it
+		if scheme, value, found := strings.Cut(r.Header.Get("Authorization"), " "); found && strings.EqualFold(scheme, "Bearer") {
+			token, user, err := m.apiTokenService.Authenticate(strings.TrimSpace(value), helpers.GetClientIP(r))
+			if err != nil {
+				if !errors.Is(err, services.ErrAPITokenUnauthorized) {
+					log.Error("Failed to authenticate API token", "error", err)
+				}
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte(`{"error":"Unauthorized - Invalid or expired API token"}`))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), helpers.ContextKey, user)
+			ctx = context.WithValue(ctx, helpers.APITokenContextKey, token)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		// Extract the cookie
 		cookie, err := r.Cookie("session_id")
 		if err != nil {
@@ -63,8 +89,38 @@ func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// RequireScope creates a middleware that requires API tokens to have the scope
+// Requests authenticated with a session cookie and anonymous requests are let through, use it
+// before RequireAuth. It will return a 403 Forbidden response if the token lacks the scope.
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := helpers.GetAPITokenFromContext(r.Context())
+			if token == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !token.HasScope(scope) {
+				log.Warn("API token without the required scope attempted to access endpoint",
+					"token_id", token.ID,
+					"user_id", token.UserID,
+					"scope", scope,
+					"path", r.URL.Path)
+				w.WriteHeader(http.StatusForbidden)
+				w.Write([]byte(`{"error":"Forbidden - API token requires the ` + scope + ` scope"}`))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), scopeCheckedKey{}, true)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
 // RequireAuth creates a middleware that requires authentication
-// It will return a 401 Unauthorized response if the user is not authenticated
+// It will return a 401 Unauthorized response if the user is not authenticated, and a 403
+// Forbidden response for API tokens on endpoints without a scope, such as token management
 func RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := helpers.GetUserFromContext(r.Context())
@@ -74,12 +130,51 @@ func RequireAuth(next http.Handler) http.Handler {
 			return
 		}
 
+		if token := helpers.GetAPITokenFromContext(r.Context()); token != nil && r.Context().Value(scopeCheckedKey{}) == nil {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden - API tokens can't access this endpoint"}`))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireModerator creates a middleware that requires the moderator or admin role
+// It will return a 403 Forbidden response if the user can't moderate, or if the request uses an
+// API token without the admin scope
+func RequireModerator(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := helpers.GetUserFromContext(r.Context())
+		if user == nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":"Unauthorized - Please log in"}`))
+			return
+		}
+
+		if !user.IsModerator() {
+			log.Warn("Non-moderator user attempted to access moderation endpoint",
+				"user_id", user.ID,
+				"role", user.Role,
+				"path", r.URL.Path)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden - Moderator access required"}`))
+			return
+		}
+
+		if token := helpers.GetAPITokenFromContext(r.Context()); token != nil && !token.HasScope(models.ScopeAdmin) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden - API token requires the ` + models.ScopeAdmin + ` scope"}`))
+			return
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
 
 // RequireAdmin creates a middleware that requires admin role
-// It will return a 403 Forbidden response if the user is not an admin
+// It will return a 403 Forbidden response if the user is not an admin, or if the request uses an
+// API token without the admin scope
 func RequireAdmin(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user := helpers.GetUserFromContext(r.Context())
@@ -100,6 +195,12 @@ func RequireAdmin(next http.Handler) http.Handler {
 			return
 		}
 
+		if token := helpers.GetAPITokenFromContext(r.Context()); token != nil && !token.HasScope(models.ScopeAdmin) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error":"Forbidden - API token requires the ` + models.ScopeAdmin + ` scope"}`))
+			return
+		}
+
 		log.Info("Admin user accessing admin endpoint",
 			"user_id", user.ID,
 			"email", user.Email,
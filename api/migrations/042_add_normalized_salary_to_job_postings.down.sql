@@ -0,0 +1,15 @@
+-- Remove normalized salary columns
+
+ALTER TABLE lmia_job_statistics
+DROP COLUMN IF EXISTS avg_salary_annual_min,
+DROP COLUMN IF EXISTS avg_salary_annual_max;
+
+DROP INDEX IF EXISTS idx_job_postings_salary_hourly_min;
+DROP INDEX IF EXISTS idx_job_postings_salary_annual_min;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS hours_per_week,
+DROP COLUMN IF EXISTS salary_hourly_min,
+DROP COLUMN IF EXISTS salary_hourly_max,
+DROP COLUMN IF EXISTS salary_annual_min,
+DROP COLUMN IF EXISTS salary_annual_max;
@@ -0,0 +1,46 @@
+-- Add normalized salary columns so postings with different salary types can be compared
+
+ALTER TABLE job_postings
+ADD COLUMN hours_per_week DECIMAL(5,2),
+ADD COLUMN salary_hourly_min DECIMAL(10,2),
+ADD COLUMN salary_hourly_max DECIMAL(10,2),
+ADD COLUMN salary_annual_min DECIMAL(12,2),
+ADD COLUMN salary_annual_max DECIMAL(12,2);
+
+-- Backfill existing postings assuming a 40 hour week
+UPDATE job_postings
+SET salary_annual_min = ROUND(CASE salary_type
+        WHEN 'yearly' THEN salary_min
+        WHEN 'monthly' THEN salary_min * 12
+        WHEN 'biweekly' THEN salary_min * 26
+        WHEN 'weekly' THEN salary_min * 52
+        ELSE salary_min * 40 * 52
+    END, 2),
+    salary_annual_max = ROUND(CASE salary_type
+        WHEN 'yearly' THEN salary_max
+        WHEN 'monthly' THEN salary_max * 12
+        WHEN 'biweekly' THEN salary_max * 26
+        WHEN 'weekly' THEN salary_max * 52
+        ELSE salary_max * 40 * 52
+    END, 2)
+WHERE salary_type IS NOT NULL;
+
+UPDATE job_postings
+SET salary_hourly_min = ROUND(salary_annual_min / (40 * 52), 2),
+    salary_hourly_max = ROUND(salary_annual_max / (40 * 52), 2)
+WHERE salary_type IS NOT NULL;
+
+-- Indexes for salary filtering and sorting
+CREATE INDEX idx_job_postings_salary_hourly_min ON job_postings(salary_hourly_min);
+CREATE INDEX idx_job_postings_salary_annual_min ON job_postings(salary_annual_min);
+
+-- Keep annualized averages alongside the hourly ones in the statistics table
+ALTER TABLE lmia_job_statistics
+ADD COLUMN avg_salary_annual_min DECIMAL(12,2),
+ADD COLUMN avg_salary_annual_max DECIMAL(12,2);
+
+COMMENT ON COLUMN job_postings.hours_per_week IS 'Weekly hours from the Job Bank detail page, when known';
+COMMENT ON COLUMN job_postings.salary_hourly_min IS 'salary_min converted to an hourly equivalent';
+COMMENT ON COLUMN job_postings.salary_annual_min IS 'salary_min converted to an annual equivalent';
+COMMENT ON COLUMN lmia_job_statistics.avg_salary_min IS 'Average hourly equivalent of salary_min';
+COMMENT ON COLUMN lmia_job_statistics.avg_salary_max IS 'Average hourly equivalent of salary_max';
@@ -0,0 +1,14 @@
+-- Remove prevailing wage comparison
+DROP INDEX IF EXISTS idx_job_postings_below_median_wage;
+DROP INDEX IF EXISTS idx_job_postings_noc_code;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS noc_code,
+DROP COLUMN IF EXISTS economic_region_code,
+DROP COLUMN IF EXISTS median_wage,
+DROP COLUMN IF EXISTS wage_percent_of_median,
+DROP COLUMN IF EXISTS below_median_wage;
+
+DROP INDEX IF EXISTS idx_prevailing_wages_province;
+DROP INDEX IF EXISTS idx_prevailing_wages_noc_code;
+DROP TABLE IF EXISTS prevailing_wages;
@@ -0,0 +1,35 @@
+-- Create prevailing_wages table from the ESDC/Job Bank wage table, keyed by NOC and economic region
+CREATE TABLE prevailing_wages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    noc_code VARCHAR(10) NOT NULL,
+    noc_title TEXT,
+    economic_region_code VARCHAR(10) NOT NULL,
+    economic_region_name TEXT,
+    region_level VARCHAR(20) NOT NULL DEFAULT 'economic_region' CHECK (region_level IN ('economic_region', 'province', 'national')),
+    province VARCHAR(2),
+    low_wage DECIMAL(12,2),
+    median_wage DECIMAL(12,2),
+    high_wage DECIMAL(12,2),
+    is_annual BOOLEAN NOT NULL DEFAULT FALSE,
+    reference_period VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(noc_code, economic_region_code)
+);
+
+CREATE INDEX idx_prevailing_wages_noc_code ON prevailing_wages(noc_code);
+CREATE INDEX idx_prevailing_wages_province ON prevailing_wages(province);
+
+-- Annotate job postings with the prevailing wage comparison
+ALTER TABLE job_postings
+ADD COLUMN noc_code VARCHAR(10),
+ADD COLUMN economic_region_code VARCHAR(10),
+ADD COLUMN median_wage DECIMAL(10,2),
+ADD COLUMN wage_percent_of_median DECIMAL(6,2),
+ADD COLUMN below_median_wage BOOLEAN;
+
+CREATE INDEX idx_job_postings_noc_code ON job_postings(noc_code);
+CREATE INDEX idx_job_postings_below_median_wage ON job_postings(below_median_wage);
+
+COMMENT ON COLUMN job_postings.median_wage IS 'Hourly median wage for the NOC and economic region';
+COMMENT ON COLUMN job_postings.wage_percent_of_median IS 'Offered hourly wage (top of range) as a percentage of the median';
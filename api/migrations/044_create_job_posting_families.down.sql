@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_job_postings_repost_count;
+DROP INDEX IF EXISTS idx_job_postings_family_id;
+DROP INDEX IF EXISTS idx_job_postings_fingerprint;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS reposted_since,
+DROP COLUMN IF EXISTS repost_count,
+DROP COLUMN IF EXISTS family_id,
+DROP COLUMN IF EXISTS fingerprint;
+
+DROP TABLE IF EXISTS job_posting_family_members;
+DROP TABLE IF EXISTS job_posting_families;
@@ -0,0 +1,42 @@
+-- Posting families group reposts of the same ad (same employer, title, location and salary)
+-- that appear on Job Bank under a new job bank ID. Members are kept separately from job_postings
+-- so a family's history survives when expired postings are removed after a scrape.
+CREATE TABLE job_posting_families (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    fingerprint VARCHAR(64) NOT NULL UNIQUE,
+    employer VARCHAR(500) NOT NULL,
+    title TEXT NOT NULL,
+    location VARCHAR(200) NOT NULL,
+    posting_count INTEGER NOT NULL DEFAULT 0,
+    first_posted_at TIMESTAMP WITH TIME ZONE,
+    last_posted_at TIMESTAMP WITH TIME ZONE,
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE job_posting_family_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    family_id UUID NOT NULL REFERENCES job_posting_families(id) ON DELETE CASCADE,
+    -- job_bank_id when the scraper found one, otherwise the posting URL
+    posting_key TEXT NOT NULL,
+    job_bank_id VARCHAR(255),
+    url TEXT NOT NULL,
+    posting_date TIMESTAMP WITH TIME ZONE,
+    first_seen_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(family_id, posting_key)
+);
+
+ALTER TABLE job_postings
+ADD COLUMN fingerprint VARCHAR(64),
+ADD COLUMN family_id UUID REFERENCES job_posting_families(id) ON DELETE SET NULL,
+ADD COLUMN repost_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN reposted_since TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_job_posting_families_posting_count ON job_posting_families(posting_count);
+CREATE INDEX idx_job_posting_families_last_posted_at ON job_posting_families(last_posted_at);
+CREATE INDEX idx_job_posting_family_members_family_id ON job_posting_family_members(family_id);
+CREATE INDEX idx_job_postings_fingerprint ON job_postings(fingerprint);
+CREATE INDEX idx_job_postings_family_id ON job_postings(family_id);
+CREATE INDEX idx_job_postings_repost_count ON job_postings(repost_count);
@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_job_postings_is_repeat_lmia_user;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS lmia_matched_at,
+DROP COLUMN IF EXISTS is_repeat_lmia_user,
+DROP COLUMN IF EXISTS lmia_approval_periods,
+DROP COLUMN IF EXISTS lmia_historical_positions,
+DROP COLUMN IF EXISTS lmia_historical_approvals,
+DROP COLUMN IF EXISTS lmia_match_confidence;
+
+DROP TABLE IF EXISTS job_posting_lmia_matches;
+
+DROP INDEX IF EXISTS idx_lmia_employers_normalized_employer;
+ALTER TABLE lmia_employers DROP COLUMN IF EXISTS normalized_employer;
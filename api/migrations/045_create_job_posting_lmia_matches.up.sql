@@ -0,0 +1,30 @@
+-- Normalized employer name used to match Job Bank postings to LMIA approvals
+ALTER TABLE lmia_employers ADD COLUMN normalized_employer VARCHAR(500);
+CREATE INDEX idx_lmia_employers_normalized_employer ON lmia_employers(normalized_employer) WHERE normalized_employer IS NOT NULL;
+
+-- Links between Job Bank postings and the LMIA approval rows of the same employer
+CREATE TABLE job_posting_lmia_matches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    job_posting_id UUID NOT NULL REFERENCES job_postings(id) ON DELETE CASCADE,
+    lmia_employer_id UUID NOT NULL REFERENCES lmia_employers(id) ON DELETE CASCADE,
+    confidence DECIMAL(4,3) NOT NULL,
+    name_match VARCHAR(20) NOT NULL CHECK (name_match IN ('exact', 'normalized')),
+    location_match VARCHAR(20) NOT NULL CHECK (location_match IN ('postal_code', 'city', 'province', 'none')),
+    noc_match BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(job_posting_id, lmia_employer_id)
+);
+
+CREATE INDEX idx_job_posting_lmia_matches_job_posting_id ON job_posting_lmia_matches(job_posting_id);
+CREATE INDEX idx_job_posting_lmia_matches_lmia_employer_id ON job_posting_lmia_matches(lmia_employer_id);
+
+-- Summary of the matched approvals, denormalized for the job APIs
+ALTER TABLE job_postings
+ADD COLUMN lmia_match_confidence DECIMAL(4,3),
+ADD COLUMN lmia_historical_approvals INTEGER,
+ADD COLUMN lmia_historical_positions INTEGER,
+ADD COLUMN lmia_approval_periods INTEGER,
+ADD COLUMN is_repeat_lmia_user BOOLEAN,
+ADD COLUMN lmia_matched_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_job_postings_is_repeat_lmia_user ON job_postings(is_repeat_lmia_user);
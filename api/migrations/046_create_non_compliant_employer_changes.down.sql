@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS non_compliant_employer_changes;
+
+DROP INDEX IF EXISTS idx_non_compliant_employers_delisted_at;
+
+ALTER TABLE non_compliant_employers
+DROP COLUMN IF EXISTS first_seen_at,
+DROP COLUMN IF EXISTS last_seen_at,
+DROP COLUMN IF EXISTS delisted_at;
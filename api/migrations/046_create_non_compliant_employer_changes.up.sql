@@ -0,0 +1,35 @@
+-- Track when an employer first and last appeared on the published list, and when it was removed
+ALTER TABLE non_compliant_employers
+ADD COLUMN first_seen_at TIMESTAMP,
+ADD COLUMN last_seen_at TIMESTAMP,
+ADD COLUMN delisted_at TIMESTAMP;
+
+UPDATE non_compliant_employers SET first_seen_at = created_at, last_seen_at = scraped_at;
+
+ALTER TABLE non_compliant_employers
+ALTER COLUMN first_seen_at SET NOT NULL,
+ALTER COLUMN first_seen_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN last_seen_at SET NOT NULL,
+ALTER COLUMN last_seen_at SET DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_non_compliant_employers_delisted_at ON non_compliant_employers(delisted_at);
+
+-- Every change observed between scrapes, one row per changed field
+CREATE TABLE non_compliant_employer_changes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    employer_id UUID NOT NULL REFERENCES non_compliant_employers(id) ON DELETE CASCADE,
+    business_operating_name TEXT NOT NULL,
+    change_type VARCHAR(20) NOT NULL CHECK (change_type IN ('listed', 'updated', 'delisted', 'relisted')),
+    field_name VARCHAR(50),
+    old_value TEXT,
+    new_value TEXT,
+    observed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_non_compliant_employer_changes_employer_id ON non_compliant_employer_changes(employer_id);
+CREATE INDEX idx_non_compliant_employer_changes_observed_at ON non_compliant_employer_changes(observed_at);
+CREATE INDEX idx_non_compliant_employer_changes_change_type ON non_compliant_employer_changes(change_type);
+
+-- Existing rows count as listed when they were first stored
+INSERT INTO non_compliant_employer_changes (employer_id, business_operating_name, change_type, observed_at)
+SELECT id, business_operating_name, 'listed', first_seen_at FROM non_compliant_employers;
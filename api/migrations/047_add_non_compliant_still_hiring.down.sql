@@ -0,0 +1,14 @@
+DROP INDEX IF EXISTS idx_job_postings_non_compliant_employer_id;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS non_compliant_penalty_amount,
+DROP COLUMN IF EXISTS non_compliant_decision_date,
+DROP COLUMN IF EXISTS is_non_compliant_employer,
+DROP COLUMN IF EXISTS non_compliant_employer_id;
+
+DROP INDEX IF EXISTS idx_non_compliant_employers_normalized_legal_name;
+DROP INDEX IF EXISTS idx_non_compliant_employers_normalized_name;
+
+ALTER TABLE non_compliant_employers
+DROP COLUMN IF EXISTS normalized_legal_name,
+DROP COLUMN IF EXISTS normalized_name;
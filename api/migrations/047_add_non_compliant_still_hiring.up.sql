@@ -0,0 +1,16 @@
+-- Normalized names used to match non-compliant employers to Job Bank postings and LMIA approvals
+ALTER TABLE non_compliant_employers
+ADD COLUMN normalized_name VARCHAR(500),
+ADD COLUMN normalized_legal_name VARCHAR(500);
+
+CREATE INDEX idx_non_compliant_employers_normalized_name ON non_compliant_employers(normalized_name) WHERE normalized_name IS NOT NULL;
+CREATE INDEX idx_non_compliant_employers_normalized_legal_name ON non_compliant_employers(normalized_legal_name) WHERE normalized_legal_name IS NOT NULL;
+
+-- Flag postings whose employer is on the non-compliant list, pointing at the most recent decision
+ALTER TABLE job_postings
+ADD COLUMN non_compliant_employer_id UUID REFERENCES non_compliant_employers(id) ON DELETE SET NULL,
+ADD COLUMN is_non_compliant_employer BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN non_compliant_decision_date DATE,
+ADD COLUMN non_compliant_penalty_amount INTEGER;
+
+CREATE INDEX idx_job_postings_non_compliant_employer_id ON job_postings(non_compliant_employer_id) WHERE non_compliant_employer_id IS NOT NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_boycotts_parsed_address_id;
+DROP INDEX IF EXISTS idx_reports_parsed_address_id;
+DROP INDEX IF EXISTS idx_non_compliant_employers_parsed_address_id;
+DROP INDEX IF EXISTS idx_lmia_employers_parsed_address_id;
+
+ALTER TABLE boycotts DROP COLUMN IF EXISTS parsed_address_id;
+ALTER TABLE reports DROP COLUMN IF EXISTS parsed_address_id;
+ALTER TABLE non_compliant_employers DROP COLUMN IF EXISTS parsed_address_id;
+ALTER TABLE lmia_employers DROP COLUMN IF EXISTS parsed_address_id;
+
+DROP TABLE IF EXISTS parsed_addresses;
@@ -0,0 +1,36 @@
+-- Components of free-text addresses, shared by every dataset that stores an address.
+-- One row per distinct normalized address.
+CREATE TABLE parsed_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    raw_address TEXT NOT NULL,
+    normalized_address TEXT NOT NULL UNIQUE,
+    formatted_address TEXT,
+    unit VARCHAR(50),
+    street_number VARCHAR(50),
+    street VARCHAR(255),
+    street_type VARCHAR(20),
+    street_direction VARCHAR(5),
+    po_box VARCHAR(20),
+    rural_route VARCHAR(20),
+    municipality VARCHAR(150),
+    province_code VARCHAR(2),
+    postal_code VARCHAR(10),
+    confidence DECIMAL(3,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_parsed_addresses_postal_code ON parsed_addresses(postal_code) WHERE postal_code IS NOT NULL;
+CREATE INDEX idx_parsed_addresses_municipality_province ON parsed_addresses(province_code, municipality);
+CREATE INDEX idx_parsed_addresses_confidence ON parsed_addresses(confidence);
+
+-- Link each dataset to the parsed form of its address
+ALTER TABLE lmia_employers ADD COLUMN parsed_address_id UUID REFERENCES parsed_addresses(id) ON DELETE SET NULL;
+ALTER TABLE non_compliant_employers ADD COLUMN parsed_address_id UUID REFERENCES parsed_addresses(id) ON DELETE SET NULL;
+ALTER TABLE reports ADD COLUMN parsed_address_id UUID REFERENCES parsed_addresses(id) ON DELETE SET NULL;
+ALTER TABLE boycotts ADD COLUMN parsed_address_id UUID REFERENCES parsed_addresses(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_lmia_employers_parsed_address_id ON lmia_employers(parsed_address_id);
+CREATE INDEX idx_non_compliant_employers_parsed_address_id ON non_compliant_employers(parsed_address_id);
+CREATE INDEX idx_reports_parsed_address_id ON reports(parsed_address_id);
+CREATE INDEX idx_boycotts_parsed_address_id ON boycotts(parsed_address_id);
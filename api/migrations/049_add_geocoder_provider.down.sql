@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_address_geocoding_cache_provider;
+DROP INDEX IF EXISTS idx_postal_codes_provider;
+
+ALTER TABLE address_geocoding_cache DROP COLUMN IF EXISTS provider;
+
+ALTER TABLE postal_codes
+DROP COLUMN IF EXISTS confidence,
+DROP COLUMN IF EXISTS provider;
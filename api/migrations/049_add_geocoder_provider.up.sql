@@ -0,0 +1,11 @@
+-- Record which geocoder produced each stored coordinate. Coordinates stored before the
+-- geocoder chain existed keep a NULL provider.
+ALTER TABLE postal_codes
+ADD COLUMN provider VARCHAR(30),
+ADD COLUMN confidence DOUBLE PRECISION;
+
+ALTER TABLE address_geocoding_cache
+ADD COLUMN provider VARCHAR(30);
+
+CREATE INDEX idx_postal_codes_provider ON postal_codes(provider);
+CREATE INDEX idx_address_geocoding_cache_provider ON address_geocoding_cache(provider);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_postal_codes_precision;
+
+ALTER TABLE address_geocoding_cache DROP COLUMN IF EXISTS precision;
+
+ALTER TABLE postal_codes DROP COLUMN IF EXISTS precision;
@@ -0,0 +1,9 @@
+-- Record how precise stored coordinates are, e.g. a full postal code or only its forward sortation
+-- area (FSA). FSA centroids are stored in postal_codes under their three character code.
+ALTER TABLE postal_codes
+ADD COLUMN precision VARCHAR(20);
+
+ALTER TABLE address_geocoding_cache
+ADD COLUMN precision VARCHAR(20);
+
+CREATE INDEX idx_postal_codes_precision ON postal_codes(precision);
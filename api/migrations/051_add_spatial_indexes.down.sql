@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_job_postings_geolocation;
+DROP INDEX IF EXISTS idx_job_postings_earth;
+
+ALTER TABLE job_postings
+DROP COLUMN IF EXISTS geocoded_at,
+DROP COLUMN IF EXISTS longitude,
+DROP COLUMN IF EXISTS latitude;
+
+DROP INDEX IF EXISTS idx_non_compliant_employers_postal_code;
+DROP INDEX IF EXISTS idx_address_geocoding_cache_earth;
+DROP INDEX IF EXISTS idx_postal_codes_earth;
+
+-- The extensions are left installed, other objects may depend on them
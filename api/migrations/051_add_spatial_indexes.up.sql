@@ -0,0 +1,24 @@
+-- earthdistance answers radius queries on plain latitude/longitude columns through a GiST index
+CREATE EXTENSION IF NOT EXISTS cube;
+CREATE EXTENSION IF NOT EXISTS earthdistance;
+
+CREATE INDEX idx_postal_codes_earth ON postal_codes
+    USING gist (ll_to_earth(latitude::float8, longitude::float8))
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+
+CREATE INDEX idx_address_geocoding_cache_earth ON address_geocoding_cache
+    USING gist (ll_to_earth(latitude, longitude));
+
+CREATE INDEX idx_non_compliant_employers_postal_code ON non_compliant_employers(postal_code) WHERE postal_code IS NOT NULL;
+
+-- Job postings only carry a city, which is geocoded once per distinct location
+ALTER TABLE job_postings
+ADD COLUMN latitude DOUBLE PRECISION,
+ADD COLUMN longitude DOUBLE PRECISION,
+ADD COLUMN geocoded_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_job_postings_earth ON job_postings
+    USING gist (ll_to_earth(latitude, longitude))
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
+CREATE INDEX idx_job_postings_geolocation ON job_postings(latitude, longitude)
+    WHERE latitude IS NOT NULL AND longitude IS NOT NULL;
@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_job_postings_electoral_district_id;
+DROP INDEX IF EXISTS idx_address_geocoding_cache_electoral_district_id;
+DROP INDEX IF EXISTS idx_postal_codes_electoral_district_id;
+
+ALTER TABLE job_postings DROP COLUMN IF EXISTS electoral_district_id;
+ALTER TABLE address_geocoding_cache DROP COLUMN IF EXISTS electoral_district_id;
+ALTER TABLE postal_codes DROP COLUMN IF EXISTS electoral_district_id;
+
+DROP TABLE IF EXISTS electoral_districts;
@@ -0,0 +1,32 @@
+-- Federal electoral districts (ridings) imported from a boundary file. Boundaries are stored as
+-- GeoJSON MultiPolygon coordinates and matched in the application, as PostGIS isn't available.
+CREATE TABLE electoral_districts (
+    id VARCHAR(10) PRIMARY KEY,           -- Federal electoral district number, e.g. 35001
+    name_en TEXT NOT NULL,
+    name_fr TEXT,
+    province_code VARCHAR(2),
+    min_latitude DOUBLE PRECISION NOT NULL,
+    min_longitude DOUBLE PRECISION NOT NULL,
+    max_latitude DOUBLE PRECISION NOT NULL,
+    max_longitude DOUBLE PRECISION NOT NULL,
+    boundary JSONB NOT NULL,
+    source_file TEXT,
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_electoral_districts_province_code ON electoral_districts(province_code);
+
+-- Geocoded points are assigned to the district they fall in. Reimporting boundaries deletes the
+-- districts, which clears the assignments so they are recomputed.
+ALTER TABLE postal_codes
+ADD COLUMN electoral_district_id VARCHAR(10) REFERENCES electoral_districts(id) ON DELETE SET NULL;
+
+ALTER TABLE address_geocoding_cache
+ADD COLUMN electoral_district_id VARCHAR(10) REFERENCES electoral_districts(id) ON DELETE SET NULL;
+
+ALTER TABLE job_postings
+ADD COLUMN electoral_district_id VARCHAR(10) REFERENCES electoral_districts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_postal_codes_electoral_district_id ON postal_codes(electoral_district_id);
+CREATE INDEX idx_address_geocoding_cache_electoral_district_id ON address_geocoding_cache(electoral_district_id);
+CREATE INDEX idx_job_postings_electoral_district_id ON job_postings(electoral_district_id);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS geocoding_reviews;
+
+ALTER TABLE address_geocoding_cache
+DROP COLUMN IF EXISTS manual_override,
+DROP COLUMN IF EXISTS layer,
+DROP COLUMN IF EXISTS match_type;
+
+ALTER TABLE postal_codes
+DROP COLUMN IF EXISTS manual_override,
+DROP COLUMN IF EXISTS layer,
+DROP COLUMN IF EXISTS match_type;
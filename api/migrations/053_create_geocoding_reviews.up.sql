@@ -0,0 +1,45 @@
+-- Record how a geocoder matched stored coordinates, e.g. Pelias match_type "fallback" on the
+-- "locality" layer. Coordinates confirmed or pinned by a reviewer are a manual override that
+-- geocoding and centroid imports never replace.
+ALTER TABLE postal_codes
+ADD COLUMN match_type VARCHAR(30),
+ADD COLUMN layer VARCHAR(30),
+ADD COLUMN manual_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE address_geocoding_cache
+ADD COLUMN match_type VARCHAR(30),
+ADD COLUMN layer VARCHAR(30),
+ADD COLUMN manual_override BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Geocoding results with a low confidence or in another province than expected, waiting for a
+-- reviewer to accept them, reject them or pin the coordinates by hand
+CREATE TABLE geocoding_reviews (
+    id SERIAL PRIMARY KEY,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('postal_code', 'address')),
+    target_key TEXT NOT NULL,             -- Postal code, or normalized address of the address cache
+    query TEXT NOT NULL,                  -- Text sent to the geocoder
+    expected_province_code VARCHAR(2),
+    provider VARCHAR(30) NOT NULL,
+    latitude DOUBLE PRECISION NOT NULL,
+    longitude DOUBLE PRECISION NOT NULL,
+    confidence DOUBLE PRECISION,
+    precision VARCHAR(20),
+    match_type VARCHAR(30),
+    layer VARCHAR(30),
+    result_postal_code VARCHAR(10),
+    result_province_code VARCHAR(2),
+    reason VARCHAR(30) NOT NULL CHECK (reason IN ('low_confidence', 'province_mismatch', 'postal_code_mismatch')),
+    detail TEXT,
+    stored BOOLEAN NOT NULL DEFAULT FALSE, -- Whether the result was saved and shown while pending
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected', 'pinned')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- A target has at most one pending review, newer results replace the pending one
+CREATE UNIQUE INDEX idx_geocoding_reviews_pending_target ON geocoding_reviews(target_type, target_key) WHERE status = 'pending';
+CREATE INDEX idx_geocoding_reviews_status ON geocoding_reviews(status, created_at);
+CREATE INDEX idx_geocoding_reviews_target ON geocoding_reviews(target_type, target_key);
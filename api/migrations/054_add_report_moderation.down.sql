@@ -0,0 +1,18 @@
+UPDATE users SET role = 'user' WHERE role = 'moderator';
+
+ALTER TABLE users DROP CONSTRAINT chk_user_role;
+ALTER TABLE users
+ADD CONSTRAINT chk_user_role
+CHECK (role IN ('user', 'admin'));
+
+COMMENT ON COLUMN users.role IS 'User role: user or admin';
+
+DROP INDEX IF EXISTS idx_reports_status;
+
+ALTER TABLE reports
+DROP COLUMN IF EXISTS published_at,
+DROP COLUMN IF EXISTS moderation_notes,
+DROP COLUMN IF EXISTS rejection_reason,
+DROP COLUMN IF EXISTS moderated_at,
+DROP COLUMN IF EXISTS moderated_by,
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,23 @@
+-- Reports are held for moderation unless their author's verification tier publishes them
+-- directly. Reports created before moderation existed were already public and stay published.
+ALTER TABLE reports
+ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'published' CHECK (status IN ('pending', 'published', 'rejected', 'flagged')),
+ADD COLUMN moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+ADD COLUMN moderated_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN rejection_reason VARCHAR(30),
+ADD COLUMN moderation_notes TEXT,
+ADD COLUMN published_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE reports SET published_at = created_at;
+
+ALTER TABLE reports ALTER COLUMN status SET DEFAULT 'pending';
+
+CREATE INDEX idx_reports_status ON reports(status, created_at);
+
+-- Moderators can work the report queue without being admins
+ALTER TABLE users DROP CONSTRAINT chk_user_role;
+ALTER TABLE users
+ADD CONSTRAINT chk_user_role
+CHECK (role IN ('user', 'moderator', 'admin'));
+
+COMMENT ON COLUMN users.role IS 'User role: user, moderator or admin';
@@ -0,0 +1,23 @@
+-- Evidence files attached to reports. Files are kept in the configured storage backend under
+-- storage_key; only moderators and the uploader see them until a moderator makes them public.
+CREATE TABLE report_attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    report_id UUID NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+    uploaded_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    checksum_sha256 CHAR(64) NOT NULL,
+    storage_backend VARCHAR(20) NOT NULL,
+    storage_key TEXT NOT NULL,
+    metadata_stripped BOOLEAN NOT NULL DEFAULT FALSE,
+    visibility VARCHAR(20) NOT NULL DEFAULT 'private' CHECK (visibility IN ('private', 'public')),
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (report_id, checksum_sha256)
+);
+
+CREATE INDEX idx_report_attachments_report_id ON report_attachments(report_id, created_at);
+CREATE INDEX idx_report_attachments_checksum ON report_attachments(checksum_sha256);
@@ -0,0 +1,5 @@
+ALTER TABLE reports
+DROP COLUMN IF EXISTS corroboration_count,
+DROP COLUMN IF EXISTS flag_count;
+
+DROP TABLE IF EXISTS report_votes;
@@ -0,0 +1,25 @@
+-- Other users corroborate reports ("I've seen this too") or flag them. Each user has one vote per
+-- report. Votes from users who share an IP address with the author or with another voter are
+-- kept but not counted.
+CREATE TABLE report_votes (
+    id SERIAL PRIMARY KEY,
+    report_id UUID NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vote_type VARCHAR(20) NOT NULL CHECK (vote_type IN ('corroborate', 'flag')),
+    flag_reason VARCHAR(30) CHECK (flag_reason IN ('inaccurate', 'personal_information', 'spam')),
+    comment TEXT,
+    ip_address INET,
+    counted BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (report_id, user_id),
+    CONSTRAINT chk_report_vote_flag_reason CHECK ((vote_type = 'flag') = (flag_reason IS NOT NULL))
+);
+
+CREATE INDEX idx_report_votes_report_id ON report_votes(report_id, vote_type);
+CREATE INDEX idx_report_votes_user_id ON report_votes(user_id);
+
+-- Counted votes, kept on the report for listings
+ALTER TABLE reports
+ADD COLUMN corroboration_count INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN flag_count INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS business_replies;
+DROP TABLE IF EXISTS business_claims;
@@ -0,0 +1,47 @@
+-- Businesses named in reports can claim their business by opening a link sent to an address at
+-- their own email domain. Names and addresses are matched on the same normalized forms the API
+-- uses to detect duplicate reports.
+CREATE TABLE business_claims (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    business_name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL,
+    business_address TEXT NOT NULL,
+    normalized_address TEXT NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    email_domain VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) NOT NULL,
+    token_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'verified', 'revoked')),
+    verified_at TIMESTAMP WITH TIME ZONE,
+    revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revocation_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_business_claims_token_hash ON business_claims(token_hash);
+CREATE INDEX idx_business_claims_user_id ON business_claims(user_id);
+-- A business has at most one verified owner, and a user one open claim per business
+CREATE UNIQUE INDEX idx_business_claims_verified ON business_claims(normalized_name, normalized_address) WHERE status = 'verified';
+CREATE UNIQUE INDEX idx_business_claims_pending ON business_claims(user_id, normalized_name, normalized_address) WHERE status = 'pending';
+
+-- Replies of verified businesses: one response per report, and one general statement (without a
+-- report) per claim. Replies are shown once a moderator publishes them.
+CREATE TABLE business_replies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    claim_id UUID NOT NULL REFERENCES business_claims(id) ON DELETE CASCADE,
+    report_id UUID REFERENCES reports(id) ON DELETE CASCADE,
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'rejected')),
+    moderated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    moderation_notes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_business_replies_report ON business_replies(report_id) WHERE report_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_business_replies_statement ON business_replies(claim_id) WHERE report_id IS NULL;
+CREATE INDEX idx_business_replies_status ON business_replies(status, updated_at);
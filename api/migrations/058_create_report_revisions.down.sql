@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS report_revisions;
+
+-- Reports deleted while soft deletion was in place are removed for good
+DELETE FROM reports WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE reports
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS deleted_by;
@@ -0,0 +1,21 @@
+-- Deleted reports are kept, hidden everywhere, so an admin can restore them
+ALTER TABLE reports
+ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE,
+ADD COLUMN deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_reports_deleted_at ON reports(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Every change to a report, with the fields it changed. History starts with this migration,
+-- earlier reports have no revisions before their next change.
+CREATE TABLE report_revisions (
+    id SERIAL PRIMARY KEY,
+    report_id UUID NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL CHECK (action IN ('create', 'update', 'moderate', 'delete', 'restore')),
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for automatic changes, e.g. community flags
+    ip_address INET,
+    changes JSONB NOT NULL DEFAULT '{}', -- Field name to {"old": ..., "new": ...}
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_report_revisions_report_id ON report_revisions(report_id, created_at);
+CREATE INDEX idx_report_revisions_changed_by ON report_revisions(changed_by);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS boycott_campaign_milestones;
+DROP TABLE IF EXISTS boycott_campaign_pledges;
+DROP TABLE IF EXISTS boycott_campaign_targets;
+DROP TABLE IF EXISTS boycott_campaigns;
@@ -0,0 +1,52 @@
+-- Organized boycotts: a campaign targets a list of businesses or every location of a brand, and
+-- users pledge to the campaign
+CREATE TABLE boycott_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL,
+    target_type VARCHAR(20) NOT NULL CHECK (target_type IN ('businesses', 'brand')),
+    brand_name VARCHAR(500), -- Set for brand campaigns
+    goal_pledges INTEGER NOT NULL CHECK (goal_pledges > 0),
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'closed')),
+    pledge_count INTEGER NOT NULL DEFAULT 0, -- Active pledges
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (target_type <> 'brand' OR brand_name IS NOT NULL)
+);
+
+CREATE INDEX idx_boycott_campaigns_status ON boycott_campaigns(status, ends_at);
+
+-- Businesses targeted by a campaign
+CREATE TABLE boycott_campaign_targets (
+    id SERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES boycott_campaigns(id) ON DELETE CASCADE,
+    business_name VARCHAR(500) NOT NULL,
+    business_address TEXT NOT NULL DEFAULT '',
+    UNIQUE(campaign_id, business_name, business_address)
+);
+
+CREATE INDEX idx_boycott_campaign_targets_business ON boycott_campaign_targets(business_name, business_address);
+
+-- Withdrawn pledges are kept so the campaign's progress can be charted over time
+CREATE TABLE boycott_campaign_pledges (
+    id SERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES boycott_campaigns(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    pledged_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    withdrawn_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE(campaign_id, user_id)
+);
+
+CREATE INDEX idx_boycott_campaign_pledges_user_id ON boycott_campaign_pledges(user_id);
+
+-- Share of the goal reached by a campaign, recorded the first time it is reached
+CREATE TABLE boycott_campaign_milestones (
+    id SERIAL PRIMARY KEY,
+    campaign_id UUID NOT NULL REFERENCES boycott_campaigns(id) ON DELETE CASCADE,
+    percent INTEGER NOT NULL,
+    pledge_count INTEGER NOT NULL,
+    reached_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE(campaign_id, percent)
+);
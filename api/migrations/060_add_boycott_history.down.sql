@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS boycott_events;
+
+DELETE FROM boycotts WHERE cancelled_at IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_boycotts_active_business;
+ALTER TABLE boycotts DROP COLUMN IF EXISTS cancelled_at;
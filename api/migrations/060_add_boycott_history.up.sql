@@ -0,0 +1,24 @@
+-- Cancelled boycotts are kept instead of deleted, and every boycott and cancellation is logged
+-- so activity can be charted over time
+ALTER TABLE boycotts ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX idx_boycotts_active_business ON boycotts(business_name, business_address) WHERE cancelled_at IS NULL;
+
+CREATE TABLE boycott_events (
+    id BIGSERIAL PRIMARY KEY,
+    boycott_id UUID NOT NULL REFERENCES boycotts(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    business_name VARCHAR(500) NOT NULL,
+    business_address TEXT NOT NULL DEFAULT '',
+    event_type VARCHAR(10) NOT NULL CHECK (event_type IN ('boycott', 'cancel')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_boycott_events_created_at ON boycott_events(created_at);
+CREATE INDEX idx_boycott_events_business ON boycott_events(business_name, business_address, created_at);
+CREATE INDEX idx_boycott_events_boycott_id ON boycott_events(boycott_id);
+
+-- Existing boycotts started when they were created, earlier cancellations are lost
+INSERT INTO boycott_events (boycott_id, user_id, business_name, business_address, event_type, created_at)
+SELECT id, user_id, business_name, COALESCE(business_address, ''), 'boycott', COALESCE(created_at, NOW())
+FROM boycotts;
@@ -0,0 +1,4 @@
+DELETE FROM scraper_jobs WHERE job_type = 'verification_tiers';
+
+DROP INDEX IF EXISTS idx_users_ip_addresses;
+DROP TABLE IF EXISTS user_tier_changes;
@@ -0,0 +1,20 @@
+-- Verification tier changes made by the tier engine, with the reasons and the signals it used
+CREATE TABLE user_tier_changes (
+    id SERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_tier VARCHAR(20) NOT NULL,
+    new_tier VARCHAR(20) NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    signals JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_tier_changes_user_id ON user_tier_changes(user_id, created_at);
+
+-- The tier signals count the other users that signed in from one of a user's IP addresses
+CREATE INDEX IF NOT EXISTS idx_users_ip_addresses ON users USING GIN (ip_addresses);
+
+-- Tiers are evaluated on a schedule, tracked like the scrapers
+INSERT INTO scraper_jobs (job_type, next_scheduled_run, status)
+VALUES ('verification_tiers', NOW(), 'pending')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,17 @@
+DROP INDEX IF EXISTS idx_business_claims_status;
+DROP INDEX IF EXISTS idx_business_claims_awaiting_review;
+
+-- Claims that were never approved don't become verified
+UPDATE business_claims SET
+    status = 'revoked',
+    revoked_at = NOW(),
+    revocation_reason = 'Review was not completed',
+    updated_at = NOW()
+WHERE status = 'awaiting_review';
+
+ALTER TABLE business_claims DROP COLUMN IF EXISTS approved_by;
+ALTER TABLE business_claims DROP COLUMN IF EXISTS email_verified_at;
+
+ALTER TABLE business_claims DROP CONSTRAINT IF EXISTS business_claims_status_check;
+ALTER TABLE business_claims ADD CONSTRAINT business_claims_status_check
+    CHECK (status IN ('pending', 'verified', 'revoked'));
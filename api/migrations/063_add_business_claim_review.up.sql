@@ -0,0 +1,20 @@
+-- Opening the emailed link only shows the user can receive email at the domain, not that the domain
+-- belongs to the business. Claims now wait for a moderator's approval before they are verified.
+ALTER TABLE business_claims DROP CONSTRAINT IF EXISTS business_claims_status_check;
+ALTER TABLE business_claims ADD CONSTRAINT business_claims_status_check
+    CHECK (status IN ('pending', 'awaiting_review', 'verified', 'revoked'));
+
+ALTER TABLE business_claims ADD COLUMN email_verified_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE business_claims ADD COLUMN approved_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Claims verified by email alone are reviewed again
+UPDATE business_claims SET
+    status = 'awaiting_review',
+    email_verified_at = verified_at,
+    verified_at = NULL,
+    updated_at = NOW()
+WHERE status = 'verified';
+
+-- A user has one claim awaiting review per business
+CREATE UNIQUE INDEX idx_business_claims_awaiting_review ON business_claims(user_id, normalized_name, normalized_address) WHERE status = 'awaiting_review';
+CREATE INDEX idx_business_claims_status ON business_claims(status, updated_at);
@@ -11,6 +11,11 @@ type AddressGeocodingCache struct {
 	Latitude          float64   `json:"latitude" db:"latitude"`
 	Longitude         float64   `json:"longitude" db:"longitude"`
 	Confidence        *float64  `json:"confidence" db:"confidence"`
+	Provider          *string   `json:"provider" db:"provider"` // Geocoder that produced the coordinates
+	Precision         *string   `json:"precision" db:"precision"`
+	MatchType         *string   `json:"match_type" db:"match_type"`
+	Layer             *string   `json:"layer" db:"layer"`
+	ManualOverride    bool      `json:"manual_override" db:"manual_override"` // Accepted or pinned by a reviewer
 	GeocodedAt        time.Time `json:"geocoded_at" db:"geocoded_at"`
 	CreatedAt         time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Scopes of an API token. Session cookies aren't limited by scopes.
+const (
+	ScopeReadLMIA     = "read:lmia"
+	ScopeReadJobs     = "read:jobs"
+	ScopeReadReports  = "read:reports" // Reports and their attachments, including the user's own unpublished ones
+	ScopeWriteReports = "write:reports"
+	ScopeReadBoycotts = "read:boycotts" // Boycotts, campaigns and the user's own boycotts and pledges
+	ScopeAdmin        = "admin:*"       // Moderation and admin endpoints, and every other scope
+)
+
+// APITokenScopes lists every API token scope
+var APITokenScopes = []string{ScopeReadLMIA, ScopeReadJobs, ScopeReadReports, ScopeWriteReports, ScopeReadBoycotts, ScopeAdmin}
+
+// APIToken is a personal token scripts use to call the API as their user. The token itself is
+// only returned when it's created, Token is empty otherwise.
+type APIToken struct {
+	ID          string         `json:"id" db:"id"`
+	UserID      string         `json:"user_id" db:"user_id"`
+	Name        string         `json:"name" db:"name"`
+	TokenPrefix string         `json:"token_prefix" db:"token_prefix"`
+	TokenHash   string         `json:"-" db:"token_hash"`
+	Scopes      pq.StringArray `json:"scopes" db:"scopes"`
+	ExpiresAt   time.Time      `json:"expires_at" db:"expires_at"`
+	LastUsedAt  *time.Time     `json:"last_used_at,omitempty" db:"last_used_at"`
+	LastUsedIP  *string        `json:"last_used_ip,omitempty" db:"last_used_ip"`
+	RevokedAt   *time.Time     `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+
+	Token string `json:"token,omitempty" db:"-"`
+}
+
+// IsActive reports whether the token can still be used
+func (t *APIToken) IsActive(now time.Time) bool {
+	return t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
+
+// HasScope reports whether the token grants the scope. The admin scope grants every scope.
+func (t *APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope) || slices.Contains(t.Scopes, ScopeAdmin)
+}
@@ -2,17 +2,57 @@ package models
 
 import "time"
 
+// Kinds of boycott events, logged each time a user starts or cancels a boycott
+const (
+	BoycottEventStart  = "boycott"
+	BoycottEventCancel = "cancel"
+)
+
 type Boycott struct {
 	ID              string    `json:"id" db:"id"`
 	UserID          string    `json:"user_id" db:"user_id"`
 	BusinessName    string    `json:"business_name" db:"business_name"`
 	BusinessAddress *string   `json:"business_address" db:"business_address"`
+	ParsedAddressID *string   `json:"parsed_address_id" db:"parsed_address_id"`
 	CreatedAt       time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+
+	// Set while the boycott is cancelled, cancelled boycotts are kept for their history
+	CancelledAt *time.Time `json:"cancelled_at,omitempty" db:"cancelled_at"`
 }
 
 type BoycottStats struct {
 	BusinessName    string `json:"business_name" db:"business_name"`
 	BusinessAddress string `json:"business_address" db:"business_address"`
 	BoycottCount    int    `json:"boycott_count" db:"boycott_count"`
-}
\ No newline at end of file
+}
+
+// BoycottActivityPoint is the boycott activity in one day or week
+type BoycottActivityPoint struct {
+	Period        time.Time `json:"period" db:"period"`
+	NewBoycotts   int       `json:"new_boycotts" db:"new_boycotts"`
+	Cancellations int       `json:"cancellations" db:"cancellations"`
+	NetBoycotts   int       `json:"net_boycotts" db:"net_boycotts"`
+}
+
+// TrendingBoycott is a business's boycott activity in a recent window, compared with the window
+// before it
+type TrendingBoycott struct {
+	BusinessName        string `json:"business_name" db:"business_name"`
+	BusinessAddress     string `json:"business_address" db:"business_address"`
+	NewBoycotts         int    `json:"new_boycotts" db:"new_boycotts"`
+	Cancellations       int    `json:"cancellations" db:"cancellations"`
+	NetBoycotts         int    `json:"net_boycotts" db:"net_boycotts"`
+	PreviousNewBoycotts int    `json:"previous_new_boycotts" db:"previous_new_boycotts"`
+	ActiveBoycotts      int    `json:"active_boycotts" db:"active_boycotts"`
+}
+
+// BoycottRegionStats rolls up boycotts by the province, or municipality, of the business address
+type BoycottRegionStats struct {
+	ProvinceCode   string  `json:"province_code" db:"province_code"`
+	Municipality   *string `json:"municipality,omitempty" db:"municipality"`
+	ActiveBoycotts int     `json:"active_boycotts" db:"active_boycotts"`
+	Businesses     int     `json:"businesses" db:"businesses"` // With active boycotts
+	NewBoycotts    int     `json:"new_boycotts" db:"new_boycotts"`
+	Cancellations  int     `json:"cancellations" db:"cancellations"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// What a boycott campaign targets
+const (
+	CampaignTargetBusinesses = "businesses" // The businesses listed in its targets
+	CampaignTargetBrand      = "brand"      // Every business operating under a brand name
+)
+
+// Boycott campaign statuses. Active campaigns take pledges until they end.
+const (
+	CampaignStatusActive = "active"
+	CampaignStatusClosed = "closed"
+)
+
+// CampaignMilestonePercents are the shares of its goal a campaign celebrates reaching
+var CampaignMilestonePercents = []int{10, 25, 50, 75, 100}
+
+// BoycottCampaign is an organized boycott of one or more businesses or of a brand, with a goal
+// number of pledges
+type BoycottCampaign struct {
+	ID          string    `json:"id" db:"id"`
+	Title       string    `json:"title" db:"title"`
+	Description string    `json:"description" db:"description"`
+	TargetType  string    `json:"target_type" db:"target_type"`
+	BrandName   *string   `json:"brand_name,omitempty" db:"brand_name"`
+	GoalPledges int       `json:"goal_pledges" db:"goal_pledges"`
+	EndsAt      time.Time `json:"ends_at" db:"ends_at"`
+	Status      string    `json:"status" db:"status"`
+	PledgeCount int       `json:"pledge_count" db:"pledge_count"`
+	CreatedBy   *string   `json:"created_by" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+
+	Targets []*BoycottCampaignTarget `json:"targets" db:"-"`
+	// Whether the viewing user pledged, nil for anonymous viewers
+	Pledged *bool `json:"pledged,omitempty" db:"-"`
+}
+
+// IsOpen reports whether the campaign still takes pledges
+func (c *BoycottCampaign) IsOpen(now time.Time) bool {
+	return c.Status == CampaignStatusActive && now.Before(c.EndsAt)
+}
+
+// MilestoneThreshold is the number of pledges needed to reach a share of the campaign's goal
+func (c *BoycottCampaign) MilestoneThreshold(percent int) int {
+	return (c.GoalPledges*percent + 99) / 100
+}
+
+type BoycottCampaignTarget struct {
+	ID              int    `json:"id" db:"id"`
+	CampaignID      string `json:"campaign_id" db:"campaign_id"`
+	BusinessName    string `json:"business_name" db:"business_name"`
+	BusinessAddress string `json:"business_address" db:"business_address"`
+}
+
+// BoycottCampaignPledge is a user's pledge to a campaign. Withdrawn pledges are kept for the
+// campaign's progress history.
+type BoycottCampaignPledge struct {
+	ID          int        `json:"id" db:"id"`
+	CampaignID  string     `json:"campaign_id" db:"campaign_id"`
+	UserID      string     `json:"user_id" db:"user_id"`
+	PledgedAt   time.Time  `json:"pledged_at" db:"pledged_at"`
+	WithdrawnAt *time.Time `json:"withdrawn_at" db:"withdrawn_at"`
+}
+
+// BoycottCampaignMilestone records when a campaign first reached a share of its goal
+type BoycottCampaignMilestone struct {
+	ID          int       `json:"id" db:"id"`
+	CampaignID  string    `json:"campaign_id" db:"campaign_id"`
+	Percent     int       `json:"percent" db:"percent"`
+	PledgeCount int       `json:"pledge_count" db:"pledge_count"`
+	ReachedAt   time.Time `json:"reached_at" db:"reached_at"`
+}
+
+// CampaignProgressPoint is a campaign's pledges on one day
+type CampaignProgressPoint struct {
+	Day         time.Time `json:"day" db:"day"`
+	PledgeCount int       `json:"pledge_count" db:"pledge_count"` // Active pledges at the end of the day
+	NewPledges  int       `json:"new_pledges" db:"new_pledges"`
+	Withdrawals int       `json:"withdrawals" db:"withdrawals"`
+}
+
+// BoycottCampaignStats is a campaign's progress towards its goal
+type BoycottCampaignStats struct {
+	CampaignID     string                      `json:"campaign_id"`
+	PledgeCount    int                         `json:"pledge_count"`
+	GoalPledges    int                         `json:"goal_pledges"`
+	PercentOfGoal  float64                     `json:"percent_of_goal"`
+	GoalReached    bool                        `json:"goal_reached"`
+	DaysRemaining  int                         `json:"days_remaining"`
+	TotalPledges   int                         `json:"total_pledges"` // Including withdrawn pledges
+	WithdrawnCount int                         `json:"withdrawn_count"`
+	Milestones     []*BoycottCampaignMilestone `json:"milestones"`
+	NextMilestone  *int                        `json:"next_milestone"` // Percent of the goal
+	DailyProgress  []*CampaignProgressPoint    `json:"daily_progress"`
+}
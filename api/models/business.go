@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+// Status of a business claim
+const (
+	BusinessClaimPending        = "pending"         // The verification link wasn't opened yet
+	BusinessClaimAwaitingReview = "awaiting_review" // The email was confirmed, a moderator hasn't approved it yet
+	BusinessClaimVerified       = "verified"
+	BusinessClaimRevoked        = "revoked"
+)
+
+// Moderation status of a business reply. Only published replies are shown publicly.
+const (
+	BusinessReplyPending   = "pending"
+	BusinessReplyPublished = "published"
+	BusinessReplyRejected  = "rejected"
+)
+
+// BusinessReplyStatuses lists every business reply status
+var BusinessReplyStatuses = []string{BusinessReplyPending, BusinessReplyPublished, BusinessReplyRejected}
+
+// BusinessClaim is a user's claim to represent a business named in reports. The user opens a link
+// sent to an address at the business's email domain, then a moderator checks that the domain
+// belongs to the business before the claim is verified.
+type BusinessClaim struct {
+	ID                string     `json:"id" db:"id"`
+	UserID            string     `json:"user_id" db:"user_id"`
+	BusinessName      string     `json:"business_name" db:"business_name"`
+	NormalizedName    string     `json:"-" db:"normalized_name"`
+	BusinessAddress   string     `json:"business_address" db:"business_address"`
+	NormalizedAddress string     `json:"-" db:"normalized_address"`
+	Email             string     `json:"email" db:"email"`
+	EmailDomain       string     `json:"email_domain" db:"email_domain"`
+	TokenHash         string     `json:"-" db:"token_hash"`
+	TokenExpiresAt    time.Time  `json:"-" db:"token_expires_at"`
+	Status            string     `json:"status" db:"status"`
+	EmailVerifiedAt   *time.Time `json:"email_verified_at,omitempty" db:"email_verified_at"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty" db:"verified_at"`
+	ApprovedBy        *string    `json:"approved_by,omitempty" db:"approved_by"`
+	RevokedBy         *string    `json:"revoked_by,omitempty" db:"revoked_by"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevocationReason  *string    `json:"revocation_reason,omitempty" db:"revocation_reason"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// BusinessReply is a verified business's response to a report, or its general statement when
+// ReportID is nil
+type BusinessReply struct {
+	ID              string     `json:"id" db:"id"`
+	ClaimID         string     `json:"claim_id" db:"claim_id"`
+	ReportID        *string    `json:"report_id" db:"report_id"`
+	Body            string     `json:"body" db:"body"`
+	Status          string     `json:"status" db:"status"`
+	ModeratedBy     *string    `json:"moderated_by,omitempty" db:"moderated_by"`
+	ModeratedAt     *time.Time `json:"moderated_at,omitempty" db:"moderated_at"`
+	ModerationNotes *string    `json:"moderation_notes,omitempty" db:"moderation_notes"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+
+	// From the claim
+	BusinessName    string `json:"business_name" db:"business_name"`
+	BusinessAddress string `json:"business_address" db:"business_address"`
+}
+
+// PublicBusinessReply is a published business reply as shown with reports. Replies are only shown
+// for verified claims, which clients display as a verified badge.
+type PublicBusinessReply struct {
+	BusinessName     string    `json:"business_name"`
+	Body             string    `json:"body"`
+	VerifiedBusiness bool      `json:"verified_business"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// Public returns the reply as shown with reports
+func (r *BusinessReply) Public() *PublicBusinessReply {
+	return &PublicBusinessReply{
+		BusinessName:     r.BusinessName,
+		Body:             r.Body,
+		VerifiedBusiness: true,
+		UpdatedAt:        r.UpdatedAt,
+	}
+}
+
+// BusinessReplyModeration is a moderator's decision on a business reply
+type BusinessReplyModeration struct {
+	Status      string
+	ModeratorID string
+	Notes       *string
+}
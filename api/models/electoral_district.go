@@ -0,0 +1,92 @@
+package models
+
+import (
+	"canada-hires/geo"
+	"time"
+)
+
+// ElectoralDistrict is a federal electoral district (riding)
+type ElectoralDistrict struct {
+	ID           string           `json:"id" db:"id"`
+	NameEN       string           `json:"name_en" db:"name_en"`
+	NameFR       *string          `json:"name_fr" db:"name_fr"`
+	ProvinceCode *string          `json:"province_code" db:"province_code"`
+	MinLatitude  float64          `json:"min_latitude" db:"min_latitude"`
+	MinLongitude float64          `json:"min_longitude" db:"min_longitude"`
+	MaxLatitude  float64          `json:"max_latitude" db:"max_latitude"`
+	MaxLongitude float64          `json:"max_longitude" db:"max_longitude"`
+	Boundary     geo.MultiPolygon `json:"-" db:"boundary"`
+	SourceFile   *string          `json:"source_file,omitempty" db:"source_file"`
+	ImportedAt   time.Time        `json:"imported_at" db:"imported_at"`
+}
+
+// ElectoralDistrictAssignment links a geocoded record to the district it falls in
+type ElectoralDistrictAssignment struct {
+	Key        string // Postal code, address cache ID or job location, depending on the dataset
+	DistrictID string
+}
+
+// GeoPoint is a pair of coordinates shared by one or more records
+type GeoPoint struct {
+	Key       string  `db:"key"`
+	Latitude  float64 `db:"latitude"`
+	Longitude float64 `db:"longitude"`
+}
+
+// ElectoralDistrictSummary totals the LMIA approvals, non-compliant employers and job postings
+// located in a district
+type ElectoralDistrictSummary struct {
+	District     *ElectoralDistrict                 `json:"district"`
+	Year         *int                               `json:"year,omitempty"`
+	LMIA         ElectoralDistrictLMIATotals        `json:"lmia"`
+	LMIAByYear   []ElectoralDistrictLMIAYear        `json:"lmia_by_year"`
+	TopEmployers []ElectoralDistrictEmployer        `json:"top_employers"`
+	NonCompliant ElectoralDistrictNonCompliantTotal `json:"non_compliant"`
+	JobPostings  ElectoralDistrictJobTotals         `json:"job_postings"`
+}
+
+type ElectoralDistrictLMIATotals struct {
+	Employers         int `json:"employers" db:"employers"`
+	ApprovedLMIAs     int `json:"approved_lmias" db:"approved_lmias"`
+	ApprovedPositions int `json:"approved_positions" db:"approved_positions"`
+}
+
+type ElectoralDistrictLMIAYear struct {
+	Year              int `json:"year" db:"year"`
+	Employers         int `json:"employers" db:"employers"`
+	ApprovedLMIAs     int `json:"approved_lmias" db:"approved_lmias"`
+	ApprovedPositions int `json:"approved_positions" db:"approved_positions"`
+}
+
+type ElectoralDistrictEmployer struct {
+	Employer          string `json:"employer" db:"employer"`
+	ApprovedLMIAs     int    `json:"approved_lmias" db:"approved_lmias"`
+	ApprovedPositions int    `json:"approved_positions" db:"approved_positions"`
+}
+
+type ElectoralDistrictNonCompliantTotal struct {
+	Employers          int        `json:"employers" db:"employers"`
+	TotalPenaltyAmount int64      `json:"total_penalty_amount" db:"total_penalty_amount"`
+	LatestDecision     *time.Time `json:"latest_decision" db:"latest_decision"`
+}
+
+type ElectoralDistrictJobTotals struct {
+	Total        int `json:"total" db:"total"`
+	TFWPostings  int `json:"tfw_postings" db:"tfw_postings"`
+	LMIAPostings int `json:"lmia_postings" db:"lmia_postings"`
+}
+
+// Datasets whose geocoded points are assigned to electoral districts. LMIA and non-compliant
+// employers are placed through the postal codes and geocoded addresses they use.
+const (
+	DistrictDatasetPostalCodes = "postal_codes"
+	DistrictDatasetAddresses   = "addresses"
+	DistrictDatasetJobPostings = "job_postings"
+)
+
+// DistrictDatasets lists every dataset assigned to electoral districts
+var DistrictDatasets = []string{
+	DistrictDatasetPostalCodes,
+	DistrictDatasetAddresses,
+	DistrictDatasetJobPostings,
+}
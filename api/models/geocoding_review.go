@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+)
+
+// What a geocoding review is about
+const (
+	GeocodingTargetPostalCode = "postal_code"
+	GeocodingTargetAddress    = "address"
+)
+
+// Why a geocoding result was sent to review
+const (
+	GeocodingReviewLowConfidence      = "low_confidence"
+	GeocodingReviewProvinceMismatch   = "province_mismatch"
+	GeocodingReviewPostalCodeMismatch = "postal_code_mismatch"
+)
+
+// Status of a geocoding review
+const (
+	GeocodingReviewPending  = "pending"
+	GeocodingReviewAccepted = "accepted" // The result was confirmed and stored as a manual override
+	GeocodingReviewRejected = "rejected" // The result was discarded and won't be stored again
+	GeocodingReviewPinned   = "pinned"   // The reviewer placed the coordinates by hand
+)
+
+// GeocodingReviewStatuses lists the review statuses that can be filtered on
+var GeocodingReviewStatuses = []string{
+	GeocodingReviewPending,
+	GeocodingReviewAccepted,
+	GeocodingReviewRejected,
+	GeocodingReviewPinned,
+}
+
+// GeocodingReview is a geocoding result waiting for, or resolved by, a reviewer
+type GeocodingReview struct {
+	ID                   int        `json:"id" db:"id"`
+	TargetType           string     `json:"target_type" db:"target_type"`
+	TargetKey            string     `json:"target_key" db:"target_key"` // Postal code or normalized address
+	Query                string     `json:"query" db:"query"`
+	ExpectedProvinceCode *string    `json:"expected_province_code" db:"expected_province_code"`
+	Provider             string     `json:"provider" db:"provider"`
+	Latitude             float64    `json:"latitude" db:"latitude"`
+	Longitude            float64    `json:"longitude" db:"longitude"`
+	Confidence           *float64   `json:"confidence" db:"confidence"`
+	Precision            *string    `json:"precision" db:"precision"`
+	MatchType            *string    `json:"match_type" db:"match_type"`
+	Layer                *string    `json:"layer" db:"layer"`
+	ResultPostalCode     *string    `json:"result_postal_code" db:"result_postal_code"`
+	ResultProvinceCode   *string    `json:"result_province_code" db:"result_province_code"`
+	Reason               string     `json:"reason" db:"reason"`
+	Detail               *string    `json:"detail" db:"detail"`
+	Stored               bool       `json:"stored" db:"stored"` // Whether the result was saved, and shown on the map, while pending
+	Status               string     `json:"status" db:"status"`
+	ReviewedBy           *string    `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedAt           *time.Time `json:"reviewed_at" db:"reviewed_at"`
+	ReviewNotes          *string    `json:"review_notes" db:"review_notes"`
+	CreatedAt            time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// GeocodingReviewFilter selects reviews for the review queue
+type GeocodingReviewFilter struct {
+	Status     string
+	Reason     string
+	TargetType string
+	Limit      int
+	Offset     int
+}
+
+// GeocodingReviewDecision is a reviewer's resolution of a review. Latitude and Longitude are only
+// set when the coordinates are pinned by hand.
+type GeocodingReviewDecision struct {
+	Status     string
+	ReviewerID string
+	Notes      *string
+	Latitude   float64
+	Longitude  float64
+}
@@ -0,0 +1,54 @@
+package models
+
+// GeoJSON types, see RFC 7946
+const (
+	GeoJSONFeatureCollection = "FeatureCollection"
+	GeoJSONFeature           = "Feature"
+	GeoJSONPoint             = "Point"
+)
+
+// FeatureCollection is a GeoJSON FeatureCollection
+type FeatureCollection struct {
+	Type     string     `json:"type"`
+	BBox     []float64  `json:"bbox,omitempty"`
+	Features []*Feature `json:"features"`
+}
+
+// Feature is a GeoJSON Feature
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"id,omitempty"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON geometry. Only points are used so far.
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+// NewFeatureCollection creates an empty collection covering a bounding box
+func NewFeatureCollection(bbox *BoundingBox) *FeatureCollection {
+	collection := &FeatureCollection{
+		Type:     GeoJSONFeatureCollection,
+		Features: []*Feature{},
+	}
+	if bbox != nil {
+		collection.BBox = []float64{bbox.MinLongitude, bbox.MinLatitude, bbox.MaxLongitude, bbox.MaxLatitude}
+	}
+	return collection
+}
+
+// NewPointFeature creates a point feature. GeoJSON positions are longitude first.
+func NewPointFeature(id string, latitude, longitude float64, properties map[string]interface{}) *Feature {
+	return &Feature{
+		Type: GeoJSONFeature,
+		ID:   id,
+		Geometry: Geometry{
+			Type:        GeoJSONPoint,
+			Coordinates: []float64{longitude, latitude},
+		},
+		Properties: properties,
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+)
+
+// Name match types for JobLMIAMatch
+const (
+	LMIANameMatchExact      = "exact"
+	LMIANameMatchNormalized = "normalized"
+)
+
+// Location match types for JobLMIAMatch, from most to least specific
+const (
+	LMIALocationMatchPostalCode = "postal_code"
+	LMIALocationMatchCity       = "city"
+	LMIALocationMatchProvince   = "province"
+	LMIALocationMatchNone       = "none"
+)
+
+// JobLMIAMatch links a Job Bank posting to an LMIA approval row of the same employer
+type JobLMIAMatch struct {
+	ID             string    `json:"id" db:"id"`
+	JobPostingID   string    `json:"job_posting_id" db:"job_posting_id"`
+	LMIAEmployerID string    `json:"lmia_employer_id" db:"lmia_employer_id"`
+	Confidence     float64   `json:"confidence" db:"confidence"`         // 0 to 1
+	NameMatch      string    `json:"name_match" db:"name_match"`         // exact, normalized
+	LocationMatch  string    `json:"location_match" db:"location_match"` // postal_code, city, province, none
+	NOCMatch       bool      `json:"noc_match" db:"noc_match"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+}
+
+// LMIAApprovalHistory is a matched LMIA approval row as returned by the job APIs
+type LMIAApprovalHistory struct {
+	LMIAEmployerID    string  `json:"lmia_employer_id" db:"lmia_employer_id"`
+	Employer          string  `json:"employer" db:"employer"`
+	Address           *string `json:"address" db:"address"`
+	ProvinceTerritory *string `json:"province_territory" db:"province_territory"`
+	ProgramStream     *string `json:"program_stream" db:"program_stream"`
+	Occupation        *string `json:"occupation" db:"occupation"`
+	ApprovedLMIAs     *int    `json:"approved_lmias" db:"approved_lmias"`
+	ApprovedPositions *int    `json:"approved_positions" db:"approved_positions"`
+	Quarter           string  `json:"quarter" db:"quarter"`
+	Year              int     `json:"year" db:"year"`
+	Confidence        float64 `json:"confidence" db:"confidence"`
+	LocationMatch     string  `json:"location_match" db:"location_match"`
+	NOCMatch          bool    `json:"noc_match" db:"noc_match"`
+}
+
+// LMIAMatchStats reports how many current postings are linked to LMIA approvals
+type LMIAMatchStats struct {
+	CurrentPostings      int     `json:"current_postings" db:"current_postings"`
+	MatchedPostings      int     `json:"matched_postings" db:"matched_postings"`
+	RepeatUserPostings   int     `json:"repeat_user_postings" db:"repeat_user_postings"`
+	MatchedPercentage    float64 `json:"matched_percentage" db:"-"`
+	RepeatUserPercentage float64 `json:"repeat_user_percentage" db:"-"` // Share of current postings from employers with approvals in more than one quarter
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"canada-hires/address"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -24,6 +26,34 @@ type JobPosting struct {
 	SalaryMax    *float64   `json:"salary_max" db:"salary_max"`         // Maximum salary
 	SalaryType   *string    `json:"salary_type" db:"salary_type"`       // hourly, weekly, biweekly, monthly, yearly
 	SalaryRaw    *string    `json:"salary_raw" db:"salary_raw"`         // Original salary string from scraper
+	HoursPerWeek          *float64   `json:"hours_per_week" db:"hours_per_week"`                   // Hours per week from the detail page (nullable)
+	SalaryHourlyMin       *float64   `json:"salary_hourly_min" db:"salary_hourly_min"`             // Minimum salary as an hourly equivalent
+	SalaryHourlyMax       *float64   `json:"salary_hourly_max" db:"salary_hourly_max"`             // Maximum salary as an hourly equivalent
+	SalaryAnnualMin       *float64   `json:"salary_annual_min" db:"salary_annual_min"`             // Minimum salary as an annual equivalent
+	SalaryAnnualMax       *float64   `json:"salary_annual_max" db:"salary_annual_max"`             // Maximum salary as an annual equivalent
+	NOCCode               *string    `json:"noc_code" db:"noc_code"`                               // NOC code from the detail page or matched title
+	EconomicRegionCode    *string    `json:"economic_region_code" db:"economic_region_code"`       // Economic region used for the wage comparison
+	MedianWage            *float64   `json:"median_wage" db:"median_wage"`                         // Hourly median wage for the NOC and region
+	WagePercentOfMedian   *float64   `json:"wage_percent_of_median" db:"wage_percent_of_median"`   // Offered hourly wage as a percentage of the median
+	BelowMedianWage       *bool      `json:"below_median_wage" db:"below_median_wage"`             // Whether the offered wage is below the median
+	Fingerprint           *string    `json:"fingerprint" db:"fingerprint"`                         // Repost fingerprint (employer, title, location, salary)
+	FamilyID              *string    `json:"family_id" db:"family_id"`                             // Posting family this posting belongs to
+	RepostCount           int        `json:"repost_count" db:"repost_count"`                       // Times the ad was reposted after the original
+	RepostedSince         *time.Time `json:"reposted_since" db:"reposted_since"`                   // Posting date of the original ad in the family
+	LMIAMatchConfidence       *float64   `json:"lmia_match_confidence" db:"lmia_match_confidence"`               // Best confidence of the link to LMIA approvals
+	LMIAHistoricalApprovals   *int       `json:"lmia_historical_approvals" db:"lmia_historical_approvals"`       // Approved LMIAs across matched approval rows
+	LMIAHistoricalPositions   *int       `json:"lmia_historical_positions" db:"lmia_historical_positions"`       // Approved positions across matched approval rows
+	LMIAApprovalPeriods       *int       `json:"lmia_approval_periods" db:"lmia_approval_periods"`               // Distinct quarters with approvals
+	IsRepeatLMIAUser          *bool      `json:"is_repeat_lmia_user" db:"is_repeat_lmia_user"`                   // Employer has approvals in more than one quarter
+	LMIAMatchedAt             *time.Time `json:"lmia_matched_at" db:"lmia_matched_at"`                           // When the posting was last matched
+	NonCompliantEmployerID    *string    `json:"non_compliant_employer_id" db:"non_compliant_employer_id"`       // Most recent non-compliance decision for the employer
+	IsNonCompliantEmployer    bool       `json:"is_non_compliant_employer" db:"is_non_compliant_employer"`       // Employer is on the IRCC non-compliant list
+	NonCompliantDecisionDate  *time.Time `json:"non_compliant_decision_date" db:"non_compliant_decision_date"`   // Date of the final decision
+	NonCompliantPenaltyAmount *int       `json:"non_compliant_penalty_amount" db:"non_compliant_penalty_amount"` // Penalty from the decision
+	Latitude                  *float64   `json:"latitude" db:"latitude"`                                         // Geocoded from the location
+	Longitude                 *float64   `json:"longitude" db:"longitude"`                                       // Geocoded from the location
+	GeocodedAt                *time.Time `json:"geocoded_at" db:"geocoded_at"`                                   // When the location was geocoded
+	ElectoralDistrictID       *string    `json:"electoral_district_id" db:"electoral_district_id"`               // Federal riding the location falls in
 	PostingDate  *time.Time `json:"posting_date" db:"posting_date"`     // When job was posted
 	URL          string     `json:"url" db:"url"`                       // Link to job posting
 	IsTFW                 bool       `json:"is_tfw" db:"is_tfw"`                                   // Whether this is a TFW position
@@ -40,6 +70,12 @@ type JobPosting struct {
 	
 	// Joined data when querying with subreddit posting information
 	SubredditPosts []JobSubredditPost `json:"subreddit_posts,omitempty"`
+
+	// Computed by SetRepostSummary, e.g. "Reposted 3 times since Jan 2, 2025"
+	RepostSummary string `json:"repost_summary,omitempty" db:"-"`
+
+	// Computed by SetNonCompliantNotice, e.g. "Employer found non-compliant on Mar 3, 2023 ($5,000 penalty)"
+	NonCompliantNotice string `json:"non_compliant_notice,omitempty" db:"-"`
 }
 
 // ScraperJobData represents the data structure from your scraper
@@ -75,13 +111,32 @@ func NewJobPostingFromScraperData(scraperData ScraperJobData, scrapingRunID stri
 
 	// Parse salary information
 	job.parseSalary()
+	job.NormalizeSalary()
 
 	// Parse location into city and province
 	job.parseLocation()
 
+	// Older scraper payloads omit the job bank ID, but it is always part of the posting URL
+	if job.JobBankID == nil || *job.JobBankID == "" {
+		job.JobBankID = jobBankIDFromURL(job.URL)
+	}
+
+	fingerprint := job.ComputeFingerprint()
+	job.Fingerprint = &fingerprint
+
 	return job
 }
 
+// jobBankIDFromURL extracts the job bank ID from a posting URL such as /jobsearch/jobpostingtfw/12345678
+func jobBankIDFromURL(url string) *string {
+	re := regexp.MustCompile(`/jobposting(?:tfw)?/(\d+)`)
+	matches := re.FindStringSubmatch(url)
+	if len(matches) < 2 {
+		return nil
+	}
+	return &matches[1]
+}
+
 // truncateString safely truncates a string to a maximum length
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -140,52 +195,125 @@ func (jp *JobPosting) parseSalary() {
 	}
 }
 
+// DefaultHoursPerWeek is assumed when the detail page doesn't state the weekly hours
+const DefaultHoursPerWeek = 40.0
+
+// weeksPerYear is used to convert between hourly, weekly and annual pay
+const weeksPerYear = 52.0
+
+// NormalizeSalary fills the hourly and annual salary equivalents from SalaryMin, SalaryMax
+// and SalaryType, using HoursPerWeek when known and DefaultHoursPerWeek otherwise
+func (jp *JobPosting) NormalizeSalary() {
+	jp.SalaryHourlyMin, jp.SalaryAnnualMin = nil, nil
+	jp.SalaryHourlyMax, jp.SalaryAnnualMax = nil, nil
+
+	if jp.SalaryType == nil {
+		return
+	}
+
+	hoursPerWeek := DefaultHoursPerWeek
+	if jp.HoursPerWeek != nil && *jp.HoursPerWeek > 0 {
+		hoursPerWeek = *jp.HoursPerWeek
+	}
+
+	jp.SalaryHourlyMin, jp.SalaryAnnualMin = normalizeSalaryAmount(jp.SalaryMin, *jp.SalaryType, hoursPerWeek)
+	jp.SalaryHourlyMax, jp.SalaryAnnualMax = normalizeSalaryAmount(jp.SalaryMax, *jp.SalaryType, hoursPerWeek)
+}
+
+// normalizeSalaryAmount converts a single salary amount of the given type to hourly and annual equivalents
+func normalizeSalaryAmount(amount *float64, salaryType string, hoursPerWeek float64) (hourly *float64, annual *float64) {
+	if amount == nil {
+		return nil, nil
+	}
+
+	var annualAmount float64
+	switch salaryType {
+	case "yearly":
+		annualAmount = *amount
+	case "monthly":
+		annualAmount = *amount * 12
+	case "biweekly":
+		annualAmount = *amount * weeksPerYear / 2
+	case "weekly":
+		annualAmount = *amount * weeksPerYear
+	default: // hourly
+		annualAmount = *amount * hoursPerWeek * weeksPerYear
+	}
+
+	hourlyAmount := roundToCents(annualAmount / (hoursPerWeek * weeksPerYear))
+	annualAmount = roundToCents(annualAmount)
+
+	return &hourlyAmount, &annualAmount
+}
+
+// roundToCents rounds a dollar amount to two decimal places
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// ApplyPrevailingWage compares the top of the offered hourly range with the prevailing median wage.
+// The top of the range is used so a posting is only flagged when its best offer is below the median.
+// Both sides are converted to hourly rates with the posting's weekly hours when they are known.
+func (jp *JobPosting) ApplyPrevailingWage(wage *PrevailingWage) {
+	jp.MedianWage, jp.WagePercentOfMedian, jp.BelowMedianWage = nil, nil, nil
+	jp.EconomicRegionCode = nil
+
+	if wage == nil {
+		return
+	}
+
+	hoursPerWeek := 0.0
+	if jp.HoursPerWeek != nil {
+		hoursPerWeek = *jp.HoursPerWeek
+	}
+	median := wage.MedianHourly(hoursPerWeek)
+	if median == nil || *median <= 0 {
+		return
+	}
+
+	// Stored hourly rates may predate the hours read from the detail page
+	if jp.SalaryType != nil {
+		jp.NormalizeSalary()
+	}
+
+	regionCode := wage.EconomicRegionCode
+	jp.EconomicRegionCode = &regionCode
+	jp.MedianWage = median
+
+	offered := jp.SalaryHourlyMax
+	if offered == nil {
+		offered = jp.SalaryHourlyMin
+	}
+	if offered == nil {
+		return
+	}
+
+	percent := roundToCents(*offered / *median * 100)
+	below := *offered < *median
+	jp.WagePercentOfMedian = &percent
+	jp.BelowMedianWage = &below
+}
+
 // parseLocation extracts city and province from location string
 func (jp *JobPosting) parseLocation() {
 	if jp.Location == "" {
 		return
 	}
 
-	// Split location by common separators
-	parts := strings.Split(jp.Location, ",")
-	if len(parts) >= 2 {
-		city := strings.TrimSpace(parts[0])
-		province := strings.TrimSpace(parts[len(parts)-1])
-		
-		// Convert province names to codes for consistency
-		province = normalizeProvince(province)
-		
-		if city != "" {
-			city = truncateString(city, 150) // Match new DB constraint
-			jp.City = &city
-		}
-		if province != "" {
-			jp.Province = &province
-		}
+	city, province := address.ParseLocation(jp.Location)
+	if city != "" {
+		city = truncateString(city, 150) // Match new DB constraint
+		jp.City = &city
+	}
+	if province != "" {
+		jp.Province = &province
 	}
 }
 
-// normalizeProvince converts full province names to standard codes or keeps them as-is
-func normalizeProvince(province string) string {
-	provinceMap := map[string]string{
-		"alberta":                     "AB",
-		"british columbia":            "BC",
-		"manitoba":                    "MB",
-		"new brunswick":               "NB",
-		"newfoundland and labrador":   "NL",
-		"northwest territories":       "NT",
-		"nova scotia":                 "NS",
-		"nunavut":                     "NU",
-		"ontario":                     "ON",
-		"prince edward island":        "PE",
-		"quebec":                      "QC",
-		"saskatchewan":                "SK",
-		"yukon":                       "YT",
-	}
-	
-	// Try to match the full name first
-	lowerProvince := strings.ToLower(strings.TrimSpace(province))
-	if code, exists := provinceMap[lowerProvince]; exists {
+// NormalizeProvince converts full province names to standard codes or keeps them as-is
+func NormalizeProvince(province string) string {
+	// Try to match a province name or abbreviation first
+	if code, ok := address.ProvinceCode(province); ok {
 		return code
 	}
 	
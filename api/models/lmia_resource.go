@@ -38,12 +38,18 @@ type LMIAEmployer struct {
 	ApprovedLMIAs     *int    `json:"approved_lmias" db:"approved_lmias"`         // "Approved LMIAs"
 	ApprovedPositions *int    `json:"approved_positions" db:"approved_positions"` // "Approved Positions"
 
+	// Derived matching field, see NormalizeEmployerName
+	NormalizedEmployer *string `json:"normalized_employer" db:"normalized_employer"`
+
 	// Derived geocoding fields
 	PostalCode *string    `json:"postal_code" db:"postal_code"`
 	Latitude   *float64   `json:"latitude" db:"latitude"`
 	Longitude  *float64   `json:"longitude" db:"longitude"`
 	GeocodedAt *time.Time `json:"geocoded_at" db:"geocoded_at"`
 
+	// Structured address, see ParsedAddress
+	ParsedAddressID *string `json:"parsed_address_id" db:"parsed_address_id"`
+
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
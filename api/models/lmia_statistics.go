@@ -18,17 +18,19 @@ type RegionData struct {
 }
 
 type LMIAStatistics struct {
-	ID              string          `json:"id" db:"id"`
-	Date            time.Time       `json:"date" db:"date"`
-	PeriodType      PeriodType      `json:"period_type" db:"period_type"`
-	TotalJobs       int             `json:"total_jobs" db:"total_jobs"`
-	UniqueEmployers int             `json:"unique_employers" db:"unique_employers"`
-	AvgSalaryMin    *float64        `json:"avg_salary_min" db:"avg_salary_min"`
-	AvgSalaryMax    *float64        `json:"avg_salary_max" db:"avg_salary_max"`
-	TopProvinces    json.RawMessage `json:"top_provinces" db:"top_provinces"`
-	TopCities       json.RawMessage `json:"top_cities" db:"top_cities"`
-	CreatedAt       time.Time       `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time       `json:"updated_at" db:"updated_at"`
+	ID                 string          `json:"id" db:"id"`
+	Date               time.Time       `json:"date" db:"date"`
+	PeriodType         PeriodType      `json:"period_type" db:"period_type"`
+	TotalJobs          int             `json:"total_jobs" db:"total_jobs"`
+	UniqueEmployers    int             `json:"unique_employers" db:"unique_employers"`
+	AvgSalaryMin       *float64        `json:"avg_salary_min" db:"avg_salary_min"`               // Average hourly equivalent
+	AvgSalaryMax       *float64        `json:"avg_salary_max" db:"avg_salary_max"`               // Average hourly equivalent
+	AvgSalaryAnnualMin *float64        `json:"avg_salary_annual_min" db:"avg_salary_annual_min"` // Average annual equivalent
+	AvgSalaryAnnualMax *float64        `json:"avg_salary_annual_max" db:"avg_salary_annual_max"` // Average annual equivalent
+	TopProvinces       json.RawMessage `json:"top_provinces" db:"top_provinces"`
+	TopCities          json.RawMessage `json:"top_cities" db:"top_cities"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
 }
 
 // GetTopProvinces unmarshals the TopProvinces JSON field
@@ -73,25 +75,29 @@ func (s *LMIAStatistics) SetTopCities(cities []RegionData) error {
 
 // JobStatisticsData represents raw aggregated data used to create statistics
 type JobStatisticsData struct {
-	TotalJobs       int
-	UniqueEmployers int
-	AvgSalaryMin    *float64
-	AvgSalaryMax    *float64
-	ProvincesCounts map[string]int
-	CitiesCounts    map[string]int
+	TotalJobs          int
+	UniqueEmployers    int
+	AvgSalaryMin       *float64
+	AvgSalaryMax       *float64
+	AvgSalaryAnnualMin *float64
+	AvgSalaryAnnualMax *float64
+	ProvincesCounts    map[string]int
+	CitiesCounts       map[string]int
 }
 
 // NewLMIAStatistics creates a new LMIAStatistics from aggregated data
 func NewLMIAStatistics(date time.Time, periodType PeriodType, data JobStatisticsData) *LMIAStatistics {
 	stats := &LMIAStatistics{
-		Date:            date,
-		PeriodType:      periodType,
-		TotalJobs:       data.TotalJobs,
-		UniqueEmployers: data.UniqueEmployers,
-		AvgSalaryMin:    data.AvgSalaryMin,
-		AvgSalaryMax:    data.AvgSalaryMax,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
+		Date:               date,
+		PeriodType:         periodType,
+		TotalJobs:          data.TotalJobs,
+		UniqueEmployers:    data.UniqueEmployers,
+		AvgSalaryMin:       data.AvgSalaryMin,
+		AvgSalaryMax:       data.AvgSalaryMax,
+		AvgSalaryAnnualMin: data.AvgSalaryAnnualMin,
+		AvgSalaryAnnualMax: data.AvgSalaryAnnualMax,
+		CreatedAt:          time.Now(),
+		UpdatedAt:          time.Now(),
 	}
 
 	// Convert province counts to sorted slice (top 10)
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Change types recorded for a non-compliant employer between scrapes
+const (
+	NonCompliantChangeListed   = "listed"
+	NonCompliantChangeUpdated  = "updated"
+	NonCompliantChangeDelisted = "delisted"
+	NonCompliantChangeRelisted = "relisted"
+)
+
+// Fields compared between scrapes. Addresses are left out since geocoding rewrites them.
+const (
+	NonCompliantFieldLegalName       = "business_legal_name"
+	NonCompliantFieldPenaltyAmount   = "penalty_amount"
+	NonCompliantFieldPenaltyCurrency = "penalty_currency"
+	NonCompliantFieldStatus          = "status"
+	NonCompliantFieldReasonCodes     = "reason_codes"
+)
+
+// NonCompliantEmployerChange is a single observed change to a listed employer. Field name and
+// values are only set for updates.
+type NonCompliantEmployerChange struct {
+	ID                    string    `json:"id" db:"id"`
+	EmployerID            string    `json:"employer_id" db:"employer_id"`
+	BusinessOperatingName string    `json:"business_operating_name" db:"business_operating_name"`
+	ChangeType            string    `json:"change_type" db:"change_type"`
+	FieldName             *string   `json:"field_name" db:"field_name"`
+	OldValue              *string   `json:"old_value" db:"old_value"`
+	NewValue              *string   `json:"new_value" db:"new_value"`
+	ObservedAt            time.Time `json:"observed_at" db:"observed_at"`
+}
+
+// NonCompliantChangeFilters narrows the change feed
+type NonCompliantChangeFilters struct {
+	ChangeType string
+	FieldName  string
+	Since      *time.Time
+}
+
+// NonCompliantEmployerHistory is an employer with every change recorded for it, oldest first
+type NonCompliantEmployerHistory struct {
+	Employer *NonCompliantEmployer        `json:"employer"`
+	Changes  []NonCompliantEmployerChange `json:"changes"`
+}
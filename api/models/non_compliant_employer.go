@@ -7,19 +7,23 @@ import (
 )
 
 type NonCompliantEmployer struct {
-	ID                    string     `json:"id" db:"id"`
-	BusinessOperatingName string     `json:"business_operating_name" db:"business_operating_name"`
-	BusinessLegalName     *string    `json:"business_legal_name" db:"business_legal_name"`
-	Address               *string    `json:"address" db:"address"`
-	DateOfFinalDecision   *time.Time `json:"date_of_final_decision" db:"date_of_final_decision"`
-	PenaltyAmount         *int       `json:"penalty_amount" db:"penalty_amount"`
-	PenaltyCurrency       string     `json:"penalty_currency" db:"penalty_currency"`
-	Status                *string    `json:"status" db:"status"`
-	ReasonCodes           []string   `json:"reason_codes" db:"reason_codes"`
-	PostalCode            *string    `json:"postal_code" db:"postal_code"`
-	ScrapedAt             time.Time  `json:"scraped_at" db:"scraped_at"`
-	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+	ID                    string         `json:"id" db:"id"`
+	BusinessOperatingName string         `json:"business_operating_name" db:"business_operating_name"`
+	BusinessLegalName     *string        `json:"business_legal_name" db:"business_legal_name"`
+	Address               *string        `json:"address" db:"address"`
+	DateOfFinalDecision   *time.Time     `json:"date_of_final_decision" db:"date_of_final_decision"`
+	PenaltyAmount         *int           `json:"penalty_amount" db:"penalty_amount"`
+	PenaltyCurrency       string         `json:"penalty_currency" db:"penalty_currency"`
+	Status                *string        `json:"status" db:"status"`
+	ReasonCodes           pq.StringArray `json:"reason_codes" db:"reason_codes"`
+	PostalCode            *string        `json:"postal_code" db:"postal_code"`
+	ParsedAddressID       *string        `json:"parsed_address_id" db:"parsed_address_id"`
+	FirstSeenAt           time.Time      `json:"first_seen_at" db:"first_seen_at"` // First scrape that listed the employer
+	LastSeenAt            time.Time      `json:"last_seen_at" db:"last_seen_at"`   // Most recent scrape that listed the employer
+	DelistedAt            *time.Time     `json:"delisted_at" db:"delisted_at"`     // Set when the employer drops off the list
+	ScrapedAt             time.Time      `json:"scraped_at" db:"scraped_at"`
+	CreatedAt             time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at" db:"updated_at"`
 
 	// Related reasons (populated by joins)
 	Reasons []NonCompliantReason `json:"reasons,omitempty"`
@@ -58,6 +62,10 @@ type NonCompliantEmployerWithReasons struct {
 	ReasonCodes           pq.StringArray       `json:"reason_codes" db:"reason_codes"` // Array of reason codes
 	Reasons               []NonCompliantReason `json:"reasons,omitempty" db:"reasons"` // Full reason objects with descriptions
 	PostalCode            *string              `json:"postal_code" db:"postal_code"`
+	ParsedAddressID       *string              `json:"parsed_address_id" db:"parsed_address_id"`
+	FirstSeenAt           time.Time            `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt            time.Time            `json:"last_seen_at" db:"last_seen_at"`
+	DelistedAt            *time.Time           `json:"delisted_at" db:"delisted_at"`
 	ScrapedAt             time.Time            `json:"scraped_at" db:"scraped_at"`
 	CreatedAt             time.Time            `json:"created_at" db:"created_at"`
 	UpdatedAt             time.Time            `json:"updated_at" db:"updated_at"`
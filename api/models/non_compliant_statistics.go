@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// NonCompliantPenaltyBreakdown counts decisions and penalties for one group, e.g. a reason code or province
+type NonCompliantPenaltyBreakdown struct {
+	Name               string  `json:"name" db:"name"`
+	Description        *string `json:"description,omitempty" db:"description"` // Reason description, only set for reason codes
+	EmployerCount      int     `json:"employer_count" db:"employer_count"`
+	TotalPenaltyAmount int64   `json:"total_penalty_amount" db:"total_penalty_amount"`
+	AvgPenaltyAmount   float64 `json:"avg_penalty_amount" db:"avg_penalty_amount"`
+	MaxPenaltyAmount   int     `json:"max_penalty_amount" db:"max_penalty_amount"`
+}
+
+// NonCompliantPenaltyStats summarizes the non-compliant list. Reasons are counted per decision citing
+// them, so a decision with several reasons counts toward each.
+type NonCompliantPenaltyStats struct {
+	TotalEmployers       int                            `json:"total_employers" db:"total_employers"`
+	PenalizedEmployers   int                            `json:"penalized_employers" db:"penalized_employers"`
+	TotalPenaltyAmount   int64                          `json:"total_penalty_amount" db:"total_penalty_amount"`
+	AvgPenaltyAmount     float64                        `json:"avg_penalty_amount" db:"avg_penalty_amount"` // Average over penalized employers
+	MedianPenaltyAmount  float64                        `json:"median_penalty_amount" db:"median_penalty_amount"`
+	EarliestDecisionDate *time.Time                     `json:"earliest_decision_date" db:"earliest_decision_date"`
+	LatestDecisionDate   *time.Time                     `json:"latest_decision_date" db:"latest_decision_date"`
+	ByReason             []NonCompliantPenaltyBreakdown `json:"by_reason"`
+	ByProvince           []NonCompliantPenaltyBreakdown `json:"by_province"`
+	ByYear               []NonCompliantPenaltyBreakdown `json:"by_year"`
+	ByStatus             []NonCompliantPenaltyBreakdown `json:"by_status"`
+	ByPenaltyBracket     []NonCompliantPenaltyBreakdown `json:"by_penalty_bracket"`
+}
+
+// NonCompliantRepeatOffender is a legal entity listed under more than one final decision
+type NonCompliantRepeatOffender struct {
+	LegalName          string         `json:"legal_name" db:"legal_name"`
+	ListingCount       int            `json:"listing_count" db:"listing_count"`
+	OperatingNames     pq.StringArray `json:"operating_names" db:"operating_names"`
+	TotalPenaltyAmount int64          `json:"total_penalty_amount" db:"total_penalty_amount"`
+	FirstDecisionDate  *time.Time     `json:"first_decision_date" db:"first_decision_date"`
+	LatestDecisionDate *time.Time     `json:"latest_decision_date" db:"latest_decision_date"`
+	EmployerIDs        pq.StringArray `json:"employer_ids" db:"employer_ids"`
+}
+
+// NonCompliantTrendPoint is the number of decisions and penalties in one period
+type NonCompliantTrendPoint struct {
+	Period             time.Time `json:"period" db:"period"`
+	DecisionCount      int       `json:"decision_count" db:"decision_count"`
+	TotalPenaltyAmount int64     `json:"total_penalty_amount" db:"total_penalty_amount"`
+	AvgPenaltyAmount   float64   `json:"avg_penalty_amount" db:"avg_penalty_amount"`
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"canada-hires/address"
+	"time"
+)
+
+// Datasets whose addresses are parsed into components
+const (
+	AddressSourceLMIA         = "lmia"
+	AddressSourceNonCompliant = "non_compliant"
+	AddressSourceReports      = "reports"
+	AddressSourceBoycotts     = "boycotts"
+)
+
+// AddressSources lists every dataset with parsed addresses
+var AddressSources = []string{
+	AddressSourceLMIA,
+	AddressSourceNonCompliant,
+	AddressSourceReports,
+	AddressSourceBoycotts,
+}
+
+// ParsedAddress is the structured form of a free-text address, shared by every record with the
+// same normalized address
+type ParsedAddress struct {
+	ID                string    `json:"id" db:"id"`
+	RawAddress        string    `json:"raw_address" db:"raw_address"`
+	NormalizedAddress string    `json:"normalized_address" db:"normalized_address"`
+	FormattedAddress  *string   `json:"formatted_address" db:"formatted_address"` // Canada Post order, e.g. "5-123 Main St W, Toronto, ON M5V 1A1"
+	Unit              *string   `json:"unit" db:"unit"`
+	StreetNumber      *string   `json:"street_number" db:"street_number"`
+	Street            *string   `json:"street" db:"street"`
+	StreetType        *string   `json:"street_type" db:"street_type"`
+	StreetDirection   *string   `json:"street_direction" db:"street_direction"`
+	POBox             *string   `json:"po_box" db:"po_box"`
+	RuralRoute        *string   `json:"rural_route" db:"rural_route"`
+	Municipality      *string   `json:"municipality" db:"municipality"`
+	ProvinceCode      *string   `json:"province_code" db:"province_code"`
+	PostalCode        *string   `json:"postal_code" db:"postal_code"`
+	Confidence        float64   `json:"confidence" db:"confidence"`
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// NewParsedAddress creates the stored form of an address parsed by the address package
+func NewParsedAddress(raw string, c address.Components) *ParsedAddress {
+	return &ParsedAddress{
+		RawAddress:        raw,
+		NormalizedAddress: address.Normalize(raw),
+		FormattedAddress:  optionalString(c.Format()),
+		Unit:              optionalString(c.Unit),
+		StreetNumber:      optionalString(c.StreetNumber),
+		Street:            optionalString(c.Street),
+		StreetType:        optionalString(c.StreetType),
+		StreetDirection:   optionalString(c.StreetDirection),
+		POBox:             optionalString(c.POBox),
+		RuralRoute:        optionalString(c.RuralRoute),
+		Municipality:      optionalString(c.Municipality),
+		ProvinceCode:      optionalString(c.ProvinceCode),
+		PostalCode:        optionalString(c.PostalCode),
+		Confidence:        c.Confidence,
+	}
+}
+
+// AddressParseSummary describes how many addresses of a dataset have been parsed
+type AddressParseSummary struct {
+	Source            string   `json:"source" db:"source"`
+	TotalRecords      int      `json:"total_records" db:"total_records"` // Records with a non-empty address
+	ParsedRecords     int      `json:"parsed_records" db:"parsed_records"`
+	LowConfidence     int      `json:"low_confidence" db:"low_confidence"` // Parsed records below 0.5 confidence
+	AverageConfidence *float64 `json:"average_confidence" db:"average_confidence"`
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
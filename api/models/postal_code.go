@@ -4,11 +4,33 @@ import (
 	"database/sql"
 )
 
+// Precision of stored coordinates, from most to least precise
+const (
+	PrecisionAddress    = "address"
+	PrecisionStreet     = "street"
+	PrecisionPostalCode = "postal_code"
+	PrecisionFSA        = "fsa" // Centroid of the forward sortation area, the first three characters of a postal code
+	PrecisionLocality   = "locality"
+)
+
+// Providers of postal code coordinates that were loaded rather than geocoded
+const (
+	PostalCodeProviderDataset = "dataset" // Imported from a centroid file
+	PostalCodeProviderDerived = "derived" // FSA centroid averaged from the postal codes it contains
+	PostalCodeProviderManual  = "manual"  // Accepted or pinned by a reviewer
+)
+
 // PostalCodeCoordinates represents the geographic coordinates of a postal code
 type PostalCodeCoordinates struct {
 	PostalCode string         `json:"postal_code" db:"postal_code"`
 	Latitude   sql.NullFloat64 `json:"latitude" db:"latitude"`
 	Longitude  sql.NullFloat64 `json:"longitude" db:"longitude"`
+	Provider   *string        `json:"provider,omitempty" db:"provider"`     // Geocoder that produced the coordinates
+	Confidence *float64       `json:"confidence,omitempty" db:"confidence"` // Confidence reported by the geocoder
+	Precision  *string        `json:"precision,omitempty" db:"precision"`
+	MatchType  *string        `json:"match_type,omitempty" db:"match_type"` // How the geocoder matched, e.g. "exact" or "fallback"
+	Layer      *string        `json:"layer,omitempty" db:"layer"`           // Kind of place matched, e.g. "postalcode" or "locality"
+	ManualOverride bool           `json:"manual_override" db:"manual_override"` // Accepted or pinned by a reviewer, never replaced by geocoding
 	Error      string         `json:"error,omitempty" db:"-"`
 }
 
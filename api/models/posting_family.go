@@ -0,0 +1,134 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// PostingFamily groups reposts of the same ad that appear on Job Bank under new job bank IDs
+type PostingFamily struct {
+	ID            string     `json:"id" db:"id"`
+	Fingerprint   string     `json:"fingerprint" db:"fingerprint"`
+	Employer      string     `json:"employer" db:"employer"`
+	Title         string     `json:"title" db:"title"`
+	Location      string     `json:"location" db:"location"`
+	PostingCount  int        `json:"posting_count" db:"posting_count"`     // Distinct postings seen, including the original
+	FirstPostedAt *time.Time `json:"first_posted_at" db:"first_posted_at"` // Earliest posting date in the family
+	LastPostedAt  *time.Time `json:"last_posted_at" db:"last_posted_at"`   // Latest posting date in the family
+	FirstSeenAt   time.Time  `json:"first_seen_at" db:"first_seen_at"`
+	LastSeenAt    time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+
+	Members []PostingFamilyMember `json:"members,omitempty"`
+}
+
+// PostingFamilyMember is a single Job Bank posting that belongs to a family
+type PostingFamilyMember struct {
+	ID          string     `json:"id" db:"id"`
+	FamilyID    string     `json:"family_id" db:"family_id"`
+	PostingKey  string     `json:"posting_key" db:"posting_key"` // job_bank_id when known, otherwise the URL
+	JobBankID   *string    `json:"job_bank_id" db:"job_bank_id"`
+	URL         string     `json:"url" db:"url"`
+	PostingDate *time.Time `json:"posting_date" db:"posting_date"`
+	FirstSeenAt time.Time  `json:"first_seen_at" db:"first_seen_at"`
+}
+
+// RepostCount is the number of times the ad was reposted after the original
+func (f *PostingFamily) RepostCount() int {
+	if f.PostingCount <= 1 {
+		return 0
+	}
+	return f.PostingCount - 1
+}
+
+var (
+	fingerprintPunctuation = regexp.MustCompile(`[^a-z0-9 ]+`)
+	fingerprintWhitespace  = regexp.MustCompile(`\s+`)
+	// Legal suffixes are often dropped or changed between reposts of the same ad
+	employerLegalSuffixes = regexp.MustCompile(`\b(inc|incorporated|ltd|limited|corp|corporation|co|company|llc|llp|ltee|enr)\b`)
+	// Gender markers and bracketed notes are added and removed between reposts
+	titleNoise = regexp.MustCompile(`\((?:[^)]*)\)|\b(m f|f m|h f|f h|m f x)\b`)
+)
+
+// normalizeFingerprintText lowercases text, strips punctuation and collapses whitespace
+func normalizeFingerprintText(text string) string {
+	text = strings.ToLower(text)
+	text = strings.ReplaceAll(text, "&", " and ")
+	text = fingerprintPunctuation.ReplaceAllString(text, " ")
+	return strings.TrimSpace(fingerprintWhitespace.ReplaceAllString(text, " "))
+}
+
+// NormalizeEmployerName normalizes an employer name for matching across postings
+func NormalizeEmployerName(employer string) string {
+	name := normalizeFingerprintText(employer)
+	name = employerLegalSuffixes.ReplaceAllString(name, " ")
+	return strings.TrimSpace(fingerprintWhitespace.ReplaceAllString(name, " "))
+}
+
+// NormalizeJobTitle normalizes a job title for matching across postings
+func NormalizeJobTitle(title string) string {
+	title = titleNoise.ReplaceAllString(strings.ToLower(title), " ")
+	return normalizeFingerprintText(title)
+}
+
+// ComputeFingerprint builds the repost fingerprint from the employer, normalized title, location
+// and hourly salary. Hourly equivalents are used so an ad reposted with the same wage written as an
+// annual salary still matches.
+func (jp *JobPosting) ComputeFingerprint() string {
+	location := jp.Location
+	if jp.City != nil && jp.Province != nil {
+		location = *jp.City + " " + *jp.Province
+	}
+
+	salary := ""
+	if jp.SalaryHourlyMin != nil {
+		salary += fmt.Sprintf("%.2f", *jp.SalaryHourlyMin)
+	}
+	salary += "-"
+	if jp.SalaryHourlyMax != nil {
+		salary += fmt.Sprintf("%.2f", *jp.SalaryHourlyMax)
+	}
+
+	parts := []string{
+		NormalizeEmployerName(jp.Employer),
+		NormalizeJobTitle(jp.Title),
+		normalizeFingerprintText(location),
+		salary,
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// PostingKey identifies a posting within its family, preferring the job bank ID over the URL
+func (jp *JobPosting) PostingKey() string {
+	if jp.JobBankID != nil && *jp.JobBankID != "" {
+		return *jp.JobBankID
+	}
+	return jp.URL
+}
+
+// SetRepostSummary fills in the human readable repost note, e.g. "Reposted 3 times since Jan 2, 2025"
+func (jp *JobPosting) SetRepostSummary() {
+	jp.RepostSummary = ""
+	if jp.RepostCount <= 0 {
+		return
+	}
+
+	times := "times"
+	if jp.RepostCount == 1 {
+		times = "time"
+	}
+
+	if jp.RepostedSince == nil {
+		jp.RepostSummary = fmt.Sprintf("Reposted %d %s", jp.RepostCount, times)
+		return
+	}
+
+	jp.RepostSummary = fmt.Sprintf("Reposted %d %s since %s", jp.RepostCount, times, jp.RepostedSince.Format("Jan 2, 2006"))
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// Region levels used in the ESDC wage table
+const (
+	WageRegionEconomicRegion = "economic_region"
+	WageRegionProvince       = "province"
+	WageRegionNational       = "national"
+)
+
+// PrevailingWage is a row from the ESDC/Job Bank wage table for a NOC in an economic region
+type PrevailingWage struct {
+	ID                 string    `json:"id" db:"id"`
+	NOCCode            string    `json:"noc_code" db:"noc_code"`
+	NOCTitle           *string   `json:"noc_title" db:"noc_title"`
+	EconomicRegionCode string    `json:"economic_region_code" db:"economic_region_code"`
+	EconomicRegionName *string   `json:"economic_region_name" db:"economic_region_name"`
+	RegionLevel        string    `json:"region_level" db:"region_level"` // economic_region, province, national
+	Province           *string   `json:"province" db:"province"`
+	LowWage            *float64  `json:"low_wage" db:"low_wage"`
+	MedianWage         *float64  `json:"median_wage" db:"median_wage"`
+	HighWage           *float64  `json:"high_wage" db:"high_wage"`
+	IsAnnual           bool      `json:"is_annual" db:"is_annual"` // Wages are annual salaries rather than hourly rates
+	ReferencePeriod    *string   `json:"reference_period" db:"reference_period"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// MedianHourly returns the median wage as an hourly rate, converting annual medians with the
+// given hours per week, or the default hours when it's 0
+func (w *PrevailingWage) MedianHourly(hoursPerWeek float64) *float64 {
+	if w.MedianWage == nil {
+		return nil
+	}
+	if hoursPerWeek <= 0 {
+		hoursPerWeek = DefaultHoursPerWeek
+	}
+
+	median := *w.MedianWage
+	if w.IsAnnual {
+		median = roundToCents(median / (hoursPerWeek * weeksPerYear))
+	}
+	return &median
+}
+
+// WageComparisonStats summarizes how offered wages on LMIA postings compare to the local median
+type WageComparisonStats struct {
+	TotalPostings         int                       `json:"total_postings"`
+	ComparedPostings      int                       `json:"compared_postings"`
+	BelowMedianPostings   int                       `json:"below_median_postings"`
+	BelowMedianPercentage float64                   `json:"below_median_percentage"`
+	AvgPercentOfMedian    *float64                  `json:"avg_percent_of_median"`
+	ByProvince            []WageComparisonBreakdown `json:"by_province"`
+	ByOccupation          []WageComparisonBreakdown `json:"by_occupation"`
+}
+
+// WageComparisonBreakdown is a grouped slice of WageComparisonStats
+type WageComparisonBreakdown struct {
+	Name                string   `json:"name" db:"name"`
+	ComparedPostings    int      `json:"compared_postings" db:"compared_postings"`
+	BelowMedianPostings int      `json:"below_median_postings" db:"below_median_postings"`
+	AvgPercentOfMedian  *float64 `json:"avg_percent_of_median" db:"avg_percent_of_median"`
+}
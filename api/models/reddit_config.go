@@ -118,6 +118,37 @@ func buildSalaryRangeText(min *float64, max *float64, salaryType *string) string
 	return ""
 }
 
+// buildSalaryEquivalentText formats the normalized hourly and annual equivalents of a posting's salary,
+// skipping the period the salary was already posted in
+func buildSalaryEquivalentText(job *JobPosting) string {
+	if job.SalaryHourlyMin == nil || job.SalaryAnnualMin == nil {
+		return ""
+	}
+
+	salaryType := "hourly"
+	if job.SalaryType != nil {
+		salaryType = *job.SalaryType
+	}
+
+	var parts []string
+	if salaryType != "hourly" {
+		parts = append(parts, formatSalaryEquivalent(job.SalaryHourlyMin, job.SalaryHourlyMax, "per hour", 2))
+	}
+	if salaryType != "yearly" {
+		parts = append(parts, formatSalaryEquivalent(job.SalaryAnnualMin, job.SalaryAnnualMax, "per year", 0))
+	}
+
+	return "(about " + strings.Join(parts, " or ") + ")"
+}
+
+// formatSalaryEquivalent formats a normalized salary range with the given precision
+func formatSalaryEquivalent(min *float64, max *float64, period string, precision int) string {
+	if max == nil || *min == *max {
+		return fmt.Sprintf("$%.*f %s", precision, *min, period)
+	}
+	return fmt.Sprintf("$%.*f - $%.*f %s", precision, *min, precision, *max, period)
+}
+
 // processTemplate replaces template placeholders with job data
 func (rc *RedditConfig) processTemplate(template string, job *JobPosting) string {
 	result := template
@@ -130,6 +161,21 @@ func (rc *RedditConfig) processTemplate(template string, job *JobPosting) string
 
 	// Replace optional fields with conditional logic
 	salaryText := buildSalaryRangeText(job.SalaryMin, job.SalaryMax, job.SalaryType)
+	if equivalentText := buildSalaryEquivalentText(job); salaryText != "" && equivalentText != "" {
+		salaryText += " " + equivalentText
+	}
+
+	// Normalized salary placeholders for custom templates
+	hourlyText, annualText := "", ""
+	if job.SalaryHourlyMin != nil {
+		hourlyText = formatSalaryEquivalent(job.SalaryHourlyMin, job.SalaryHourlyMax, "per hour", 2)
+	}
+	if job.SalaryAnnualMin != nil {
+		annualText = formatSalaryEquivalent(job.SalaryAnnualMin, job.SalaryAnnualMax, "per year", 0)
+	}
+	result = strings.ReplaceAll(result, "{{.SalaryHourly}}", hourlyText)
+	result = strings.ReplaceAll(result, "{{.SalaryAnnual}}", annualText)
+
 	if salaryText != "" {
 		result = strings.ReplaceAll(result, "{{if .SalaryRaw}}**Salary:** {{.SalaryRaw}}{{end}}", "**Salary:** "+salaryText)
 	} else {
@@ -2,28 +2,104 @@ package models
 
 import "time"
 
+// Moderation status of a report. Only published reports are shown publicly.
+const (
+	ReportStatusPending   = "pending"
+	ReportStatusPublished = "published"
+	ReportStatusRejected  = "rejected"
+	ReportStatusFlagged   = "flagged" // Published before, hidden until a moderator looks at it again
+)
+
+// ReportStatuses lists every report status
+var ReportStatuses = []string{ReportStatusPending, ReportStatusPublished, ReportStatusRejected, ReportStatusFlagged}
+
+// Reasons a moderator can give for rejecting a report
+const (
+	RejectionDefamatory          = "defamatory"
+	RejectionUnverifiable        = "unverifiable"
+	RejectionPersonalInformation = "personal_information"
+	RejectionDuplicate           = "duplicate"
+	RejectionSpam                = "spam"
+	RejectionOffTopic            = "off_topic"
+	RejectionOther               = "other"
+)
+
+// RejectionReasons lists every report rejection reason
+var RejectionReasons = []string{
+	RejectionDefamatory,
+	RejectionUnverifiable,
+	RejectionPersonalInformation,
+	RejectionDuplicate,
+	RejectionSpam,
+	RejectionOffTopic,
+	RejectionOther,
+}
+
 type Report struct {
-	ID              string    `json:"id" db:"id"`
-	UserID          string    `json:"user_id" db:"user_id"`
-	BusinessName    string    `json:"business_name" db:"business_name"`
-	BusinessAddress string    `json:"business_address" db:"business_address"`
-	ReportSource    string    `json:"report_source" db:"report_source"`
-	ConfidenceLevel *int      `json:"confidence_level" db:"confidence_level"` // Deprecated: use TFWRatio
-	TFWRatio        *string   `json:"tfw_ratio" db:"tfw_ratio"`
-	AdditionalNotes *string   `json:"additional_notes" db:"additional_notes"`
-	IPAddress       *string   `json:"ip_address" db:"ip_address"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID              string     `json:"id" db:"id"`
+	UserID          string     `json:"user_id" db:"user_id"`
+	BusinessName    string     `json:"business_name" db:"business_name"`
+	BusinessAddress string     `json:"business_address" db:"business_address"`
+	ReportSource    string     `json:"report_source" db:"report_source"`
+	ConfidenceLevel *int       `json:"confidence_level" db:"confidence_level"` // Deprecated: use TFWRatio
+	TFWRatio        *string    `json:"tfw_ratio" db:"tfw_ratio"`
+	AdditionalNotes *string    `json:"additional_notes" db:"additional_notes"`
+	IPAddress       *string    `json:"ip_address" db:"ip_address"`
+	ParsedAddressID *string    `json:"parsed_address_id" db:"parsed_address_id"`
+	Status          string     `json:"status" db:"status"`
+	ModeratedBy     *string    `json:"moderated_by" db:"moderated_by"`
+	ModeratedAt     *time.Time `json:"moderated_at" db:"moderated_at"`
+	RejectionReason *string    `json:"rejection_reason" db:"rejection_reason"`
+	ModerationNotes *string    `json:"moderation_notes" db:"moderation_notes"`
+	PublishedAt     *time.Time `json:"published_at" db:"published_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Counted community votes, see ReportVote
+	CorroborationCount int `json:"corroboration_count" db:"corroboration_count"`
+	FlagCount          int `json:"flag_count" db:"flag_count"`
+
+	// Set when the report was deleted, deleted reports are hidden until an admin restores them
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	DeletedBy *string    `json:"deleted_by,omitempty" db:"deleted_by"`
+
+	// Published response of the verified business, if any
+	BusinessReply *PublicBusinessReply `json:"business_reply,omitempty" db:"-"`
+}
+
+// ReportModeration is a moderator's decision applied to one or more reports
+type ReportModeration struct {
+	Status          string
+	ModeratorID     string
+	RejectionReason *string
+	Notes           *string
+
+	// Where the moderator made the decision from, for the report's history
+	IPAddress *string
 }
 
 type ReportsByAddress struct {
 	BusinessName    string    `json:"business_name" db:"business_name"`
 	BusinessAddress string    `json:"business_address" db:"business_address"`
-	ReportCount     int       `json:"report_count" db:"report_count"`
+	ReportCount     int       `json:"report_count" db:"report_count"`         // Distinct reporters
 	ConfidenceLevel float64   `json:"confidence_level" db:"confidence_level"` // Deprecated: use TFWRatioDistribution
 	TFWRatioFew     int       `json:"tfw_ratio_few" db:"tfw_ratio_few"`
 	TFWRatioMany    int       `json:"tfw_ratio_many" db:"tfw_ratio_many"`
 	TFWRatioMost    int       `json:"tfw_ratio_most" db:"tfw_ratio_most"`
 	TFWRatioAll     int       `json:"tfw_ratio_all" db:"tfw_ratio_all"`
 	LatestReport    time.Time `json:"latest_report" db:"latest_report"`
+
+	// Distinct users who corroborated a published report on the business
+	CorroborationCount int `json:"corroboration_count" db:"corroboration_count"`
+
+	// Distinct reporters weighed by their verification tier
+	WeightedReportCount float64 `json:"weighted_report_count" db:"weighted_report_count"`
+
+	// Published statement of the business when a user verified they represent it
+	BusinessStatement *PublicBusinessReply `json:"business_statement,omitempty" db:"-"`
+
+	// Set when the business is on the IRCC non-compliant employers list
+	IsNonCompliantEmployer   bool       `json:"is_non_compliant_employer" db:"-"`
+	NonCompliantEmployerID   *string    `json:"non_compliant_employer_id,omitempty" db:"-"`
+	NonCompliantDecisionDate *time.Time `json:"non_compliant_decision_date,omitempty" db:"-"`
 }
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Visibility of a report attachment. Private attachments are only shown to moderators and to
+// their uploader.
+const (
+	AttachmentPrivate = "private"
+	AttachmentPublic  = "public"
+)
+
+// Content types accepted as report evidence
+const (
+	AttachmentTypeJPEG = "image/jpeg"
+	AttachmentTypePNG  = "image/png"
+	AttachmentTypePDF  = "application/pdf"
+)
+
+// AttachmentContentTypes lists every accepted attachment content type
+var AttachmentContentTypes = []string{AttachmentTypeJPEG, AttachmentTypePNG, AttachmentTypePDF}
+
+// ReportAttachment is an evidence file attached to a report
+type ReportAttachment struct {
+	ID               string     `json:"id" db:"id"`
+	ReportID         string     `json:"report_id" db:"report_id"`
+	UploadedBy       string     `json:"uploaded_by" db:"uploaded_by"`
+	FileName         string     `json:"file_name" db:"file_name"`
+	ContentType      string     `json:"content_type" db:"content_type"`
+	SizeBytes        int64      `json:"size_bytes" db:"size_bytes"`
+	ChecksumSHA256   string     `json:"checksum_sha256" db:"checksum_sha256"`
+	StorageBackend   string     `json:"-" db:"storage_backend"`
+	StorageKey       string     `json:"-" db:"storage_key"`
+	MetadataStripped bool       `json:"metadata_stripped" db:"metadata_stripped"` // False for PDFs, which keep their document metadata
+	Visibility       string     `json:"visibility" db:"visibility"`
+	ReviewedBy       *string    `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
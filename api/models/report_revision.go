@@ -0,0 +1,99 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// Kinds of changes recorded in a report's history
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionModerate = "moderate"
+	RevisionDelete   = "delete"
+	RevisionRestore  = "restore"
+)
+
+// ReportFieldChange is the value of a report field before and after a change
+type ReportFieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ReportChanges maps report field names to their changes
+type ReportChanges map[string]ReportFieldChange
+
+func (c ReportChanges) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *ReportChanges) Scan(value interface{}) error {
+	*c = ReportChanges{}
+
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return nil
+	}
+}
+
+// ReportRevision is one change to a report: who made it, from where and which fields it changed
+type ReportRevision struct {
+	ID        int           `json:"id" db:"id"`
+	ReportID  string        `json:"report_id" db:"report_id"`
+	Action    string        `json:"action" db:"action"`
+	ChangedBy *string       `json:"changed_by" db:"changed_by"` // Nil for automatic changes
+	IPAddress *string       `json:"ip_address" db:"ip_address"`
+	Changes   ReportChanges `json:"changes" db:"changes"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// ReportActor identifies who changed a report, and from which IP address, for its history.
+// A nil UserID marks a change made by the system.
+type ReportActor struct {
+	UserID    *string
+	IPAddress *string
+}
+
+// DiffReports returns the tracked fields that differ between two versions of a report. A nil
+// before lists every field that is set, for a new report.
+func DiffReports(before, after *Report) ReportChanges {
+	if before == nil {
+		before = &Report{}
+	}
+
+	changes := ReportChanges{}
+	add := func(field string, old, new interface{}) {
+		if old != new {
+			changes[field] = ReportFieldChange{Old: old, New: new}
+		}
+	}
+
+	add("business_name", before.BusinessName, after.BusinessName)
+	add("business_address", before.BusinessAddress, after.BusinessAddress)
+	add("report_source", before.ReportSource, after.ReportSource)
+	add("confidence_level", derefOrNil(before.ConfidenceLevel), derefOrNil(after.ConfidenceLevel))
+	add("tfw_ratio", derefOrNil(before.TFWRatio), derefOrNil(after.TFWRatio))
+	add("additional_notes", derefOrNil(before.AdditionalNotes), derefOrNil(after.AdditionalNotes))
+	add("status", before.Status, after.Status)
+	add("rejection_reason", derefOrNil(before.RejectionReason), derefOrNil(after.RejectionReason))
+	add("moderation_notes", derefOrNil(before.ModerationNotes), derefOrNil(after.ModerationNotes))
+
+	return changes
+}
+
+// derefOrNil returns the value a pointer points to, or an untyped nil, so values compare equal
+// regardless of which pointer holds them
+func derefOrNil[T any](value *T) interface{} {
+	if value == nil {
+		return nil
+	}
+	return *value
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Kinds of community votes on a report
+const (
+	VoteCorroborate = "corroborate" // The voter has seen the same thing
+	VoteFlag        = "flag"        // The voter thinks the report should be reviewed
+)
+
+// Reasons a user can give for flagging a report
+const (
+	FlagInaccurate          = "inaccurate"
+	FlagPersonalInformation = "personal_information"
+	FlagSpam                = "spam"
+)
+
+// FlagReasons lists every report flag reason
+var FlagReasons = []string{FlagInaccurate, FlagPersonalInformation, FlagSpam}
+
+// ReportVote is a user's corroboration or flag of someone else's report. Votes that failed the
+// IP address checks are stored so they can't be cast again, but aren't counted.
+type ReportVote struct {
+	ID         int       `json:"id" db:"id"`
+	ReportID   string    `json:"report_id" db:"report_id"`
+	UserID     string    `json:"user_id" db:"user_id"`
+	VoteType   string    `json:"vote_type" db:"vote_type"`
+	FlagReason *string   `json:"flag_reason,omitempty" db:"flag_reason"`
+	Comment    *string   `json:"comment,omitempty" db:"comment"`
+	IPAddress  *string   `json:"-" db:"ip_address"`
+	Counted    bool      `json:"counted" db:"counted"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// ReportVoteResult is the outcome of a vote
+type ReportVoteResult struct {
+	Vote               *ReportVote `json:"vote"`
+	CorroborationCount int         `json:"corroboration_count"`
+	FlagCount          int         `json:"flag_count"`
+	SentToModeration   bool        `json:"sent_to_moderation"` // The flag moved the report into the moderation queue
+}
@@ -0,0 +1,160 @@
+package models
+
+import "time"
+
+// Datasets that can be searched by location
+const (
+	SpatialDatasetLMIA         = "lmia"
+	SpatialDatasetNonCompliant = "non_compliant"
+	SpatialDatasetJobPostings  = "job_postings"
+	SpatialDatasetReports      = "reports"
+)
+
+// SpatialDatasets lists every dataset that can be searched by location
+var SpatialDatasets = []string{
+	SpatialDatasetLMIA,
+	SpatialDatasetNonCompliant,
+	SpatialDatasetJobPostings,
+	SpatialDatasetReports,
+}
+
+// BoundingBox is a map viewport in degrees
+type BoundingBox struct {
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+}
+
+// Center returns the middle of the box
+func (b BoundingBox) Center() (latitude, longitude float64) {
+	return (b.MinLatitude + b.MaxLatitude) / 2, (b.MinLongitude + b.MaxLongitude) / 2
+}
+
+// SpatialQuery selects records within a radius of a point, or inside a bounding box when BBox
+// is set. Results are sorted by distance from the point, which is the center of the box for
+// bounding box queries.
+type SpatialQuery struct {
+	Latitude     float64
+	Longitude    float64
+	RadiusMeters float64
+	BBox         *BoundingBox
+	Limit        int
+}
+
+// NearbyLMIAEmployer is an LMIA employer at one postal code with its approvals summed across quarters
+type NearbyLMIAEmployer struct {
+	Employer          string  `json:"employer" db:"employer"`
+	Address           *string `json:"address" db:"address"`
+	PostalCode        string  `json:"postal_code" db:"postal_code"`
+	ApprovedLMIAs     int     `json:"approved_lmias" db:"approved_lmias"`
+	ApprovedPositions int     `json:"approved_positions" db:"approved_positions"`
+	LatestYear        int     `json:"latest_year" db:"latest_year"`
+	Latitude          float64 `json:"latitude" db:"latitude"`
+	Longitude         float64 `json:"longitude" db:"longitude"`
+	Precision         *string `json:"precision" db:"precision"`
+	DistanceMeters    float64 `json:"distance_meters" db:"distance_meters"`
+}
+
+// NearbyNonCompliantEmployer is a non-compliant employer decision with its location
+type NearbyNonCompliantEmployer struct {
+	ID                    string     `json:"id" db:"id"`
+	BusinessOperatingName string     `json:"business_operating_name" db:"business_operating_name"`
+	BusinessLegalName     *string    `json:"business_legal_name" db:"business_legal_name"`
+	Address               *string    `json:"address" db:"address"`
+	DateOfFinalDecision   *time.Time `json:"date_of_final_decision" db:"date_of_final_decision"`
+	PenaltyAmount         *int       `json:"penalty_amount" db:"penalty_amount"`
+	Status                *string    `json:"status" db:"status"`
+	Latitude              float64    `json:"latitude" db:"latitude"`
+	Longitude             float64    `json:"longitude" db:"longitude"`
+	Precision             *string    `json:"precision" db:"precision"`
+	DistanceMeters        float64    `json:"distance_meters" db:"distance_meters"`
+}
+
+// NearbyJobPosting is a job posting placed at its geocoded location
+type NearbyJobPosting struct {
+	ID             string     `json:"id" db:"id"`
+	Title          string     `json:"title" db:"title"`
+	Employer       string     `json:"employer" db:"employer"`
+	Location       string     `json:"location" db:"location"`
+	SalaryMin      *float64   `json:"salary_min" db:"salary_min"`
+	SalaryMax      *float64   `json:"salary_max" db:"salary_max"`
+	SalaryType     *string    `json:"salary_type" db:"salary_type"`
+	PostingDate    *time.Time `json:"posting_date" db:"posting_date"`
+	URL            string     `json:"url" db:"url"`
+	IsTFW          bool       `json:"is_tfw" db:"is_tfw"`
+	HasLMIA        bool       `json:"has_lmia" db:"has_lmia"`
+	Latitude       float64    `json:"latitude" db:"latitude"`
+	Longitude      float64    `json:"longitude" db:"longitude"`
+	DistanceMeters float64    `json:"distance_meters" db:"distance_meters"`
+}
+
+// NearbyReportLocation groups the reports about a business at one address
+type NearbyReportLocation struct {
+	BusinessName    string    `json:"business_name" db:"business_name"`
+	BusinessAddress string    `json:"business_address" db:"business_address"`
+	ReportCount     int       `json:"report_count" db:"report_count"`
+	LatestReport    time.Time `json:"latest_report" db:"latest_report"`
+	Latitude        float64   `json:"latitude" db:"latitude"`
+	Longitude       float64   `json:"longitude" db:"longitude"`
+	Precision       *string   `json:"precision" db:"precision"`
+	DistanceMeters  float64   `json:"distance_meters" db:"distance_meters"`
+}
+
+// SpatialSearchResponse holds the records of each requested dataset, nearest first
+type SpatialSearchResponse struct {
+	Latitude              float64                       `json:"latitude"`
+	Longitude             float64                       `json:"longitude"`
+	RadiusMeters          float64                       `json:"radius_meters,omitempty"`
+	BBox                  *BoundingBox                  `json:"bbox,omitempty"`
+	LMIAEmployers         []*NearbyLMIAEmployer         `json:"lmia_employers"`
+	NonCompliantEmployers []*NearbyNonCompliantEmployer `json:"non_compliant_employers"`
+	JobPostings           []*NearbyJobPosting           `json:"job_postings"`
+	Reports               []*NearbyReportLocation       `json:"reports"`
+}
+
+// Layers of the clustered map
+const (
+	MapLayerLMIA         = "lmia"
+	MapLayerNonCompliant = "non_compliant"
+	MapLayerJobPostings  = "job_postings"
+)
+
+// MapLayers lists every layer that can be clustered
+var MapLayers = []string{
+	MapLayerLMIA,
+	MapLayerNonCompliant,
+	MapLayerJobPostings,
+}
+
+// MapClusterQuery selects the points of a layer inside a viewport and the grid they are grouped
+// on. Year and Quarter filter LMIA approvals, Year also filters non-compliance decisions, and
+// Days limits job postings to recent ones.
+type MapClusterQuery struct {
+	Layer         string
+	Zoom          int
+	BBox          BoundingBox
+	CellLatitude  float64
+	CellLongitude float64
+	Year          int
+	Quarter       string
+	Days          int
+	Limit         int
+}
+
+// MapCluster is a grid cell holding one or more points of a layer, placed at their centroid.
+// Only the totals of the clustered layer are set, and Name and RecordID only for single points.
+type MapCluster struct {
+	CellX              int64   `db:"cell_x"`
+	CellY              int64   `db:"cell_y"`
+	Latitude           float64 `db:"latitude"`
+	Longitude          float64 `db:"longitude"`
+	PointCount         int     `db:"point_count"`
+	ApprovedLMIAs      *int    `db:"approved_lmias"`
+	ApprovedPositions  *int    `db:"approved_positions"`
+	TotalPenaltyAmount *int64  `db:"total_penalty_amount"`
+	TFWPostings        *int    `db:"tfw_postings"`
+	LMIAPostings       *int    `db:"lmia_postings"`
+	Name               *string `db:"name"`
+	RecordID           *string `db:"record_id"`
+}
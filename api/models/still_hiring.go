@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// StillHiringEmployer is a non-compliant employer that advertises LMIA jobs or received LMIA
+// approvals after its final decision
+type StillHiringEmployer struct {
+	ID                     string     `json:"id" db:"id"`
+	BusinessOperatingName  string     `json:"business_operating_name" db:"business_operating_name"`
+	BusinessLegalName      *string    `json:"business_legal_name" db:"business_legal_name"`
+	Address                *string    `json:"address" db:"address"`
+	DateOfFinalDecision    *time.Time `json:"date_of_final_decision" db:"date_of_final_decision"`
+	PenaltyAmount          *int       `json:"penalty_amount" db:"penalty_amount"`
+	Status                 *string    `json:"status" db:"status"`
+	ActiveJobPostings      int        `json:"active_job_postings" db:"active_job_postings"`           // Current Job Bank postings by the employer
+	LatestPostingDate      *time.Time `json:"latest_posting_date" db:"latest_posting_date"`           // Most recent of those postings
+	ApprovalsAfterDecision int        `json:"approvals_after_decision" db:"approvals_after_decision"` // Approved LMIAs in quarters starting after the decision
+	PositionsAfterDecision int        `json:"positions_after_decision" db:"positions_after_decision"` // Approved positions in those quarters
+	LatestApprovalPeriod   *time.Time `json:"latest_approval_period" db:"latest_approval_period"`     // Start of the latest quarter with approvals
+}
+
+// NonCompliantMatchCandidate is the subset of a non-compliant employer used for name matching
+type NonCompliantMatchCandidate struct {
+	ID                  string     `db:"id"`
+	NormalizedName      *string    `db:"normalized_name"`
+	NormalizedLegalName *string    `db:"normalized_legal_name"`
+	DateOfFinalDecision *time.Time `db:"date_of_final_decision"`
+	PenaltyAmount       *int       `db:"penalty_amount"`
+}
+
+// NonCompliantFlag marks a job posting as belonging to a non-compliant employer
+type NonCompliantFlag struct {
+	JobPostingID           string     `db:"job_posting_id"`
+	NonCompliantEmployerID string     `db:"non_compliant_employer_id"`
+	DecisionDate           *time.Time `db:"non_compliant_decision_date"`
+	PenaltyAmount          *int       `db:"non_compliant_penalty_amount"`
+}
+
+// SetNonCompliantNotice fills in the warning shown on postings by non-compliant employers,
+// e.g. "Employer found non-compliant on Mar 3, 2023 ($5,000 penalty)"
+func (jp *JobPosting) SetNonCompliantNotice() {
+	jp.NonCompliantNotice = ""
+	if !jp.IsNonCompliantEmployer {
+		return
+	}
+
+	notice := "Employer is on the IRCC non-compliant employers list"
+	if jp.NonCompliantDecisionDate != nil {
+		notice = fmt.Sprintf("Employer found non-compliant on %s", jp.NonCompliantDecisionDate.Format("Jan 2, 2006"))
+	}
+	if jp.NonCompliantPenaltyAmount != nil && *jp.NonCompliantPenaltyAmount > 0 {
+		notice += fmt.Sprintf(" ($%s penalty)", formatThousands(*jp.NonCompliantPenaltyAmount))
+	}
+
+	jp.NonCompliantNotice = notice
+}
+
+// formatThousands formats an amount with comma separators, e.g. 12500 -> "12,500"
+func formatThousands(amount int) string {
+	digits := strconv.Itoa(amount)
+	for i := len(digits) - 3; i > 0; i -= 3 {
+		digits = digits[:i] + "," + digits[i:]
+	}
+	return digits
+}
@@ -17,8 +17,9 @@ const (
 type UserRole string
 
 const (
-	RoleUser  UserRole = "user"
-	RoleAdmin UserRole = "admin"
+	RoleUser      UserRole = "user"
+	RoleModerator UserRole = "moderator"
+	RoleAdmin     UserRole = "admin"
 )
 
 type IPAddresses []string
@@ -60,3 +61,8 @@ type User struct {
 func (u *User) IsAdmin() bool {
 	return u.Role == RoleAdmin
 }
+
+// IsModerator checks if the user can moderate content, which admins can too
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
+}
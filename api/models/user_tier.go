@@ -0,0 +1,105 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Email domain reputations considered by the tier engine
+const (
+	EmailDomainDisposable    = "disposable"    // Throwaway inbox services
+	EmailDomainInstitutional = "institutional" // Government and education domains
+	EmailDomainStandard      = "standard"
+)
+
+// Rank orders verification tiers from basic to trusted
+func (t VerificationTier) Rank() int {
+	switch t {
+	case VerificationTrusted:
+		return 2
+	case VerificationEnhanced:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// AtLeast reports whether the tier is the given tier or a higher one
+func (t VerificationTier) AtLeast(tier VerificationTier) bool {
+	return t.Rank() >= tier.Rank()
+}
+
+// ReportWeight is how much a report counts towards a business's totals, by its author's tier
+func (t VerificationTier) ReportWeight() float64 {
+	switch t {
+	case VerificationTrusted:
+		return 2
+	case VerificationEnhanced:
+		return 1.5
+	default:
+		return 1
+	}
+}
+
+// UserTierSignals are the facts about an account that its verification tier is computed from
+type UserTierSignals struct {
+	UserID              string           `json:"user_id" db:"user_id"`
+	CurrentTier         VerificationTier `json:"current_tier" db:"verification_tier"`
+	EmailDomain         *string          `json:"email_domain" db:"email_domain"`
+	AccountCreatedAt    time.Time        `json:"account_created_at" db:"created_at"`
+	PublishedReports    int              `json:"published_reports" db:"published_reports"`
+	CorroboratedReports int              `json:"corroborated_reports" db:"corroborated_reports"` // Published reports with counted corroborations
+	Corroborations      int              `json:"corroborations" db:"corroborations"`
+	RejectedReports     int              `json:"rejected_reports" db:"rejected_reports"` // Including deleted ones
+	FlaggedReports      int              `json:"flagged_reports" db:"flagged_reports"`
+	IPAddressCount      int              `json:"ip_address_count" db:"ip_address_count"`
+	SharedIPAccounts    int              `json:"shared_ip_accounts" db:"shared_ip_accounts"` // Other accounts seen on the same IP addresses
+}
+
+func (s UserTierSignals) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *UserTierSignals) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	default:
+		return nil
+	}
+}
+
+// UserTierChange records a tier change made by the tier engine
+type UserTierChange struct {
+	ID        int              `json:"id" db:"id"`
+	UserID    string           `json:"user_id" db:"user_id"`
+	OldTier   VerificationTier `json:"old_tier" db:"old_tier"`
+	NewTier   VerificationTier `json:"new_tier" db:"new_tier"`
+	Reasons   pq.StringArray   `json:"reasons" db:"reasons"`
+	Signals   UserTierSignals  `json:"signals" db:"signals"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+}
+
+// TierEvaluation is the tier an account qualifies for, and what the next tier still needs
+type TierEvaluation struct {
+	UserID          string            `json:"user_id"`
+	CurrentTier     VerificationTier  `json:"current_tier"`
+	QualifiedTier   VerificationTier  `json:"qualified_tier"`
+	Reasons         []string          `json:"reasons"`
+	NextTier        *VerificationTier `json:"next_tier,omitempty"`
+	NextTierMissing []string          `json:"next_tier_missing,omitempty"`
+	Signals         UserTierSignals   `json:"signals"`
+}
+
+// TierEvaluationSummary counts the outcome of evaluating every account
+type TierEvaluationSummary struct {
+	Evaluated int `json:"evaluated"`
+	Promoted  int `json:"promoted"`
+	Demoted   int `json:"demoted"`
+	Failed    int `json:"failed"`
+}
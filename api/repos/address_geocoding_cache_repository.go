@@ -2,7 +2,7 @@ package repos
 
 import (
 	"canada-hires/models"
-	"strings"
+	"database/sql"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -22,32 +22,10 @@ func NewAddressGeocodingCacheRepository(db *sqlx.DB) AddressGeocodingCacheReposi
 	return &addressGeocodingCacheRepository{db: db}
 }
 
-// NormalizeAddress normalizes an address string for consistent caching
-func NormalizeAddress(address string) string {
-	// Convert to lowercase and trim whitespace
-	normalized := strings.ToLower(strings.TrimSpace(address))
-	
-	// Remove extra whitespace between words
-	words := strings.Fields(normalized)
-	normalized = strings.Join(words, " ")
-	
-	// Remove common punctuation
-	replacements := []string{
-		",", "",
-		".", "",
-		"  ", " ", // Replace double spaces with single space
-	}
-	
-	replacer := strings.NewReplacer(replacements...)
-	normalized = replacer.Replace(normalized)
-	
-	return strings.TrimSpace(normalized)
-}
-
 func (r *addressGeocodingCacheRepository) GetByNormalizedAddress(normalizedAddress string) (*models.AddressGeocodingCache, error) {
 	var cache models.AddressGeocodingCache
 	query := `
-		SELECT id, address, normalized_address, latitude, longitude, confidence, geocoded_at, created_at, updated_at
+		SELECT id, address, normalized_address, latitude, longitude, confidence, provider, precision, match_type, layer, manual_override, geocoded_at, created_at, updated_at
 		FROM address_geocoding_cache
 		WHERE normalized_address = $1`
 
@@ -61,29 +39,49 @@ func (r *addressGeocodingCacheRepository) GetByNormalizedAddress(normalizedAddre
 
 func (r *addressGeocodingCacheRepository) Upsert(cache *models.AddressGeocodingCache) error {
 	query := `
-		INSERT INTO address_geocoding_cache (address, normalized_address, latitude, longitude, confidence, geocoded_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO address_geocoding_cache (address, normalized_address, latitude, longitude, confidence, provider, precision, match_type, layer, geocoded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		ON CONFLICT (normalized_address) DO UPDATE SET
 			address = EXCLUDED.address,
 			latitude = EXCLUDED.latitude,
 			longitude = EXCLUDED.longitude,
 			confidence = EXCLUDED.confidence,
+			provider = EXCLUDED.provider,
+			precision = EXCLUDED.precision,
+			match_type = EXCLUDED.match_type,
+			layer = EXCLUDED.layer,
 			geocoded_at = EXCLUDED.geocoded_at,
+			-- Moved coordinates are assigned to an electoral district again
+			electoral_district_id = CASE
+				WHEN (address_geocoding_cache.latitude, address_geocoding_cache.longitude) IS NOT DISTINCT FROM (EXCLUDED.latitude, EXCLUDED.longitude)
+				THEN address_geocoding_cache.electoral_district_id
+			END,
 			updated_at = CURRENT_TIMESTAMP
+		-- Coordinates accepted or pinned by a reviewer are never replaced
+		WHERE NOT address_geocoding_cache.manual_override
 		RETURNING id, created_at, updated_at`
 
-	return r.db.QueryRow(query,
+	err := r.db.QueryRow(query,
 		cache.Address,
 		cache.NormalizedAddress,
 		cache.Latitude,
 		cache.Longitude,
 		cache.Confidence,
+		cache.Provider,
+		cache.Precision,
+		cache.MatchType,
+		cache.Layer,
 		cache.GeocodedAt,
 	).Scan(&cache.ID, &cache.CreatedAt, &cache.UpdatedAt)
+	// The row wasn't updated because a reviewer overrode its coordinates
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
 }
 
 func (r *addressGeocodingCacheRepository) DeleteOlderThan(days int) error {
-	query := `DELETE FROM address_geocoding_cache WHERE geocoded_at < $1`
+	query := `DELETE FROM address_geocoding_cache WHERE geocoded_at < $1 AND NOT manual_override`
 	cutoffDate := time.Now().AddDate(0, 0, -days)
 	
 	_, err := r.db.Exec(query, cutoffDate)
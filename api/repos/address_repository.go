@@ -0,0 +1,180 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type AddressRepository interface {
+	UpsertParsedAddress(parsed *models.ParsedAddress) error
+	GetParsedAddressByID(id string) (*models.ParsedAddress, error)
+	GetUnparsedAddresses(source string, limit int) ([]string, error)
+	LinkParsedAddresses(source string, links map[string]string) (int64, error)
+	GetParseSummary() ([]models.AddressParseSummary, error)
+}
+
+type addressRepository struct {
+	db *sqlx.DB
+}
+
+func NewAddressRepository(db *sqlx.DB) AddressRepository {
+	return &addressRepository{db: db}
+}
+
+// addressSourceColumns maps each address source to the table and column holding its free-text address
+var addressSourceColumns = map[string]struct{ table, column string }{
+	models.AddressSourceLMIA:         {"lmia_employers", "address"},
+	models.AddressSourceNonCompliant: {"non_compliant_employers", "address"},
+	models.AddressSourceReports:      {"reports", "business_address"},
+	models.AddressSourceBoycotts:     {"boycotts", "business_address"},
+}
+
+func addressSourceColumn(source string) (table, column string, err error) {
+	target, ok := addressSourceColumns[source]
+	if !ok {
+		return "", "", fmt.Errorf("unknown address source: %s", source)
+	}
+	return target.table, target.column, nil
+}
+
+// UpsertParsedAddress stores the components of an address, replacing those of an earlier parse of
+// the same normalized address, and sets the ID of the stored row
+func (r *addressRepository) UpsertParsedAddress(parsed *models.ParsedAddress) error {
+	query := `
+		INSERT INTO parsed_addresses (
+			raw_address, normalized_address, formatted_address, unit, street_number, street,
+			street_type, street_direction, po_box, rural_route, municipality, province_code,
+			postal_code, confidence
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT (normalized_address) DO UPDATE SET
+			raw_address = EXCLUDED.raw_address,
+			formatted_address = EXCLUDED.formatted_address,
+			unit = EXCLUDED.unit,
+			street_number = EXCLUDED.street_number,
+			street = EXCLUDED.street,
+			street_type = EXCLUDED.street_type,
+			street_direction = EXCLUDED.street_direction,
+			po_box = EXCLUDED.po_box,
+			rural_route = EXCLUDED.rural_route,
+			municipality = EXCLUDED.municipality,
+			province_code = EXCLUDED.province_code,
+			postal_code = EXCLUDED.postal_code,
+			confidence = EXCLUDED.confidence,
+			updated_at = NOW()
+		RETURNING id, created_at, updated_at`
+
+	err := r.db.QueryRowx(query,
+		parsed.RawAddress, parsed.NormalizedAddress, parsed.FormattedAddress, parsed.Unit,
+		parsed.StreetNumber, parsed.Street, parsed.StreetType, parsed.StreetDirection,
+		parsed.POBox, parsed.RuralRoute, parsed.Municipality, parsed.ProvinceCode,
+		parsed.PostalCode, parsed.Confidence,
+	).Scan(&parsed.ID, &parsed.CreatedAt, &parsed.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert parsed address: %w", err)
+	}
+
+	return nil
+}
+
+func (r *addressRepository) GetParsedAddressByID(id string) (*models.ParsedAddress, error) {
+	var parsed models.ParsedAddress
+	query := `SELECT * FROM parsed_addresses WHERE id = $1`
+
+	err := r.db.Get(&parsed, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsed, nil
+}
+
+// GetUnparsedAddresses returns distinct addresses of a source that are not linked to a parsed address yet
+func (r *addressRepository) GetUnparsedAddresses(source string, limit int) ([]string, error) {
+	table, column, err := addressSourceColumn(source)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT DISTINCT %[2]s
+		FROM %[1]s
+		WHERE parsed_address_id IS NULL
+		  AND %[2]s IS NOT NULL
+		  AND BTRIM(%[2]s) != ''
+		LIMIT $1`, table, column)
+
+	var addresses []string
+	if err := r.db.Select(&addresses, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get unparsed addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// LinkParsedAddresses points every unlinked record of a source with one of the given raw addresses
+// at its parsed address. links maps raw address to parsed address ID.
+func (r *addressRepository) LinkParsedAddresses(source string, links map[string]string) (int64, error) {
+	table, column, err := addressSourceColumn(source)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`UPDATE %s SET parsed_address_id = $1 WHERE %s = $2 AND parsed_address_id IS NULL`, table, column)
+
+	var linked int64
+	for rawAddress, parsedAddressID := range links {
+		result, err := tx.Exec(query, parsedAddressID, rawAddress)
+		if err != nil {
+			return 0, fmt.Errorf("failed to link parsed address: %w", err)
+		}
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get affected rows: %w", err)
+		}
+		linked += affected
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return linked, nil
+}
+
+// GetParseSummary returns parsing coverage and confidence for every address source
+func (r *addressRepository) GetParseSummary() ([]models.AddressParseSummary, error) {
+	var summaries []models.AddressParseSummary
+	for _, source := range models.AddressSources {
+		table, column, err := addressSourceColumn(source)
+		if err != nil {
+			return nil, err
+		}
+
+		query := fmt.Sprintf(`
+			SELECT
+				$1::text as source,
+				COUNT(*) as total_records,
+				COUNT(t.parsed_address_id) as parsed_records,
+				COUNT(*) FILTER (WHERE pa.confidence < 0.5) as low_confidence,
+				ROUND(AVG(pa.confidence), 2)::float8 as average_confidence
+			FROM %[1]s t
+			LEFT JOIN parsed_addresses pa ON pa.id = t.parsed_address_id
+			WHERE t.%[2]s IS NOT NULL AND BTRIM(t.%[2]s) != ''`, table, column)
+
+		var summary models.AddressParseSummary
+		if err := r.db.Get(&summary, query, source); err != nil {
+			return nil, fmt.Errorf("failed to get address parse summary for %s: %w", source, err)
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,94 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"database/sql"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type APITokenRepository interface {
+	Create(token *models.APIToken) error
+	GetByHash(tokenHash string) (*models.APIToken, error)
+	GetByUserID(userID string) ([]*models.APIToken, error)
+	Revoke(id, userID string) error
+	UpdateLastUsed(id, ipAddress string) error
+}
+
+type apiTokenRepository struct {
+	db *sqlx.DB
+}
+
+func NewAPITokenRepository(db *sqlx.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(token *models.APIToken) error {
+	query := `
+		INSERT INTO api_tokens (user_id, name, token_prefix, token_hash, scopes, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowx(query, token.UserID, token.Name, token.TokenPrefix, token.TokenHash, token.Scopes, token.ExpiresAt).
+		Scan(&token.ID, &token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create API token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) GetByHash(tokenHash string) (*models.APIToken, error) {
+	var token models.APIToken
+	query := `SELECT * FROM api_tokens WHERE token_hash = $1`
+
+	if err := r.db.Get(&token, query, tokenHash); err != nil {
+		return nil, fmt.Errorf("failed to get API token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// GetByUserID returns the tokens of a user including revoked and expired ones, newest first
+func (r *apiTokenRepository) GetByUserID(userID string) ([]*models.APIToken, error) {
+	var tokens []*models.APIToken
+	query := `SELECT * FROM api_tokens WHERE user_id = $1 ORDER BY created_at DESC`
+
+	if err := r.db.Select(&tokens, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get API tokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Revoke revokes a token of the user. It returns sql.ErrNoRows when the user has no such token
+// or it's already revoked.
+func (r *apiTokenRepository) Revoke(id, userID string) error {
+	query := `UPDATE api_tokens SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.Exec(query, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke API token: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to revoke API token: %w", sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(id, ipAddress string) error {
+	query := `UPDATE api_tokens SET last_used_at = NOW(), last_used_ip = $2 WHERE id = $1`
+
+	if _, err := r.db.Exec(query, id, ipAddress); err != nil {
+		return fmt.Errorf("failed to update API token last used: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,343 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type BoycottCampaignRepository interface {
+	Create(campaign *models.BoycottCampaign) error
+	GetByID(id string) (*models.BoycottCampaign, error)
+	List(status string, limit, offset int) ([]*models.BoycottCampaign, int, error)
+	GetByPledger(userID string, limit, offset int) ([]*models.BoycottCampaign, error)
+	GetTargets(campaignIDs []string) (map[string][]*models.BoycottCampaignTarget, error)
+	Close(id string) error
+
+	// Pledges
+	Pledge(campaignID, userID string) (*models.BoycottCampaign, []*models.BoycottCampaignMilestone, error)
+	Withdraw(campaignID, userID string) (bool, error)
+	GetPledgedCampaignIDs(userID string, campaignIDs []string) ([]string, error)
+
+	// Progress
+	GetMilestones(campaignID string) ([]*models.BoycottCampaignMilestone, error)
+	GetPledgeTotals(campaignID string) (total, withdrawn int, err error)
+	GetDailyProgress(campaignID string, from, to time.Time) ([]*models.CampaignProgressPoint, error)
+}
+
+type boycottCampaignRepository struct {
+	db *sqlx.DB
+}
+
+func NewBoycottCampaignRepository(db *sqlx.DB) BoycottCampaignRepository {
+	return &boycottCampaignRepository{db: db}
+}
+
+// Create saves a campaign with its targets
+func (r *boycottCampaignRepository) Create(campaign *models.BoycottCampaign) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO boycott_campaigns (title, description, target_type, brand_name, goal_pledges, ends_at, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING *
+	`
+
+	targets := campaign.Targets
+	err = tx.Get(campaign, query, campaign.Title, campaign.Description, campaign.TargetType, campaign.BrandName,
+		campaign.GoalPledges, campaign.EndsAt, campaign.CreatedBy)
+	if err != nil {
+		return fmt.Errorf("failed to insert boycott campaign: %w", err)
+	}
+
+	for _, target := range targets {
+		target.CampaignID = campaign.ID
+		err = tx.Get(&target.ID, `
+			INSERT INTO boycott_campaign_targets (campaign_id, business_name, business_address)
+			VALUES ($1, $2, $3)
+			RETURNING id
+		`, target.CampaignID, target.BusinessName, target.BusinessAddress)
+		if err != nil {
+			return fmt.Errorf("failed to insert boycott campaign target: %w", err)
+		}
+	}
+	campaign.Targets = targets
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *boycottCampaignRepository) GetByID(id string) (*models.BoycottCampaign, error) {
+	var campaign models.BoycottCampaign
+	if err := r.db.Get(&campaign, `SELECT * FROM boycott_campaigns WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	return &campaign, nil
+}
+
+// List returns campaigns, newest first, and their total. Status is "active" for campaigns taking
+// pledges, "ended" for closed and expired campaigns, or empty for every campaign.
+func (r *boycottCampaignRepository) List(status string, limit, offset int) ([]*models.BoycottCampaign, int, error) {
+	condition := "TRUE"
+	switch status {
+	case "active":
+		condition = "status = 'active' AND ends_at > NOW()"
+	case "ended":
+		condition = "(status = 'closed' OR ends_at <= NOW())"
+	}
+
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM boycott_campaigns WHERE `+condition); err != nil {
+		return nil, 0, fmt.Errorf("failed to count boycott campaigns: %w", err)
+	}
+
+	var campaigns []*models.BoycottCampaign
+	query := `SELECT * FROM boycott_campaigns WHERE ` + condition + ` ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	if err := r.db.Select(&campaigns, query, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get boycott campaigns: %w", err)
+	}
+
+	return campaigns, total, nil
+}
+
+// GetByPledger returns the campaigns a user currently pledges to, most recent pledge first
+func (r *boycottCampaignRepository) GetByPledger(userID string, limit, offset int) ([]*models.BoycottCampaign, error) {
+	var campaigns []*models.BoycottCampaign
+	query := `
+		SELECT c.* FROM boycott_campaigns c
+		JOIN boycott_campaign_pledges p ON p.campaign_id = c.id
+		WHERE p.user_id = $1 AND p.withdrawn_at IS NULL
+		ORDER BY p.pledged_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	if err := r.db.Select(&campaigns, query, userID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to get pledged boycott campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// GetTargets returns the targets of campaigns, by campaign ID
+func (r *boycottCampaignRepository) GetTargets(campaignIDs []string) (map[string][]*models.BoycottCampaignTarget, error) {
+	targets := make(map[string][]*models.BoycottCampaignTarget, len(campaignIDs))
+	if len(campaignIDs) == 0 {
+		return targets, nil
+	}
+
+	var rows []*models.BoycottCampaignTarget
+	query := `
+		SELECT * FROM boycott_campaign_targets
+		WHERE campaign_id::text = ANY($1)
+		ORDER BY business_name, business_address
+	`
+	if err := r.db.Select(&rows, query, pq.Array(campaignIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign targets: %w", err)
+	}
+
+	for _, target := range rows {
+		targets[target.CampaignID] = append(targets[target.CampaignID], target)
+	}
+
+	return targets, nil
+}
+
+// Close stops a campaign from taking pledges
+func (r *boycottCampaignRepository) Close(id string) error {
+	query := `UPDATE boycott_campaigns SET status = 'closed', updated_at = NOW() WHERE id = $1`
+	if _, err := r.db.Exec(query, id); err != nil {
+		return fmt.Errorf("failed to close boycott campaign: %w", err)
+	}
+
+	return nil
+}
+
+// Pledge records a user's pledge to a campaign, renewing a withdrawn one. It returns the campaign
+// with its new pledge count and the milestones the pledge made it reach.
+func (r *boycottCampaignRepository) Pledge(campaignID, userID string) (*models.BoycottCampaign, []*models.BoycottCampaignMilestone, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Pledging again while a pledge is active keeps its original date
+	_, err = tx.Exec(`
+		INSERT INTO boycott_campaign_pledges (campaign_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (campaign_id, user_id) DO UPDATE SET pledged_at = NOW(), withdrawn_at = NULL
+		WHERE boycott_campaign_pledges.withdrawn_at IS NOT NULL
+	`, campaignID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to save boycott campaign pledge: %w", err)
+	}
+
+	campaign, err := updatePledgeCount(tx, campaignID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Milestones are only recorded once, a campaign that drops below one keeps it
+	var reached []*models.BoycottCampaignMilestone
+	for _, percent := range models.CampaignMilestonePercents {
+		threshold := campaign.MilestoneThreshold(percent)
+		if campaign.PledgeCount < threshold {
+			break
+		}
+
+		var milestones []*models.BoycottCampaignMilestone
+		err = tx.Select(&milestones, `
+			INSERT INTO boycott_campaign_milestones (campaign_id, percent, pledge_count)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (campaign_id, percent) DO NOTHING
+			RETURNING *
+		`, campaignID, percent, threshold)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to record boycott campaign milestone: %w", err)
+		}
+		reached = append(reached, milestones...)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return campaign, reached, nil
+}
+
+// Withdraw withdraws a user's pledge and returns whether there was one
+func (r *boycottCampaignRepository) Withdraw(campaignID, userID string) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		UPDATE boycott_campaign_pledges SET withdrawn_at = NOW()
+		WHERE campaign_id = $1 AND user_id = $2 AND withdrawn_at IS NULL
+	`, campaignID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw boycott campaign pledge: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	if _, err = updatePledgeCount(tx, campaignID); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// updatePledgeCount recounts the active pledges of a campaign
+func updatePledgeCount(tx *sqlx.Tx, campaignID string) (*models.BoycottCampaign, error) {
+	var campaign models.BoycottCampaign
+	err := tx.Get(&campaign, `
+		UPDATE boycott_campaigns SET
+			pledge_count = (
+				SELECT COUNT(*) FROM boycott_campaign_pledges
+				WHERE campaign_id = $1 AND withdrawn_at IS NULL
+			),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING *
+	`, campaignID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update boycott campaign pledge count: %w", err)
+	}
+
+	return &campaign, nil
+}
+
+// GetPledgedCampaignIDs returns which of the given campaigns a user currently pledges to
+func (r *boycottCampaignRepository) GetPledgedCampaignIDs(userID string, campaignIDs []string) ([]string, error) {
+	var ids []string
+	if len(campaignIDs) == 0 {
+		return ids, nil
+	}
+
+	query := `
+		SELECT campaign_id FROM boycott_campaign_pledges
+		WHERE user_id = $1 AND campaign_id::text = ANY($2) AND withdrawn_at IS NULL
+	`
+	if err := r.db.Select(&ids, query, userID, pq.Array(campaignIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get pledged boycott campaigns: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetMilestones returns the milestones a campaign reached, in the order it reached them
+func (r *boycottCampaignRepository) GetMilestones(campaignID string) ([]*models.BoycottCampaignMilestone, error) {
+	var milestones []*models.BoycottCampaignMilestone
+	query := `SELECT * FROM boycott_campaign_milestones WHERE campaign_id = $1 ORDER BY percent`
+
+	if err := r.db.Select(&milestones, query, campaignID); err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+// GetPledgeTotals returns the number of users who ever pledged to a campaign and how many of them
+// withdrew
+func (r *boycottCampaignRepository) GetPledgeTotals(campaignID string) (total, withdrawn int, err error) {
+	var totals struct {
+		Total     int `db:"total"`
+		Withdrawn int `db:"withdrawn"`
+	}
+	err = r.db.Get(&totals, `
+		SELECT
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE withdrawn_at IS NOT NULL) as withdrawn
+		FROM boycott_campaign_pledges
+		WHERE campaign_id = $1
+	`, campaignID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count boycott campaign pledges: %w", err)
+	}
+
+	return totals.Total, totals.Withdrawn, nil
+}
+
+// GetDailyProgress returns a campaign's pledges for each day between two dates, inclusive
+func (r *boycottCampaignRepository) GetDailyProgress(campaignID string, from, to time.Time) ([]*models.CampaignProgressPoint, error) {
+	query := `
+		SELECT
+			d.day,
+			COUNT(p.id) FILTER (
+				WHERE p.pledged_at < d.day + INTERVAL '1 day'
+				  AND (p.withdrawn_at IS NULL OR p.withdrawn_at >= d.day + INTERVAL '1 day')
+			) as pledge_count,
+			COUNT(p.id) FILTER (WHERE p.pledged_at >= d.day AND p.pledged_at < d.day + INTERVAL '1 day') as new_pledges,
+			COUNT(p.id) FILTER (WHERE p.withdrawn_at >= d.day AND p.withdrawn_at < d.day + INTERVAL '1 day') as withdrawals
+		FROM generate_series(date_trunc('day', $2::timestamptz), date_trunc('day', $3::timestamptz), INTERVAL '1 day') AS d(day)
+		LEFT JOIN boycott_campaign_pledges p ON p.campaign_id = $1
+		GROUP BY d.day
+		ORDER BY d.day
+	`
+
+	var points []*models.CampaignProgressPoint
+	if err := r.db.Select(&points, query, campaignID, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign progress: %w", err)
+	}
+
+	return points, nil
+}
@@ -16,6 +16,11 @@ type BoycottRepository interface {
 	GetBoycottCount(businessName, businessAddress string) (int, error)
 	IsBoycottedByUser(userID, businessName, businessAddress string) (bool, error)
 	Delete(userID, businessName, businessAddress string) error
+
+	// Trends
+	GetActivity(businessName, businessAddress, interval string, since time.Time) ([]*models.BoycottActivityPoint, error)
+	GetTrending(since, previousSince time.Time, limit int) ([]*models.TrendingBoycott, error)
+	GetRegionalStats(groupBy, provinceCode string, since time.Time) ([]*models.BoycottRegionStats, error)
 }
 
 type boycottRepository struct {
@@ -48,12 +53,15 @@ func (r *boycottRepository) Toggle(userID, businessName, businessAddress string)
 
 	err = tx.Get(&existing, checkQuery, checkArgs...)
 
-	if err == nil {
-		// Boycott exists, delete it (toggle off)
-		deleteQuery := `DELETE FROM boycotts WHERE id = $1`
-		_, err = tx.Exec(deleteQuery, existing.ID)
+	if err == nil && existing.CancelledAt == nil {
+		// Boycott is active, cancel it (toggle off). The row is kept for the boycott history.
+		cancelQuery := `UPDATE boycotts SET cancelled_at = NOW(), updated_at = NOW() WHERE id = $1`
+		_, err = tx.Exec(cancelQuery, existing.ID)
 		if err != nil {
-			return nil, fmt.Errorf("failed to delete boycott: %w", err)
+			return nil, fmt.Errorf("failed to cancel boycott: %w", err)
+		}
+		if err = insertBoycottEvent(tx, &existing, models.BoycottEventCancel); err != nil {
+			return nil, err
 		}
 
 		if err = tx.Commit(); err != nil {
@@ -63,6 +71,24 @@ func (r *boycottRepository) Toggle(userID, businessName, businessAddress string)
 		return nil, nil // Return nil to indicate boycott was removed
 	}
 
+	if err == nil {
+		// Boycott was cancelled before, start it again
+		restartQuery := `UPDATE boycotts SET cancelled_at = NULL, created_at = NOW(), updated_at = NOW() WHERE id = $1 RETURNING *`
+		var boycott models.Boycott
+		if err = tx.Get(&boycott, restartQuery, existing.ID); err != nil {
+			return nil, fmt.Errorf("failed to restart boycott: %w", err)
+		}
+		if err = insertBoycottEvent(tx, &boycott, models.BoycottEventStart); err != nil {
+			return nil, err
+		}
+
+		if err = tx.Commit(); err != nil {
+			return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		}
+
+		return &boycott, nil
+	}
+
 	// Boycott doesn't exist, create it (toggle on)
 	boycott := &models.Boycott{
 		ID:              uuid.New().String(),
@@ -82,6 +108,9 @@ func (r *boycottRepository) Toggle(userID, businessName, businessAddress string)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert boycott: %w", err)
 	}
+	if err = insertBoycottEvent(tx, boycott, models.BoycottEventStart); err != nil {
+		return nil, err
+	}
 
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -92,7 +121,7 @@ func (r *boycottRepository) Toggle(userID, businessName, businessAddress string)
 
 func (r *boycottRepository) GetByUserID(userID string, limit, offset int) ([]*models.Boycott, error) {
 	var boycotts []*models.Boycott
-	query := `SELECT * FROM boycotts WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	query := `SELECT * FROM boycotts WHERE user_id = $1 AND cancelled_at IS NULL ORDER BY created_at DESC LIMIT $2 OFFSET $3`
 
 	err := r.db.Select(&boycotts, query, userID, limit, offset)
 	if err != nil {
@@ -110,6 +139,7 @@ func (r *boycottRepository) GetTopBoycotted(limit int) ([]*models.BoycottStats,
 			COALESCE(business_address, '') as business_address,
 			COUNT(*) as boycott_count
 		FROM boycotts
+		WHERE cancelled_at IS NULL
 		GROUP BY business_name, business_address
 		ORDER BY boycott_count DESC
 		LIMIT $1
@@ -129,10 +159,10 @@ func (r *boycottRepository) GetBoycottCount(businessName, businessAddress string
 	var args []interface{}
 
 	if businessAddress == "" {
-		query = `SELECT COUNT(*) FROM boycotts WHERE business_name = $1 AND (business_address IS NULL OR business_address = '')`
+		query = `SELECT COUNT(*) FROM boycotts WHERE business_name = $1 AND (business_address IS NULL OR business_address = '') AND cancelled_at IS NULL`
 		args = []interface{}{businessName}
 	} else {
-		query = `SELECT COUNT(*) FROM boycotts WHERE business_name = $1 AND business_address = $2`
+		query = `SELECT COUNT(*) FROM boycotts WHERE business_name = $1 AND business_address = $2 AND cancelled_at IS NULL`
 		args = []interface{}{businessName, businessAddress}
 	}
 
@@ -150,10 +180,10 @@ func (r *boycottRepository) IsBoycottedByUser(userID, businessName, businessAddr
 	var args []interface{}
 
 	if businessAddress == "" {
-		query = `SELECT COUNT(*) FROM boycotts WHERE user_id = $1 AND business_name = $2 AND (business_address IS NULL OR business_address = '')`
+		query = `SELECT COUNT(*) FROM boycotts WHERE user_id = $1 AND business_name = $2 AND (business_address IS NULL OR business_address = '') AND cancelled_at IS NULL`
 		args = []interface{}{userID, businessName}
 	} else {
-		query = `SELECT COUNT(*) FROM boycotts WHERE user_id = $1 AND business_name = $2 AND business_address = $3`
+		query = `SELECT COUNT(*) FROM boycotts WHERE user_id = $1 AND business_name = $2 AND business_address = $3 AND cancelled_at IS NULL`
 		args = []interface{}{userID, businessName, businessAddress}
 	}
 
@@ -175,17 +205,24 @@ func (r *boycottRepository) Delete(userID, businessName, businessAddress string)
 	var query string
 	var args []interface{}
 
+	// Cancelled boycotts are kept for the boycott history, like toggled off ones
 	if businessAddress == "" {
-		query = `DELETE FROM boycotts WHERE user_id = $1 AND business_name = $2 AND (business_address IS NULL OR business_address = '')`
+		query = `UPDATE boycotts SET cancelled_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND business_name = $2 AND (business_address IS NULL OR business_address = '') AND cancelled_at IS NULL RETURNING *`
 		args = []interface{}{userID, businessName}
 	} else {
-		query = `DELETE FROM boycotts WHERE user_id = $1 AND business_name = $2 AND business_address = $3`
+		query = `UPDATE boycotts SET cancelled_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND business_name = $2 AND business_address = $3 AND cancelled_at IS NULL RETURNING *`
 		args = []interface{}{userID, businessName, businessAddress}
 	}
 
-	_, err = tx.Exec(query, args...)
+	var cancelled []*models.Boycott
+	err = tx.Select(&cancelled, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to delete boycott: %w", err)
+		return fmt.Errorf("failed to cancel boycott: %w", err)
+	}
+	for _, boycott := range cancelled {
+		if err = insertBoycottEvent(tx, boycott, models.BoycottEventCancel); err != nil {
+			return err
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -194,3 +231,130 @@ func (r *boycottRepository) Delete(userID, businessName, businessAddress string)
 
 	return nil
 }
+
+// insertBoycottEvent logs the start or cancellation of a boycott
+func insertBoycottEvent(tx *sqlx.Tx, boycott *models.Boycott, eventType string) error {
+	businessAddress := ""
+	if boycott.BusinessAddress != nil {
+		businessAddress = *boycott.BusinessAddress
+	}
+
+	query := `
+		INSERT INTO boycott_events (boycott_id, user_id, business_name, business_address, event_type)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	if _, err := tx.Exec(query, boycott.ID, boycott.UserID, boycott.BusinessName, businessAddress, eventType); err != nil {
+		return fmt.Errorf("failed to log boycott event: %w", err)
+	}
+
+	return nil
+}
+
+// GetActivity returns the new boycotts and cancellations in each day or week since a date, for
+// one business or for every business when the name is empty. Without an address, every location
+// of the business is included.
+func (r *boycottRepository) GetActivity(businessName, businessAddress, interval string, since time.Time) ([]*models.BoycottActivityPoint, error) {
+	if interval != "day" && interval != "week" {
+		return nil, fmt.Errorf("invalid boycott activity interval: %s", interval)
+	}
+
+	query := `
+		SELECT
+			periods.period,
+			COUNT(e.id) FILTER (WHERE e.event_type = 'boycott') as new_boycotts,
+			COUNT(e.id) FILTER (WHERE e.event_type = 'cancel') as cancellations,
+			COUNT(e.id) FILTER (WHERE e.event_type = 'boycott') - COUNT(e.id) FILTER (WHERE e.event_type = 'cancel') as net_boycotts
+		FROM generate_series(date_trunc($1, $2::timestamptz), date_trunc($1, NOW()), ('1 ' || $1)::interval) AS periods(period)
+		LEFT JOIN boycott_events e
+			ON date_trunc($1, e.created_at) = periods.period
+			AND ($3 = '' OR e.business_name = $3)
+			AND ($4 = '' OR e.business_address = $4)
+		GROUP BY periods.period
+		ORDER BY periods.period
+	`
+
+	var points []*models.BoycottActivityPoint
+	if err := r.db.Select(&points, query, interval, since, businessName, businessAddress); err != nil {
+		return nil, fmt.Errorf("failed to get boycott activity: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetTrending returns the businesses with the most net new boycotts since a date, along with their
+// new boycotts in the window before it, starting at previousSince
+func (r *boycottRepository) GetTrending(since, previousSince time.Time, limit int) ([]*models.TrendingBoycott, error) {
+	query := `
+		WITH activity AS (
+			SELECT
+				business_name,
+				business_address,
+				COUNT(*) FILTER (WHERE event_type = 'boycott' AND created_at >= $1) as new_boycotts,
+				COUNT(*) FILTER (WHERE event_type = 'cancel' AND created_at >= $1) as cancellations,
+				COUNT(*) FILTER (WHERE event_type = 'boycott' AND created_at < $1) as previous_new_boycotts
+			FROM boycott_events
+			WHERE created_at >= $2
+			GROUP BY business_name, business_address
+		)
+		SELECT
+			a.business_name,
+			a.business_address,
+			a.new_boycotts,
+			a.cancellations,
+			a.new_boycotts - a.cancellations as net_boycotts,
+			a.previous_new_boycotts,
+			(
+				SELECT COUNT(*) FROM boycotts b
+				WHERE b.business_name = a.business_name
+				  AND COALESCE(b.business_address, '') = a.business_address
+				  AND b.cancelled_at IS NULL
+			) as active_boycotts
+		FROM activity a
+		WHERE a.new_boycotts > 0
+		ORDER BY net_boycotts DESC, a.new_boycotts DESC, active_boycotts DESC
+		LIMIT $3
+	`
+
+	var trending []*models.TrendingBoycott
+	if err := r.db.Select(&trending, query, since, previousSince, limit); err != nil {
+		return nil, fmt.Errorf("failed to get trending boycotts: %w", err)
+	}
+
+	return trending, nil
+}
+
+// GetRegionalStats rolls up boycotts by the province or municipality of their parsed business
+// address, optionally within one province. New boycotts and cancellations are counted since a date.
+// Boycotts whose address couldn't be placed in a province are left out.
+func (r *boycottRepository) GetRegionalStats(groupBy, provinceCode string, since time.Time) ([]*models.BoycottRegionStats, error) {
+	municipality := "NULL::text"
+	if groupBy == "municipality" {
+		municipality = "pa.municipality"
+	} else if groupBy != "province" {
+		return nil, fmt.Errorf("invalid boycott region grouping: %s", groupBy)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			pa.province_code,
+			%s as municipality,
+			COUNT(DISTINCT b.id) FILTER (WHERE b.cancelled_at IS NULL) as active_boycotts,
+			COUNT(DISTINCT (b.business_name, b.business_address)) FILTER (WHERE b.cancelled_at IS NULL) as businesses,
+			COUNT(e.id) FILTER (WHERE e.event_type = 'boycott') as new_boycotts,
+			COUNT(e.id) FILTER (WHERE e.event_type = 'cancel') as cancellations
+		FROM boycotts b
+		JOIN parsed_addresses pa ON pa.id = b.parsed_address_id
+		LEFT JOIN boycott_events e ON e.boycott_id = b.id AND e.created_at >= $1
+		WHERE pa.province_code IS NOT NULL
+		  AND ($2 = '' OR pa.province_code = $2)
+		GROUP BY 1, 2
+		ORDER BY active_boycotts DESC, new_boycotts DESC, 1, 2
+	`, municipality)
+
+	var stats []*models.BoycottRegionStats
+	if err := r.db.Select(&stats, query, since, provinceCode); err != nil {
+		return nil, fmt.Errorf("failed to get regional boycott stats: %w", err)
+	}
+
+	return stats, nil
+}
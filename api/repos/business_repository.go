@@ -1,13 +1,321 @@
 package repos
 
-import "github.com/jmoiron/sqlx"
+import (
+	"canada-hires/models"
+	"database/sql"
+	"fmt"
 
-type BusinessRepository interface{}
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
 
-type businessRepository struct{
+type BusinessRepository interface {
+	// Claims
+	SaveClaim(claim *models.BusinessClaim) error
+	GetClaimByID(id string) (*models.BusinessClaim, error)
+	GetClaimByTokenHash(tokenHash string) (*models.BusinessClaim, error)
+	GetClaimsByUserID(userID string) ([]*models.BusinessClaim, error)
+	GetVerifiedClaim(normalizedName, normalizedAddress string) (*models.BusinessClaim, error)
+	ConfirmClaimEmail(id string) (*models.BusinessClaim, error)
+	GetClaimQueue(limit, offset int) ([]*models.BusinessClaim, int, error)
+	ApproveClaim(id, moderatorID string) (*models.BusinessClaim, error)
+	RevokeClaim(id, moderatorID string, reason *string) (*models.BusinessClaim, error)
+
+	// Replies
+	SaveReply(reply *models.BusinessReply) error
+	GetReplyByID(id string) (*models.BusinessReply, error)
+	GetPublishedReportReplies(reportIDs []string) (map[string]*models.BusinessReply, error)
+	GetPublishedStatements(normalizedNames, normalizedAddresses []string) ([]*models.BusinessReply, error)
+	GetReplyQueue(status string, limit, offset int) ([]*models.BusinessReply, int, error)
+	ModerateReply(id string, moderation models.BusinessReplyModeration) (*models.BusinessReply, error)
+}
+
+type businessRepository struct {
 	db *sqlx.DB
 }
 
 func NewBusinessRepository(db *sqlx.DB) BusinessRepository {
 	return &businessRepository{db: db}
 }
+
+// Replies are read with the business they belong to
+const businessReplySelect = `
+	SELECT br.*, bc.business_name, bc.business_address
+	FROM business_replies br
+	JOIN business_claims bc ON bc.id = br.claim_id
+`
+
+// SaveClaim stores a new claim. A user claiming the same business again while their claim is
+// pending gets a new verification token instead of a second claim.
+func (r *businessRepository) SaveClaim(claim *models.BusinessClaim) error {
+	query := `
+		INSERT INTO business_claims (user_id, business_name, normalized_name, business_address, normalized_address,
+			email, email_domain, token_hash, token_expires_at, status)
+		VALUES (:user_id, :business_name, :normalized_name, :business_address, :normalized_address,
+			:email, :email_domain, :token_hash, :token_expires_at, 'pending')
+		ON CONFLICT (user_id, normalized_name, normalized_address) WHERE status = 'pending' DO UPDATE SET
+			business_name = EXCLUDED.business_name,
+			business_address = EXCLUDED.business_address,
+			email = EXCLUDED.email,
+			email_domain = EXCLUDED.email_domain,
+			token_hash = EXCLUDED.token_hash,
+			token_expires_at = EXCLUDED.token_expires_at,
+			updated_at = NOW()
+		RETURNING *
+	`
+
+	rows, err := r.db.NamedQuery(query, claim)
+	if err != nil {
+		return fmt.Errorf("failed to save business claim: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.StructScan(claim); err != nil {
+			return fmt.Errorf("failed to read saved business claim: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *businessRepository) GetClaimByID(id string) (*models.BusinessClaim, error) {
+	var claim models.BusinessClaim
+	if err := r.db.Get(&claim, `SELECT * FROM business_claims WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *businessRepository) GetClaimByTokenHash(tokenHash string) (*models.BusinessClaim, error) {
+	var claim models.BusinessClaim
+	if err := r.db.Get(&claim, `SELECT * FROM business_claims WHERE token_hash = $1`, tokenHash); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// GetClaimsByUserID returns a user's claims, newest first
+func (r *businessRepository) GetClaimsByUserID(userID string) ([]*models.BusinessClaim, error) {
+	var claims []*models.BusinessClaim
+	err := r.db.Select(&claims, `SELECT * FROM business_claims WHERE user_id = $1 ORDER BY created_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business claims: %w", err)
+	}
+	return claims, nil
+}
+
+// GetVerifiedClaim returns the verified claim of a business
+func (r *businessRepository) GetVerifiedClaim(normalizedName, normalizedAddress string) (*models.BusinessClaim, error) {
+	var claim models.BusinessClaim
+	query := `SELECT * FROM business_claims WHERE normalized_name = $1 AND normalized_address = $2 AND status = 'verified'`
+	if err := r.db.Get(&claim, query, normalizedName, normalizedAddress); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// ConfirmClaimEmail records that the emailed link of a pending claim was opened. The claim then
+// waits for a moderator. It fails with a unique violation when the user has another claim on the
+// business awaiting review.
+func (r *businessRepository) ConfirmClaimEmail(id string) (*models.BusinessClaim, error) {
+	query := `
+		UPDATE business_claims SET
+			status = 'awaiting_review',
+			email_verified_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING *
+	`
+
+	var claim models.BusinessClaim
+	if err := r.db.Get(&claim, query, id); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// GetClaimQueue returns claims awaiting review, oldest first, and their total
+func (r *businessRepository) GetClaimQueue(limit, offset int) ([]*models.BusinessClaim, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM business_claims WHERE status = 'awaiting_review'`); err != nil {
+		return nil, 0, fmt.Errorf("failed to count business claims: %w", err)
+	}
+
+	var claims []*models.BusinessClaim
+	query := `SELECT * FROM business_claims WHERE status = 'awaiting_review' ORDER BY updated_at ASC LIMIT $1 OFFSET $2`
+	if err := r.db.Select(&claims, query, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get business claim queue: %w", err)
+	}
+
+	return claims, total, nil
+}
+
+// ApproveClaim marks a claim awaiting review as verified. It fails with a unique violation when the
+// business was verified by another claim in the meantime.
+func (r *businessRepository) ApproveClaim(id, moderatorID string) (*models.BusinessClaim, error) {
+	query := `
+		UPDATE business_claims SET
+			status = 'verified',
+			verified_at = NOW(),
+			approved_by = $2,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'awaiting_review'
+		RETURNING *
+	`
+
+	var claim models.BusinessClaim
+	if err := r.db.Get(&claim, query, id, moderatorID); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// RevokeClaim withdraws a claim. Replies of a revoked claim are no longer shown.
+func (r *businessRepository) RevokeClaim(id, moderatorID string, reason *string) (*models.BusinessClaim, error) {
+	query := `
+		UPDATE business_claims SET
+			status = 'revoked',
+			revoked_by = $2,
+			revoked_at = NOW(),
+			revocation_reason = $3,
+			updated_at = NOW()
+		WHERE id = $1 AND status <> 'revoked'
+		RETURNING *
+	`
+
+	var claim models.BusinessClaim
+	if err := r.db.Get(&claim, query, id, moderatorID, reason); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// SaveReply stores a reply, replacing the report's earlier response or the claim's earlier
+// statement. Changed replies wait for moderation again.
+func (r *businessRepository) SaveReply(reply *models.BusinessReply) error {
+	conflict := `(report_id) WHERE report_id IS NOT NULL`
+	if reply.ReportID == nil {
+		conflict = `(claim_id) WHERE report_id IS NULL`
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO business_replies (claim_id, report_id, body, status)
+		VALUES (:claim_id, :report_id, :body, 'pending')
+		ON CONFLICT %s DO UPDATE SET
+			claim_id = EXCLUDED.claim_id,
+			body = EXCLUDED.body,
+			status = 'pending',
+			moderated_by = NULL,
+			moderated_at = NULL,
+			moderation_notes = NULL,
+			updated_at = NOW()
+		RETURNING id
+	`, conflict)
+
+	rows, err := r.db.NamedQuery(query, reply)
+	if err != nil {
+		return fmt.Errorf("failed to save business reply: %w", err)
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		if err := rows.Scan(&reply.ID); err != nil {
+			return fmt.Errorf("failed to read saved business reply: %w", err)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *businessRepository) GetReplyByID(id string) (*models.BusinessReply, error) {
+	var reply models.BusinessReply
+	if err := r.db.Get(&reply, businessReplySelect+` WHERE br.id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+// GetPublishedReportReplies returns the published responses of verified businesses to the given
+// reports, by report ID
+func (r *businessRepository) GetPublishedReportReplies(reportIDs []string) (map[string]*models.BusinessReply, error) {
+	replies := make(map[string]*models.BusinessReply)
+	if len(reportIDs) == 0 {
+		return replies, nil
+	}
+
+	var rows []*models.BusinessReply
+	query := businessReplySelect + `
+		WHERE br.report_id::text = ANY($1)
+		  AND br.status = 'published'
+		  AND bc.status = 'verified'
+	`
+	if err := r.db.Select(&rows, query, pq.Array(reportIDs)); err != nil {
+		return nil, fmt.Errorf("failed to get business replies: %w", err)
+	}
+
+	for _, reply := range rows {
+		replies[*reply.ReportID] = reply
+	}
+	return replies, nil
+}
+
+// GetPublishedStatements returns the published statements of verified businesses, for pairs of
+// normalized names and addresses at the same index
+func (r *businessRepository) GetPublishedStatements(normalizedNames, normalizedAddresses []string) ([]*models.BusinessReply, error) {
+	if len(normalizedNames) == 0 {
+		return nil, nil
+	}
+
+	var statements []*models.BusinessReply
+	query := `
+		SELECT br.*, bc.business_name, bc.business_address
+		FROM unnest($1::text[], $2::text[]) AS business(normalized_name, normalized_address)
+		JOIN business_claims bc ON bc.normalized_name = business.normalized_name
+		  AND bc.normalized_address = business.normalized_address
+		  AND bc.status = 'verified'
+		JOIN business_replies br ON br.claim_id = bc.id
+		  AND br.report_id IS NULL
+		  AND br.status = 'published'
+	`
+	if err := r.db.Select(&statements, query, pq.Array(normalizedNames), pq.Array(normalizedAddresses)); err != nil {
+		return nil, fmt.Errorf("failed to get business statements: %w", err)
+	}
+	return statements, nil
+}
+
+// GetReplyQueue returns replies in a status, oldest first, and their total
+func (r *businessRepository) GetReplyQueue(status string, limit, offset int) ([]*models.BusinessReply, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM business_replies WHERE status = $1`, status); err != nil {
+		return nil, 0, fmt.Errorf("failed to count business replies: %w", err)
+	}
+
+	var replies []*models.BusinessReply
+	query := businessReplySelect + ` WHERE br.status = $1 ORDER BY br.updated_at ASC LIMIT $2 OFFSET $3`
+	if err := r.db.Select(&replies, query, status, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get business reply queue: %w", err)
+	}
+
+	return replies, total, nil
+}
+
+// ModerateReply publishes or rejects a reply
+func (r *businessRepository) ModerateReply(id string, moderation models.BusinessReplyModeration) (*models.BusinessReply, error) {
+	query := `
+		UPDATE business_replies SET
+			status = $2,
+			moderated_by = $3,
+			moderated_at = NOW(),
+			moderation_notes = $4,
+			updated_at = NOW()
+		WHERE id = $1
+	`
+	res, err := r.db.Exec(query, id, moderation.Status, moderation.ModeratorID, moderation.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate business reply: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return nil, fmt.Errorf("failed to moderate business reply: %w", sql.ErrNoRows)
+	}
+
+	return r.GetReplyByID(id)
+}
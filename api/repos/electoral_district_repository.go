@@ -0,0 +1,308 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ElectoralDistrictRepository interface {
+	ReplaceAll(districts []*models.ElectoralDistrict) error
+	GetAllWithBoundaries() ([]*models.ElectoralDistrict, error)
+	List(provinceCode string) ([]*models.ElectoralDistrict, error)
+	GetByID(id string) (*models.ElectoralDistrict, error)
+	GetByPostalCode(postalCode string) (*models.ElectoralDistrict, error)
+	GetUnassignedPoints(dataset, after string, limit int) ([]models.GeoPoint, error)
+	AssignPoints(dataset string, assignments []models.ElectoralDistrictAssignment) (int64, error)
+	GetSummary(id string, year int) (*models.ElectoralDistrictSummary, error)
+}
+
+type electoralDistrictRepository struct {
+	db *sqlx.DB
+}
+
+func NewElectoralDistrictRepository(db *sqlx.DB) ElectoralDistrictRepository {
+	return &electoralDistrictRepository{db: db}
+}
+
+// electoralDistrictColumns are the columns of a district without its boundary
+const electoralDistrictColumns = `
+	id, name_en, name_fr, province_code, min_latitude, min_longitude, max_latitude, max_longitude,
+	source_file, imported_at`
+
+// districtDatasetQueries holds, for each dataset, the query selecting the geocoded points without
+// a district after a key, and the statement assigning districts to keys given as arrays
+var districtDatasetQueries = map[string]struct{ unassigned, assign string }{
+	models.DistrictDatasetPostalCodes: {
+		unassigned: `
+			SELECT postal_code as key, latitude::float8 as latitude, longitude::float8 as longitude
+			FROM postal_codes
+			WHERE electoral_district_id IS NULL
+			  AND latitude IS NOT NULL
+			  AND longitude IS NOT NULL
+			  AND postal_code > $1
+			ORDER BY postal_code
+			LIMIT $2`,
+		assign: `
+			UPDATE postal_codes pc
+			SET electoral_district_id = v.district_id
+			FROM unnest($1::text[], $2::text[]) AS v(key, district_id)
+			WHERE pc.postal_code = v.key`,
+	},
+	models.DistrictDatasetAddresses: {
+		unassigned: `
+			SELECT id::text as key, latitude, longitude
+			FROM address_geocoding_cache
+			WHERE electoral_district_id IS NULL
+			  AND id > $1::int
+			ORDER BY id
+			LIMIT $2`,
+		assign: `
+			UPDATE address_geocoding_cache agc
+			SET electoral_district_id = v.district_id
+			FROM unnest($1::text[], $2::text[]) AS v(key, district_id)
+			WHERE agc.id = v.key::int`,
+	},
+	// Postings are geocoded once per distinct location, so they are assigned per location too
+	models.DistrictDatasetJobPostings: {
+		unassigned: `
+			SELECT location as key, MIN(latitude) as latitude, MIN(longitude) as longitude
+			FROM job_postings
+			WHERE electoral_district_id IS NULL
+			  AND latitude IS NOT NULL
+			  AND longitude IS NOT NULL
+			  AND location > $1
+			GROUP BY location
+			ORDER BY location
+			LIMIT $2`,
+		assign: `
+			UPDATE job_postings jp
+			SET electoral_district_id = v.district_id
+			FROM unnest($1::text[], $2::text[]) AS v(key, district_id)
+			WHERE jp.location = v.key AND jp.latitude IS NOT NULL`,
+	},
+}
+
+func districtDatasetQuery(dataset string) (unassigned, assign string, err error) {
+	queries, ok := districtDatasetQueries[dataset]
+	if !ok {
+		return "", "", fmt.Errorf("unknown electoral district dataset: %s", dataset)
+	}
+	return queries.unassigned, queries.assign, nil
+}
+
+// ReplaceAll replaces every district with those of a new boundary file. Deleting the old
+// districts clears the district of every geocoded point, so they are all assigned again.
+func (r *electoralDistrictRepository) ReplaceAll(districts []*models.ElectoralDistrict) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM electoral_districts`); err != nil {
+		return fmt.Errorf("failed to delete electoral districts: %w", err)
+	}
+
+	query := `
+		INSERT INTO electoral_districts (
+			id, name_en, name_fr, province_code, min_latitude, min_longitude, max_latitude,
+			max_longitude, boundary, source_file, imported_at
+		) VALUES (
+			:id, :name_en, :name_fr, :province_code, :min_latitude, :min_longitude, :max_latitude,
+			:max_longitude, :boundary, :source_file, NOW()
+		)`
+
+	// Boundaries are large, insert them one at a time
+	for _, district := range districts {
+		if _, err := tx.NamedExec(query, district); err != nil {
+			return fmt.Errorf("failed to insert electoral district %s: %w", district.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit electoral districts: %w", err)
+	}
+
+	return nil
+}
+
+// GetAllWithBoundaries returns every district with its boundary
+func (r *electoralDistrictRepository) GetAllWithBoundaries() ([]*models.ElectoralDistrict, error) {
+	var districts []*models.ElectoralDistrict
+	query := `SELECT ` + electoralDistrictColumns + `, boundary FROM electoral_districts ORDER BY id`
+	if err := r.db.Select(&districts, query); err != nil {
+		return nil, fmt.Errorf("failed to get electoral district boundaries: %w", err)
+	}
+	return districts, nil
+}
+
+// List returns the districts of a province, or of the whole country when provinceCode is empty
+func (r *electoralDistrictRepository) List(provinceCode string) ([]*models.ElectoralDistrict, error) {
+	query := `
+		SELECT ` + electoralDistrictColumns + `
+		FROM electoral_districts
+		WHERE ($1 = '' OR province_code = $1)
+		ORDER BY province_code, name_en
+	`
+
+	var districts []*models.ElectoralDistrict
+	if err := r.db.Select(&districts, query, provinceCode); err != nil {
+		return nil, fmt.Errorf("failed to list electoral districts: %w", err)
+	}
+	return districts, nil
+}
+
+func (r *electoralDistrictRepository) GetByID(id string) (*models.ElectoralDistrict, error) {
+	var district models.ElectoralDistrict
+	query := `SELECT ` + electoralDistrictColumns + ` FROM electoral_districts WHERE id = $1`
+	if err := r.db.Get(&district, query, id); err != nil {
+		return nil, err
+	}
+	return &district, nil
+}
+
+// GetByPostalCode returns the district a postal code was assigned to
+func (r *electoralDistrictRepository) GetByPostalCode(postalCode string) (*models.ElectoralDistrict, error) {
+	var district models.ElectoralDistrict
+	query := `
+		SELECT ` + electoralDistrictColumns + `
+		FROM electoral_districts
+		WHERE id = (SELECT electoral_district_id FROM postal_codes WHERE postal_code = $1)
+	`
+	if err := r.db.Get(&district, query, postalCode); err != nil {
+		return nil, err
+	}
+	return &district, nil
+}
+
+// GetUnassignedPoints returns the geocoded points of a dataset without a district, ordered by key
+// and starting after the given key, so points outside every district are only read once per run
+func (r *electoralDistrictRepository) GetUnassignedPoints(dataset, after string, limit int) ([]models.GeoPoint, error) {
+	query, _, err := districtDatasetQuery(dataset)
+	if err != nil {
+		return nil, err
+	}
+
+	// Address cache keys are numeric IDs
+	if after == "" && dataset == models.DistrictDatasetAddresses {
+		after = "0"
+	}
+
+	var points []models.GeoPoint
+	if err := r.db.Select(&points, query, after, limit); err != nil {
+		return nil, fmt.Errorf("failed to get unassigned %s: %w", dataset, err)
+	}
+	return points, nil
+}
+
+// AssignPoints stores the district of each point and returns the number of records updated
+func (r *electoralDistrictRepository) AssignPoints(dataset string, assignments []models.ElectoralDistrictAssignment) (int64, error) {
+	_, query, err := districtDatasetQuery(dataset)
+	if err != nil {
+		return 0, err
+	}
+	if len(assignments) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, len(assignments))
+	districtIDs := make([]string, len(assignments))
+	for i, assignment := range assignments {
+		keys[i] = assignment.Key
+		districtIDs[i] = assignment.DistrictID
+	}
+
+	result, err := r.db.Exec(query, pq.Array(keys), pq.Array(districtIDs))
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign electoral districts to %s: %w", dataset, err)
+	}
+	return result.RowsAffected()
+}
+
+// GetSummary totals the records located in a district, for a single year when year is set. LMIA
+// employers are located by postal code, non-compliant employers by geocoded address or postal code
+// and job postings by their geocoded location.
+func (r *electoralDistrictRepository) GetSummary(id string, year int) (*models.ElectoralDistrictSummary, error) {
+	summary := &models.ElectoralDistrictSummary{}
+
+	args := []interface{}{id}
+	lmiaYear, decisionYear, postingYear := "", "", ""
+	if year > 0 {
+		args = append(args, year)
+		lmiaYear = " AND e.year = $2"
+		decisionYear = " AND EXTRACT(YEAR FROM e.date_of_final_decision) = $2"
+		postingYear = " AND EXTRACT(YEAR FROM posting_date) = $2"
+	}
+
+	lmiaQuery := `
+		SELECT
+			COUNT(DISTINCT e.employer) as employers,
+			COALESCE(SUM(e.approved_lmias), 0) as approved_lmias,
+			COALESCE(SUM(e.approved_positions), 0) as approved_positions
+		FROM lmia_employers e
+		JOIN postal_codes pc ON pc.postal_code = e.postal_code
+		WHERE pc.electoral_district_id = $1` + lmiaYear
+	if err := r.db.Get(&summary.LMIA, lmiaQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get district LMIA totals: %w", err)
+	}
+
+	byYearQuery := `
+		SELECT
+			e.year,
+			COUNT(DISTINCT e.employer) as employers,
+			COALESCE(SUM(e.approved_lmias), 0) as approved_lmias,
+			COALESCE(SUM(e.approved_positions), 0) as approved_positions
+		FROM lmia_employers e
+		JOIN postal_codes pc ON pc.postal_code = e.postal_code
+		WHERE pc.electoral_district_id = $1
+		GROUP BY e.year
+		ORDER BY e.year`
+	if err := r.db.Select(&summary.LMIAByYear, byYearQuery, id); err != nil {
+		return nil, fmt.Errorf("failed to get district LMIA totals by year: %w", err)
+	}
+
+	topEmployersQuery := `
+		SELECT
+			e.employer,
+			COALESCE(SUM(e.approved_lmias), 0) as approved_lmias,
+			COALESCE(SUM(e.approved_positions), 0) as approved_positions
+		FROM lmia_employers e
+		JOIN postal_codes pc ON pc.postal_code = e.postal_code
+		WHERE pc.electoral_district_id = $1` + lmiaYear + `
+		GROUP BY e.employer
+		ORDER BY approved_positions DESC, e.employer
+		LIMIT 10`
+	if err := r.db.Select(&summary.TopEmployers, topEmployersQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get district top employers: %w", err)
+	}
+
+	nonCompliantQuery := `
+		SELECT
+			COUNT(*) as employers,
+			COALESCE(SUM(e.penalty_amount), 0) as total_penalty_amount,
+			MAX(e.date_of_final_decision) as latest_decision
+		FROM non_compliant_employers e
+		LEFT JOIN parsed_addresses pa ON pa.id = e.parsed_address_id
+		LEFT JOIN address_geocoding_cache agc ON agc.normalized_address = pa.normalized_address
+		LEFT JOIN postal_codes pc ON pc.postal_code = COALESCE(e.postal_code, pa.postal_code)
+		WHERE COALESCE(agc.electoral_district_id, pc.electoral_district_id) = $1` + decisionYear
+	if err := r.db.Get(&summary.NonCompliant, nonCompliantQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get district non-compliant totals: %w", err)
+	}
+
+	jobsQuery := `
+		SELECT
+			COUNT(*) as total,
+			COUNT(*) FILTER (WHERE is_tfw) as tfw_postings,
+			COUNT(*) FILTER (WHERE has_lmia) as lmia_postings
+		FROM job_postings
+		WHERE electoral_district_id = $1` + postingYear
+	if err := r.db.Get(&summary.JobPostings, jobsQuery, args...); err != nil {
+		return nil, fmt.Errorf("failed to get district job posting totals: %w", err)
+	}
+
+	return summary, nil
+}
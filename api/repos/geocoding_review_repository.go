@@ -0,0 +1,339 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Results within this many degrees of a rejected one, about 10 metres, count as the same result
+const rejectedResultTolerance = 0.0001
+
+type GeocodingReviewRepository interface {
+	Enqueue(review *models.GeocodingReview) (bool, error)
+	IsRejected(targetType, targetKey, provider string, latitude, longitude float64) (bool, error)
+	List(filter models.GeocodingReviewFilter) ([]*models.GeocodingReview, int, error)
+	GetByID(id int) (*models.GeocodingReview, error)
+	GetStatusCounts() (map[string]int, error)
+	Resolve(id int, decision models.GeocodingReviewDecision) (*models.GeocodingReview, error)
+}
+
+type geocodingReviewRepository struct {
+	db *sqlx.DB
+}
+
+func NewGeocodingReviewRepository(db *sqlx.DB) GeocodingReviewRepository {
+	return &geocodingReviewRepository{db: db}
+}
+
+const geocodingReviewColumns = `
+	id, target_type, target_key, query, expected_province_code, provider, latitude, longitude,
+	confidence, precision, match_type, layer, result_postal_code, result_province_code, reason,
+	detail, stored, status, reviewed_by, reviewed_at, review_notes, created_at, updated_at`
+
+// Enqueue adds a result to the review queue, replacing the pending review of the same target.
+// Results a reviewer already rejected aren't queued again. Returns whether the result was queued.
+func (r *geocodingReviewRepository) Enqueue(review *models.GeocodingReview) (bool, error) {
+	query := `
+		INSERT INTO geocoding_reviews (
+			target_type, target_key, query, expected_province_code, provider, latitude, longitude,
+			confidence, precision, match_type, layer, result_postal_code, result_province_code,
+			reason, detail, stored
+		)
+		SELECT :target_type, :target_key, :query, :expected_province_code, :provider, :latitude, :longitude,
+			:confidence, :precision, :match_type, :layer, :result_postal_code, :result_province_code,
+			:reason, :detail, :stored
+		WHERE NOT EXISTS (
+			SELECT 1 FROM geocoding_reviews
+			WHERE target_type = :target_type
+			  AND target_key = :target_key
+			  AND provider = :provider
+			  AND status = 'rejected'
+			  AND ABS(latitude - :latitude) < :tolerance
+			  AND ABS(longitude - :longitude) < :tolerance
+		)
+		ON CONFLICT (target_type, target_key) WHERE status = 'pending' DO UPDATE SET
+			query = EXCLUDED.query,
+			expected_province_code = EXCLUDED.expected_province_code,
+			provider = EXCLUDED.provider,
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			confidence = EXCLUDED.confidence,
+			precision = EXCLUDED.precision,
+			match_type = EXCLUDED.match_type,
+			layer = EXCLUDED.layer,
+			result_postal_code = EXCLUDED.result_postal_code,
+			result_province_code = EXCLUDED.result_province_code,
+			reason = EXCLUDED.reason,
+			detail = EXCLUDED.detail,
+			stored = EXCLUDED.stored,
+			updated_at = NOW()
+	`
+
+	args := map[string]interface{}{
+		"target_type":            review.TargetType,
+		"target_key":             review.TargetKey,
+		"query":                  review.Query,
+		"expected_province_code": review.ExpectedProvinceCode,
+		"provider":               review.Provider,
+		"latitude":               review.Latitude,
+		"longitude":              review.Longitude,
+		"confidence":             review.Confidence,
+		"precision":              review.Precision,
+		"match_type":             review.MatchType,
+		"layer":                  review.Layer,
+		"result_postal_code":     review.ResultPostalCode,
+		"result_province_code":   review.ResultProvinceCode,
+		"reason":                 review.Reason,
+		"detail":                 review.Detail,
+		"stored":                 review.Stored,
+		"tolerance":              rejectedResultTolerance,
+	}
+
+	result, err := r.db.NamedExec(query, args)
+	if err != nil {
+		return false, fmt.Errorf("failed to queue geocoding review: %w", err)
+	}
+
+	queued, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get queued geocoding reviews: %w", err)
+	}
+
+	return queued > 0, nil
+}
+
+// IsRejected reports whether a reviewer rejected this result for the target before
+func (r *geocodingReviewRepository) IsRejected(targetType, targetKey, provider string, latitude, longitude float64) (bool, error) {
+	var rejected bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM geocoding_reviews
+			WHERE target_type = $1
+			  AND target_key = $2
+			  AND provider = $3
+			  AND status = 'rejected'
+			  AND ABS(latitude - $4) < $6
+			  AND ABS(longitude - $5) < $6
+		)`
+
+	if err := r.db.Get(&rejected, query, targetType, targetKey, provider, latitude, longitude, rejectedResultTolerance); err != nil {
+		return false, fmt.Errorf("failed to check rejected geocoding results: %w", err)
+	}
+
+	return rejected, nil
+}
+
+// List returns the reviews matching the filter, oldest first, and the total number of matches
+func (r *geocodingReviewRepository) List(filter models.GeocodingReviewFilter) ([]*models.GeocodingReview, int, error) {
+	var conditions []string
+	var args []interface{}
+	addCondition := func(column, value string) {
+		if value == "" {
+			return
+		}
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+	addCondition("status", filter.Status)
+	addCondition("reason", filter.Reason)
+	addCondition("target_type", filter.TargetType)
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := r.db.Get(&total, "SELECT COUNT(*) FROM geocoding_reviews "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("failed to count geocoding reviews: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM geocoding_reviews
+		%s
+		ORDER BY created_at, id
+		LIMIT $%d OFFSET $%d`,
+		geocodingReviewColumns, where, len(args)+1, len(args)+2)
+
+	var reviews []*models.GeocodingReview
+	if err := r.db.Select(&reviews, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list geocoding reviews: %w", err)
+	}
+
+	return reviews, total, nil
+}
+
+func (r *geocodingReviewRepository) GetByID(id int) (*models.GeocodingReview, error) {
+	var review models.GeocodingReview
+	query := fmt.Sprintf(`SELECT %s FROM geocoding_reviews WHERE id = $1`, geocodingReviewColumns)
+
+	if err := r.db.Get(&review, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get geocoding review: %w", err)
+	}
+
+	return &review, nil
+}
+
+// GetStatusCounts returns the number of reviews in each status
+func (r *geocodingReviewRepository) GetStatusCounts() (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	if err := r.db.Select(&rows, `SELECT status, COUNT(*) as count FROM geocoding_reviews GROUP BY status`); err != nil {
+		return nil, fmt.Errorf("failed to count geocoding reviews by status: %w", err)
+	}
+
+	counts := make(map[string]int, len(models.GeocodingReviewStatuses))
+	for _, status := range models.GeocodingReviewStatuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// Resolve records a reviewer's decision on a pending review and applies it to the stored
+// coordinates in the same transaction. Accepted and pinned coordinates are stored as a manual
+// override, a rejected result is removed if it was stored. Returns sql.ErrNoRows when the review
+// doesn't exist or was already resolved.
+func (r *geocodingReviewRepository) Resolve(id int, decision models.GeocodingReviewDecision) (*models.GeocodingReview, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var review models.GeocodingReview
+	query := fmt.Sprintf(`
+		UPDATE geocoding_reviews
+		SET status = $2, reviewed_by = $3, reviewed_at = NOW(), review_notes = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING %s`, geocodingReviewColumns)
+
+	if err := tx.Get(&review, query, id, decision.Status, decision.ReviewerID, decision.Notes); err != nil {
+		return nil, fmt.Errorf("failed to resolve geocoding review: %w", err)
+	}
+
+	switch decision.Status {
+	case models.GeocodingReviewAccepted:
+		err = storeOverride(tx, &review, review.Provider, review.Latitude, review.Longitude, review.Confidence, review.Precision, review.MatchType, review.Layer)
+	case models.GeocodingReviewPinned:
+		// Coordinates placed by hand are as precise as the target itself
+		precision := models.PrecisionAddress
+		if review.TargetType == models.GeocodingTargetPostalCode {
+			precision = models.PrecisionPostalCode
+		}
+		confidence := 1.0
+		err = storeOverride(tx, &review, models.PostalCodeProviderManual, decision.Latitude, decision.Longitude, &confidence, &precision, nil, nil)
+	case models.GeocodingReviewRejected:
+		if review.Stored {
+			err = removeRejected(tx, &review)
+		}
+	default:
+		err = fmt.Errorf("invalid geocoding review status: %s", decision.Status)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return &review, nil
+}
+
+// storeOverride saves the coordinates of a review's target as a manual override
+func storeOverride(tx *sqlx.Tx, review *models.GeocodingReview, provider string, latitude, longitude float64, confidence *float64, precision, matchType, layer *string) error {
+	var query string
+	var args []interface{}
+	switch review.TargetType {
+	case models.GeocodingTargetPostalCode:
+		query = `
+			INSERT INTO postal_codes (postal_code, latitude, longitude, provider, confidence, precision, match_type, layer, manual_override, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, TRUE, NOW(), NOW())
+			ON CONFLICT (postal_code) DO UPDATE SET
+				latitude = EXCLUDED.latitude,
+				longitude = EXCLUDED.longitude,
+				provider = EXCLUDED.provider,
+				confidence = EXCLUDED.confidence,
+				precision = EXCLUDED.precision,
+				match_type = EXCLUDED.match_type,
+				layer = EXCLUDED.layer,
+				manual_override = TRUE,
+				electoral_district_id = CASE
+					WHEN (postal_codes.latitude, postal_codes.longitude) IS NOT DISTINCT FROM (EXCLUDED.latitude, EXCLUDED.longitude)
+					THEN postal_codes.electoral_district_id
+				END,
+				updated_at = NOW()`
+		args = []interface{}{review.TargetKey, latitude, longitude, provider, confidence, precision, matchType, layer}
+	case models.GeocodingTargetAddress:
+		query = `
+			INSERT INTO address_geocoding_cache (address, normalized_address, latitude, longitude, confidence, provider, precision, match_type, layer, manual_override, geocoded_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, TRUE, CURRENT_TIMESTAMP)
+			ON CONFLICT (normalized_address) DO UPDATE SET
+				latitude = EXCLUDED.latitude,
+				longitude = EXCLUDED.longitude,
+				confidence = EXCLUDED.confidence,
+				provider = EXCLUDED.provider,
+				precision = EXCLUDED.precision,
+				match_type = EXCLUDED.match_type,
+				layer = EXCLUDED.layer,
+				manual_override = TRUE,
+				geocoded_at = EXCLUDED.geocoded_at,
+				electoral_district_id = CASE
+					WHEN (address_geocoding_cache.latitude, address_geocoding_cache.longitude) IS NOT DISTINCT FROM (EXCLUDED.latitude, EXCLUDED.longitude)
+					THEN address_geocoding_cache.electoral_district_id
+				END,
+				updated_at = CURRENT_TIMESTAMP`
+		args = []interface{}{review.Query, review.TargetKey, latitude, longitude, confidence, provider, precision, matchType, layer}
+	default:
+		return fmt.Errorf("invalid geocoding review target: %s", review.TargetType)
+	}
+
+	if _, err := tx.Exec(query, args...); err != nil {
+		return fmt.Errorf("failed to store reviewed coordinates: %w", err)
+	}
+
+	return nil
+}
+
+// removeRejected removes the rejected result from its target, unless the coordinates changed
+// since. Postal codes keep their row without coordinates, cached addresses are deleted so their
+// location falls back to their postal code.
+func removeRejected(tx *sqlx.Tx, review *models.GeocodingReview) error {
+	var query string
+	switch review.TargetType {
+	case models.GeocodingTargetPostalCode:
+		query = `
+			UPDATE postal_codes
+			SET latitude = NULL, longitude = NULL, provider = NULL, confidence = NULL, precision = NULL,
+				match_type = NULL, layer = NULL, electoral_district_id = NULL, updated_at = NOW()
+			WHERE postal_code = $1
+			  AND NOT manual_override
+			  AND ABS(latitude - $2) < $4
+			  AND ABS(longitude - $3) < $4`
+	case models.GeocodingTargetAddress:
+		query = `
+			DELETE FROM address_geocoding_cache
+			WHERE normalized_address = $1
+			  AND NOT manual_override
+			  AND ABS(latitude - $2) < $4
+			  AND ABS(longitude - $3) < $4`
+	default:
+		return fmt.Errorf("invalid geocoding review target: %s", review.TargetType)
+	}
+
+	if _, err := tx.Exec(query, review.TargetKey, review.Latitude, review.Longitude, rejectedResultTolerance); err != nil {
+		return fmt.Errorf("failed to remove rejected coordinates: %w", err)
+	}
+
+	return nil
+}
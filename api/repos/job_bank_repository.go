@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type JobBankRepository interface {
@@ -27,16 +28,24 @@ type JobBankRepository interface {
 	GetJobPostingByURL(url string) (*models.JobPosting, error)
 	UpdateJobPostingRedditStatus(id string, redditPosted bool) error
 	UpdateJobRedditApprovalStatus(id string, status string, approvedBy string, approvedAt *time.Time, rejectionReason *string) error
+	UpdateJobPostingDetails(posting *models.JobPosting) error
+	UpdateJobPostingWageComparison(posting *models.JobPosting) error
 	GetJobPostingsNotPostedToReddit(limit int) ([]*models.JobPosting, error)
 	SearchJobPostingsByEmployer(employer string, limit int) ([]*models.JobPosting, error)
 	GetJobPostingsByLocation(city, province string, limit int) ([]*models.JobPosting, error)
 	GetJobPostingsByScrapingRun(scrapingRunID string) ([]*models.JobPosting, error)
 	GetRecentJobPostings(limit int) ([]*models.JobPosting, error)
+	GetJobPostingsAfter(afterID string, limit int) ([]*models.JobPosting, error)
 	SearchJobPostingsAdvanced(filters map[string]interface{}) ([]*models.JobPosting, int, error)
 	GetJobPostingsCount() (int, error)
 	GetDistinctEmployersCount() (int, error)
 	GetEmployerJobCounts(limit int) ([]map[string]interface{}, error)
 	DeleteJobPostingsNotInScrapeRun(scrapingRunID string, currentJobBankIDs []string) (int, error)
+
+	// Posting families (reposts of the same ad)
+	GetPostingFamilyByID(id string) (*models.PostingFamily, error)
+	GetMostRepostedFamilies(minReposts, limit int) ([]*models.PostingFamily, error)
+	RebuildPostingFamilies() (int, error)
 }
 
 type jobBankRepository struct {
@@ -135,10 +144,12 @@ func (r *jobBankRepository) CreateJobPosting(posting *models.JobPosting) error {
 
 	query := `
 		INSERT INTO job_postings (id, job_bank_id, title, employer, location, province, city,
-								 salary_min, salary_max, salary_type, posting_date, url, is_tfw,
+								 salary_min, salary_max, salary_type, hours_per_week, salary_hourly_min, salary_hourly_max,
+								 salary_annual_min, salary_annual_max, fingerprint, posting_date, url, is_tfw,
 								 has_lmia, reddit_posted, description, scraping_run_id, created_at, updated_at)
 		VALUES (:id, :job_bank_id, :title, :employer, :location, :province, :city,
-				:salary_min, :salary_max, :salary_type, :posting_date, :url, :is_tfw,
+				:salary_min, :salary_max, :salary_type, :hours_per_week, :salary_hourly_min, :salary_hourly_max,
+				:salary_annual_min, :salary_annual_max, :fingerprint, :posting_date, :url, :is_tfw,
 				:has_lmia, :reddit_posted, :description, :scraping_run_id, :created_at, :updated_at)
 		ON CONFLICT (job_bank_id) DO UPDATE SET
 			title = EXCLUDED.title,
@@ -149,6 +160,12 @@ func (r *jobBankRepository) CreateJobPosting(posting *models.JobPosting) error {
 			salary_min = EXCLUDED.salary_min,
 			salary_max = EXCLUDED.salary_max,
 			salary_type = EXCLUDED.salary_type,
+			hours_per_week = COALESCE(EXCLUDED.hours_per_week, job_postings.hours_per_week),
+			salary_hourly_min = EXCLUDED.salary_hourly_min,
+			salary_hourly_max = EXCLUDED.salary_hourly_max,
+			salary_annual_min = EXCLUDED.salary_annual_min,
+			salary_annual_max = EXCLUDED.salary_annual_max,
+			fingerprint = EXCLUDED.fingerprint,
 			posting_date = EXCLUDED.posting_date,
 			url = EXCLUDED.url,
 			has_lmia = EXCLUDED.has_lmia,
@@ -159,12 +176,21 @@ func (r *jobBankRepository) CreateJobPosting(posting *models.JobPosting) error {
 	posting.ID = uuid.New().String()
 	posting.CreatedAt = time.Now()
 	posting.UpdatedAt = time.Now()
+	setFingerprint(posting)
+
+	if err := carryExistingHours(tx, []*models.JobPosting{posting}); err != nil {
+		return err
+	}
 
 	_, err = tx.NamedExec(query, posting)
 	if err != nil {
 		return fmt.Errorf("failed to insert job posting: %w", err)
 	}
 
+	if err := r.assignPostingFamilies(tx, []*models.JobPosting{posting}); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -185,10 +211,12 @@ func (r *jobBankRepository) CreateJobPostingsBatch(postings []*models.JobPosting
 
 	query := `
 		INSERT INTO job_postings (id, job_bank_id, title, employer, location, province, city,
-								 salary_min, salary_max, salary_type, posting_date, url, is_tfw,
+								 salary_min, salary_max, salary_type, hours_per_week, salary_hourly_min, salary_hourly_max,
+								 salary_annual_min, salary_annual_max, fingerprint, posting_date, url, is_tfw,
 								 has_lmia, reddit_posted, description, scraping_run_id, created_at, updated_at)
 		VALUES (:id, :job_bank_id, :title, :employer, :location, :province, :city,
-				:salary_min, :salary_max, :salary_type, :posting_date, :url, :is_tfw,
+				:salary_min, :salary_max, :salary_type, :hours_per_week, :salary_hourly_min, :salary_hourly_max,
+				:salary_annual_min, :salary_annual_max, :fingerprint, :posting_date, :url, :is_tfw,
 				:has_lmia, :reddit_posted, :description, :scraping_run_id, :created_at, :updated_at)
 		ON CONFLICT (job_bank_id) DO UPDATE SET
 			title = EXCLUDED.title,
@@ -199,6 +227,12 @@ func (r *jobBankRepository) CreateJobPostingsBatch(postings []*models.JobPosting
 			salary_min = EXCLUDED.salary_min,
 			salary_max = EXCLUDED.salary_max,
 			salary_type = EXCLUDED.salary_type,
+			hours_per_week = COALESCE(EXCLUDED.hours_per_week, job_postings.hours_per_week),
+			salary_hourly_min = EXCLUDED.salary_hourly_min,
+			salary_hourly_max = EXCLUDED.salary_hourly_max,
+			salary_annual_min = EXCLUDED.salary_annual_min,
+			salary_annual_max = EXCLUDED.salary_annual_max,
+			fingerprint = EXCLUDED.fingerprint,
 			posting_date = EXCLUDED.posting_date,
 			url = EXCLUDED.url,
 			has_lmia = EXCLUDED.has_lmia,
@@ -210,6 +244,11 @@ func (r *jobBankRepository) CreateJobPostingsBatch(postings []*models.JobPosting
 		posting.ID = uuid.New().String()
 		posting.CreatedAt = time.Now()
 		posting.UpdatedAt = time.Now()
+		setFingerprint(posting)
+	}
+
+	if err := carryExistingHours(tx, postings); err != nil {
+		return err
 	}
 
 	_, err = tx.NamedExec(query, postings)
@@ -217,6 +256,11 @@ func (r *jobBankRepository) CreateJobPostingsBatch(postings []*models.JobPosting
 		return fmt.Errorf("failed to insert job postings batch: %w", err)
 	}
 
+	// Group reposts of the same ad, which arrive with a new job bank ID and URL
+	if err := r.assignPostingFamilies(tx, postings); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -245,6 +289,8 @@ func (r *jobBankRepository) GetJobPostingByID(id string) (*models.JobPosting, er
 		return nil, err
 	}
 
+	posting.SetRepostSummary()
+	posting.SetNonCompliantNotice()
 	return &posting, nil
 }
 
@@ -342,6 +388,17 @@ func (r *jobBankRepository) GetRecentJobPostings(limit int) ([]*models.JobPostin
 	return postings, nil
 }
 
+// GetJobPostingsAfter returns a page of job postings ordered by id, starting after the given
+// posting id. The nil UUID starts from the first posting.
+func (r *jobBankRepository) GetJobPostingsAfter(afterID string, limit int) ([]*models.JobPosting, error) {
+	var postings []*models.JobPosting
+	query := `SELECT * FROM job_postings WHERE id > $1 ORDER BY id LIMIT $2`
+	if err := r.db.Select(&postings, query, afterID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get job postings: %w", err)
+	}
+	return postings, nil
+}
+
 func (r *jobBankRepository) GetJobPostingsCount() (int, error) {
 	var count int
 	query := `SELECT COUNT(*) FROM job_postings`
@@ -520,6 +577,39 @@ func (r *jobBankRepository) UpdateJobRedditApprovalStatus(id string, status stri
 	return err
 }
 
+// UpdateJobPostingDetails stores the fields taken from the detail page along with the normalized salary columns
+func (r *jobBankRepository) UpdateJobPostingDetails(posting *models.JobPosting) error {
+	query := `
+		UPDATE job_postings
+		SET noc_code = :noc_code,
+		    hours_per_week = :hours_per_week,
+		    salary_hourly_min = :salary_hourly_min,
+		    salary_hourly_max = :salary_hourly_max,
+		    salary_annual_min = :salary_annual_min,
+		    salary_annual_max = :salary_annual_max,
+		    updated_at = NOW()
+		WHERE id = :id`
+
+	_, err := r.db.NamedExec(query, posting)
+	return err
+}
+
+// UpdateJobPostingWageComparison stores the prevailing wage comparison for a job posting
+func (r *jobBankRepository) UpdateJobPostingWageComparison(posting *models.JobPosting) error {
+	query := `
+		UPDATE job_postings
+		SET noc_code = :noc_code,
+		    economic_region_code = :economic_region_code,
+		    median_wage = :median_wage,
+		    wage_percent_of_median = :wage_percent_of_median,
+		    below_median_wage = :below_median_wage,
+		    updated_at = NOW()
+		WHERE id = :id`
+
+	_, err := r.db.NamedExec(query, posting)
+	return err
+}
+
 // GetJobPostingsNotPostedToReddit retrieves job postings that haven't been posted to Reddit
 func (r *jobBankRepository) GetJobPostingsNotPostedToReddit(limit int) ([]*models.JobPosting, error) {
 	var postings []*models.JobPosting
@@ -652,11 +742,13 @@ func (r *jobBankRepository) createJobPostingsBatchUpdated(postings []*models.Job
 
 	query := `
 		INSERT INTO job_postings (id, job_bank_id, title, employer, location, province, city,
-								 salary_min, salary_max, salary_type, salary_raw, posting_date, url, is_tfw,
-								 has_lmia, description, scraping_run_id, created_at, updated_at)
+								 salary_min, salary_max, salary_type, salary_raw, hours_per_week,
+								 salary_hourly_min, salary_hourly_max, salary_annual_min, salary_annual_max,
+								 fingerprint, posting_date, url, is_tfw, has_lmia, description, scraping_run_id, created_at, updated_at)
 		VALUES (:id, :job_bank_id, :title, :employer, :location, :province, :city,
-				:salary_min, :salary_max, :salary_type, :salary_raw, :posting_date, :url, :is_tfw,
-				:has_lmia, :description, :scraping_run_id, :created_at, :updated_at)
+				:salary_min, :salary_max, :salary_type, :salary_raw, :hours_per_week,
+				:salary_hourly_min, :salary_hourly_max, :salary_annual_min, :salary_annual_max,
+				:fingerprint, :posting_date, :url, :is_tfw, :has_lmia, :description, :scraping_run_id, :created_at, :updated_at)
 		ON CONFLICT (url) DO UPDATE SET
 			title = EXCLUDED.title,
 			employer = EXCLUDED.employer,
@@ -667,6 +759,12 @@ func (r *jobBankRepository) createJobPostingsBatchUpdated(postings []*models.Job
 			salary_max = EXCLUDED.salary_max,
 			salary_type = EXCLUDED.salary_type,
 			salary_raw = EXCLUDED.salary_raw,
+			hours_per_week = COALESCE(EXCLUDED.hours_per_week, job_postings.hours_per_week),
+			salary_hourly_min = EXCLUDED.salary_hourly_min,
+			salary_hourly_max = EXCLUDED.salary_hourly_max,
+			salary_annual_min = EXCLUDED.salary_annual_min,
+			salary_annual_max = EXCLUDED.salary_annual_max,
+			fingerprint = EXCLUDED.fingerprint,
 			posting_date = EXCLUDED.posting_date,
 			has_lmia = EXCLUDED.has_lmia,
 			description = EXCLUDED.description,
@@ -674,11 +772,20 @@ func (r *jobBankRepository) createJobPostingsBatchUpdated(postings []*models.Job
 		WHERE job_postings.updated_at < EXCLUDED.updated_at
 	`
 
+	if err := carryExistingHours(tx, deduplicatedPostings); err != nil {
+		return nil, err
+	}
+
 	_, err = tx.NamedExec(query, deduplicatedPostings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to insert job postings batch: %w", err)
 	}
 
+	// Group reposts of the same ad, which arrive with a new job bank ID and URL
+	if err := r.assignPostingFamilies(tx, deduplicatedPostings); err != nil {
+		return nil, err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -732,13 +839,59 @@ func (r *jobBankRepository) SearchJobPostingsAdvanced(filters map[string]interfa
 		argIndex++
 	}
 	
-	// Add salary filter
-	if salaryMin, ok := filters["salary_min"].(*float64); ok && salaryMin != nil {
-		whereClause += fmt.Sprintf(" AND salary_min >= $%d", argIndex)
-		args = append(args, *salaryMin)
+	// Add salary filters against the normalized columns so hourly and yearly postings are comparable
+	if salaryHourlyMin, ok := filters["salary_hourly_min"].(*float64); ok && salaryHourlyMin != nil {
+		whereClause += fmt.Sprintf(" AND salary_hourly_min >= $%d", argIndex)
+		args = append(args, *salaryHourlyMin)
+		argIndex++
+	}
+
+	if salaryAnnualMin, ok := filters["salary_annual_min"].(*float64); ok && salaryAnnualMin != nil {
+		whereClause += fmt.Sprintf(" AND salary_annual_min >= $%d", argIndex)
+		args = append(args, *salaryAnnualMin)
 		argIndex++
 	}
 	
+	// Add prevailing wage filters
+	if belowMedian, ok := filters["below_median_wage"].(*bool); ok && belowMedian != nil {
+		whereClause += fmt.Sprintf(" AND below_median_wage = $%d", argIndex)
+		args = append(args, *belowMedian)
+		argIndex++
+	}
+
+	if maxPercent, ok := filters["max_percent_of_median"].(*float64); ok && maxPercent != nil {
+		whereClause += fmt.Sprintf(" AND wage_percent_of_median <= $%d", argIndex)
+		args = append(args, *maxPercent)
+		argIndex++
+	}
+
+	if nocCode, ok := filters["noc_code"].(string); ok && nocCode != "" {
+		whereClause += fmt.Sprintf(" AND noc_code = $%d", argIndex)
+		args = append(args, nocCode)
+		argIndex++
+	}
+
+	// Add repost filter
+	if minReposts, ok := filters["min_repost_count"].(int); ok && minReposts > 0 {
+		whereClause += fmt.Sprintf(" AND repost_count >= $%d", argIndex)
+		args = append(args, minReposts)
+		argIndex++
+	}
+
+	// Add LMIA approval history filter
+	if repeatUser, ok := filters["is_repeat_lmia_user"].(*bool); ok && repeatUser != nil {
+		whereClause += fmt.Sprintf(" AND COALESCE(is_repeat_lmia_user, false) = $%d", argIndex)
+		args = append(args, *repeatUser)
+		argIndex++
+	}
+
+	// Add non-compliant employer filter
+	if nonCompliant, ok := filters["is_non_compliant_employer"].(*bool); ok && nonCompliant != nil {
+		whereClause += fmt.Sprintf(" AND is_non_compliant_employer = $%d", argIndex)
+		args = append(args, *nonCompliant)
+		argIndex++
+	}
+
 	// Add days filter (jobs posted within X days)
 	// Only apply the filter if days > 0, otherwise show all jobs
 	if days, ok := filters["days"].(int); ok && days > 0 {
@@ -764,16 +917,24 @@ func (r *jobBankRepository) SearchJobPostingsAdvanced(filters map[string]interfa
 	sortOrder := "DESC"
 	if sort, ok := filters["sort_by"].(string); ok && sort != "" {
 		// Validate sort field to prevent SQL injection
-		validSorts := map[string]bool{
-			"posting_date": true,
-			"created_at":   true,
-			"title":        true,
-			"employer":     true,
-			"salary_min":   true,
-			"salary_max":   true,
+		// salary_min and salary_max sort on the hourly equivalents since raw values mix salary types
+		validSorts := map[string]string{
+			"posting_date":              "posting_date",
+			"created_at":                "created_at",
+			"title":                     "title",
+			"employer":                  "employer",
+			"salary_min":                "salary_hourly_min",
+			"salary_max":                "salary_hourly_max",
+			"salary_hourly_min":         "salary_hourly_min",
+			"salary_hourly_max":         "salary_hourly_max",
+			"salary_annual_min":         "salary_annual_min",
+			"salary_annual_max":         "salary_annual_max",
+			"wage_percent_of_median":    "wage_percent_of_median",
+			"repost_count":              "repost_count",
+			"lmia_historical_positions": "lmia_historical_positions",
 		}
-		if validSorts[sort] {
-			sortBy = sort
+		if column, ok := validSorts[sort]; ok {
+			sortBy = column
 		}
 	}
 	if order, ok := filters["sort_order"].(string); ok && (order == "ASC" || order == "DESC" || order == "asc" || order == "desc") {
@@ -782,11 +943,18 @@ func (r *jobBankRepository) SearchJobPostingsAdvanced(filters map[string]interfa
 	
 	selectQuery := fmt.Sprintf(`
 		SELECT id, job_bank_id, title, employer, location, province, city,
-			   salary_min, salary_max, salary_type, salary_raw, posting_date, url, 
-			   is_tfw, has_lmia, reddit_posted, reddit_approval_status, reddit_approved_by, 
+			   salary_min, salary_max, salary_type, salary_raw, hours_per_week,
+			   salary_hourly_min, salary_hourly_max, salary_annual_min, salary_annual_max,
+			   noc_code, economic_region_code, median_wage, wage_percent_of_median, below_median_wage,
+			   fingerprint, family_id, repost_count, reposted_since,
+			   lmia_match_confidence, lmia_historical_approvals, lmia_historical_positions,
+			   lmia_approval_periods, is_repeat_lmia_user, lmia_matched_at,
+			   non_compliant_employer_id, is_non_compliant_employer, non_compliant_decision_date,
+			   non_compliant_penalty_amount,
+			   posting_date, url, is_tfw, has_lmia, reddit_posted, reddit_approval_status, reddit_approved_by, 
 			   reddit_approved_at, reddit_rejection_reason, description, scraping_run_id, 
 			   created_at, updated_at
-		FROM job_postings%s ORDER BY %s %s`, whereClause, sortBy, sortOrder)
+		FROM job_postings%s ORDER BY %s %s NULLS LAST`, whereClause, sortBy, sortOrder)
 	
 	// Add pagination
 	limit := 25
@@ -806,6 +974,11 @@ func (r *jobBankRepository) SearchJobPostingsAdvanced(filters map[string]interfa
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to execute search query: %w", err)
 	}
+
+	for _, posting := range postings {
+		posting.SetRepostSummary()
+		posting.SetNonCompliantNotice()
+	}
 	
 	return postings, totalCount, nil
 }
@@ -863,4 +1036,296 @@ func (r *jobBankRepository) DeleteJobPostingsNotInScrapeRun(scrapingRunID string
 	}
 
 	return int(deletedCount), nil
-}
\ No newline at end of file
+}
+
+// setFingerprint computes the repost fingerprint of postings built without one, such as those
+// parsed directly from Job Bank search pages
+func setFingerprint(posting *models.JobPosting) {
+	if posting.Fingerprint != nil && *posting.Fingerprint != "" {
+		return
+	}
+	fingerprint := posting.ComputeFingerprint()
+	posting.Fingerprint = &fingerprint
+}
+
+// carryExistingHours gives postings without weekly hours the hours already stored for the same
+// job bank ID or URL, and normalizes their salaries with them. The search results don't show the
+// hours, so without this a rescrape would store salaries normalized at DefaultHoursPerWeek next to
+// the hours read from the detail page.
+func carryExistingHours(tx *sqlx.Tx, postings []*models.JobPosting) error {
+	var jobBankIDs, urls []string
+	for _, posting := range postings {
+		if posting.HoursPerWeek != nil {
+			continue
+		}
+		if posting.JobBankID != nil && *posting.JobBankID != "" {
+			jobBankIDs = append(jobBankIDs, *posting.JobBankID)
+		}
+		urls = append(urls, posting.URL)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	var existing []struct {
+		JobBankID    *string `db:"job_bank_id"`
+		URL          string  `db:"url"`
+		HoursPerWeek float64 `db:"hours_per_week"`
+	}
+	query := `
+		SELECT job_bank_id, url, hours_per_week FROM job_postings
+		WHERE hours_per_week IS NOT NULL AND (job_bank_id = ANY($1) OR url = ANY($2))
+	`
+	if err := tx.Select(&existing, query, pq.Array(jobBankIDs), pq.Array(urls)); err != nil {
+		return fmt.Errorf("failed to get stored weekly hours: %w", err)
+	}
+
+	hoursByJobBankID := make(map[string]float64)
+	hoursByURL := make(map[string]float64)
+	for _, row := range existing {
+		if row.JobBankID != nil {
+			hoursByJobBankID[*row.JobBankID] = row.HoursPerWeek
+		}
+		hoursByURL[row.URL] = row.HoursPerWeek
+	}
+
+	for _, posting := range postings {
+		if posting.HoursPerWeek != nil {
+			continue
+		}
+		hours, ok := hoursByURL[posting.URL]
+		if posting.JobBankID != nil {
+			if byID, found := hoursByJobBankID[*posting.JobBankID]; found {
+				hours, ok = byID, true
+			}
+		}
+		if ok {
+			posting.HoursPerWeek = &hours
+			posting.NormalizeSalary()
+		}
+	}
+
+	return nil
+}
+
+// assignPostingFamilies adds postings to the family for their fingerprint, then refreshes the
+// family counts and the denormalized repost columns on job_postings
+func (r *jobBankRepository) assignPostingFamilies(tx *sqlx.Tx, postings []*models.JobPosting) error {
+	now := time.Now()
+	families := make(map[string]*models.PostingFamily)
+	for _, posting := range postings {
+		if posting.Fingerprint == nil || *posting.Fingerprint == "" {
+			continue
+		}
+		if _, exists := families[*posting.Fingerprint]; exists {
+			continue
+		}
+		families[*posting.Fingerprint] = &models.PostingFamily{
+			ID:          uuid.New().String(),
+			Fingerprint: *posting.Fingerprint,
+			Employer:    posting.Employer,
+			Title:       posting.Title,
+			Location:    posting.Location,
+			FirstSeenAt: now,
+			LastSeenAt:  now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+	}
+
+	if len(families) == 0 {
+		return nil
+	}
+
+	var familyRows []*models.PostingFamily
+	var fingerprints []string
+	for fingerprint, family := range families {
+		familyRows = append(familyRows, family)
+		fingerprints = append(fingerprints, fingerprint)
+	}
+
+	familyQuery := `
+		INSERT INTO job_posting_families (id, fingerprint, employer, title, location,
+										  first_seen_at, last_seen_at, created_at, updated_at)
+		VALUES (:id, :fingerprint, :employer, :title, :location,
+				:first_seen_at, :last_seen_at, :created_at, :updated_at)
+		ON CONFLICT (fingerprint) DO UPDATE SET
+			last_seen_at = EXCLUDED.last_seen_at,
+			updated_at = EXCLUDED.updated_at
+	`
+	if _, err := tx.NamedExec(familyQuery, familyRows); err != nil {
+		return fmt.Errorf("failed to upsert posting families: %w", err)
+	}
+
+	// Look up the family IDs, since existing families keep their original ID
+	query, args, err := sqlx.In("SELECT id, fingerprint FROM job_posting_families WHERE fingerprint IN (?)", fingerprints)
+	if err != nil {
+		return fmt.Errorf("failed to build posting family query: %w", err)
+	}
+	var storedFamilies []models.PostingFamily
+	if err := tx.Select(&storedFamilies, tx.Rebind(query), args...); err != nil {
+		return fmt.Errorf("failed to get posting families: %w", err)
+	}
+
+	familyIDs := make(map[string]string)
+	var ids []string
+	for _, family := range storedFamilies {
+		familyIDs[family.Fingerprint] = family.ID
+		ids = append(ids, family.ID)
+	}
+
+	// A posting can appear twice in one batch under the same key, so dedupe members first
+	memberKeys := make(map[string]bool)
+	var members []*models.PostingFamilyMember
+	for _, posting := range postings {
+		if posting.Fingerprint == nil {
+			continue
+		}
+		familyID, ok := familyIDs[*posting.Fingerprint]
+		if !ok {
+			continue
+		}
+		key := posting.PostingKey()
+		if memberKeys[familyID+"|"+key] {
+			continue
+		}
+		memberKeys[familyID+"|"+key] = true
+		members = append(members, &models.PostingFamilyMember{
+			ID:          uuid.New().String(),
+			FamilyID:    familyID,
+			PostingKey:  key,
+			JobBankID:   posting.JobBankID,
+			URL:         posting.URL,
+			PostingDate: posting.PostingDate,
+			FirstSeenAt: now,
+		})
+	}
+
+	memberQuery := `
+		INSERT INTO job_posting_family_members (id, family_id, posting_key, job_bank_id, url, posting_date, first_seen_at)
+		VALUES (:id, :family_id, :posting_key, :job_bank_id, :url, :posting_date, :first_seen_at)
+		ON CONFLICT (family_id, posting_key) DO NOTHING
+	`
+	if _, err := tx.NamedExec(memberQuery, members); err != nil {
+		return fmt.Errorf("failed to insert posting family members: %w", err)
+	}
+
+	countQuery, args, err := sqlx.In(`
+		UPDATE job_posting_families f
+		SET posting_count = m.posting_count,
+			first_posted_at = m.first_posted_at,
+			last_posted_at = m.last_posted_at
+		FROM (
+			SELECT family_id,
+				   COUNT(*) as posting_count,
+				   MIN(COALESCE(posting_date, first_seen_at)) as first_posted_at,
+				   MAX(COALESCE(posting_date, first_seen_at)) as last_posted_at
+			FROM job_posting_family_members
+			WHERE family_id IN (?)
+			GROUP BY family_id
+		) m
+		WHERE f.id = m.family_id
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build posting family count query: %w", err)
+	}
+	if _, err := tx.Exec(tx.Rebind(countQuery), args...); err != nil {
+		return fmt.Errorf("failed to update posting family counts: %w", err)
+	}
+
+	repostQuery, args, err := sqlx.In(`
+		UPDATE job_postings jp
+		SET family_id = f.id,
+			repost_count = GREATEST(f.posting_count - 1, 0),
+			reposted_since = f.first_posted_at
+		FROM job_posting_families f
+		WHERE jp.fingerprint = f.fingerprint
+		AND f.id IN (?)
+	`, ids)
+	if err != nil {
+		return fmt.Errorf("failed to build repost count query: %w", err)
+	}
+	if _, err := tx.Exec(tx.Rebind(repostQuery), args...); err != nil {
+		return fmt.Errorf("failed to update repost counts: %w", err)
+	}
+
+	return nil
+}
+
+// GetPostingFamilyByID retrieves a posting family with every posting seen in it
+func (r *jobBankRepository) GetPostingFamilyByID(id string) (*models.PostingFamily, error) {
+	var family models.PostingFamily
+	err := r.db.Get(&family, `SELECT * FROM job_posting_families WHERE id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT * FROM job_posting_family_members
+		WHERE family_id = $1
+		ORDER BY posting_date ASC NULLS LAST, first_seen_at ASC
+	`
+	if err := r.db.Select(&family.Members, query, id); err != nil {
+		return nil, fmt.Errorf("failed to get posting family members: %w", err)
+	}
+
+	return &family, nil
+}
+
+// GetMostRepostedFamilies lists the families with the most reposts
+func (r *jobBankRepository) GetMostRepostedFamilies(minReposts, limit int) ([]*models.PostingFamily, error) {
+	var families []*models.PostingFamily
+	query := `
+		SELECT * FROM job_posting_families
+		WHERE posting_count - 1 >= $1
+		ORDER BY posting_count DESC, last_posted_at DESC NULLS LAST
+		LIMIT $2
+	`
+
+	err := r.db.Select(&families, query, minReposts, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reposted families: %w", err)
+	}
+
+	return families, nil
+}
+
+// RebuildPostingFamilies recomputes fingerprints for every stored posting and assigns them to families.
+// Used to backfill postings stored before repost detection existed, or after the fingerprint changes.
+func (r *jobBankRepository) RebuildPostingFamilies() (int, error) {
+	var postings []*models.JobPosting
+	if err := r.db.Select(&postings, `SELECT * FROM job_postings ORDER BY posting_date ASC NULLS LAST`); err != nil {
+		return 0, fmt.Errorf("failed to get job postings: %w", err)
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, posting := range postings {
+		fingerprint := posting.ComputeFingerprint()
+		posting.Fingerprint = &fingerprint
+		if _, err := tx.Exec(`UPDATE job_postings SET fingerprint = $2 WHERE id = $1`, posting.ID, fingerprint); err != nil {
+			return 0, fmt.Errorf("failed to update fingerprint: %w", err)
+		}
+	}
+
+	batchSize := 1000
+	for i := 0; i < len(postings); i += batchSize {
+		end := i + batchSize
+		if end > len(postings) {
+			end = len(postings)
+		}
+		if err := r.assignPostingFamilies(tx, postings[i:end]); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(postings), nil
+}
@@ -0,0 +1,157 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type JobLMIAMatchRepository interface {
+	BackfillNormalizedEmployers() (int, error)
+	GetLMIAEmployersByNormalizedName(normalizedName string) ([]*models.LMIAEmployer, error)
+	ReplaceMatchesForJobPosting(jobPostingID string, matches []*models.JobLMIAMatch) error
+	GetApprovalHistoryForJobPosting(jobPostingID string) ([]*models.LMIAApprovalHistory, error)
+}
+
+type jobLMIAMatchRepository struct {
+	db *sqlx.DB
+}
+
+func NewJobLMIAMatchRepository(db *sqlx.DB) JobLMIAMatchRepository {
+	return &jobLMIAMatchRepository{db: db}
+}
+
+// BackfillNormalizedEmployers fills in normalized_employer for LMIA rows imported before the column existed
+func (r *jobLMIAMatchRepository) BackfillNormalizedEmployers() (int, error) {
+	var employers []string
+	query := `SELECT DISTINCT employer FROM lmia_employers WHERE normalized_employer IS NULL`
+	if err := r.db.Select(&employers, query); err != nil {
+		return 0, fmt.Errorf("failed to get employers without normalized names: %w", err)
+	}
+
+	if len(employers) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `UPDATE lmia_employers SET normalized_employer = $2 WHERE employer = $1 AND normalized_employer IS NULL`
+	for _, employer := range employers {
+		if _, err := tx.Exec(updateQuery, employer, models.NormalizeEmployerName(employer)); err != nil {
+			return 0, fmt.Errorf("failed to update normalized employer name: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(employers), nil
+}
+
+// GetLMIAEmployersByNormalizedName returns every approval row, across all quarters, for a normalized employer name
+func (r *jobLMIAMatchRepository) GetLMIAEmployersByNormalizedName(normalizedName string) ([]*models.LMIAEmployer, error) {
+	var employers []*models.LMIAEmployer
+	query := `
+		SELECT * FROM lmia_employers
+		WHERE normalized_employer = $1
+		ORDER BY year DESC, quarter DESC
+	`
+
+	err := r.db.Select(&employers, query, normalizedName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LMIA employers by name: %w", err)
+	}
+
+	return employers, nil
+}
+
+// ReplaceMatchesForJobPosting replaces a posting's LMIA matches and refreshes the summary columns on job_postings
+func (r *jobLMIAMatchRepository) ReplaceMatchesForJobPosting(jobPostingID string, matches []*models.JobLMIAMatch) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM job_posting_lmia_matches WHERE job_posting_id = $1`, jobPostingID); err != nil {
+		return fmt.Errorf("failed to delete existing LMIA matches: %w", err)
+	}
+
+	if len(matches) > 0 {
+		query := `
+			INSERT INTO job_posting_lmia_matches (id, job_posting_id, lmia_employer_id, confidence,
+												  name_match, location_match, noc_match, created_at)
+			VALUES (:id, :job_posting_id, :lmia_employer_id, :confidence,
+					:name_match, :location_match, :noc_match, :created_at)
+		`
+
+		now := time.Now()
+		for _, match := range matches {
+			match.ID = uuid.New().String()
+			match.JobPostingID = jobPostingID
+			match.CreatedAt = now
+		}
+
+		if _, err := tx.NamedExec(query, matches); err != nil {
+			return fmt.Errorf("failed to insert LMIA matches: %w", err)
+		}
+	}
+
+	summaryQuery := `
+		UPDATE job_postings jp
+		SET lmia_match_confidence = s.confidence,
+			lmia_historical_approvals = s.approvals,
+			lmia_historical_positions = s.positions,
+			lmia_approval_periods = NULLIF(s.periods, 0),
+			is_repeat_lmia_user = s.periods > 1,
+			lmia_matched_at = NOW()
+		FROM (
+			SELECT MAX(m.confidence) as confidence,
+				   SUM(e.approved_lmias) as approvals,
+				   SUM(e.approved_positions) as positions,
+				   COUNT(DISTINCT (e.year, e.quarter)) as periods
+			FROM job_posting_lmia_matches m
+			JOIN lmia_employers e ON e.id = m.lmia_employer_id
+			WHERE m.job_posting_id = $1
+		) s
+		WHERE jp.id = $1
+	`
+	if _, err := tx.Exec(summaryQuery, jobPostingID); err != nil {
+		return fmt.Errorf("failed to update LMIA match summary: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetApprovalHistoryForJobPosting returns the matched LMIA approvals for a posting, most recent first
+func (r *jobLMIAMatchRepository) GetApprovalHistoryForJobPosting(jobPostingID string) ([]*models.LMIAApprovalHistory, error) {
+	var history []*models.LMIAApprovalHistory
+	query := `
+		SELECT e.id as lmia_employer_id, e.employer, e.address, e.province_territory, e.program_stream,
+			   e.occupation, e.approved_lmias, e.approved_positions, e.quarter, e.year,
+			   m.confidence, m.location_match, m.noc_match
+		FROM job_posting_lmia_matches m
+		JOIN lmia_employers e ON e.id = m.lmia_employer_id
+		WHERE m.job_posting_id = $1
+		ORDER BY e.year DESC, e.quarter DESC, m.confidence DESC
+	`
+
+	err := r.db.Select(&history, query, jobPostingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LMIA approval history: %w", err)
+	}
+
+	return history, nil
+}
@@ -155,15 +155,17 @@ func (r *lmiaRepository) CreateEmployer(employer *models.LMIAEmployer) error {
 	query := `
 		INSERT INTO lmia_employers (id, resource_id, province_territory, program_stream, employer,
 								   address, occupation, incorporate_status, approved_lmias, approved_positions,
-								   quarter, year, created_at, updated_at, postal_code)
+								   quarter, year, created_at, updated_at, postal_code, normalized_employer)
 		VALUES (:id, :resource_id, :province_territory, :program_stream, :employer,
 				:address, :occupation, :incorporate_status, :approved_lmias, :approved_positions,
-				:quarter, :year, :created_at, :updated_at, :postal_code)
+				:quarter, :year, :created_at, :updated_at, :postal_code, :normalized_employer)
 	`
 
 	employer.ID = uuid.New().String()
 	employer.CreatedAt = time.Now()
 	employer.UpdatedAt = time.Now()
+	normalized := models.NormalizeEmployerName(employer.Employer)
+	employer.NormalizedEmployer = &normalized
 
 	_, err = tx.NamedExec(query, employer)
 	if err != nil {
@@ -191,16 +193,18 @@ func (r *lmiaRepository) CreateEmployersBatch(employers []*models.LMIAEmployer)
 	query := `
 		INSERT INTO lmia_employers (id, resource_id, province_territory, program_stream, employer,
 								   address, occupation, incorporate_status, approved_lmias, approved_positions,
-								   quarter, year, created_at, updated_at, postal_code)
+								   quarter, year, created_at, updated_at, postal_code, normalized_employer)
 		VALUES (:id, :resource_id, :province_territory, :program_stream, :employer,
 				:address, :occupation, :incorporate_status, :approved_lmias, :approved_positions,
-				:quarter, :year, :created_at, :updated_at, :postal_code)
+				:quarter, :year, :created_at, :updated_at, :postal_code, :normalized_employer)
 	`
 
 	for _, employer := range employers {
 		employer.ID = uuid.New().String()
 		employer.CreatedAt = time.Now()
 		employer.UpdatedAt = time.Now()
+		normalized := models.NormalizeEmployerName(employer.Employer)
+		employer.NormalizedEmployer = &normalized
 	}
 
 	_, err = tx.NamedExec(query, employers)
@@ -32,6 +32,12 @@ type LMIAStatisticsRepository interface {
 	
 	// Regional stats from raw job data
 	GetRegionalStatsFromJobs(startDate, endDate time.Time) (map[string]int, map[string]int, error)
+
+	// Prevailing wage comparison from raw job data
+	GetWageComparisonStats(startDate, endDate time.Time) (*models.WageComparisonStats, error)
+
+	// LMIA approval matching for current job postings
+	GetLMIAMatchStats() (*models.LMIAMatchStats, error)
 }
 
 type lmiaStatisticsRepository struct {
@@ -52,10 +58,11 @@ func (r *lmiaStatisticsRepository) CreateStatistics(stats *models.LMIAStatistics
 
 	query := `
 		INSERT INTO lmia_job_statistics (id, date, period_type, total_jobs, unique_employers, 
-										avg_salary_min, avg_salary_max, top_provinces, top_cities, 
-										created_at, updated_at)
+										avg_salary_min, avg_salary_max, avg_salary_annual_min, avg_salary_annual_max,
+										top_provinces, top_cities, created_at, updated_at)
 		VALUES (:id, :date, :period_type, :total_jobs, :unique_employers,
-				:avg_salary_min, :avg_salary_max, :top_provinces, :top_cities,
+				:avg_salary_min, :avg_salary_max, :avg_salary_annual_min, :avg_salary_annual_max,
+				:top_provinces, :top_cities,
 				:created_at, :updated_at)
 	`
 
@@ -81,6 +88,7 @@ func (r *lmiaStatisticsRepository) UpdateStatistics(stats *models.LMIAStatistics
 		UPDATE lmia_job_statistics 
 		SET total_jobs = :total_jobs, unique_employers = :unique_employers,
 			avg_salary_min = :avg_salary_min, avg_salary_max = :avg_salary_max,
+			avg_salary_annual_min = :avg_salary_annual_min, avg_salary_annual_max = :avg_salary_annual_max,
 			top_provinces = :top_provinces, top_cities = :top_cities,
 			updated_at = :updated_at
 		WHERE id = :id
@@ -106,16 +114,19 @@ func (r *lmiaStatisticsRepository) UpsertStatistics(stats *models.LMIAStatistics
 
 	query := `
 		INSERT INTO lmia_job_statistics (id, date, period_type, total_jobs, unique_employers, 
-										avg_salary_min, avg_salary_max, top_provinces, top_cities, 
-										created_at, updated_at)
+										avg_salary_min, avg_salary_max, avg_salary_annual_min, avg_salary_annual_max,
+										top_provinces, top_cities, created_at, updated_at)
 		VALUES (:id, :date, :period_type, :total_jobs, :unique_employers,
-				:avg_salary_min, :avg_salary_max, :top_provinces, :top_cities,
+				:avg_salary_min, :avg_salary_max, :avg_salary_annual_min, :avg_salary_annual_max,
+				:top_provinces, :top_cities,
 				:created_at, :updated_at)
 		ON CONFLICT (date, period_type) DO UPDATE SET
 			total_jobs = EXCLUDED.total_jobs,
 			unique_employers = EXCLUDED.unique_employers,
 			avg_salary_min = EXCLUDED.avg_salary_min,
 			avg_salary_max = EXCLUDED.avg_salary_max,
+			avg_salary_annual_min = EXCLUDED.avg_salary_annual_min,
+			avg_salary_annual_max = EXCLUDED.avg_salary_annual_max,
 			top_provinces = EXCLUDED.top_provinces,
 			top_cities = EXCLUDED.top_cities,
 			updated_at = EXCLUDED.updated_at
@@ -198,8 +209,10 @@ func (r *lmiaStatisticsRepository) GetJobStatisticsForDate(date time.Time) (*mod
 		SELECT 
 			COUNT(*) as total_jobs,
 			COUNT(DISTINCT employer) as unique_employers,
-			AVG(salary_min) as avg_salary_min,
-			AVG(salary_max) as avg_salary_max
+			AVG(salary_hourly_min) as avg_salary_min,
+			AVG(salary_hourly_max) as avg_salary_max,
+			AVG(salary_annual_min) as avg_salary_annual_min,
+			AVG(salary_annual_max) as avg_salary_annual_max
 		FROM job_postings 
 		WHERE posting_date >= $1 AND posting_date <= $2
 		AND is_tfw = true AND has_lmia = true
@@ -207,7 +220,7 @@ func (r *lmiaStatisticsRepository) GetJobStatisticsForDate(date time.Time) (*mod
 
 	var data models.JobStatisticsData
 	row := r.db.QueryRow(query, startOfDay, endOfDay)
-	err := row.Scan(&data.TotalJobs, &data.UniqueEmployers, &data.AvgSalaryMin, &data.AvgSalaryMax)
+	err := row.Scan(&data.TotalJobs, &data.UniqueEmployers, &data.AvgSalaryMin, &data.AvgSalaryMax, &data.AvgSalaryAnnualMin, &data.AvgSalaryAnnualMax)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job statistics for date: %w", err)
 	}
@@ -259,8 +272,10 @@ func (r *lmiaStatisticsRepository) GetJobStatisticsForMonth(year int, month int)
 		SELECT 
 			COUNT(*) as total_jobs,
 			COUNT(DISTINCT employer) as unique_employers,
-			AVG(salary_min) as avg_salary_min,
-			AVG(salary_max) as avg_salary_max
+			AVG(salary_hourly_min) as avg_salary_min,
+			AVG(salary_hourly_max) as avg_salary_max,
+			AVG(salary_annual_min) as avg_salary_annual_min,
+			AVG(salary_annual_max) as avg_salary_annual_max
 		FROM job_postings 
 		WHERE posting_date >= $1 AND posting_date <= $2
 		AND is_tfw = true AND has_lmia = true
@@ -268,7 +283,7 @@ func (r *lmiaStatisticsRepository) GetJobStatisticsForMonth(year int, month int)
 
 	var data models.JobStatisticsData
 	row := r.db.QueryRow(query, startOfMonth, endOfMonth)
-	err := row.Scan(&data.TotalJobs, &data.UniqueEmployers, &data.AvgSalaryMin, &data.AvgSalaryMax)
+	err := row.Scan(&data.TotalJobs, &data.UniqueEmployers, &data.AvgSalaryMin, &data.AvgSalaryMax, &data.AvgSalaryAnnualMin, &data.AvgSalaryAnnualMax)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get job statistics for month: %w", err)
 	}
@@ -482,4 +497,80 @@ func (r *lmiaStatisticsRepository) GetRegionalStatsFromJobs(startDate, endDate t
 	}
 
 	return provinceCounts, cityCounts, nil
-}
\ No newline at end of file
+}
+
+// GetWageComparisonStats compares offered wages with the prevailing median for LMIA postings in a date range
+func (r *lmiaStatisticsRepository) GetWageComparisonStats(startDate, endDate time.Time) (*models.WageComparisonStats, error) {
+	stats := &models.WageComparisonStats{}
+
+	totalsQuery := `
+		SELECT
+			COUNT(*) as total_postings,
+			COUNT(median_wage) as compared_postings,
+			COUNT(*) FILTER (WHERE below_median_wage = true) as below_median_postings,
+			ROUND(AVG(wage_percent_of_median)::numeric, 2)::float8 as avg_percent_of_median
+		FROM job_postings
+		WHERE posting_date >= $1 AND posting_date <= $2
+		AND is_tfw = true AND has_lmia = true
+	`
+
+	row := r.db.QueryRow(totalsQuery, startDate, endDate)
+	if err := row.Scan(&stats.TotalPostings, &stats.ComparedPostings, &stats.BelowMedianPostings, &stats.AvgPercentOfMedian); err != nil {
+		return nil, fmt.Errorf("failed to get wage comparison totals: %w", err)
+	}
+
+	if stats.ComparedPostings > 0 {
+		stats.BelowMedianPercentage = float64(stats.BelowMedianPostings) / float64(stats.ComparedPostings) * 100
+	}
+
+	breakdownQuery := `
+		SELECT
+			%s as name,
+			COUNT(*) as compared_postings,
+			COUNT(*) FILTER (WHERE below_median_wage = true) as below_median_postings,
+			ROUND(AVG(wage_percent_of_median)::numeric, 2)::float8 as avg_percent_of_median
+		FROM job_postings
+		WHERE posting_date >= $1 AND posting_date <= $2
+		AND is_tfw = true AND has_lmia = true
+		AND median_wage IS NOT NULL AND %s IS NOT NULL
+		GROUP BY %s
+		ORDER BY below_median_postings DESC, compared_postings DESC
+		LIMIT 20
+	`
+
+	err := r.db.Select(&stats.ByProvince, fmt.Sprintf(breakdownQuery, "province", "province", "province"), startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wage comparison by province: %w", err)
+	}
+
+	err = r.db.Select(&stats.ByOccupation, fmt.Sprintf(breakdownQuery, "noc_code", "noc_code", "noc_code"), startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wage comparison by occupation: %w", err)
+	}
+
+	return stats, nil
+}
+
+// GetLMIAMatchStats reports the share of current LMIA postings linked to approvals and from repeat LMIA users
+func (r *lmiaStatisticsRepository) GetLMIAMatchStats() (*models.LMIAMatchStats, error) {
+	var stats models.LMIAMatchStats
+	query := `
+		SELECT
+			COUNT(*) as current_postings,
+			COUNT(lmia_match_confidence) as matched_postings,
+			COUNT(*) FILTER (WHERE is_repeat_lmia_user = true) as repeat_user_postings
+		FROM job_postings
+		WHERE is_tfw = true AND has_lmia = true
+	`
+
+	if err := r.db.Get(&stats, query); err != nil {
+		return nil, fmt.Errorf("failed to get LMIA match stats: %w", err)
+	}
+
+	if stats.CurrentPostings > 0 {
+		stats.MatchedPercentage = float64(stats.MatchedPostings) / float64(stats.CurrentPostings) * 100
+		stats.RepeatUserPercentage = float64(stats.RepeatUserPostings) / float64(stats.CurrentPostings) * 100
+	}
+
+	return &stats, nil
+}
@@ -4,6 +4,9 @@ import (
 	"canada-hires/models"
 	"database/sql"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -29,7 +32,26 @@ type NonCompliantRepository interface {
 	// Bulk operations
 	CreateEmployersWithReasons(data []models.ScraperNonCompliantData) error
 	UpsertEmployersWithReasons(data []models.ScraperNonCompliantData) error
-	ClearAllNonCompliantData() error
+	MarkDelistedEmployers(seenBefore time.Time) (int, error)
+
+	// Change history
+	GetChanges(filters models.NonCompliantChangeFilters, limit, offset int) ([]models.NonCompliantEmployerChange, error)
+	GetChangesCount(filters models.NonCompliantChangeFilters) (int, error)
+	GetEmployerChanges(employerID string) ([]models.NonCompliantEmployerChange, error)
+
+	// Still hiring cross-reference
+	BackfillNormalizedNames() (int, error)
+	GetMatchCandidates() ([]models.NonCompliantMatchCandidate, error)
+	GetMatchCandidatesByNormalizedNames(names []string) ([]models.NonCompliantMatchCandidate, error)
+	ReplaceJobPostingFlags(flags []models.NonCompliantFlag) error
+	GetStillHiringEmployers(limit, offset int) ([]models.StillHiringEmployer, error)
+	GetStillHiringCount() (int, error)
+
+	// Penalty analytics
+	GetPenaltyStats() (*models.NonCompliantPenaltyStats, error)
+	GetLargestPenalties(limit int) ([]models.NonCompliantEmployerWithReasons, error)
+	GetRepeatOffenders(minListings, limit int) ([]models.NonCompliantRepeatOffender, error)
+	GetPenaltyTrends(period string) ([]models.NonCompliantTrendPoint, error)
 
 	// Stats
 	GetLatestScrapedDate() (*time.Time, error)
@@ -117,7 +139,8 @@ func (r *nonCompliantRepository) GetEmployerByID(id string) (*models.NonComplian
 	query := `
 		SELECT id, business_operating_name, business_legal_name, address,
 		       date_of_final_decision, penalty_amount, penalty_currency, status,
-		       reason_codes, postal_code, scraped_at, created_at, updated_at
+		       reason_codes, postal_code, first_seen_at, last_seen_at, delisted_at,
+		       scraped_at, created_at, updated_at
 		FROM non_compliant_employers
 		WHERE id = $1`
 
@@ -145,7 +168,8 @@ func (r *nonCompliantRepository) GetEmployersWithReasons(limit, offset int) ([]m
 		SELECT
 			e.id, e.business_operating_name, e.business_legal_name, e.address,
 			e.date_of_final_decision, e.penalty_amount, e.penalty_currency, e.status,
-			e.reason_codes, e.postal_code, e.scraped_at, e.created_at, e.updated_at
+			e.reason_codes, e.postal_code, e.first_seen_at, e.last_seen_at, e.delisted_at,
+			e.scraped_at, e.created_at, e.updated_at
 		FROM non_compliant_employers e
 		ORDER BY e.date_of_final_decision DESC, e.business_operating_name
 		LIMIT $1 OFFSET $2`
@@ -165,6 +189,7 @@ func (r *nonCompliantRepository) GetEmployersWithReasons(limit, offset int) ([]m
 			&employer.ID, &employer.BusinessOperatingName, &employer.BusinessLegalName,
 			&employer.Address, &employer.DateOfFinalDecision, &employer.PenaltyAmount,
 			&employer.PenaltyCurrency, &employer.Status, &reasonCodesArray, &employer.PostalCode,
+			&employer.FirstSeenAt, &employer.LastSeenAt, &employer.DelistedAt,
 			&employer.ScrapedAt, &employer.CreatedAt, &employer.UpdatedAt,
 		)
 		if err != nil {
@@ -311,6 +336,8 @@ func (r *nonCompliantRepository) CreateEmployersWithReasons(data []models.Scrape
 	return tx.Commit()
 }
 
+// UpsertEmployersWithReasons stores the scraped employers and records every listing and field change
+// against the previously stored state in non_compliant_employer_changes
 func (r *nonCompliantRepository) UpsertEmployersWithReasons(data []models.ScraperNonCompliantData) error {
 	if len(data) == 0 {
 		return nil
@@ -322,6 +349,7 @@ func (r *nonCompliantRepository) UpsertEmployersWithReasons(data []models.Scrape
 	}
 	defer tx.Rollback()
 
+	now := time.Now()
 	for _, item := range data {
 		// Parse date
 		var finalDecisionDate *time.Time
@@ -331,41 +359,287 @@ func (r *nonCompliantRepository) UpsertEmployersWithReasons(data []models.Scrape
 			}
 		}
 
-		// Convert reason codes slice to PostgreSQL array format
-		reasonCodes := item.ReasonCodes
+		var existing models.NonCompliantEmployerWithReasons
+		existingQuery := `
+			SELECT id, business_operating_name, business_legal_name, penalty_amount, penalty_currency,
+			       status, reason_codes, delisted_at
+			FROM non_compliant_employers
+			WHERE business_operating_name = $1
+			  AND COALESCE(date_of_final_decision, '1900-01-01'::date) = COALESCE($2::date, '1900-01-01'::date)
+			FOR UPDATE`
+
+		err = tx.Get(&existing, existingQuery, item.BusinessOperatingName, finalDecisionDate)
+		if err == sql.ErrNoRows {
+			insertQuery := `
+				INSERT INTO non_compliant_employers (
+					business_operating_name, business_legal_name, address,
+					date_of_final_decision, penalty_amount, penalty_currency, status, reason_codes,
+					scraped_at, first_seen_at, last_seen_at, normalized_name, normalized_legal_name
+				) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $9, NULLIF($10, ''), NULLIF($11, ''))
+				RETURNING id`
+
+			var employerID string
+			err = tx.QueryRow(insertQuery,
+				item.BusinessOperatingName, &item.BusinessLegalName, &item.Address,
+				finalDecisionDate, &item.PenaltyAmount, item.PenaltyCurrency,
+				&item.Status, pq.Array(item.ReasonCodes), now,
+				models.NormalizeEmployerName(item.BusinessOperatingName), models.NormalizeEmployerName(item.BusinessLegalName),
+			).Scan(&employerID)
+			if err != nil {
+				return fmt.Errorf("failed to insert employer: %w", err)
+			}
 
-		employerQuery := `
-			INSERT INTO non_compliant_employers (
-				business_operating_name, business_legal_name, address,
-				date_of_final_decision, penalty_amount, penalty_currency, status, reason_codes, scraped_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-			ON CONFLICT (business_operating_name, COALESCE(date_of_final_decision, '1900-01-01'::date))
-			DO UPDATE SET
-				business_legal_name = EXCLUDED.business_legal_name,
-				penalty_amount = EXCLUDED.penalty_amount,
-				penalty_currency = EXCLUDED.penalty_currency,
-				status = EXCLUDED.status,
-				reason_codes = EXCLUDED.reason_codes,
-				scraped_at = EXCLUDED.scraped_at
-			RETURNING id`
+			change := models.NonCompliantEmployerChange{
+				EmployerID:            employerID,
+				BusinessOperatingName: item.BusinessOperatingName,
+				ChangeType:            models.NonCompliantChangeListed,
+				ObservedAt:            now,
+			}
+			if err := insertEmployerChanges(tx, []models.NonCompliantEmployerChange{change}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get existing employer: %w", err)
+		}
 
-		var employerID string
-		err = tx.QueryRow(employerQuery,
-			item.BusinessOperatingName, &item.BusinessLegalName, &item.Address,
-			finalDecisionDate, &item.PenaltyAmount, item.PenaltyCurrency,
-			&item.Status, pq.Array(reasonCodes), time.Now(),
-		).Scan(&employerID)
+		changes := diffEmployerFields(&existing, item, now)
+		if existing.DelistedAt != nil {
+			changes = append(changes, models.NonCompliantEmployerChange{
+				EmployerID:            existing.ID,
+				BusinessOperatingName: existing.BusinessOperatingName,
+				ChangeType:            models.NonCompliantChangeRelisted,
+				ObservedAt:            now,
+			})
+		}
+
+		updateQuery := `
+			UPDATE non_compliant_employers SET
+				business_legal_name = $2,
+				penalty_amount = $3,
+				penalty_currency = $4,
+				status = $5,
+				reason_codes = $6,
+				scraped_at = $7,
+				last_seen_at = $7,
+				delisted_at = NULL,
+				normalized_legal_name = NULLIF($8, '')
+			WHERE id = $1`
+
+		_, err = tx.Exec(updateQuery, existing.ID,
+			&item.BusinessLegalName, &item.PenaltyAmount, item.PenaltyCurrency,
+			&item.Status, pq.Array(item.ReasonCodes), now, models.NormalizeEmployerName(item.BusinessLegalName))
 		if err != nil {
-			return fmt.Errorf("failed to upsert employer: %w", err)
+			return fmt.Errorf("failed to update employer: %w", err)
+		}
+
+		if err := insertEmployerChanges(tx, changes); err != nil {
+			return err
 		}
 	}
 
 	return tx.Commit()
 }
 
-func (r *nonCompliantRepository) ClearAllNonCompliantData() error {
-	_, err := r.db.Exec("DELETE FROM non_compliant_employers")
-	return err
+// diffEmployerFields compares the stored employer with freshly scraped data and returns one change per differing field
+func diffEmployerFields(existing *models.NonCompliantEmployerWithReasons, item models.ScraperNonCompliantData, observedAt time.Time) []models.NonCompliantEmployerChange {
+	// Penalties are compared as nullable amounts, the scraper reports a missing penalty as 0
+	oldPenalty := penaltyValue(existing.PenaltyAmount)
+	newPenalty := penaltyValue(&item.PenaltyAmount)
+
+	fields := []struct {
+		name     string
+		oldValue string
+		newValue string
+	}{
+		{models.NonCompliantFieldLegalName, stringValue(existing.BusinessLegalName), item.BusinessLegalName},
+		{models.NonCompliantFieldPenaltyAmount, formatPenalty(oldPenalty), formatPenalty(newPenalty)},
+		{models.NonCompliantFieldPenaltyCurrency, existing.PenaltyCurrency, item.PenaltyCurrency},
+		{models.NonCompliantFieldStatus, stringValue(existing.Status), item.Status},
+		{models.NonCompliantFieldReasonCodes, joinReasonCodes(existing.ReasonCodes), joinReasonCodes(item.ReasonCodes)},
+	}
+
+	var changes []models.NonCompliantEmployerChange
+	for _, field := range fields {
+		if field.oldValue == field.newValue {
+			continue
+		}
+
+		fieldName, oldValue, newValue := field.name, field.oldValue, field.newValue
+		change := models.NonCompliantEmployerChange{
+			EmployerID:            existing.ID,
+			BusinessOperatingName: existing.BusinessOperatingName,
+			ChangeType:            models.NonCompliantChangeUpdated,
+			FieldName:             &fieldName,
+			ObservedAt:            observedAt,
+		}
+		if oldValue != "" {
+			change.OldValue = &oldValue
+		}
+		if newValue != "" {
+			change.NewValue = &newValue
+		}
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// joinReasonCodes sorts reason codes so a reordering on the published list isn't recorded as a change
+func joinReasonCodes(codes []string) string {
+	sorted := append([]string(nil), codes...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// penaltyValue treats a zero penalty as no penalty, so NULL and 0 compare equal
+func penaltyValue(amount *int) *int {
+	if amount == nil || *amount == 0 {
+		return nil
+	}
+	return amount
+}
+
+func formatPenalty(amount *int) string {
+	if amount == nil {
+		return ""
+	}
+	return strconv.Itoa(*amount)
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func insertEmployerChanges(tx *sqlx.Tx, changes []models.NonCompliantEmployerChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO non_compliant_employer_changes (
+			employer_id, business_operating_name, change_type, field_name, old_value, new_value, observed_at
+		) VALUES (
+			:employer_id, :business_operating_name, :change_type, :field_name, :old_value, :new_value, :observed_at
+		)`
+
+	if _, err := tx.NamedExec(query, changes); err != nil {
+		return fmt.Errorf("failed to insert employer changes: %w", err)
+	}
+	return nil
+}
+
+// MarkDelistedEmployers flags employers that were not seen since the given scrape time as removed
+// from the list, and returns the number flagged
+func (r *nonCompliantRepository) MarkDelistedEmployers(seenBefore time.Time) (int, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var delisted []models.NonCompliantEmployerChange
+	query := `
+		UPDATE non_compliant_employers
+		SET delisted_at = $1
+		WHERE last_seen_at < $1 AND delisted_at IS NULL
+		RETURNING id as employer_id, business_operating_name`
+
+	if err := tx.Select(&delisted, query, seenBefore); err != nil {
+		return 0, fmt.Errorf("failed to mark delisted employers: %w", err)
+	}
+
+	for i := range delisted {
+		delisted[i].ChangeType = models.NonCompliantChangeDelisted
+		delisted[i].ObservedAt = seenBefore
+	}
+
+	if err := insertEmployerChanges(tx, delisted); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(delisted), nil
+}
+
+// buildChangeFilters builds the WHERE clause for the change feed
+func buildChangeFilters(filters models.NonCompliantChangeFilters) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filters.ChangeType != "" {
+		args = append(args, filters.ChangeType)
+		conditions = append(conditions, fmt.Sprintf("change_type = $%d", len(args)))
+	}
+	if filters.FieldName != "" {
+		args = append(args, filters.FieldName)
+		conditions = append(conditions, fmt.Sprintf("field_name = $%d", len(args)))
+	}
+	if filters.Since != nil {
+		args = append(args, *filters.Since)
+		conditions = append(conditions, fmt.Sprintf("observed_at >= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// GetChanges returns recorded changes across all employers, most recent first
+func (r *nonCompliantRepository) GetChanges(filters models.NonCompliantChangeFilters, limit, offset int) ([]models.NonCompliantEmployerChange, error) {
+	where, args := buildChangeFilters(filters)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT id, employer_id, business_operating_name, change_type, field_name, old_value, new_value, observed_at
+		FROM non_compliant_employer_changes
+		%s
+		ORDER BY observed_at DESC, business_operating_name
+		LIMIT $%d OFFSET $%d`, where, len(args)-1, len(args))
+
+	changes := []models.NonCompliantEmployerChange{}
+	if err := r.db.Select(&changes, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetChangesCount returns the number of recorded changes matching the filters
+func (r *nonCompliantRepository) GetChangesCount(filters models.NonCompliantChangeFilters) (int, error) {
+	where, args := buildChangeFilters(filters)
+
+	var count int
+	query := "SELECT COUNT(*) FROM non_compliant_employer_changes " + where
+	if err := r.db.Get(&count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to count non-compliant changes: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetEmployerChanges returns every change recorded for an employer, oldest first
+func (r *nonCompliantRepository) GetEmployerChanges(employerID string) ([]models.NonCompliantEmployerChange, error) {
+	changes := []models.NonCompliantEmployerChange{}
+	query := `
+		SELECT id, employer_id, business_operating_name, change_type, field_name, old_value, new_value, observed_at
+		FROM non_compliant_employer_changes
+		WHERE employer_id = $1
+		ORDER BY observed_at, id`
+
+	if err := r.db.Select(&changes, query, employerID); err != nil {
+		return nil, fmt.Errorf("failed to get employer changes: %w", err)
+	}
+
+	return changes, nil
 }
 
 func (r *nonCompliantRepository) GetLatestScrapedDate() (*time.Time, error) {
@@ -482,7 +756,8 @@ func (r *nonCompliantRepository) GetEmployersByPostalCode(postalCode string, lim
 		SELECT
 			epc.id, epc.business_operating_name, epc.business_legal_name, epc.address,
 			epc.date_of_final_decision, epc.penalty_amount, epc.penalty_currency, epc.status,
-			epc.reason_codes, epc.postal_code, epc.scraped_at, epc.created_at, epc.updated_at
+			epc.reason_codes, epc.postal_code, epc.first_seen_at, epc.last_seen_at, epc.delisted_at,
+			epc.scraped_at, epc.created_at, epc.updated_at
 		FROM extracted_postal_codes epc
 		WHERE epc.extracted_postal_code = $1
 		ORDER BY epc.date_of_final_decision DESC, epc.business_operating_name
@@ -531,7 +806,10 @@ func (r *nonCompliantRepository) GetEmployersWithoutExtractablePostalCodes() ([]
 func (r *nonCompliantRepository) GetEmployersByCoordinates(lat, lng float64, limit, offset int) ([]models.NonCompliantEmployerWithReasons, error) {
 	query := `
 		SELECT
-			e.*
+			e.id, e.business_operating_name, e.business_legal_name, e.address,
+			e.date_of_final_decision, e.penalty_amount, e.penalty_currency, e.status,
+			e.reason_codes, e.postal_code, e.parsed_address_id, e.first_seen_at, e.last_seen_at, e.delisted_at,
+			e.scraped_at, e.created_at, e.updated_at
 		FROM non_compliant_employers e
 		JOIN address_geocoding_cache agc ON agc.normalized_address = LOWER(TRIM(REGEXP_REPLACE(REGEXP_REPLACE(e.address, '[,.]', '', 'g'), '\s+', ' ', 'g')))
 		WHERE e.address IS NOT NULL
@@ -566,7 +844,9 @@ func (r *nonCompliantRepository) GetEmployersByCoordinates(lat, lng float64, lim
 func (r *nonCompliantRepository) UpdateEmployerAddress(employerID, address string) error {
 	query := `
 		UPDATE non_compliant_employers
-		SET address = $2, updated_at = CURRENT_TIMESTAMP
+		SET address = $2,
+		    parsed_address_id = CASE WHEN address IS DISTINCT FROM $2 THEN NULL ELSE parsed_address_id END,
+		    updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1`
 
 	_, err := r.db.Exec(query, employerID, address)
@@ -586,3 +866,406 @@ func (r *nonCompliantRepository) GetAllEmployers() ([]models.NonCompliantEmploye
 	err := r.db.Select(&employers, query)
 	return employers, err
 }
+
+// BackfillNormalizedNames fills in the normalized names for employers stored before the columns existed
+func (r *nonCompliantRepository) BackfillNormalizedNames() (int, error) {
+	var employers []models.NonCompliantEmployer
+	query := `
+		SELECT id, business_operating_name, business_legal_name
+		FROM non_compliant_employers
+		WHERE normalized_name IS NULL`
+
+	if err := r.db.Select(&employers, query); err != nil {
+		return 0, fmt.Errorf("failed to get employers without normalized names: %w", err)
+	}
+
+	if len(employers) == 0 {
+		return 0, nil
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	updateQuery := `
+		UPDATE non_compliant_employers
+		SET normalized_name = NULLIF($2, ''), normalized_legal_name = NULLIF($3, '')
+		WHERE id = $1`
+	for _, employer := range employers {
+		_, err := tx.Exec(updateQuery, employer.ID,
+			models.NormalizeEmployerName(employer.BusinessOperatingName),
+			models.NormalizeEmployerName(stringValue(employer.BusinessLegalName)))
+		if err != nil {
+			return 0, fmt.Errorf("failed to update normalized employer names: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(employers), nil
+}
+
+// GetMatchCandidates returns the normalized names and decisions of every listed employer
+func (r *nonCompliantRepository) GetMatchCandidates() ([]models.NonCompliantMatchCandidate, error) {
+	var candidates []models.NonCompliantMatchCandidate
+	query := `
+		SELECT id, normalized_name, normalized_legal_name, date_of_final_decision, penalty_amount
+		FROM non_compliant_employers
+		WHERE normalized_name IS NOT NULL OR normalized_legal_name IS NOT NULL`
+
+	if err := r.db.Select(&candidates, query); err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant match candidates: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// GetMatchCandidatesByNormalizedNames returns listed employers whose operating or legal name matches one of the names
+func (r *nonCompliantRepository) GetMatchCandidatesByNormalizedNames(names []string) ([]models.NonCompliantMatchCandidate, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT id, normalized_name, normalized_legal_name, date_of_final_decision, penalty_amount
+		FROM non_compliant_employers
+		WHERE normalized_name IN (?) OR normalized_legal_name IN (?)`, names, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build non-compliant name query: %w", err)
+	}
+
+	var candidates []models.NonCompliantMatchCandidate
+	if err := r.db.Select(&candidates, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant employers by name: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// ReplaceJobPostingFlags clears the non-compliant flag on every posting and sets it on the given postings
+func (r *nonCompliantRepository) ReplaceJobPostingFlags(flags []models.NonCompliantFlag) error {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	clearQuery := `
+		UPDATE job_postings
+		SET non_compliant_employer_id = NULL, is_non_compliant_employer = FALSE,
+			non_compliant_decision_date = NULL, non_compliant_penalty_amount = NULL
+		WHERE is_non_compliant_employer OR non_compliant_employer_id IS NOT NULL`
+	if _, err := tx.Exec(clearQuery); err != nil {
+		return fmt.Errorf("failed to clear non-compliant flags: %w", err)
+	}
+
+	updateQuery := `
+		UPDATE job_postings
+		SET non_compliant_employer_id = :non_compliant_employer_id, is_non_compliant_employer = TRUE,
+			non_compliant_decision_date = :non_compliant_decision_date,
+			non_compliant_penalty_amount = :non_compliant_penalty_amount
+		WHERE id = :job_posting_id`
+	for _, flag := range flags {
+		if _, err := tx.NamedExec(updateQuery, flag); err != nil {
+			return fmt.Errorf("failed to flag job posting: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// stillHiringQuery aggregates, per non-compliant decision, the flagged Job Bank postings and the LMIA
+// approvals in quarters starting after the decision. Quarters look like "Q1" or "Q1Q2"; the first one
+// is used as the start of the period.
+const stillHiringQuery = `
+	WITH postings AS (
+		SELECT non_compliant_employer_id, COUNT(*) as active_job_postings, MAX(posting_date) as latest_posting_date
+		FROM job_postings
+		WHERE non_compliant_employer_id IS NOT NULL
+		GROUP BY non_compliant_employer_id
+	),
+	approvals AS (
+		SELECT nce.id as non_compliant_employer_id,
+			   COALESCE(SUM(le.approved_lmias), 0) as approvals_after_decision,
+			   COALESCE(SUM(le.approved_positions), 0) as positions_after_decision,
+			   MAX(make_date(le.year, (SUBSTRING(le.quarter FROM 2 FOR 1)::int - 1) * 3 + 1, 1)) as latest_approval_period
+		FROM non_compliant_employers nce
+		JOIN lmia_employers le ON le.normalized_employer IN (nce.normalized_name, nce.normalized_legal_name)
+		WHERE nce.date_of_final_decision IS NOT NULL
+		  AND le.quarter ~ '^Q[1-4]'
+		  AND make_date(le.year, (SUBSTRING(le.quarter FROM 2 FOR 1)::int - 1) * 3 + 1, 1) > nce.date_of_final_decision
+		GROUP BY nce.id
+	)
+	SELECT e.id, e.business_operating_name, e.business_legal_name, e.address, e.date_of_final_decision,
+		   e.penalty_amount, e.status,
+		   COALESCE(p.active_job_postings, 0) as active_job_postings, p.latest_posting_date,
+		   COALESCE(a.approvals_after_decision, 0) as approvals_after_decision,
+		   COALESCE(a.positions_after_decision, 0) as positions_after_decision, a.latest_approval_period
+	FROM non_compliant_employers e
+	LEFT JOIN postings p ON p.non_compliant_employer_id = e.id
+	LEFT JOIN approvals a ON a.non_compliant_employer_id = e.id
+	WHERE p.non_compliant_employer_id IS NOT NULL OR a.non_compliant_employer_id IS NOT NULL`
+
+// GetStillHiringEmployers returns non-compliant employers that are still hiring through the LMIA program
+func (r *nonCompliantRepository) GetStillHiringEmployers(limit, offset int) ([]models.StillHiringEmployer, error) {
+	employers := []models.StillHiringEmployer{}
+	query := stillHiringQuery + `
+	ORDER BY active_job_postings DESC, positions_after_decision DESC, e.date_of_final_decision DESC
+	LIMIT $1 OFFSET $2`
+
+	if err := r.db.Select(&employers, query, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to get still hiring employers: %w", err)
+	}
+
+	return employers, nil
+}
+
+func (r *nonCompliantRepository) GetStillHiringCount() (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM (" + stillHiringQuery + ") still_hiring"
+	if err := r.db.Get(&count, query); err != nil {
+		return 0, fmt.Errorf("failed to count still hiring employers: %w", err)
+	}
+
+	return count, nil
+}
+
+// nonCompliantProvincePatterns maps each province code to the names the published addresses end with
+var nonCompliantProvincePatterns = []struct {
+	code    string
+	pattern string
+}{
+	{"AB", `\mAB|alberta`},
+	{"BC", `\mBC|british columbia|colombie-britannique`},
+	{"MB", `\mMB|manitoba`},
+	{"NB", `\mNB|new brunswick|nouveau-brunswick`},
+	{"NL", `\mNL|newfoundland and labrador|terre-neuve-et-labrador`},
+	{"NT", `\mNT|northwest territories|territoires du nord-ouest`},
+	{"NS", `\mNS|nova scotia|nouvelle-écosse`},
+	{"NU", `\mNU|nunavut`},
+	{"ON", `\mON|ontario`},
+	{"PE", `\mPE|prince edward island|île-du-prince-édouard`},
+	{"QC", `\mQC|quebec|québec`},
+	{"SK", `\mSK|saskatchewan`},
+	{"YT", `\mYT|yukon`},
+}
+
+// nonCompliantProvinceExpr derives an employer's province from the end of its address, optionally
+// followed by a postal code, and falls back to the first letter of the postal code
+var nonCompliantProvinceExpr = func() string {
+	var expr strings.Builder
+	expr.WriteString("CASE")
+	for _, province := range nonCompliantProvincePatterns {
+		fmt.Fprintf(&expr, "\n\t\t\tWHEN e.address ~* '(%s)[\\s,]*([A-Za-z]\\d[A-Za-z]\\s*\\d[A-Za-z]\\d)?\\s*$' THEN '%s'", province.pattern, province.code)
+	}
+	expr.WriteString(`
+			ELSE CASE UPPER(LEFT(COALESCE(e.postal_code, SUBSTRING(e.address FROM '[A-Za-z]\d[A-Za-z]\s*\d[A-Za-z]\d')), 1))
+				WHEN 'A' THEN 'NL' WHEN 'B' THEN 'NS' WHEN 'C' THEN 'PE' WHEN 'E' THEN 'NB'
+				WHEN 'G' THEN 'QC' WHEN 'H' THEN 'QC' WHEN 'J' THEN 'QC'
+				WHEN 'K' THEN 'ON' WHEN 'L' THEN 'ON' WHEN 'M' THEN 'ON' WHEN 'N' THEN 'ON' WHEN 'P' THEN 'ON'
+				WHEN 'R' THEN 'MB' WHEN 'S' THEN 'SK' WHEN 'T' THEN 'AB' WHEN 'V' THEN 'BC'
+				WHEN 'X' THEN 'NT' WHEN 'Y' THEN 'YT'
+				ELSE 'Unknown'
+			END
+		END`)
+	return expr.String()
+}()
+
+// nonCompliantPenaltyBracketExpr groups penalties into brackets, with a sort key so brackets come back in order
+const nonCompliantPenaltyBracketExpr = `
+	CASE
+		WHEN COALESCE(e.penalty_amount, 0) = 0 THEN '0:No penalty'
+		WHEN e.penalty_amount < 10000 THEN '1:Under $10,000'
+		WHEN e.penalty_amount < 50000 THEN '2:$10,000 - $49,999'
+		WHEN e.penalty_amount < 100000 THEN '3:$50,000 - $99,999'
+		WHEN e.penalty_amount < 500000 THEN '4:$100,000 - $499,999'
+		ELSE '5:$500,000 and over'
+	END`
+
+// penaltyBreakdownQuery aggregates decisions grouped by the given expression
+const penaltyBreakdownQuery = `
+	SELECT %s as name,
+		   COUNT(*) as employer_count,
+		   COALESCE(SUM(e.penalty_amount), 0) as total_penalty_amount,
+		   COALESCE(ROUND(AVG(e.penalty_amount)::numeric, 2), 0)::float8 as avg_penalty_amount,
+		   COALESCE(MAX(e.penalty_amount), 0) as max_penalty_amount
+	FROM non_compliant_employers e
+	GROUP BY 1
+	ORDER BY %s`
+
+// GetPenaltyStats returns totals and breakdowns by reason, province, decision year, status and penalty bracket
+func (r *nonCompliantRepository) GetPenaltyStats() (*models.NonCompliantPenaltyStats, error) {
+	stats := &models.NonCompliantPenaltyStats{}
+
+	totalsQuery := `
+		SELECT COUNT(*) as total_employers,
+			   COUNT(*) FILTER (WHERE penalty_amount > 0) as penalized_employers,
+			   COALESCE(SUM(penalty_amount), 0) as total_penalty_amount,
+			   COALESCE(ROUND(AVG(penalty_amount) FILTER (WHERE penalty_amount > 0)::numeric, 2), 0)::float8 as avg_penalty_amount,
+			   COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY penalty_amount) FILTER (WHERE penalty_amount > 0), 0)::float8 as median_penalty_amount,
+			   MIN(date_of_final_decision) as earliest_decision_date,
+			   MAX(date_of_final_decision) as latest_decision_date
+		FROM non_compliant_employers`
+
+	if err := r.db.Get(stats, totalsQuery); err != nil {
+		return nil, fmt.Errorf("failed to get penalty totals: %w", err)
+	}
+
+	reasonQuery := `
+		SELECT rc.code as name,
+			   nr.description,
+			   COUNT(*) as employer_count,
+			   COALESCE(SUM(e.penalty_amount), 0) as total_penalty_amount,
+			   COALESCE(ROUND(AVG(e.penalty_amount)::numeric, 2), 0)::float8 as avg_penalty_amount,
+			   COALESCE(MAX(e.penalty_amount), 0) as max_penalty_amount
+		FROM non_compliant_employers e
+		CROSS JOIN LATERAL unnest(e.reason_codes) as rc(code)
+		LEFT JOIN non_compliant_reasons nr ON nr.reason_code = rc.code
+		GROUP BY rc.code, nr.description
+		ORDER BY employer_count DESC, rc.code`
+
+	if err := r.db.Select(&stats.ByReason, reasonQuery); err != nil {
+		return nil, fmt.Errorf("failed to get penalties by reason: %w", err)
+	}
+
+	breakdowns := []struct {
+		name    string
+		dest    *[]models.NonCompliantPenaltyBreakdown
+		groupBy string
+		orderBy string
+	}{
+		{"province", &stats.ByProvince, nonCompliantProvinceExpr, "employer_count DESC, name"},
+		{"year", &stats.ByYear, "COALESCE(EXTRACT(YEAR FROM e.date_of_final_decision)::int::text, 'Unknown')", "name"},
+		{"status", &stats.ByStatus, "COALESCE(NULLIF(e.status, ''), 'Unknown')", "employer_count DESC, name"},
+		{"penalty bracket", &stats.ByPenaltyBracket, nonCompliantPenaltyBracketExpr, "name"},
+	}
+
+	for _, breakdown := range breakdowns {
+		query := fmt.Sprintf(penaltyBreakdownQuery, breakdown.groupBy, breakdown.orderBy)
+		if err := r.db.Select(breakdown.dest, query); err != nil {
+			return nil, fmt.Errorf("failed to get penalties by %s: %w", breakdown.name, err)
+		}
+	}
+
+	// Drop the sort key from the bracket labels
+	for i := range stats.ByPenaltyBracket {
+		if _, label, ok := strings.Cut(stats.ByPenaltyBracket[i].Name, ":"); ok {
+			stats.ByPenaltyBracket[i].Name = label
+		}
+	}
+
+	return stats, nil
+}
+
+// GetLargestPenalties returns the decisions with the largest penalties
+func (r *nonCompliantRepository) GetLargestPenalties(limit int) ([]models.NonCompliantEmployerWithReasons, error) {
+	employers := []models.NonCompliantEmployerWithReasons{}
+	query := `
+		SELECT id, business_operating_name, business_legal_name, address,
+		       date_of_final_decision, penalty_amount, penalty_currency, status,
+		       reason_codes, postal_code, first_seen_at, last_seen_at, delisted_at,
+		       scraped_at, created_at, updated_at
+		FROM non_compliant_employers
+		WHERE penalty_amount > 0
+		ORDER BY penalty_amount DESC, date_of_final_decision DESC
+		LIMIT $1`
+
+	if err := r.db.Select(&employers, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get largest penalties: %w", err)
+	}
+
+	var codes []string
+	for _, employer := range employers {
+		codes = append(codes, employer.ReasonCodes...)
+	}
+	reasons, err := r.getReasonsByCodes(codes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range employers {
+		for _, code := range employers[i].ReasonCodes {
+			if reason, ok := reasons[code]; ok {
+				employers[i].Reasons = append(employers[i].Reasons, reason)
+			}
+		}
+	}
+
+	return employers, nil
+}
+
+// getReasonsByCodes loads the reasons of every given code in one query, keyed by code
+func (r *nonCompliantRepository) getReasonsByCodes(codes []string) (map[string]models.NonCompliantReason, error) {
+	reasons := make(map[string]models.NonCompliantReason)
+	if len(codes) == 0 {
+		return reasons, nil
+	}
+
+	var rows []models.NonCompliantReason
+	query := "SELECT id, reason_code, description, created_at, updated_at FROM non_compliant_reasons WHERE reason_code = ANY($1)"
+	if err := r.db.Select(&rows, query, pq.Array(codes)); err != nil {
+		return nil, fmt.Errorf("failed to get non-compliant reasons: %w", err)
+	}
+
+	for _, reason := range rows {
+		reasons[reason.ReasonCode] = reason
+	}
+	return reasons, nil
+}
+
+// GetRepeatOffenders returns legal entities listed under at least minListings final decisions.
+// The operating name is used when the legal name is missing.
+func (r *nonCompliantRepository) GetRepeatOffenders(minListings, limit int) ([]models.NonCompliantRepeatOffender, error) {
+	offenders := []models.NonCompliantRepeatOffender{}
+	query := `
+		SELECT MAX(COALESCE(NULLIF(TRIM(business_legal_name), ''), business_operating_name)) as legal_name,
+			   COUNT(*) as listing_count,
+			   array_agg(DISTINCT business_operating_name) as operating_names,
+			   COALESCE(SUM(penalty_amount), 0) as total_penalty_amount,
+			   MIN(date_of_final_decision) as first_decision_date,
+			   MAX(date_of_final_decision) as latest_decision_date,
+			   array_agg(id::text ORDER BY date_of_final_decision) as employer_ids
+		FROM non_compliant_employers
+		GROUP BY LOWER(TRIM(COALESCE(NULLIF(TRIM(business_legal_name), ''), business_operating_name)))
+		HAVING COUNT(*) >= $1
+		ORDER BY listing_count DESC, total_penalty_amount DESC
+		LIMIT $2`
+
+	if err := r.db.Select(&offenders, query, minListings, limit); err != nil {
+		return nil, fmt.Errorf("failed to get repeat offenders: %w", err)
+	}
+
+	return offenders, nil
+}
+
+// GetPenaltyTrends returns decision counts and penalty totals per month or year of the final decision
+func (r *nonCompliantRepository) GetPenaltyTrends(period string) ([]models.NonCompliantTrendPoint, error) {
+	if period != "month" && period != "year" {
+		return nil, fmt.Errorf("invalid trend period: %s", period)
+	}
+
+	points := []models.NonCompliantTrendPoint{}
+	query := fmt.Sprintf(`
+		SELECT date_trunc('%s', date_of_final_decision) as period,
+			   COUNT(*) as decision_count,
+			   COALESCE(SUM(penalty_amount), 0) as total_penalty_amount,
+			   COALESCE(ROUND(AVG(penalty_amount)::numeric, 2), 0)::float8 as avg_penalty_amount
+		FROM non_compliant_employers
+		WHERE date_of_final_decision IS NOT NULL
+		GROUP BY 1
+		ORDER BY 1`, period)
+
+	if err := r.db.Select(&points, query); err != nil {
+		return nil, fmt.Errorf("failed to get penalty trends: %w", err)
+	}
+
+	return points, nil
+}
@@ -13,6 +13,9 @@ type PostalCodeRepository interface {
 	Create(postalCode *models.PostalCodeCoordinates) error
 	Update(postalCode *models.PostalCodeCoordinates) error
 	Upsert(postalCode *models.PostalCodeCoordinates) error
+	UpsertCentroidsBatch(centroids []*models.PostalCodeCoordinates) error
+	DeriveFSACentroids() (int64, error)
+	GetMunicipalityCentroid(municipality, provinceCode string) (*models.PostalCodeCoordinates, error)
 }
 
 type postalCodeRepository struct {
@@ -27,7 +30,7 @@ func (r *postalCodeRepository) GetByPostalCode(postalCode string) (*models.Posta
 	var result models.PostalCodeCoordinates
 	
 	query := `
-		SELECT postal_code, latitude, longitude
+		SELECT postal_code, latitude, longitude, provider, confidence, precision, match_type, layer, manual_override
 		FROM postal_codes 
 		WHERE postal_code = $1
 	`
@@ -47,11 +50,11 @@ func (r *postalCodeRepository) Create(postalCode *models.PostalCodeCoordinates)
 	}
 	
 	query := `
-		INSERT INTO postal_codes (postal_code, latitude, longitude, created_at, updated_at)
-		VALUES ($1, $2, $3, NOW(), NOW())
+		INSERT INTO postal_codes (postal_code, latitude, longitude, provider, confidence, precision, match_type, layer, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 	`
 	
-	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude)
+	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude, postalCode.Provider, postalCode.Confidence, postalCode.Precision, postalCode.MatchType, postalCode.Layer)
 	if err != nil {
 		return fmt.Errorf("failed to create postal code: %w", err)
 	}
@@ -62,11 +65,12 @@ func (r *postalCodeRepository) Create(postalCode *models.PostalCodeCoordinates)
 func (r *postalCodeRepository) Update(postalCode *models.PostalCodeCoordinates) error {
 	query := `
 		UPDATE postal_codes 
-		SET latitude = $2, longitude = $3, updated_at = NOW()
+		SET latitude = $2, longitude = $3, provider = $4, confidence = $5, precision = $6, match_type = $7, layer = $8, updated_at = NOW()
 		WHERE postal_code = $1
+		  AND NOT manual_override
 	`
 	
-	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude)
+	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude, postalCode.Provider, postalCode.Confidence, postalCode.Precision, postalCode.MatchType, postalCode.Layer)
 	if err != nil {
 		return fmt.Errorf("failed to update postal code: %w", err)
 	}
@@ -81,16 +85,29 @@ func (r *postalCodeRepository) Upsert(postalCode *models.PostalCodeCoordinates)
 	}
 	
 	query := `
-		INSERT INTO postal_codes (postal_code, latitude, longitude, created_at, updated_at)
-		VALUES ($1, $2, $3, NOW(), NOW())
+		INSERT INTO postal_codes (postal_code, latitude, longitude, provider, confidence, precision, match_type, layer, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 		ON CONFLICT (postal_code) 
 		DO UPDATE SET 
 			latitude = EXCLUDED.latitude,
 			longitude = EXCLUDED.longitude,
+			-- Coordinates saved again without a provider keep the recorded one
+			provider = COALESCE(EXCLUDED.provider, postal_codes.provider),
+			confidence = COALESCE(EXCLUDED.confidence, postal_codes.confidence),
+			precision = COALESCE(EXCLUDED.precision, postal_codes.precision),
+			match_type = CASE WHEN EXCLUDED.provider IS NULL THEN postal_codes.match_type ELSE EXCLUDED.match_type END,
+			layer = CASE WHEN EXCLUDED.provider IS NULL THEN postal_codes.layer ELSE EXCLUDED.layer END,
+			-- Moved coordinates are assigned to an electoral district again
+			electoral_district_id = CASE
+				WHEN (postal_codes.latitude, postal_codes.longitude) IS NOT DISTINCT FROM (EXCLUDED.latitude, EXCLUDED.longitude)
+				THEN postal_codes.electoral_district_id
+			END,
 			updated_at = NOW()
+		-- Coordinates accepted or pinned by a reviewer are never replaced
+		WHERE NOT postal_codes.manual_override
 	`
 	
-	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude)
+	_, err := r.db.Exec(query, postalCode.PostalCode, postalCode.Latitude, postalCode.Longitude, postalCode.Provider, postalCode.Confidence, postalCode.Precision, postalCode.MatchType, postalCode.Layer)
 	if err != nil {
 		return fmt.Errorf("failed to upsert postal code: %w", err)
 	}
@@ -102,7 +119,7 @@ func (r *postalCodeRepository) GetAllPostalCodes() (map[string]models.PostalCode
 	var rows []models.PostalCodeCoordinates
 	
 	query := `
-		SELECT postal_code, latitude, longitude
+		SELECT postal_code, latitude, longitude, provider, confidence, precision, match_type, layer, manual_override
 		FROM postal_codes 
 		WHERE latitude IS NOT NULL AND longitude IS NOT NULL
 	`
@@ -121,3 +138,109 @@ func (r *postalCodeRepository) GetAllPostalCodes() (map[string]models.PostalCode
 	return result, nil
 }
 
+// UpsertCentroidsBatch stores imported postal code and FSA centroids. A centroid never replaces
+// coordinates that are more precise, e.g. a geocoded postal code with an FSA centroid.
+func (r *postalCodeRepository) UpsertCentroidsBatch(centroids []*models.PostalCodeCoordinates) error {
+	if len(centroids) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO postal_codes (postal_code, latitude, longitude, provider, confidence, precision, created_at, updated_at)
+		VALUES (:postal_code, :latitude, :longitude, :provider, :confidence, :precision, NOW(), NOW())
+		ON CONFLICT (postal_code) DO UPDATE SET
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			provider = EXCLUDED.provider,
+			confidence = EXCLUDED.confidence,
+			precision = EXCLUDED.precision,
+			match_type = NULL,
+			layer = NULL,
+			electoral_district_id = CASE
+				WHEN (postal_codes.latitude, postal_codes.longitude) IS NOT DISTINCT FROM (EXCLUDED.latitude, EXCLUDED.longitude)
+				THEN postal_codes.electoral_district_id
+			END,
+			updated_at = NOW()
+		WHERE NOT postal_codes.manual_override
+		  AND (postal_codes.latitude IS NULL
+		   OR postal_codes.precision IS DISTINCT FROM 'postal_code'
+		   OR EXCLUDED.precision = 'postal_code')
+	`
+
+	// Keep each batch well under the PostgreSQL parameter limit (6 parameters per row)
+	batchSize := 5000
+	for i := 0; i < len(centroids); i += batchSize {
+		end := i + batchSize
+		if end > len(centroids) {
+			end = len(centroids)
+		}
+
+		if _, err := tx.NamedExec(query, centroids[i:end]); err != nil {
+			return fmt.Errorf("failed to upsert postal code centroids batch %d-%d: %w", i, end, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeriveFSACentroids averages the postal codes of every forward sortation area into an FSA
+// centroid. Centroids that were imported are left alone.
+func (r *postalCodeRepository) DeriveFSACentroids() (int64, error) {
+	query := `
+		INSERT INTO postal_codes (postal_code, latitude, longitude, provider, precision, created_at, updated_at)
+		SELECT LEFT(postal_code, 3), AVG(latitude), AVG(longitude), 'derived', 'fsa', NOW(), NOW()
+		FROM postal_codes
+		WHERE precision = 'postal_code'
+		  AND LENGTH(postal_code) = 7
+		  AND latitude IS NOT NULL
+		  AND longitude IS NOT NULL
+		GROUP BY LEFT(postal_code, 3)
+		ON CONFLICT (postal_code) DO UPDATE SET
+			latitude = EXCLUDED.latitude,
+			longitude = EXCLUDED.longitude,
+			updated_at = NOW()
+		WHERE postal_codes.provider = 'derived'
+	`
+
+	result, err := r.db.Exec(query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to derive FSA centroids: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetMunicipalityCentroid places a municipality at the median of the postal codes of the parsed
+// addresses in it. The coordinates are invalid when none of its postal codes are known.
+func (r *postalCodeRepository) GetMunicipalityCentroid(municipality, provinceCode string) (*models.PostalCodeCoordinates, error) {
+	var result models.PostalCodeCoordinates
+
+	query := `
+		SELECT
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY pc.latitude) as latitude,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY pc.longitude) as longitude
+		FROM parsed_addresses pa
+		JOIN postal_codes pc ON pc.postal_code = pa.postal_code
+		WHERE pa.province_code = $2
+		  AND LOWER(pa.municipality) = LOWER($1)
+		  AND pc.latitude IS NOT NULL
+		  AND pc.longitude IS NOT NULL
+	`
+
+	err := r.db.Get(&result, query, municipality, provinceCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get municipality centroid: %w", err)
+	}
+
+	return &result, nil
+}
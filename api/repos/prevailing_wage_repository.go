@@ -0,0 +1,166 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type PrevailingWageRepository interface {
+	UpsertWagesBatch(wages []*models.PrevailingWage) error
+	GetWagesCount() (int, error)
+	FindWageForLocation(nocCode, province, city string) (*models.PrevailingWage, error)
+	FindNOCCodeByTitle(title string) (string, error)
+}
+
+type prevailingWageRepository struct {
+	db *sqlx.DB
+}
+
+func NewPrevailingWageRepository(db *sqlx.DB) PrevailingWageRepository {
+	return &prevailingWageRepository{db: db}
+}
+
+// UpsertWagesBatch inserts or updates wage rows keyed by NOC code and economic region
+func (r *prevailingWageRepository) UpsertWagesBatch(wages []*models.PrevailingWage) error {
+	if len(wages) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO prevailing_wages (id, noc_code, noc_title, economic_region_code, economic_region_name,
+									  region_level, province, low_wage, median_wage, high_wage, is_annual,
+									  reference_period, created_at, updated_at)
+		VALUES (:id, :noc_code, :noc_title, :economic_region_code, :economic_region_name,
+				:region_level, :province, :low_wage, :median_wage, :high_wage, :is_annual,
+				:reference_period, :created_at, :updated_at)
+		ON CONFLICT (noc_code, economic_region_code) DO UPDATE SET
+			noc_title = EXCLUDED.noc_title,
+			economic_region_name = EXCLUDED.economic_region_name,
+			region_level = EXCLUDED.region_level,
+			province = EXCLUDED.province,
+			low_wage = EXCLUDED.low_wage,
+			median_wage = EXCLUDED.median_wage,
+			high_wage = EXCLUDED.high_wage,
+			is_annual = EXCLUDED.is_annual,
+			reference_period = EXCLUDED.reference_period,
+			updated_at = EXCLUDED.updated_at
+	`
+
+	now := time.Now()
+	for _, wage := range wages {
+		wage.ID = uuid.New().String()
+		wage.CreatedAt = now
+		wage.UpdatedAt = now
+	}
+
+	// Keep each batch well under the PostgreSQL parameter limit (14 parameters per row)
+	batchSize := 1000
+	for i := 0; i < len(wages); i += batchSize {
+		end := i + batchSize
+		if end > len(wages) {
+			end = len(wages)
+		}
+
+		if _, err := tx.NamedExec(query, wages[i:end]); err != nil {
+			return fmt.Errorf("failed to upsert prevailing wages batch %d-%d: %w", i, end, err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetWagesCount returns the number of wage rows loaded
+func (r *prevailingWageRepository) GetWagesCount() (int, error) {
+	var count int
+	err := r.db.Get(&count, `SELECT COUNT(*) FROM prevailing_wages`)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindWageForLocation finds the most specific wage row for a NOC code. It prefers an economic
+// region in the province whose name contains the city, then the province, then the national row.
+func (r *prevailingWageRepository) FindWageForLocation(nocCode, province, city string) (*models.PrevailingWage, error) {
+	var wage models.PrevailingWage
+	query := `
+		SELECT * FROM prevailing_wages
+		WHERE noc_code = $1
+		AND median_wage IS NOT NULL
+		AND (
+			(region_level = 'economic_region' AND province = $2 AND $3 != '' AND economic_region_name ILIKE '%' || $3 || '%')
+			OR (region_level = 'province' AND province = $2)
+			OR region_level = 'national'
+		)
+		ORDER BY CASE region_level
+			WHEN 'economic_region' THEN 0
+			WHEN 'province' THEN 1
+			ELSE 2
+		END
+		LIMIT 1
+	`
+
+	err := r.db.Get(&wage, query, nocCode, province, escapeLikePattern(city))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find prevailing wage: %w", err)
+	}
+
+	return &wage, nil
+}
+
+// FindNOCCodeByTitle matches a job title against NOC titles in the wage table. NOC titles are
+// plural ("Cooks"), so the title is matched as a prefix of the NOC title.
+func (r *prevailingWageRepository) FindNOCCodeByTitle(title string) (string, error) {
+	// An empty prefix would match every NOC title
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return "", nil
+	}
+
+	var nocCode string
+	query := `
+		SELECT noc_code FROM prevailing_wages
+		WHERE noc_title ILIKE $1 || '%'
+		GROUP BY noc_code, noc_title
+		ORDER BY LENGTH(noc_title) ASC
+		LIMIT 1
+	`
+
+	err := r.db.Get(&nocCode, query, escapeLikePattern(title))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to match NOC title: %w", err)
+	}
+
+	return nocCode, nil
+}
+
+// likeEscaper escapes the LIKE wildcards, using the default backslash escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern makes a value match literally inside a LIKE pattern, so titles such as
+// "100% remote" don't act as wildcards
+func escapeLikePattern(value string) string {
+	return likeEscaper.Replace(value)
+}
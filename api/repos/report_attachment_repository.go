@@ -0,0 +1,121 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReportAttachmentRepository interface {
+	Create(attachment *models.ReportAttachment) error
+	GetByID(id string) (*models.ReportAttachment, error)
+	GetByChecksum(reportID, checksum string) (*models.ReportAttachment, error)
+	GetByReportID(reportID string, publicOnly bool) ([]*models.ReportAttachment, error)
+	CountByReportID(reportID string) (int, error)
+	SetVisibility(id, visibility, reviewerID string) (*models.ReportAttachment, error)
+	Delete(id string) error
+}
+
+type reportAttachmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportAttachmentRepository(db *sqlx.DB) ReportAttachmentRepository {
+	return &reportAttachmentRepository{db: db}
+}
+
+func (r *reportAttachmentRepository) Create(attachment *models.ReportAttachment) error {
+	query := `
+		INSERT INTO report_attachments (id, report_id, uploaded_by, file_name, content_type, size_bytes,
+			checksum_sha256, storage_backend, storage_key, metadata_stripped, visibility, created_at, updated_at)
+		VALUES (:id, :report_id, :uploaded_by, :file_name, :content_type, :size_bytes,
+			:checksum_sha256, :storage_backend, :storage_key, :metadata_stripped, :visibility, :created_at, :updated_at)
+	`
+
+	if attachment.ID == "" {
+		attachment.ID = uuid.New().String()
+	}
+	if attachment.Visibility == "" {
+		attachment.Visibility = models.AttachmentPrivate
+	}
+	attachment.CreatedAt = time.Now().UTC()
+	attachment.UpdatedAt = attachment.CreatedAt
+
+	if _, err := r.db.NamedExec(query, attachment); err != nil {
+		return fmt.Errorf("failed to insert report attachment: %w", err)
+	}
+
+	return nil
+}
+
+func (r *reportAttachmentRepository) GetByID(id string) (*models.ReportAttachment, error) {
+	var attachment models.ReportAttachment
+	if err := r.db.Get(&attachment, `SELECT * FROM report_attachments WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetByChecksum returns the attachment of a report with the given SHA-256 checksum
+func (r *reportAttachmentRepository) GetByChecksum(reportID, checksum string) (*models.ReportAttachment, error) {
+	var attachment models.ReportAttachment
+	query := `SELECT * FROM report_attachments WHERE report_id = $1 AND checksum_sha256 = $2`
+	if err := r.db.Get(&attachment, query, reportID, checksum); err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// GetByReportID returns the attachments of a report, oldest first, optionally only the public ones
+func (r *reportAttachmentRepository) GetByReportID(reportID string, publicOnly bool) ([]*models.ReportAttachment, error) {
+	query := `SELECT * FROM report_attachments WHERE report_id = $1`
+	if publicOnly {
+		query += ` AND visibility = 'public'`
+	}
+	query += ` ORDER BY created_at ASC`
+
+	var attachments []*models.ReportAttachment
+	if err := r.db.Select(&attachments, query, reportID); err != nil {
+		return nil, fmt.Errorf("failed to get report attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func (r *reportAttachmentRepository) CountByReportID(reportID string) (int, error) {
+	var count int
+	if err := r.db.Get(&count, `SELECT COUNT(*) FROM report_attachments WHERE report_id = $1`, reportID); err != nil {
+		return 0, fmt.Errorf("failed to count report attachments: %w", err)
+	}
+	return count, nil
+}
+
+// SetVisibility records a moderator's decision to show or hide an attachment
+func (r *reportAttachmentRepository) SetVisibility(id, visibility, reviewerID string) (*models.ReportAttachment, error) {
+	query := `
+		UPDATE report_attachments SET
+			visibility = $2,
+			reviewed_by = $3,
+			reviewed_at = NOW(),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING *
+	`
+
+	var attachment models.ReportAttachment
+	if err := r.db.Get(&attachment, query, id, visibility, reviewerID); err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *reportAttachmentRepository) Delete(id string) error {
+	if _, err := r.db.Exec(`DELETE FROM report_attachments WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to delete report attachment: %w", err)
+	}
+	return nil
+}
@@ -2,12 +2,14 @@ package repos
 
 import (
 	"canada-hires/models"
+	"database/sql"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ReportFilters struct {
@@ -25,9 +27,18 @@ type ReportRepository interface {
 	GetAll(limit, offset int) ([]*models.Report, error)
 	GetWithFilters(filters ReportFilters, limit, offset int) ([]*models.Report, error)
 	GetByAddress(address string) ([]*models.Report, error)
+	GetRecentByReporter(userID string, ipAddress *string, since time.Time) ([]*models.Report, error)
 	GetReportsGroupedByAddress(filters *ReportFilters, limit, offset int) ([]*models.ReportsByAddress, error)
-	Update(report *models.Report) error
-	Delete(id string) error
+	GetModerationQueue(statuses []string, limit, offset int) ([]*models.Report, int, error)
+	GetStatusCounts() (map[string]int, error)
+	Moderate(ids []string, moderation models.ReportModeration) ([]string, error)
+	Update(report *models.Report, actor models.ReportActor) error
+	Delete(id string, actor models.ReportActor) error
+
+	// Deleted reports
+	GetByIDIncludingDeleted(id string) (*models.Report, error)
+	GetDeleted(limit, offset int) ([]*models.Report, int, error)
+	Restore(id string, actor models.ReportActor) error
 }
 
 type reportRepository struct {
@@ -47,20 +58,31 @@ func (r *reportRepository) Create(report *models.Report) error {
 
 	query := `
 		INSERT INTO reports (id, user_id, business_name, business_address, report_source,
-			confidence_level, tfw_ratio, additional_notes, ip_address, created_at, updated_at)
+			confidence_level, tfw_ratio, additional_notes, ip_address, status, published_at, created_at, updated_at)
 		VALUES (:id, :user_id, :business_name, :business_address, :report_source,
-			:confidence_level, :tfw_ratio, :additional_notes, :ip_address, :created_at, :updated_at)
+			:confidence_level, :tfw_ratio, :additional_notes, :ip_address, :status, :published_at, :created_at, :updated_at)
 	`
 
 	report.ID = uuid.New().String()
 	report.CreatedAt = time.Now().UTC()
 	report.UpdatedAt = time.Now().UTC()
+	if report.Status == "" {
+		report.Status = models.ReportStatusPending
+	}
+	if report.Status == models.ReportStatusPublished {
+		report.PublishedAt = &report.CreatedAt
+	}
 
 	_, err = tx.NamedExec(query, report)
 	if err != nil {
 		return fmt.Errorf("failed to insert report: %w", err)
 	}
 
+	author := models.ReportActor{UserID: &report.UserID, IPAddress: report.IPAddress}
+	if err = insertReportRevision(tx, report.ID, models.RevisionCreate, author, models.DiffReports(nil, report)); err != nil {
+		return err
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -70,7 +92,7 @@ func (r *reportRepository) Create(report *models.Report) error {
 
 func (r *reportRepository) GetByID(id string) (*models.Report, error) {
 	var report models.Report
-	query := `SELECT * FROM reports WHERE id = $1`
+	query := `SELECT * FROM reports WHERE id = $1 AND deleted_at IS NULL`
 
 	err := r.db.Get(&report, query, id)
 	if err != nil {
@@ -82,7 +104,7 @@ func (r *reportRepository) GetByID(id string) (*models.Report, error) {
 
 func (r *reportRepository) GetAll(limit, offset int) ([]*models.Report, error) {
 	var reports []*models.Report
-	query := `SELECT * FROM reports ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+	query := `SELECT * FROM reports WHERE status = 'published' AND deleted_at IS NULL ORDER BY created_at DESC LIMIT $1 OFFSET $2`
 
 	err := r.db.Select(&reports, query, limit, offset)
 	if err != nil {
@@ -94,7 +116,7 @@ func (r *reportRepository) GetAll(limit, offset int) ([]*models.Report, error) {
 
 func (r *reportRepository) GetByUserID(userID string, limit, offset int) ([]*models.Report, error) {
 	var reports []*models.Report
-	query := `SELECT * FROM reports WHERE user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	query := `SELECT * FROM reports WHERE user_id = $1 AND deleted_at IS NULL ORDER BY created_at DESC LIMIT $2 OFFSET $3`
 
 	err := r.db.Select(&reports, query, userID, limit, offset)
 	if err != nil {
@@ -106,7 +128,7 @@ func (r *reportRepository) GetByUserID(userID string, limit, offset int) ([]*mod
 
 func (r *reportRepository) GetByBusinessName(businessName string, limit, offset int) ([]*models.Report, error) {
 	var reports []*models.Report
-	query := `SELECT * FROM reports WHERE business_name ILIKE $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+	query := `SELECT * FROM reports WHERE status = 'published' AND deleted_at IS NULL AND business_name ILIKE $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3`
 
 	err := r.db.Select(&reports, query, "%"+businessName+"%", limit, offset)
 	if err != nil {
@@ -122,8 +144,8 @@ func (r *reportRepository) GetWithFilters(filters ReportFilters, limit, offset i
 	var conditions []string
 	argCount := 0
 
-	// Base query
-	query := `SELECT * FROM reports WHERE 1=1`
+	// Base query, only published reports are public
+	query := `SELECT * FROM reports WHERE status = 'published' AND deleted_at IS NULL`
 
 	// Add business name/query filter
 	if filters.Query != "" {
@@ -175,21 +197,30 @@ func (r *reportRepository) GetWithFilters(filters ReportFilters, limit, offset i
 	return reports, nil
 }
 
-func (r *reportRepository) Update(report *models.Report) error {
+// Update saves a report and records the fields that changed in its history
+func (r *reportRepository) Update(report *models.Report, actor models.ReportActor) error {
 	tx, err := r.db.Beginx()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
+	var before models.Report
+	if err = tx.Get(&before, `SELECT * FROM reports WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, report.ID); err != nil {
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
 	query := `
 		UPDATE reports SET
 			business_name = :business_name,
 			business_address = :business_address,
+			parsed_address_id = CASE WHEN business_address IS DISTINCT FROM :business_address THEN NULL ELSE parsed_address_id END,
 			report_source = :report_source,
 			confidence_level = :confidence_level,
 			tfw_ratio = :tfw_ratio,
 			additional_notes = :additional_notes,
+			status = :status,
+			published_at = CASE WHEN :status = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
 			updated_at = NOW()
 		WHERE id = :id
 	`
@@ -201,6 +232,18 @@ func (r *reportRepository) Update(report *models.Report) error {
 		return fmt.Errorf("failed to update report: %w", err)
 	}
 
+	var after models.Report
+	if err = tx.Get(&after, `SELECT * FROM reports WHERE id = $1`, report.ID); err != nil {
+		return fmt.Errorf("failed to get updated report: %w", err)
+	}
+
+	// Saving a report unchanged leaves no revision
+	if changes := models.DiffReports(&before, &after); len(changes) > 0 {
+		if err = insertReportRevision(tx, report.ID, models.RevisionUpdate, actor, changes); err != nil {
+			return err
+		}
+	}
+
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -208,17 +251,41 @@ func (r *reportRepository) Update(report *models.Report) error {
 	return nil
 }
 
-func (r *reportRepository) Delete(id string) error {
+// Delete hides a report everywhere. The report and its history are kept so an admin can restore it.
+func (r *reportRepository) Delete(id string, actor models.ReportActor) error {
+	query := `UPDATE reports SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND deleted_at IS NULL`
+	return r.setDeleted(id, models.RevisionDelete, actor, query, id, actor.UserID)
+}
+
+// Restore brings back a deleted report with the status it had when it was deleted
+func (r *reportRepository) Restore(id string, actor models.ReportActor) error {
+	query := `UPDATE reports SET deleted_at = NULL, deleted_by = NULL WHERE id = $1 AND deleted_at IS NOT NULL`
+	return r.setDeleted(id, models.RevisionRestore, actor, query, id)
+}
+
+// setDeleted runs a query that deletes or restores a report and records it in the report's
+// history. sql.ErrNoRows is returned when the report isn't in the expected state.
+func (r *reportRepository) setDeleted(id, action string, actor models.ReportActor, query string, args ...interface{}) error {
 	tx, err := r.db.Beginx()
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	query := `DELETE FROM reports WHERE id = $1`
-	_, err = tx.Exec(query, id)
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to %s report: %w", action, err)
+	}
+	rows, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("failed to delete report: %w", err)
+		return fmt.Errorf("failed to %s report: %w", action, err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("failed to %s report: %w", action, sql.ErrNoRows)
+	}
+
+	if err = insertReportRevision(tx, id, action, actor, nil); err != nil {
+		return err
 	}
 
 	if err = tx.Commit(); err != nil {
@@ -228,9 +295,35 @@ func (r *reportRepository) Delete(id string) error {
 	return nil
 }
 
+// GetByIDIncludingDeleted returns a report whether or not it was deleted
+func (r *reportRepository) GetByIDIncludingDeleted(id string) (*models.Report, error) {
+	var report models.Report
+	if err := r.db.Get(&report, `SELECT * FROM reports WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetDeleted returns deleted reports, most recently deleted first, and their total
+func (r *reportRepository) GetDeleted(limit, offset int) ([]*models.Report, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM reports WHERE deleted_at IS NOT NULL`); err != nil {
+		return nil, 0, fmt.Errorf("failed to count deleted reports: %w", err)
+	}
+
+	var reports []*models.Report
+	query := `SELECT * FROM reports WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC LIMIT $1 OFFSET $2`
+	if err := r.db.Select(&reports, query, limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get deleted reports: %w", err)
+	}
+
+	return reports, total, nil
+}
+
 func (r *reportRepository) GetByAddress(address string) ([]*models.Report, error) {
 	var reports []*models.Report
-	query := `SELECT * FROM reports WHERE business_address = $1 ORDER BY created_at DESC`
+	query := `SELECT * FROM reports WHERE status = 'published' AND deleted_at IS NULL AND business_address = $1 ORDER BY created_at DESC`
 
 	err := r.db.Select(&reports, query, address)
 	if err != nil {
@@ -240,11 +333,32 @@ func (r *reportRepository) GetByAddress(address string) ([]*models.Report, error
 	return reports, nil
 }
 
+// GetRecentByReporter returns the reports filed by a user, or from an IP address, since the given
+// time, newest first. Rejected and flagged reports are left out, so they can't be merged into.
+func (r *reportRepository) GetRecentByReporter(userID string, ipAddress *string, since time.Time) ([]*models.Report, error) {
+	var reports []*models.Report
+	query := `
+		SELECT * FROM reports
+		WHERE (user_id = $1 OR ip_address = $2::inet)
+		  AND created_at >= $3
+		  AND status NOT IN ('rejected', 'flagged')
+		  AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	err := r.db.Select(&reports, query, userID, ipAddress, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent reports by reporter: %w", err)
+	}
+
+	return reports, nil
+}
+
 func (r *reportRepository) GetReportsGroupedByAddress(filters *ReportFilters, limit, offset int) ([]*models.ReportsByAddress, error) {
 	var grouped []*models.ReportsByAddress
 
 	// Build the WHERE conditions
-	conditions := []string{"1=1"} // Start with a neutral condition
+	conditions := []string{"status = 'published'", "deleted_at IS NULL"} // Only published reports are public
 	args := []interface{}{}
 	argCount := 0
 
@@ -279,25 +393,47 @@ func (r *reportRepository) GetReportsGroupedByAddress(filters *ReportFilters, li
 		}
 	}
 
-	// Build the final query
+	// Build the final query. Each reporter counts once per business, with their latest report, so
+	// reports filed again by the same user don't inflate the totals. Corroborations of the
+	// business's reports are counted once per user. The weighted count weighs each reporter by
+	// their verification tier.
 	whereClause := strings.Join(conditions, " AND ")
 	query := fmt.Sprintf(`
 		SELECT
 			business_name,
 			business_address,
 			COUNT(*) as report_count,
+			SUM(CASE author_tier WHEN 'trusted' THEN %g WHEN 'enhanced' THEN %g ELSE %g END) as weighted_report_count,
 			AVG(COALESCE(confidence_level, 5)) as confidence_level,
 			SUM(CASE WHEN tfw_ratio = 'few' THEN 1 ELSE 0 END) as tfw_ratio_few,
 			SUM(CASE WHEN tfw_ratio = 'many' THEN 1 ELSE 0 END) as tfw_ratio_many,
 			SUM(CASE WHEN tfw_ratio = 'most' THEN 1 ELSE 0 END) as tfw_ratio_most,
 			SUM(CASE WHEN tfw_ratio = 'all' THEN 1 ELSE 0 END) as tfw_ratio_all,
-			MAX(created_at) as latest_report
-		FROM reports
-		WHERE %s
+			MAX(created_at) as latest_report,
+			(
+				SELECT COUNT(DISTINCT v.user_id)
+				FROM report_votes v
+				JOIN reports corroborated ON corroborated.id = v.report_id
+				WHERE corroborated.business_address = latest_reports.business_address
+				  AND corroborated.business_name = latest_reports.business_name
+				  AND corroborated.status = 'published'
+				  AND corroborated.deleted_at IS NULL
+				  AND v.vote_type = 'corroborate'
+				  AND v.counted
+			) as corroboration_count
+		FROM (
+			SELECT DISTINCT ON (business_address, business_name, user_id)
+				business_name, business_address, confidence_level, tfw_ratio, created_at,
+				(SELECT COALESCE(users.verification_tier, 'basic') FROM users WHERE users.id = reports.user_id) as author_tier
+			FROM reports
+			WHERE %s
+			ORDER BY business_address, business_name, user_id, created_at DESC
+		) latest_reports
 		GROUP BY business_address, business_name
-		ORDER BY report_count DESC, latest_report DESC
+		ORDER BY weighted_report_count DESC, report_count DESC, corroboration_count DESC, latest_report DESC
 		LIMIT $%d OFFSET $%d
-	`, whereClause, argCount+1, argCount+2)
+	`, models.VerificationTrusted.ReportWeight(), models.VerificationEnhanced.ReportWeight(), models.VerificationBasic.ReportWeight(),
+		whereClause, argCount+1, argCount+2)
 
 	// Add limit and offset to args
 	args = append(args, limit, offset)
@@ -309,3 +445,102 @@ func (r *reportRepository) GetReportsGroupedByAddress(filters *ReportFilters, li
 
 	return grouped, nil
 }
+
+// GetModerationQueue returns the reports in the given statuses, oldest first, and their total
+func (r *reportRepository) GetModerationQueue(statuses []string, limit, offset int) ([]*models.Report, int, error) {
+	var total int
+	if err := r.db.Get(&total, `SELECT COUNT(*) FROM reports WHERE status = ANY($1) AND deleted_at IS NULL`, pq.Array(statuses)); err != nil {
+		return nil, 0, fmt.Errorf("failed to count reports in moderation queue: %w", err)
+	}
+
+	var reports []*models.Report
+	query := `SELECT * FROM reports WHERE status = ANY($1) AND deleted_at IS NULL ORDER BY created_at ASC LIMIT $2 OFFSET $3`
+	if err := r.db.Select(&reports, query, pq.Array(statuses), limit, offset); err != nil {
+		return nil, 0, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// GetStatusCounts returns the number of reports in each status
+func (r *reportRepository) GetStatusCounts() (map[string]int, error) {
+	var rows []struct {
+		Status string `db:"status"`
+		Count  int    `db:"count"`
+	}
+	if err := r.db.Select(&rows, `SELECT status, COUNT(*) as count FROM reports WHERE deleted_at IS NULL GROUP BY status`); err != nil {
+		return nil, fmt.Errorf("failed to count reports by status: %w", err)
+	}
+
+	counts := make(map[string]int, len(models.ReportStatuses))
+	for _, status := range models.ReportStatuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+
+	return counts, nil
+}
+
+// Moderate applies a moderation decision to the reports with the given IDs and returns the IDs
+// of the reports that were updated. Reports already in the target status are left alone. Each
+// updated report gets a revision with the moderator's changes.
+func (r *reportRepository) Moderate(ids []string, moderation models.ReportModeration) ([]string, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var before []*models.Report
+	err = tx.Select(&before, `
+		SELECT * FROM reports
+		WHERE id::text = ANY($1) AND status <> $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, pq.Array(ids), moderation.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reports to moderate: %w", err)
+	}
+
+	query := `
+		UPDATE reports SET
+			status = $2,
+			moderated_by = $3,
+			moderated_at = NOW(),
+			rejection_reason = $4,
+			moderation_notes = $5,
+			published_at = CASE WHEN $2 = 'published' THEN COALESCE(published_at, NOW()) ELSE published_at END,
+			updated_at = NOW()
+		WHERE id::text = ANY($1)
+		  AND status <> $2
+		  AND deleted_at IS NULL
+		RETURNING *
+	`
+
+	var after []*models.Report
+	err = tx.Select(&after, query, pq.Array(ids), moderation.Status, moderation.ModeratorID, moderation.RejectionReason, moderation.Notes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to moderate reports: %w", err)
+	}
+
+	previous := make(map[string]*models.Report, len(before))
+	for _, report := range before {
+		previous[report.ID] = report
+	}
+
+	moderator := models.ReportActor{UserID: &moderation.ModeratorID, IPAddress: moderation.IPAddress}
+	updated := make([]string, 0, len(after))
+	for _, report := range after {
+		if err = insertReportRevision(tx, report.ID, models.RevisionModerate, moderator, models.DiffReports(previous[report.ID], report)); err != nil {
+			return nil, err
+		}
+		updated = append(updated, report.ID)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,47 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type ReportRevisionRepository interface {
+	GetByReportID(reportID string) ([]*models.ReportRevision, error)
+}
+
+type reportRevisionRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportRevisionRepository(db *sqlx.DB) ReportRevisionRepository {
+	return &reportRevisionRepository{db: db}
+}
+
+// GetByReportID returns the history of a report, oldest change first
+func (r *reportRevisionRepository) GetByReportID(reportID string) ([]*models.ReportRevision, error) {
+	var revisions []*models.ReportRevision
+	query := `SELECT * FROM report_revisions WHERE report_id = $1 ORDER BY created_at ASC, id ASC`
+
+	if err := r.db.Select(&revisions, query, reportID); err != nil {
+		return nil, fmt.Errorf("failed to get report revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// insertReportRevision records a change to a report in the transaction that made it, so a
+// change is never saved without its revision
+func insertReportRevision(tx *sqlx.Tx, reportID, action string, actor models.ReportActor, changes models.ReportChanges) error {
+	query := `
+		INSERT INTO report_revisions (report_id, action, changed_by, ip_address, changes)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	if _, err := tx.Exec(query, reportID, action, actor.UserID, actor.IPAddress, changes); err != nil {
+		return fmt.Errorf("failed to record report revision: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type ReportVoteRepository interface {
+	Upsert(vote *models.ReportVote, flagThreshold int) (*models.ReportVoteResult, error)
+	Delete(reportID, userID string) (bool, error)
+	GetByReportAndUser(reportID, userID string) (*models.ReportVote, error)
+	GetByReportID(reportID string) ([]*models.ReportVote, error)
+	CountSharedIPVotes(reportID, userID string, ipAddresses []string) (int, error)
+}
+
+type reportVoteRepository struct {
+	db *sqlx.DB
+}
+
+func NewReportVoteRepository(db *sqlx.DB) ReportVoteRepository {
+	return &reportVoteRepository{db: db}
+}
+
+// Upsert records a user's vote, replacing their earlier vote on the report, and updates the
+// report's vote counts. A published report is flagged for moderation when the counted flags
+// cast since it was last moderated reach the threshold, 0 never flags.
+func (r *reportVoteRepository) Upsert(vote *models.ReportVote, flagThreshold int) (*models.ReportVoteResult, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO report_votes (report_id, user_id, vote_type, flag_reason, comment, ip_address, counted)
+		VALUES (:report_id, :user_id, :vote_type, :flag_reason, :comment, :ip_address, :counted)
+		ON CONFLICT (report_id, user_id) DO UPDATE SET
+			vote_type = EXCLUDED.vote_type,
+			flag_reason = EXCLUDED.flag_reason,
+			comment = EXCLUDED.comment,
+			ip_address = EXCLUDED.ip_address,
+			-- A vote that failed the IP address checks stays uncounted when it's changed
+			counted = report_votes.counted AND EXCLUDED.counted,
+			updated_at = NOW()
+		RETURNING *
+	`
+
+	rows, err := tx.NamedQuery(query, vote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save report vote: %w", err)
+	}
+	saved := &models.ReportVote{}
+	if rows.Next() {
+		err = rows.StructScan(saved)
+	}
+	rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read saved report vote: %w", err)
+	}
+
+	result, err := updateVoteCounts(tx, vote.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	result.Vote = saved
+
+	if vote.VoteType == models.VoteFlag && saved.Counted && flagThreshold > 0 {
+		// Flags cast before a moderator last looked at the report were already dealt with
+		var recentFlags int
+		err = tx.Get(&recentFlags, `
+			SELECT COUNT(*) FROM report_votes v
+			JOIN reports r ON r.id = v.report_id
+			WHERE v.report_id = $1 AND v.vote_type = 'flag' AND v.counted
+			  AND v.updated_at > COALESCE(r.moderated_at, '-infinity')
+		`, vote.ReportID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count recent flags: %w", err)
+		}
+
+		if recentFlags >= flagThreshold {
+			res, err := tx.Exec(`
+				UPDATE reports SET status = 'flagged', updated_at = NOW()
+				WHERE id = $1 AND status = 'published' AND deleted_at IS NULL
+			`, vote.ReportID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to flag report: %w", err)
+			}
+			affected, _ := res.RowsAffected()
+			result.SentToModeration = affected > 0
+
+			// Community flags move the report without a user behind the change
+			if result.SentToModeration {
+				changes := models.ReportChanges{"status": {Old: models.ReportStatusPublished, New: models.ReportStatusFlagged}}
+				if err = insertReportRevision(tx, vote.ReportID, models.RevisionModerate, models.ReportActor{}, changes); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// Delete withdraws a user's vote and returns whether there was one
+func (r *reportVoteRepository) Delete(reportID, userID string) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`DELETE FROM report_votes WHERE report_id = $1 AND user_id = $2`, reportID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete report vote: %w", err)
+	}
+	if affected, _ := res.RowsAffected(); affected == 0 {
+		return false, nil
+	}
+
+	if _, err := updateVoteCounts(tx, reportID); err != nil {
+		return false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return true, nil
+}
+
+// updateVoteCounts recounts the counted votes of a report
+func updateVoteCounts(tx *sqlx.Tx, reportID string) (*models.ReportVoteResult, error) {
+	result := &models.ReportVoteResult{}
+	err := tx.QueryRowx(`
+		UPDATE reports SET
+			corroboration_count = counts.corroborations,
+			flag_count = counts.flags
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE vote_type = 'corroborate' AND counted) AS corroborations,
+				COUNT(*) FILTER (WHERE vote_type = 'flag' AND counted) AS flags
+			FROM report_votes
+			WHERE report_id = $1
+		) counts
+		WHERE reports.id = $1
+		RETURNING reports.corroboration_count, reports.flag_count
+	`, reportID).Scan(&result.CorroborationCount, &result.FlagCount)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to update report vote counts: %w", err)
+	}
+	return result, nil
+}
+
+func (r *reportVoteRepository) GetByReportAndUser(reportID, userID string) (*models.ReportVote, error) {
+	var vote models.ReportVote
+	err := r.db.Get(&vote, `SELECT * FROM report_votes WHERE report_id = $1 AND user_id = $2`, reportID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// GetByReportID returns every vote on a report, newest first
+func (r *reportVoteRepository) GetByReportID(reportID string) ([]*models.ReportVote, error) {
+	var votes []*models.ReportVote
+	err := r.db.Select(&votes, `SELECT * FROM report_votes WHERE report_id = $1 ORDER BY updated_at DESC`, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report votes: %w", err)
+	}
+	return votes, nil
+}
+
+// CountSharedIPVotes counts the counted votes of other users on a report that were cast from, or
+// whose accounts were used from, one of the given IP addresses
+func (r *reportVoteRepository) CountSharedIPVotes(reportID, userID string, ipAddresses []string) (int, error) {
+	if len(ipAddresses) == 0 {
+		return 0, nil
+	}
+
+	query := `
+		SELECT COUNT(*) FROM report_votes v
+		JOIN users u ON u.id = v.user_id
+		WHERE v.report_id = $1
+		  AND v.user_id <> $2
+		  AND v.counted
+		  AND (host(v.ip_address) = ANY($3) OR u.ip_addresses ?| $3)
+	`
+
+	var count int
+	if err := r.db.Get(&count, query, reportID, userID, pq.Array(ipAddresses)); err != nil {
+		return 0, fmt.Errorf("failed to count votes from shared IP addresses: %w", err)
+	}
+	return count, nil
+}
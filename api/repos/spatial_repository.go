@@ -0,0 +1,420 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type SpatialRepository interface {
+	FindLMIAEmployers(q models.SpatialQuery) ([]*models.NearbyLMIAEmployer, error)
+	FindNonCompliantEmployers(q models.SpatialQuery) ([]*models.NearbyNonCompliantEmployer, error)
+	FindJobPostings(q models.SpatialQuery) ([]*models.NearbyJobPosting, error)
+	FindReports(q models.SpatialQuery) ([]*models.NearbyReportLocation, error)
+	GetUngeocodedJobLocations(limit int) ([]string, error)
+	SetJobLocationCoordinates(location string, latitude, longitude *float64) (int64, error)
+	GetUngeocodedReportAddresses(limit int) ([]string, error)
+	ClusterMapLayer(q models.MapClusterQuery) ([]*models.MapCluster, error)
+}
+
+type spatialRepository struct {
+	db *sqlx.DB
+}
+
+func NewSpatialRepository(db *sqlx.DB) SpatialRepository {
+	return &spatialRepository{db: db}
+}
+
+// spatialFilter builds the conditions of a spatial query. The center of the query is always bound
+// to $1 and $2, followed by the radius or the corners of the bounding box and finally the limit.
+type spatialFilter struct {
+	query models.SpatialQuery
+	args  []interface{}
+}
+
+func newSpatialFilter(q models.SpatialQuery) *spatialFilter {
+	f := &spatialFilter{query: q, args: []interface{}{q.Latitude, q.Longitude}}
+	if q.BBox != nil {
+		f.args = append(f.args, q.BBox.MinLatitude, q.BBox.MaxLatitude, q.BBox.MinLongitude, q.BBox.MaxLongitude)
+	} else {
+		f.args = append(f.args, q.RadiusMeters)
+	}
+	return f
+}
+
+// condition matches the coordinates in the given columns. The column expressions must match those
+// of the earthdistance indexes for radius queries to use them.
+func (f *spatialFilter) condition(latitude, longitude string) string {
+	if f.query.BBox != nil {
+		return fmt.Sprintf("%[1]s BETWEEN $3 AND $4 AND %[2]s BETWEEN $5 AND $6", latitude, longitude)
+	}
+	// earth_box is a cheap indexed pre-filter, the distance check trims its corners
+	return fmt.Sprintf(
+		"earth_box(ll_to_earth($1, $2), $3) @> ll_to_earth(%[1]s, %[2]s) AND %[3]s <= $3",
+		latitude, longitude, f.distance(latitude, longitude),
+	)
+}
+
+// distance is the distance in meters between the coordinates in the given columns and the center
+func (f *spatialFilter) distance(latitude, longitude string) string {
+	return fmt.Sprintf("earth_distance(ll_to_earth($1, $2), ll_to_earth(%s, %s))", latitude, longitude)
+}
+
+// limit returns the placeholder of the limit and the arguments of the whole query
+func (f *spatialFilter) limit() (string, []interface{}) {
+	return fmt.Sprintf("$%d", len(f.args)+1), append(f.args, f.query.Limit)
+}
+
+// nearbyPostalCodes selects the geocoded postal codes matching the filter with their distance
+func (f *spatialFilter) nearbyPostalCodes() string {
+	return fmt.Sprintf(`
+		nearby_postal_codes AS (
+			SELECT
+				postal_code,
+				latitude::float8 as latitude,
+				longitude::float8 as longitude,
+				precision,
+				%s as distance_meters
+			FROM postal_codes
+			WHERE latitude IS NOT NULL
+			  AND longitude IS NOT NULL
+			  AND %s
+		)`,
+		f.distance("latitude::float8", "longitude::float8"),
+		f.condition("latitude::float8", "longitude::float8"),
+	)
+}
+
+// FindLMIAEmployers returns LMIA employers located by their postal code, with approvals summed
+// across every quarter they appear in
+func (r *spatialRepository) FindLMIAEmployers(q models.SpatialQuery) ([]*models.NearbyLMIAEmployer, error) {
+	filter := newSpatialFilter(q)
+	limit, args := filter.limit()
+
+	query := fmt.Sprintf(`
+		WITH %s
+		SELECT
+			e.employer,
+			MAX(e.address) as address,
+			npc.postal_code,
+			COALESCE(SUM(e.approved_lmias), 0) as approved_lmias,
+			COALESCE(SUM(e.approved_positions), 0) as approved_positions,
+			MAX(e.year) as latest_year,
+			npc.latitude,
+			npc.longitude,
+			npc.precision,
+			npc.distance_meters
+		FROM lmia_employers e
+		JOIN nearby_postal_codes npc ON npc.postal_code = e.postal_code
+		GROUP BY e.employer, npc.postal_code, npc.latitude, npc.longitude, npc.precision, npc.distance_meters
+		ORDER BY npc.distance_meters, approved_lmias DESC
+		LIMIT %s
+	`, filter.nearbyPostalCodes(), limit)
+
+	var employers []*models.NearbyLMIAEmployer
+	if err := r.db.Select(&employers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find nearby LMIA employers: %w", err)
+	}
+
+	return employers, nil
+}
+
+// FindNonCompliantEmployers returns non-compliant employers placed at their geocoded address, or
+// at the centroid of their postal code when the address was never geocoded
+func (r *spatialRepository) FindNonCompliantEmployers(q models.SpatialQuery) ([]*models.NearbyNonCompliantEmployer, error) {
+	filter := newSpatialFilter(q)
+	limit, args := filter.limit()
+
+	query := fmt.Sprintf(`
+		WITH %s
+		SELECT
+			e.id,
+			e.business_operating_name,
+			e.business_legal_name,
+			e.address,
+			e.date_of_final_decision,
+			e.penalty_amount,
+			e.status,
+			COALESCE(agc.latitude, npc.latitude) as latitude,
+			COALESCE(agc.longitude, npc.longitude) as longitude,
+			CASE WHEN agc.id IS NOT NULL THEN agc.precision ELSE npc.precision END as precision,
+			COALESCE(%s, npc.distance_meters) as distance_meters
+		FROM non_compliant_employers e
+		LEFT JOIN parsed_addresses pa ON pa.id = e.parsed_address_id
+		LEFT JOIN address_geocoding_cache agc ON agc.normalized_address = pa.normalized_address
+		LEFT JOIN nearby_postal_codes npc ON npc.postal_code = COALESCE(e.postal_code, pa.postal_code)
+		WHERE (agc.id IS NOT NULL AND %s)
+		   OR (agc.id IS NULL AND npc.postal_code IS NOT NULL)
+		ORDER BY distance_meters, e.date_of_final_decision DESC NULLS LAST
+		LIMIT %s
+	`,
+		filter.nearbyPostalCodes(),
+		filter.distance("agc.latitude", "agc.longitude"),
+		filter.condition("agc.latitude", "agc.longitude"),
+		limit,
+	)
+
+	var employers []*models.NearbyNonCompliantEmployer
+	if err := r.db.Select(&employers, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find nearby non-compliant employers: %w", err)
+	}
+
+	return employers, nil
+}
+
+// FindJobPostings returns the job postings whose location has been geocoded
+func (r *spatialRepository) FindJobPostings(q models.SpatialQuery) ([]*models.NearbyJobPosting, error) {
+	filter := newSpatialFilter(q)
+	limit, args := filter.limit()
+
+	query := fmt.Sprintf(`
+		SELECT
+			id, title, employer, location, salary_min, salary_max, salary_type,
+			posting_date, url, is_tfw, has_lmia, latitude, longitude,
+			%s as distance_meters
+		FROM job_postings
+		WHERE latitude IS NOT NULL
+		  AND longitude IS NOT NULL
+		  AND %s
+		ORDER BY distance_meters, posting_date DESC NULLS LAST
+		LIMIT %s
+	`, filter.distance("latitude", "longitude"), filter.condition("latitude", "longitude"), limit)
+
+	var postings []*models.NearbyJobPosting
+	if err := r.db.Select(&postings, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find nearby job postings: %w", err)
+	}
+
+	return postings, nil
+}
+
+// FindReports returns the reported businesses, one row per business and address, placed like
+// non-compliant employers
+func (r *spatialRepository) FindReports(q models.SpatialQuery) ([]*models.NearbyReportLocation, error) {
+	filter := newSpatialFilter(q)
+	limit, args := filter.limit()
+
+	query := fmt.Sprintf(`
+		WITH %s,
+		located_reports AS (
+			SELECT
+				r.business_name,
+				r.business_address,
+				r.created_at,
+				COALESCE(agc.latitude, npc.latitude) as latitude,
+				COALESCE(agc.longitude, npc.longitude) as longitude,
+				CASE WHEN agc.id IS NOT NULL THEN agc.precision ELSE npc.precision END as precision,
+				COALESCE(%s, npc.distance_meters) as distance_meters
+			FROM reports r
+			LEFT JOIN parsed_addresses pa ON pa.id = r.parsed_address_id
+			LEFT JOIN address_geocoding_cache agc ON agc.normalized_address = pa.normalized_address
+			LEFT JOIN nearby_postal_codes npc ON npc.postal_code = pa.postal_code
+			WHERE r.status = 'published'
+			  AND r.deleted_at IS NULL
+			  AND ((agc.id IS NOT NULL AND %s)
+			   OR (agc.id IS NULL AND npc.postal_code IS NOT NULL))
+		)
+		SELECT
+			business_name,
+			business_address,
+			COUNT(*) as report_count,
+			MAX(created_at) as latest_report,
+			latitude,
+			longitude,
+			precision,
+			distance_meters
+		FROM located_reports
+		GROUP BY business_name, business_address, latitude, longitude, precision, distance_meters
+		ORDER BY distance_meters, report_count DESC
+		LIMIT %s
+	`,
+		filter.nearbyPostalCodes(),
+		filter.distance("agc.latitude", "agc.longitude"),
+		filter.condition("agc.latitude", "agc.longitude"),
+		limit,
+	)
+
+	var reports []*models.NearbyReportLocation
+	if err := r.db.Select(&reports, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to find nearby reports: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetUngeocodedJobLocations returns distinct job posting locations that were never geocoded.
+// Locations that failed are retried after a month, once the geocoding data may have improved.
+func (r *spatialRepository) GetUngeocodedJobLocations(limit int) ([]string, error) {
+	query := `
+		SELECT DISTINCT location
+		FROM job_postings
+		WHERE latitude IS NULL
+		  AND location <> ''
+		  AND (geocoded_at IS NULL OR geocoded_at < NOW() - INTERVAL '30 days')
+		LIMIT $1
+	`
+
+	var locations []string
+	if err := r.db.Select(&locations, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get ungeocoded job locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+// SetJobLocationCoordinates stores the coordinates of every job posting at a location. Nil
+// coordinates record a failed attempt so the location isn't retried right away.
+func (r *spatialRepository) SetJobLocationCoordinates(location string, latitude, longitude *float64) (int64, error) {
+	query := `
+		UPDATE job_postings
+		SET latitude = $2, longitude = $3, geocoded_at = NOW()
+		WHERE location = $1 AND latitude IS NULL
+	`
+
+	result, err := r.db.Exec(query, location, latitude, longitude)
+	if err != nil {
+		return 0, fmt.Errorf("failed to set job location coordinates: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetUngeocodedReportAddresses returns distinct addresses of reports that aren't deleted and have
+// neither a geocoded address nor a geocoded postal code
+func (r *spatialRepository) GetUngeocodedReportAddresses(limit int) ([]string, error) {
+	query := `
+		SELECT DISTINCT r.business_address
+		FROM reports r
+		LEFT JOIN parsed_addresses pa ON pa.id = r.parsed_address_id
+		LEFT JOIN address_geocoding_cache agc ON agc.normalized_address = pa.normalized_address
+		LEFT JOIN postal_codes pc ON pc.postal_code = pa.postal_code AND pc.latitude IS NOT NULL
+		WHERE r.business_address <> ''
+		  AND r.deleted_at IS NULL
+		  AND agc.id IS NULL
+		  AND pc.postal_code IS NULL
+		LIMIT $1
+	`
+
+	var addresses []string
+	if err := r.db.Select(&addresses, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to get ungeocoded report addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// ClusterMapLayer groups the points of a layer inside the viewport on a grid of the given cell
+// size, busiest cells first. The viewport is bound to $1 to $4 and the cell size to $5 and $6,
+// followed by the filters of the layer.
+func (r *spatialRepository) ClusterMapLayer(q models.MapClusterQuery) ([]*models.MapCluster, error) {
+	args := []interface{}{
+		q.BBox.MinLatitude, q.BBox.MaxLatitude, q.BBox.MinLongitude, q.BBox.MaxLongitude,
+		q.CellLatitude, q.CellLongitude,
+	}
+	bind := func(value interface{}) string {
+		args = append(args, value)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	var points, totals string
+	switch q.Layer {
+	case models.MapLayerLMIA:
+		// One point per employer and postal code, with its approvals summed across quarters
+		points = `
+			SELECT
+				e.employer as name,
+				e.postal_code as record_id,
+				pc.latitude::float8 as latitude,
+				pc.longitude::float8 as longitude,
+				SUM(COALESCE(e.approved_lmias, 0)) as approved_lmias,
+				SUM(COALESCE(e.approved_positions, 0)) as approved_positions
+			FROM lmia_employers e
+			JOIN postal_codes pc ON pc.postal_code = e.postal_code
+			WHERE pc.latitude IS NOT NULL
+			  AND pc.longitude IS NOT NULL
+			  AND pc.latitude::float8 BETWEEN $1 AND $2
+			  AND pc.longitude::float8 BETWEEN $3 AND $4`
+		if q.Year > 0 {
+			points += " AND e.year = " + bind(q.Year)
+		}
+		if q.Quarter != "" {
+			points += " AND e.quarter = " + bind(q.Quarter)
+		}
+		points += `
+			GROUP BY e.employer, e.postal_code, pc.latitude, pc.longitude`
+		totals = `
+			SUM(p.approved_lmias)::bigint as approved_lmias,
+			SUM(p.approved_positions)::bigint as approved_positions,`
+
+	case models.MapLayerNonCompliant:
+		// Placed at the geocoded address, or at the centroid of the postal code
+		points = `
+			SELECT
+				e.business_operating_name as name,
+				e.id::text as record_id,
+				COALESCE(agc.latitude, pc.latitude::float8) as latitude,
+				COALESCE(agc.longitude, pc.longitude::float8) as longitude,
+				COALESCE(e.penalty_amount, 0) as penalty_amount
+			FROM non_compliant_employers e
+			LEFT JOIN parsed_addresses pa ON pa.id = e.parsed_address_id
+			LEFT JOIN address_geocoding_cache agc ON agc.normalized_address = pa.normalized_address
+			LEFT JOIN postal_codes pc ON pc.postal_code = COALESCE(e.postal_code, pa.postal_code)
+			WHERE COALESCE(agc.latitude, pc.latitude::float8) BETWEEN $1 AND $2
+			  AND COALESCE(agc.longitude, pc.longitude::float8) BETWEEN $3 AND $4`
+		if q.Year > 0 {
+			points += " AND EXTRACT(YEAR FROM e.date_of_final_decision) = " + bind(q.Year)
+		}
+		totals = `
+			SUM(p.penalty_amount)::bigint as total_penalty_amount,`
+
+	case models.MapLayerJobPostings:
+		points = `
+			SELECT
+				title as name,
+				id::text as record_id,
+				latitude,
+				longitude,
+				is_tfw,
+				has_lmia
+			FROM job_postings
+			WHERE latitude IS NOT NULL
+			  AND longitude IS NOT NULL
+			  AND latitude BETWEEN $1 AND $2
+			  AND longitude BETWEEN $3 AND $4`
+		if q.Days > 0 {
+			points += " AND posting_date >= NOW() - make_interval(days => " + bind(q.Days) + ")"
+		}
+		totals = `
+			COUNT(*) FILTER (WHERE p.is_tfw) as tfw_postings,
+			COUNT(*) FILTER (WHERE p.has_lmia) as lmia_postings,`
+
+	default:
+		return nil, fmt.Errorf("unknown map layer: %s", q.Layer)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			FLOOR(p.longitude / $6)::bigint as cell_x,
+			FLOOR(p.latitude / $5)::bigint as cell_y,
+			AVG(p.latitude) as latitude,
+			AVG(p.longitude) as longitude,
+			COUNT(*) as point_count,
+			%s
+			CASE WHEN COUNT(*) = 1 THEN MIN(p.name) END as name,
+			CASE WHEN COUNT(*) = 1 THEN MIN(p.record_id) END as record_id
+		FROM (%s
+		) p
+		GROUP BY cell_x, cell_y
+		ORDER BY point_count DESC
+		LIMIT %s
+	`, totals, points, bind(q.Limit))
+
+	var clusters []*models.MapCluster
+	if err := r.db.Select(&clusters, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to cluster %s map layer: %w", q.Layer, err)
+	}
+
+	return clusters, nil
+}
@@ -0,0 +1,131 @@
+package repos
+
+import (
+	"canada-hires/models"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type UserTierRepository interface {
+	GetSignals(afterUserID string, limit int) ([]*models.UserTierSignals, error)
+	GetSignalsByUserID(userID string) (*models.UserTierSignals, error)
+	ApplyTierChange(change *models.UserTierChange) (bool, error)
+	GetTierHistory(userID string) ([]*models.UserTierChange, error)
+}
+
+type userTierRepository struct {
+	db *sqlx.DB
+}
+
+func NewUserTierRepository(db *sqlx.DB) UserTierRepository {
+	return &userTierRepository{db: db}
+}
+
+// userTierSignalsQuery gathers the tier signals of users. Rejected reports count even when the
+// author deleted them, so deleting a report doesn't clear a moderation outcome.
+const userTierSignalsQuery = `
+	SELECT
+		u.id AS user_id,
+		COALESCE(u.verification_tier, 'basic') AS verification_tier,
+		u.email_domain,
+		u.created_at,
+		COUNT(r.id) FILTER (WHERE r.status = 'published' AND r.deleted_at IS NULL) AS published_reports,
+		COUNT(r.id) FILTER (WHERE r.status = 'published' AND r.deleted_at IS NULL AND r.corroboration_count > 0) AS corroborated_reports,
+		COALESCE(SUM(r.corroboration_count) FILTER (WHERE r.status = 'published' AND r.deleted_at IS NULL), 0) AS corroborations,
+		COUNT(r.id) FILTER (WHERE r.status = 'rejected') AS rejected_reports,
+		COUNT(r.id) FILTER (WHERE r.status = 'flagged' AND r.deleted_at IS NULL) AS flagged_reports,
+		COALESCE(jsonb_array_length(u.ip_addresses), 0) AS ip_address_count,
+		(
+			SELECT COUNT(*) FROM users other
+			WHERE other.id <> u.id
+			AND other.ip_addresses ?| ARRAY(SELECT jsonb_array_elements_text(u.ip_addresses))
+		) AS shared_ip_accounts
+	FROM users u
+	LEFT JOIN reports r ON r.user_id = u.id
+`
+
+// GetSignals returns the tier signals of a page of users ordered by id, starting after the
+// given user id. The nil UUID starts from the first user.
+func (r *userTierRepository) GetSignals(afterUserID string, limit int) ([]*models.UserTierSignals, error) {
+	var signals []*models.UserTierSignals
+	query := userTierSignalsQuery + `
+		WHERE u.id > $1
+		GROUP BY u.id
+		ORDER BY u.id
+		LIMIT $2
+	`
+
+	if err := r.db.Select(&signals, query, afterUserID, limit); err != nil {
+		return nil, fmt.Errorf("failed to get user tier signals: %w", err)
+	}
+
+	return signals, nil
+}
+
+// GetSignalsByUserID returns the tier signals of one user
+func (r *userTierRepository) GetSignalsByUserID(userID string) (*models.UserTierSignals, error) {
+	var signals models.UserTierSignals
+	query := userTierSignalsQuery + `
+		WHERE u.id = $1
+		GROUP BY u.id
+	`
+
+	if err := r.db.Get(&signals, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get user tier signals: %w", err)
+	}
+
+	return &signals, nil
+}
+
+// ApplyTierChange updates the tier of a user and records the change. Nothing is changed when the
+// user's tier is no longer the old tier of the change, which is reported as false.
+func (r *userTierRepository) ApplyTierChange(change *models.UserTierChange) (bool, error) {
+	tx, err := r.db.Beginx()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE users SET verification_tier = $2, updated_at = NOW()
+		WHERE id = $1 AND COALESCE(verification_tier, 'basic') = $3
+	`, change.UserID, change.NewTier, change.OldTier)
+	if err != nil {
+		return false, fmt.Errorf("failed to update verification tier: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	query := `
+		INSERT INTO user_tier_changes (user_id, old_tier, new_tier, reasons, signals)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+	if err := tx.QueryRowx(query, change.UserID, change.OldTier, change.NewTier, change.Reasons, change.Signals).Scan(&change.ID, &change.CreatedAt); err != nil {
+		return false, fmt.Errorf("failed to record tier change: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit tier change: %w", err)
+	}
+
+	return true, nil
+}
+
+// GetTierHistory returns the tier changes of a user, most recent first
+func (r *userTierRepository) GetTierHistory(userID string) ([]*models.UserTierChange, error) {
+	var changes []*models.UserTierChange
+	query := `SELECT * FROM user_tier_changes WHERE user_id = $1 ORDER BY created_at DESC, id DESC`
+
+	if err := r.db.Select(&changes, query, userID); err != nil {
+		return nil, fmt.Errorf("failed to get tier history: %w", err)
+	}
+
+	return changes, nil
+}
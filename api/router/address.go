@@ -0,0 +1,28 @@
+package router
+
+import (
+	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AddressRoutes sets up routes for parsed address endpoints
+func AddressRoutes(controller controllers.AddressController, authMW func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Route("/addresses", func(r chi.Router) {
+			// Public routes
+			r.Get("/parse", controller.ParseAddress)
+			r.Get("/{address_id}", controller.GetParsedAddress)
+		})
+
+		// Admin routes (require an admin)
+		r.Route("/admin/addresses", func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireAdmin)
+			r.Post("/parse", controller.ParseAllAddresses)
+			r.Get("/summary", controller.GetParseSummary)
+		})
+	}
+}
@@ -66,15 +66,28 @@ func (ar *adminRouter) Init(r chi.Router) {
 			r.Get("/preview/{job_id}", ar.jobController.PreviewRedditPost)
 		})
 
+		// Repost detection endpoints
+		r.Post("/jobs/families/rebuild", ar.jobController.RebuildPostingFamilies)
+
+		// LMIA approval matching endpoints
+		r.Post("/jobs/lmia-matches/run", ar.jobController.TriggerLMIAMatching)
+
 		// Scraper and statistics endpoints
 		r.Route("/scraper", func(r chi.Router) {
 			r.Post("/run", ar.jobController.TriggerScraper)
 			r.Post("/statistics", ar.jobController.TriggerStatisticsAggregation)
 		})
 
+		// Prevailing wage endpoints
+		r.Route("/wages", func(r chi.Router) {
+			r.Post("/import", ar.jobController.ImportPrevailingWages)
+			r.Post("/compare", ar.jobController.TriggerWageComparison)
+		})
+
 		// LMIA endpoints
 		r.Route("/lmia", func(r chi.Router) {
 			r.Post("/geocode", ar.lmiaController.TriggerGeocoding)
+			r.Post("/postal-codes/import", ar.lmiaController.ImportPostalCodeCentroids)
 		})
 	})
 }
\ No newline at end of file
@@ -2,24 +2,59 @@ package router
 
 import (
 	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"canada-hires/models"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func BoycottRoutes(boycottController controllers.BoycottController, authMW func(http.Handler) http.Handler) func(r chi.Router) {
+func BoycottRoutes(boycottController controllers.BoycottController, campaignController controllers.BoycottCampaignController, authMW func(http.Handler) http.Handler) func(r chi.Router) {
 	return func(r chi.Router) {
 		r.Route("/boycotts", func(r chi.Router) {
 			// Public routes
 			r.Get("/top", boycottController.GetTopBoycotted)
+			r.Get("/trending", boycottController.GetTrending)
+			r.Get("/trends/activity", boycottController.GetActivity)
+			r.Get("/trends/regions", boycottController.GetRegionalStats)
 
-			// Protected routes
+			// The user's boycotts, API tokens need the read:boycotts scope
 			r.Group(func(r chi.Router) {
 				r.Use(authMW)
+				r.Use(middleware.RequireScope(models.ScopeReadBoycotts))
 				r.Get("/stats", boycottController.GetBoycottStats)
-				r.Post("/toggle", boycottController.ToggleBoycott)
 				r.Get("/my", boycottController.GetUserBoycotts)
 			})
+
+			// Changes need a signed in user, API tokens can't make them
+			r.Group(func(r chi.Router) {
+				r.Use(authMW)
+				r.Use(middleware.RequireAuth)
+				r.Post("/toggle", boycottController.ToggleBoycott)
+			})
+
+			// Organized campaigns, signed in users also see whether they pledged
+			r.Route("/campaigns", func(r chi.Router) {
+				r.Use(authMW)
+
+				// API tokens need the read:boycotts scope
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequireScope(models.ScopeReadBoycotts))
+					r.Get("/", campaignController.ListCampaigns)
+					r.Get("/my", campaignController.GetMyCampaigns)
+					r.Get("/{id}", campaignController.GetCampaign)
+					r.Get("/{id}/stats", campaignController.GetCampaignStats)
+				})
+
+				// Changes need a signed in user, API tokens can't make them
+				r.Group(func(r chi.Router) {
+					r.Use(middleware.RequireAuth)
+					r.Post("/", campaignController.CreateCampaign)
+					r.Post("/{id}/close", campaignController.CloseCampaign)
+					r.Post("/{id}/pledge", campaignController.Pledge)
+					r.Delete("/{id}/pledge", campaignController.WithdrawPledge)
+				})
+			})
 		})
 	}
 }
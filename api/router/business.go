@@ -3,6 +3,8 @@ package router
 import (
 	"canada-hires/container"
 	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -14,12 +16,14 @@ type BusinessRouter interface {
 type businessRouter struct {
 	cn                 *container.Container
 	businessController controllers.BusinessController
+	authMW             func(http.Handler) http.Handler
 }
 
-func NewBusinessRouter(cn *container.Container, businessController controllers.BusinessController) BusinessRouter {
+func NewBusinessRouter(cn *container.Container, businessController controllers.BusinessController, authMW func(http.Handler) http.Handler) BusinessRouter {
 	return &businessRouter{
 		cn:                 cn,
 		businessController: businessController,
+		authMW:             authMW,
 	}
 }
 
@@ -29,5 +33,38 @@ func (br *businessRouter) Init(r chi.Router) {
 		r.Post("/", br.businessController.CreateBusiness)
 		r.Get("/{id}", br.businessController.GetBusiness)
 		r.Put("/{id}", br.businessController.UpdateBusiness)
+
+		// Public routes - the emailed claim link and published statements
+		r.Get("/claims/verify/{token}", br.businessController.ConfirmClaimPage)
+		r.Post("/claims/verify/{token}", br.businessController.VerifyClaim)
+		r.Get("/statement", br.businessController.GetStatement)
+
+		// Protected routes - authentication required
+		r.Group(func(r chi.Router) {
+			// Apply auth middleware to extract user from cookie
+			r.Use(br.authMW)
+			// Apply authentication requirement middleware
+			r.Use(middleware.RequireAuth)
+			r.Post("/claims", br.businessController.StartClaim)
+			r.Get("/claims/me", br.businessController.GetMyClaims)
+			// Verified businesses reply once per report and keep one general statement
+			r.Put("/claims/{claim_id}/statement", br.businessController.SetStatement)
+			r.Put("/replies/reports/{report_id}", br.businessController.ReplyToReport)
+		})
+
+		// Moderation routes - moderators and admins
+		r.Group(func(r chi.Router) {
+			// Apply auth middleware to extract user from cookie
+			r.Use(br.authMW)
+			// Apply moderator requirement middleware
+			r.Use(middleware.RequireModerator)
+			r.Get("/replies/queue", br.businessController.GetReplyQueue)
+			r.Post("/replies/{reply_id}/publish", br.businessController.PublishReply)
+			r.Post("/replies/{reply_id}/reject", br.businessController.RejectReply)
+			// Claims confirmed by email are approved, or revoked, once the domain is checked
+			r.Get("/claims/queue", br.businessController.GetClaimQueue)
+			r.Post("/claims/{claim_id}/approve", br.businessController.ApproveClaim)
+			r.Post("/claims/{claim_id}/revoke", br.businessController.RevokeClaim)
+		})
 	})
 }
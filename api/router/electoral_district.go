@@ -0,0 +1,30 @@
+package router
+
+import (
+	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// ElectoralDistrictRoutes sets up routes for federal electoral districts (ridings)
+func ElectoralDistrictRoutes(controller controllers.ElectoralDistrictController, authMW func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Route("/ridings", func(r chi.Router) {
+			// Public routes
+			r.Get("/", controller.ListDistricts)
+			r.Get("/lookup", controller.LookupPostalCode)
+			r.Get("/{district_id}", controller.GetDistrict)
+			r.Get("/{district_id}/summary", controller.GetSummary)
+		})
+
+		// Admin routes (require an admin)
+		r.Route("/admin/ridings", func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireAdmin)
+			r.Post("/import", controller.ImportBoundaries)
+			r.Post("/assign", controller.AssignDistricts)
+		})
+	}
+}
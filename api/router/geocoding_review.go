@@ -0,0 +1,26 @@
+package router
+
+import (
+	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// GeocodingReviewRoutes sets up routes for reviewing low-confidence and mismatched geocoding results
+func GeocodingReviewRoutes(controller controllers.GeocodingReviewController, authMW func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		// Admin routes, decisions are recorded with the reviewer
+		r.Route("/admin/geocoding/reviews", func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireAdmin)
+			r.Get("/", controller.ListReviews)
+			r.Get("/summary", controller.GetSummary)
+			r.Get("/{review_id}", controller.GetReview)
+			r.Post("/{review_id}/accept", controller.AcceptReview)
+			r.Post("/{review_id}/reject", controller.RejectReview)
+			r.Post("/{review_id}/pin", controller.PinReview)
+		})
+	}
+}
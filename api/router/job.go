@@ -2,15 +2,25 @@ package router
 
 import (
 	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"canada-hires/models"
+	"net/http"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func JobRoutes(r chi.Router, jobController *controllers.JobController) {
+func JobRoutes(r chi.Router, jobController *controllers.JobController, authMW func(http.Handler) http.Handler) {
 	r.Route("/api/jobs", func(r chi.Router) {
-		// Job postings endpoints
-		r.Get("/", jobController.GetJobPostings)
-		r.Get("/stats", jobController.GetJobStats)
+		// Job postings endpoints, API tokens need the read:jobs scope
+		r.Group(func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireScope(models.ScopeReadJobs))
+			r.Get("/", jobController.GetJobPostings)
+			r.Get("/stats", jobController.GetJobStats)
+			r.Get("/reposts", jobController.GetRepostedJobs)
+			r.Get("/families/{family_id}", jobController.GetPostingFamily)
+			r.Get("/{job_id}/lmia-approvals", jobController.GetJobLMIAApprovals)
+		})
 		
 		// Scraping endpoints
 		r.Post("/scraping-runs", jobController.CreateScrapingRun)
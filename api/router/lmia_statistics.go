@@ -2,6 +2,8 @@ package router
 
 import (
 	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"canada-hires/models"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -11,11 +13,17 @@ import (
 func LMIAStatisticsRoutes(controller controllers.LMIAStatisticsController, authMW func(http.Handler) http.Handler) func(chi.Router) {
 	return func(r chi.Router) {
 		r.Route("/lmia/statistics", func(r chi.Router) {
-			// Public routes
-			r.Get("/daily", controller.GetDailyTrends)
-			r.Get("/monthly", controller.GetMonthlyTrends)
-			r.Get("/summary", controller.GetTrendsSummary)
-			r.Get("/regional", controller.GetRegionalStats)
+			// Public routes, API tokens need the read:lmia scope
+			r.Group(func(r chi.Router) {
+				r.Use(authMW)
+				r.Use(middleware.RequireScope(models.ScopeReadLMIA))
+				r.Get("/daily", controller.GetDailyTrends)
+				r.Get("/monthly", controller.GetMonthlyTrends)
+				r.Get("/summary", controller.GetTrendsSummary)
+				r.Get("/regional", controller.GetRegionalStats)
+				r.Get("/wages", controller.GetWageComparison)
+				r.Get("/repeat-employers", controller.GetRepeatEmployers)
+			})
 
 			// Admin routes (require authentication)
 			r.Group(func(r chi.Router) {
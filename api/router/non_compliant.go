@@ -2,6 +2,7 @@ package router
 
 import (
 	"canada-hires/controllers"
+	"canada-hires/middleware"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
@@ -17,14 +18,25 @@ func NonCompliantRoutes(controller *controllers.NonCompliantController, authMW f
 			r.Get("/locations", controller.GetNonCompliantLocations)
 			r.Get("/employers/postal-code/{postal_code}", controller.GetNonCompliantEmployersByPostalCode)
 			r.Get("/employers/coordinates/{lat}/{lng}", controller.GetNonCompliantEmployersByCoordinates)
+			r.Get("/employers/{employer_id}/history", controller.GetNonCompliantEmployerHistory)
+			r.Get("/changes", controller.GetNonCompliantChanges)
+			r.Get("/still-hiring", controller.GetStillHiringEmployers)
+			r.Get("/statistics", controller.GetPenaltyStats)
+			r.Get("/statistics/largest-penalties", controller.GetLargestPenalties)
+			r.Get("/statistics/repeat-offenders", controller.GetRepeatOffenders)
+			r.Get("/statistics/trends", controller.GetPenaltyTrends)
 		})
 
 		// Admin routes for scraping operations
 		r.Route("/admin/non-compliant", func(r chi.Router) {
 			r.Use(authMW) // Apply authentication middleware
+			r.Use(middleware.RequireAdmin)
 			r.Post("/scrape", controller.TriggerNonCompliantScraper)
 			r.Get("/status", controller.GetNonCompliantScrapingStatus)
 			r.Post("/geocode", controller.TriggerNonCompliantGeocoding)
+			r.Post("/address-geocode", controller.TriggerNonCompliantAddressGeocode)
+			r.Post("/still-hiring/match", controller.TriggerStillHiringMatching)
+			r.Post("/import", controller.ImportNonCompliantPage)
 		})
 	}
 }
\ No newline at end of file
@@ -4,6 +4,7 @@ import (
 	"canada-hires/container"
 	"canada-hires/controllers"
 	"canada-hires/middleware"
+	"canada-hires/models"
 	"net/http"
 
 	"github.com/charmbracelet/log"
@@ -16,22 +17,26 @@ type ReportRouter interface {
 }
 
 type reportRouter struct {
-	cn               *container.Container
-	reportController controllers.ReportController
-	authMW           func(http.Handler) http.Handler
+	cn                   *container.Container
+	reportController     controllers.ReportController
+	attachmentController controllers.ReportAttachmentController
+	voteController       controllers.ReportVoteController
+	authMW               func(http.Handler) http.Handler
 }
 
-func NewReportRouter(cn *container.Container, reportController controllers.ReportController, authMW func(http.Handler) http.Handler) ReportRouter {
+func NewReportRouter(cn *container.Container, reportController controllers.ReportController, attachmentController controllers.ReportAttachmentController, voteController controllers.ReportVoteController, authMW func(http.Handler) http.Handler) ReportRouter {
 	return &reportRouter{
-		cn:               cn,
-		reportController: reportController,
-		authMW:           authMW,
+		cn:                   cn,
+		reportController:     reportController,
+		attachmentController: attachmentController,
+		voteController:       voteController,
+		authMW:               authMW,
 	}
 }
 
 func (rr *reportRouter) InjectReportRoutes(r chi.Router) {
-	err := rr.cn.Invoke(func(reportController controllers.ReportController, authMW func(http.Handler) http.Handler) {
-		rr := NewReportRouter(rr.cn, reportController, authMW)
+	err := rr.cn.Invoke(func(reportController controllers.ReportController, attachmentController controllers.ReportAttachmentController, voteController controllers.ReportVoteController, authMW func(http.Handler) http.Handler) {
+		rr := NewReportRouter(rr.cn, reportController, attachmentController, voteController, authMW)
 		rr.Init(r)
 	})
 
@@ -42,32 +47,75 @@ func (rr *reportRouter) InjectReportRoutes(r chi.Router) {
 
 func (rr *reportRouter) Init(r chi.Router) {
 	r.Route("/reports", func(r chi.Router) {
-		// Public routes - no authentication required
-		r.Get("/", rr.reportController.GetReports)
-		r.Get("/{id}", rr.reportController.GetReportByID)
-		r.Get("/business/{businessName}", rr.reportController.GetReports)
-		r.Get("/address", rr.reportController.GetReports)
-		r.Get("/grouped-by-address", rr.reportController.GetReportsGrouped)
+		// Public routes - no authentication required, API tokens need the read:reports scope
+		r.Group(func(r chi.Router) {
+			r.Use(rr.authMW)
+			r.Use(middleware.RequireScope(models.ScopeReadReports))
+			r.Get("/", rr.reportController.GetReports)
+			// Authors and moderators can also see their reports that aren't published
+			r.Get("/{id}", rr.reportController.GetReportByID)
+			r.Get("/business/{businessName}", rr.reportController.GetReports)
+			r.Get("/address", rr.reportController.GetReports)
+			r.Get("/grouped-by-address", rr.reportController.GetReportsGrouped)
+		})
 		
 		// Protected routes - authentication required
 		r.Group(func(r chi.Router) {
 			// Apply auth middleware to extract user from cookie
 			r.Use(rr.authMW)
+			// API tokens need the write:reports scope
+			r.Use(middleware.RequireScope(models.ScopeWriteReports))
 			// Apply authentication requirement middleware
 			r.Use(middleware.RequireAuth)
 			r.Post("/", rr.reportController.CreateReport)
 			r.Put("/{id}", rr.reportController.UpdateReport)
 			r.Delete("/{id}", rr.reportController.DeleteReport)
 			r.Get("/user/me", rr.reportController.GetUserReports)
+			// Community corroborations and flags, one vote per user and report
+			r.Post("/{id}/votes", rr.voteController.CastVote)
+			r.Get("/{id}/votes/me", rr.voteController.GetMyVote)
+			r.Delete("/{id}/votes/me", rr.voteController.WithdrawVote)
 		})
 		
-		// Admin-only routes
+		// Moderation routes - moderators and admins
+		r.Group(func(r chi.Router) {
+			// Apply auth middleware to extract user from cookie
+			r.Use(rr.authMW)
+			// Apply moderator requirement middleware
+			r.Use(middleware.RequireModerator)
+			// Moderators can access reports in every status
+			r.Get("/moderation/queue", rr.reportController.GetModerationQueue)
+			r.Get("/moderation/summary", rr.reportController.GetModerationSummary)
+			r.Post("/moderation/bulk", rr.reportController.BulkModerateReports)
+			r.Post("/{id}/publish", rr.reportController.PublishReport)
+			r.Post("/{id}/reject", rr.reportController.RejectReport)
+			r.Post("/{id}/flag", rr.reportController.FlagReport)
+			r.Get("/{id}/votes", rr.voteController.GetReportVotes)
+			// Every change to the report, including deletion
+			r.Get("/{id}/history", rr.reportController.GetReportHistory)
+		})
+
+		// Admin routes - deleted reports can be restored
 		r.Group(func(r chi.Router) {
 			// Apply auth middleware to extract user from cookie
 			r.Use(rr.authMW)
 			// Apply admin requirement middleware
 			r.Use(middleware.RequireAdmin)
-			// Admin can access all reports without status filtering
+			r.Get("/deleted", rr.reportController.GetDeletedReports)
+			r.Post("/{id}/restore", rr.reportController.RestoreReport)
+		})
+
+		// Evidence attachments, private ones are only visible to the author and moderators
+		r.Route("/{id}/attachments", func(r chi.Router) {
+			r.Use(rr.authMW)
+			r.With(middleware.RequireScope(models.ScopeReadReports)).Get("/", rr.attachmentController.GetAttachments)
+			r.With(middleware.RequireScope(models.ScopeReadReports)).Get("/{attachment_id}", rr.attachmentController.DownloadAttachment)
+
+			r.With(middleware.RequireScope(models.ScopeWriteReports), middleware.RequireAuth).Post("/", rr.attachmentController.UploadAttachment)
+			r.With(middleware.RequireScope(models.ScopeWriteReports), middleware.RequireAuth).Delete("/{attachment_id}", rr.attachmentController.DeleteAttachment)
+
+			r.With(middleware.RequireModerator).Post("/{attachment_id}/approve", rr.attachmentController.ApproveAttachment)
+			r.With(middleware.RequireModerator).Post("/{attachment_id}/hide", rr.attachmentController.HideAttachment)
 		})
 	})
 }
@@ -18,15 +18,15 @@ func InitRoutes(cn *container.Container, r *chi.Mux) {
 	adr := &adminRouter{}
 
 	// Invoke the router initializers
-	err := cn.Invoke(func(authController controllers.AuthController, businessController controllers.BusinessController, reportController controllers.ReportController, userController controllers.UserController, jobController *controllers.JobController, lmiaController *controllers.LMIAController, authMW func(http.Handler) http.Handler, requireMW func(http.Handler) http.Handler) {
+	err := cn.Invoke(func(authController controllers.AuthController, businessController controllers.BusinessController, reportController controllers.ReportController, reportAttachmentController controllers.ReportAttachmentController, reportVoteController controllers.ReportVoteController, userController controllers.UserController, tierController controllers.VerificationTierController, apiTokenController controllers.APITokenController, jobController *controllers.JobController, lmiaController *controllers.LMIAController, authMW func(http.Handler) http.Handler, requireMW func(http.Handler) http.Handler) {
 		*ar = *NewAuthRouter(cn, authController).(*authRouter)
-		*br = *NewBusinessRouter(cn, businessController).(*businessRouter)
-		*rr = *NewReportRouter(cn, reportController, authMW).(*reportRouter)
-		*ur = *NewUserRouter(cn, userController, authMW, requireMW).(*userRouter)
+		*br = *NewBusinessRouter(cn, businessController, authMW).(*businessRouter)
+		*rr = *NewReportRouter(cn, reportController, reportAttachmentController, reportVoteController, authMW).(*reportRouter)
+		*ur = *NewUserRouter(cn, userController, tierController, apiTokenController, authMW, requireMW).(*userRouter)
 		*adr = *NewAdminRouter(cn, jobController, lmiaController, authMW).(*adminRouter)
 		
 		// Initialize job routes
-		JobRoutes(r, jobController)
+		JobRoutes(r, jobController, authMW)
 	})
 
 	if err != nil {
@@ -42,8 +42,8 @@ func InitRoutes(cn *container.Container, r *chi.Mux) {
 		adr.InjectAdminRoutes(r)
 		
 		// Add LMIA routes
-		err := cn.Invoke(func(lmiaController *controllers.LMIAController) {
-			LMIARoutes(lmiaController)(r)
+		err := cn.Invoke(func(lmiaController *controllers.LMIAController, authMW func(http.Handler) http.Handler) {
+			LMIARoutes(lmiaController, authMW)(r)
 		})
 		if err != nil {
 			log.Error("Failed to initialize LMIA routes", "error", err)
@@ -66,8 +66,8 @@ func InitRoutes(cn *container.Container, r *chi.Mux) {
 		}
 		
 		// Add boycott routes
-		err = cn.Invoke(func(boycottController controllers.BoycottController, authMW func(http.Handler) http.Handler) {
-			BoycottRoutes(boycottController, authMW)(r)
+		err = cn.Invoke(func(boycottController controllers.BoycottController, campaignController controllers.BoycottCampaignController, authMW func(http.Handler) http.Handler) {
+			BoycottRoutes(boycottController, campaignController, authMW)(r)
 		})
 		if err != nil {
 			log.Error("Failed to initialize boycott routes", "error", err)
@@ -81,6 +81,46 @@ func InitRoutes(cn *container.Container, r *chi.Mux) {
 			log.Error("Failed to initialize non-compliant routes", "error", err)
 		}
 		
+		// Add parsed address routes
+		err = cn.Invoke(func(addressController controllers.AddressController, authMW func(http.Handler) http.Handler) {
+			AddressRoutes(addressController, authMW)(r)
+		})
+		if err != nil {
+			log.Error("Failed to initialize address routes", "error", err)
+		}
+
+		// Add spatial search routes
+		err = cn.Invoke(func(spatialController controllers.SpatialController, authMW func(http.Handler) http.Handler) {
+			SpatialRoutes(spatialController, authMW)(r)
+		})
+		if err != nil {
+			log.Error("Failed to initialize spatial routes", "error", err)
+		}
+
+		// Add electoral district routes
+		err = cn.Invoke(func(electoralDistrictController controllers.ElectoralDistrictController, authMW func(http.Handler) http.Handler) {
+			ElectoralDistrictRoutes(electoralDistrictController, authMW)(r)
+		})
+		if err != nil {
+			log.Error("Failed to initialize electoral district routes", "error", err)
+		}
+
+		// Add geocoding review routes
+		err = cn.Invoke(func(geocodingReviewController controllers.GeocodingReviewController, authMW func(http.Handler) http.Handler) {
+			GeocodingReviewRoutes(geocodingReviewController, authMW)(r)
+		})
+		if err != nil {
+			log.Error("Failed to initialize geocoding review routes", "error", err)
+		}
+
+		// Add verification tier admin routes
+		err = cn.Invoke(func(tierController controllers.VerificationTierController, authMW func(http.Handler) http.Handler) {
+			VerificationTierRoutes(tierController, authMW)(r)
+		})
+		if err != nil {
+			log.Error("Failed to initialize verification tier routes", "error", err)
+		}
+		
 		// Add search routes
 		searchController := controllers.NewSearchController()
 		SearchRoutes(searchController)(r)
@@ -0,0 +1,28 @@
+package router
+
+import (
+	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SpatialRoutes sets up routes for radius and bounding box searches and map clusters across datasets
+func SpatialRoutes(controller controllers.SpatialController, authMW func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Route("/spatial", func(r chi.Router) {
+			// Public routes
+			r.Get("/near", controller.Near)
+			r.Get("/within", controller.Within)
+			r.Get("/clusters", controller.Clusters)
+		})
+
+		// Admin routes (require an admin)
+		r.Route("/admin/spatial", func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireAdmin)
+			r.Post("/geocode", controller.GeocodeLocations)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package router
 import (
 	"canada-hires/container"
 	"canada-hires/controllers"
+	"canada-hires/middleware"
 	"net/http"
 
 	"github.com/charmbracelet/log"
@@ -10,24 +11,28 @@ import (
 )
 
 type userRouter struct {
-	cn             *container.Container
-	userController controllers.UserController
-	authMW         func(http.Handler) http.Handler
-	requireMW      func(http.Handler) http.Handler
+	cn                 *container.Container
+	userController     controllers.UserController
+	tierController     controllers.VerificationTierController
+	apiTokenController controllers.APITokenController
+	authMW             func(http.Handler) http.Handler
+	requireMW          func(http.Handler) http.Handler
 }
 
-func NewUserRouter(cn *container.Container, userController controllers.UserController, authMW func(http.Handler) http.Handler, requireMW func(http.Handler) http.Handler) AuthRouter {
+func NewUserRouter(cn *container.Container, userController controllers.UserController, tierController controllers.VerificationTierController, apiTokenController controllers.APITokenController, authMW func(http.Handler) http.Handler, requireMW func(http.Handler) http.Handler) AuthRouter {
 	return &userRouter{
-		cn:             cn,
-		userController: userController,
-		authMW:         authMW,
-		requireMW:      requireMW,
+		cn:                 cn,
+		userController:     userController,
+		tierController:     tierController,
+		apiTokenController: apiTokenController,
+		authMW:             authMW,
+		requireMW:          requireMW,
 	}
 }
 
 func (ar *userRouter) InjectAuthRoutes(r chi.Router) {
-	err := ar.cn.Invoke(func(userController controllers.UserController) {
-		ar := NewUserRouter(ar.cn, userController, ar.authMW, ar.requireMW)
+	err := ar.cn.Invoke(func(userController controllers.UserController, tierController controllers.VerificationTierController, apiTokenController controllers.APITokenController) {
+		ar := NewUserRouter(ar.cn, userController, tierController, apiTokenController, ar.authMW, ar.requireMW)
 		ar.Init(r)
 	})
 
@@ -40,5 +45,14 @@ func (ar *userRouter) Init(r chi.Router) {
 	r.Route("/user", func(r chi.Router) {
 		r.Use(ar.authMW)
 		r.With(ar.requireMW).Get("/profile", ar.userController.GetUser)
+		// Verification tier, what the next tier needs and past tier changes
+		r.With(middleware.RequireAuth).Get("/tier", ar.tierController.GetMyTier)
+		// API tokens can only be managed with a session, not with another token
+		r.Route("/api-tokens", func(r chi.Router) {
+			r.Use(middleware.RequireAuth)
+			r.Get("/", ar.apiTokenController.GetMyTokens)
+			r.Post("/", ar.apiTokenController.CreateToken)
+			r.Delete("/{token_id}", ar.apiTokenController.RevokeToken)
+		})
 	})
 }
@@ -0,0 +1,22 @@
+package router
+
+import (
+	"canada-hires/controllers"
+	"canada-hires/middleware"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// VerificationTierRoutes sets up admin routes for reviewing and re-evaluating user verification tiers
+func VerificationTierRoutes(controller controllers.VerificationTierController, authMW func(http.Handler) http.Handler) func(chi.Router) {
+	return func(r chi.Router) {
+		r.Route("/admin/users", func(r chi.Router) {
+			r.Use(authMW)
+			r.Use(middleware.RequireAdmin)
+			r.Post("/tiers/evaluate", controller.EvaluateAll)
+			r.Get("/{user_id}/tier", controller.GetUserTier)
+			r.Post("/{user_id}/tier/evaluate", controller.EvaluateUser)
+		})
+	}
+}
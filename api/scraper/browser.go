@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 
@@ -354,109 +353,50 @@ func removeTabsAndNewLines(str string) string {
 // ScrapeReasonDescriptions scrapes all reason code descriptions from the non-compliant page
 func (s *Scraper) ScrapeReasonDescriptions() (map[string]string, error) {
 	fmt.Println("🎯 Scraping reason descriptions from non-compliant page...")
-	
+
+	var html string
 	err := chromedp.Run(s.ctx,
 		chromedp.Navigate(nonCompliantURL),
 		chromedp.WaitVisible("ol li", chromedp.ByQuery),
 		chromedp.Sleep(2*time.Second), // Wait for page to fully load
+		chromedp.OuterHTML("html", &html, chromedp.ByQuery),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to navigate to non-compliant page: %v", err)
 	}
 
-	// Extract reason descriptions using JavaScript
-	var reasonsJSON string
-	err = chromedp.Run(s.ctx,
-		chromedp.Evaluate(`(function() {
-			const reasonsMap = {};
-			
-			// Look for list items with ids like "list1", "list2", etc.
-			for (let i = 1; i <= 50; i++) {
-				const element = document.getElementById('list' + i);
-				if (element) {
-					const description = element.textContent.trim();
-					if (description) {
-						reasonsMap[i.toString()] = description;
-					}
-				}
-			}
-			
-			// If no elements with list IDs found, try to extract from ordered list
-			if (Object.keys(reasonsMap).length === 0) {
-				const listItems = document.querySelectorAll('ol li');
-				for (let i = 0; i < listItems.length; i++) {
-					const item = listItems[i];
-					const description = item.textContent.trim();
-					if (description) {
-						// Use 1-based indexing to match reason codes
-						reasonsMap[(i + 1).toString()] = description;
-					}
-				}
-			}
-			
-			// If still no results, try to find any numbered list pattern
-			if (Object.keys(reasonsMap).length === 0) {
-				const allLists = document.querySelectorAll('ol, ul');
-				for (let list of allLists) {
-					const items = list.querySelectorAll('li');
-					if (items.length > 10) { // Likely the main reasons list
-						for (let i = 0; i < items.length; i++) {
-							const item = items[i];
-							const description = item.textContent.trim();
-							if (description) {
-								reasonsMap[(i + 1).toString()] = description;
-							}
-						}
-						break;
-					}
-				}
-			}
-			
-			return JSON.stringify(reasonsMap);
-		})()`, &reasonsJSON),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract reason descriptions: %v", err)
-	}
-
-	// Parse JSON data
-	var reasonsMap map[string]string
-	err = json.Unmarshal([]byte(reasonsJSON), &reasonsMap)
+	page, err := ParseNonCompliantPage(strings.NewReader(html))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse reasons JSON: %v", err)
+		return nil, err
 	}
 
-	fmt.Printf("✅ Extracted %d reason descriptions\n", len(reasonsMap))
-	for code, desc := range reasonsMap {
-		if len(desc) > 100 {
-			fmt.Printf("   Reason %s: %s...\n", code, desc[:100])
-		} else {
-			fmt.Printf("   Reason %s: %s\n", code, desc)
-		}
-	}
-
-	return reasonsMap, nil
+	fmt.Printf("✅ Extracted %d reason descriptions\n", len(page.ReasonDescriptions))
+	return page.ReasonDescriptions, nil
 }
 
 // ScrapeNonCompliantEmployersWithReasons scrapes both reason descriptions and employer data
 func (s *Scraper) ScrapeNonCompliantEmployersWithReasons() ([]scraper_types.NonCompliantEmployerData, map[string]string, error) {
-	// First, scrape the reason descriptions
-	reasonDescriptions, err := s.ScrapeReasonDescriptions()
+	page, err := ScrapeNonCompliantPages(&chromedpNonCompliantFetcher{scraper: s})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to scrape reason descriptions: %v", err)
+		return nil, nil, fmt.Errorf("failed to scrape employers: %v", err)
 	}
 
-	// Then scrape the employer data
-	employers, err := s.ScrapeNonCompliantEmployers()
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to scrape employers: %v", err)
+	for _, warning := range page.Warnings {
+		fmt.Printf("⚠️  %s\n", warning)
 	}
 
-	return employers, reasonDescriptions, nil
+	return page.Employers, page.ReasonDescriptions, nil
 }
 
 // ScrapeNonCompliantEmployers scrapes the non-compliant employers page with pagination
 func (s *Scraper) ScrapeNonCompliantEmployers() ([]scraper_types.NonCompliantEmployerData, error) {
+	employers, _, err := s.ScrapeNonCompliantEmployersWithReasons()
+	return employers, err
+}
+
+// FetchNonCompliantPages renders the non-compliant employers page and returns the HTML of every
+// page of the table, following the pagination until the last page
+func (s *Scraper) FetchNonCompliantPages() ([]string, error) {
 	fmt.Println("🎯 Navigating to non-compliant employers page...")
 
 	err := chromedp.Run(s.ctx,
@@ -505,7 +445,7 @@ func (s *Scraper) ScrapeNonCompliantEmployers() ([]scraper_types.NonCompliantEmp
 		// Don't return error - continue with default page size
 	}
 
-	var allEmployers []scraper_types.NonCompliantEmployerData
+	var pages []string
 	pageNumber := 1
 
 	for {
@@ -520,14 +460,14 @@ func (s *Scraper) ScrapeNonCompliantEmployers() ([]scraper_types.NonCompliantEmp
 			return nil, fmt.Errorf("failed to wait for table on page %d: %v", pageNumber, err)
 		}
 
-		// Scrape current page
-		employers, err := s.parseNonCompliantEmployersPage()
+		// Capture the current page for parsing
+		var html string
+		err = chromedp.Run(s.ctx, chromedp.OuterHTML("html", &html, chromedp.ByQuery))
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse page %d: %v", pageNumber, err)
+			return nil, fmt.Errorf("failed to read page %d: %v", pageNumber, err)
 		}
 
-		allEmployers = append(allEmployers, employers...)
-		fmt.Printf("✅ Scraped %d employers from page %d (total: %d)\n", len(employers), pageNumber, len(allEmployers))
+		pages = append(pages, html)
 
 		// Check if next button exists and is enabled
 		var nextButtonDisabled bool
@@ -600,229 +540,6 @@ func (s *Scraper) ScrapeNonCompliantEmployers() ([]scraper_types.NonCompliantEmp
 		pageNumber++
 	}
 
-	fmt.Printf("🎉 Scraping completed! Total employers scraped: %d\n", len(allEmployers))
-	return allEmployers, nil
-}
-
-// parseNonCompliantEmployersPage parses the current page of the non-compliant employers table
-func (s *Scraper) parseNonCompliantEmployersPage() ([]scraper_types.NonCompliantEmployerData, error) {
-	// Extract table data using JavaScript
-	var tableDataJSON string
-	err := chromedp.Run(s.ctx,
-		chromedp.Evaluate(`(function() {
-			// Function to clean and parse addresses
-			function cleanAddress(rawAddress) {
-				if (!rawAddress) return rawAddress;
-				
-				// Canadian provinces/territories (both English and French)
-				const provinces = [
-					'Alberta', 'AB', 'Colombie-Britannique', 'British Columbia', 'BC',
-					'Manitoba', 'MB', 'Nouveau-Brunswick', 'New Brunswick', 'NB',
-					'Terre-Neuve-et-Labrador', 'Newfoundland and Labrador', 'NL',
-					'Territoires du Nord-Ouest', 'Northwest Territories', 'NT',
-					'Nouvelle-Écosse', 'Nova Scotia', 'NS', 'Nunavut', 'NU',
-					'Ontario', 'ON', 'Île-du-Prince-Édouard', 'Prince Edward Island', 'PE',
-					'Québec', 'Quebec', 'QC', 'Saskatchewan', 'SK', 'Yukon', 'YT'
-				];
-				
-				// Create regex pattern for provinces
-				const provincePattern = '(' + provinces.join('|') + ')';
-				const regex = new RegExp(provincePattern + '\\s*$', 'i');
-				
-				// Check if address ends with a province
-				const provinceMatch = rawAddress.match(regex);
-				if (!provinceMatch) return rawAddress; // No province found, return as-is
-				
-				const province = provinceMatch[1];
-				const addressWithoutProvince = rawAddress.replace(regex, '').trim();
-				
-				// Look for common patterns where city runs into street address
-				// Pattern 1: Street name directly followed by city name (no comma)
-				// Look for patterns like "rue SomethingCityName" or "Street NameCityName"
-				const streetPatterns = [
-					/^(.+(?:rue|street|ave|avenue|blvd|boulevard|rd|road|dr|drive|place|pl|way)\s+\w+)([A-Z][a-z]+(?:\s+[A-Z][a-z]+)*)\s*,?\s*$/i,
-					/^(.+\s+)([A-Z][a-z]+(?:\s+[A-Z][a-z]+)*)\s*,?\s*$/
-				];
-				
-				for (let pattern of streetPatterns) {
-					const match = addressWithoutProvince.match(pattern);
-					if (match && match[2]) {
-						const streetAddress = match[1].trim();
-						const cityName = match[2].trim();
-						
-						// Additional validation: city name should be reasonable length (2+ chars, not all caps)
-						if (cityName.length > 1 && !cityName.match(/^[A-Z]+$/)) {
-							return streetAddress + ', ' + cityName + ', ' + province;
-						}
-					}
-				}
-				
-				// Fallback: if we have a province but couldn't parse the city, just add commas where needed
-				if (addressWithoutProvince.includes(',')) {
-					return rawAddress; // Already has commas, probably formatted correctly
-				} else {
-					// Try to add comma before last word before province (assuming it's the city)
-					const parts = addressWithoutProvince.trim().split(/\s+/);
-					if (parts.length > 1) {
-						const cityPart = parts[parts.length - 1];
-						const streetPart = parts.slice(0, -1).join(' ');
-						return streetPart + ', ' + cityPart + ', ' + province;
-					}
-				}
-				
-				return rawAddress; // Return original if can't parse
-			}
-			
-			const rows = document.querySelectorAll('table tbody tr');
-			let data = [];
-			
-			for (let row of rows) {
-				const cells = row.querySelectorAll('td');
-				if (cells.length >= 7) {
-					// Extract reason codes from the Reason(s) column
-					const reasonCell = cells[3];
-					const reasonLinks = reasonCell.querySelectorAll('a');
-					const reasonCodes = Array.from(reasonLinks).map(link => {
-						// Extract reason code from href attribute like "#list6" -> "6"
-						const href = link.getAttribute('href');
-						if (href && href.startsWith('#list')) {
-							return href.replace('#list', '');
-						}
-						// Fallback to text content if href doesn't match expected format
-						return link.textContent.trim();
-					}).filter(code => code && code !== '');
-					
-					// Parse penalty amount
-					const penaltyText = cells[5].textContent.trim();
-					const penaltyMatch = penaltyText.match(/\$?([\d,]+)/);
-					const penaltyAmount = penaltyMatch ? parseInt(penaltyMatch[1].replace(/,/g, '')) : 0;
-					
-					data.push({
-						businessOperatingName: cells[0].textContent.trim(),
-						businessLegalName: cells[1].textContent.trim(),
-						address: cleanAddress(cells[2].textContent.trim()),
-						reasonCodes: reasonCodes,
-						dateOfFinalDecision: cells[4].textContent.trim(),
-						penaltyAmount: penaltyAmount,
-						penaltyCurrency: 'CAD',
-						status: cells[6].textContent.trim()
-					});
-				}
-			}
-			
-			return JSON.stringify(data);
-		})()`, &tableDataJSON),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to extract table data: %v", err)
-	}
-
-	// Parse JSON data
-	var employers []scraper_types.NonCompliantEmployerData
-	err = json.Unmarshal([]byte(tableDataJSON), &employers)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse table JSON: %v", err)
-	}
-
-	// Clean and validate the data
-	var cleanedEmployers []scraper_types.NonCompliantEmployerData
-	for _, employer := range employers {
-		// Skip empty rows
-		if employer.BusinessOperatingName == "" {
-			continue
-		}
-
-		// Clean date format (expecting YYYY-MM-DD)
-		if employer.DateOfFinalDecision != "" {
-			employer.DateOfFinalDecision = s.cleanDate(employer.DateOfFinalDecision)
-		}
-
-		// Ensure reason codes are not empty
-		var validReasons []string
-		for _, reason := range employer.ReasonCodes {
-			if reason != "" {
-				validReasons = append(validReasons, reason)
-			}
-		}
-		employer.ReasonCodes = validReasons
-
-		cleanedEmployers = append(cleanedEmployers, employer)
-	}
-
-	return cleanedEmployers, nil
-}
-
-// cleanDate attempts to parse and normalize various date formats to YYYY-MM-DD
-func (s *Scraper) cleanDate(dateStr string) string {
-	dateStr = strings.TrimSpace(dateStr)
-	if dateStr == "" {
-		return ""
-	}
-
-	// Try different date formats that might appear on the page
-	formats := []string{
-		"2006-01-02", // YYYY-MM-DD (already correct)
-		"2006/01/02", // YYYY/MM/DD
-		"01/02/2006", // MM/DD/YYYY
-		"02/01/2006", // DD/MM/YYYY
-		"January 2, 2006",
-		"Jan 2, 2006",
-		"2 January 2006",
-		"2 Jan 2006",
-	}
-
-	for _, format := range formats {
-		if parsedTime, err := time.Parse(format, dateStr); err == nil {
-			return parsedTime.Format("2006-01-02")
-		}
-	}
-
-	// Handle special case of invalid dates like "2019-02-29"
-	if matched := regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`).FindStringSubmatch(dateStr); matched != nil {
-		year := matched[1]
-		month := matched[2]
-		day := matched[3]
-		
-		// Try to fix common invalid dates
-		fixedDateStr := s.fixInvalidDate(year, month, day)
-		if fixedDateStr != dateStr {
-			fmt.Printf("🔧 Fixed invalid date %s -> %s\n", dateStr, fixedDateStr)
-			return fixedDateStr
-		}
-	}
-
-	// If no format matches, return empty string instead of invalid date
-	fmt.Printf("⚠️  Could not parse date: %s, skipping\n", dateStr)
-	return ""
-}
-
-// fixInvalidDate attempts to fix common invalid dates like Feb 29 in non-leap years
-func (s *Scraper) fixInvalidDate(year, month, day string) string {
-	yearInt, _ := strconv.Atoi(year)
-	monthInt, _ := strconv.Atoi(month)
-	dayInt, _ := strconv.Atoi(day)
-	
-	// Fix February 29 in non-leap years
-	if monthInt == 2 && dayInt == 29 && !isLeapYear(yearInt) {
-		return fmt.Sprintf("%s-02-28", year) // Change to Feb 28
-	}
-	
-	// Fix other impossible dates
-	daysInMonth := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
-	if isLeapYear(yearInt) {
-		daysInMonth[1] = 29 // February in leap year
-	}
-	
-	if monthInt >= 1 && monthInt <= 12 && dayInt > daysInMonth[monthInt-1] {
-		// Day exceeds maximum for the month, set to last day of month
-		return fmt.Sprintf("%04d-%02d-%02d", yearInt, monthInt, daysInMonth[monthInt-1])
-	}
-	
-	// Return original if no fix needed
-	return fmt.Sprintf("%s-%s-%s", year, month, day)
-}
-
-// isLeapYear checks if a year is a leap year
-func isLeapYear(year int) bool {
-	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+	fmt.Printf("🎉 Scraping completed! Total pages scraped: %d\n", len(pages))
+	return pages, nil
 }
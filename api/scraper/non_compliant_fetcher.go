@@ -0,0 +1,149 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Sources for the non-compliant employers page
+const (
+	NonCompliantSourceChromedp = "chromedp"
+	NonCompliantSourceHTTP     = "http"
+	NonCompliantSourceFile     = "file"
+)
+
+// NonCompliantPageFetcher returns the HTML of every page of the non-compliant employers table.
+// The first page also carries the reason list.
+type NonCompliantPageFetcher interface {
+	FetchPages() ([]string, error)
+	Close()
+}
+
+// NewNonCompliantPageFetcher creates a fetcher for the given source. The file source reads the
+// saved pages listed in paths, comma separated.
+func NewNonCompliantPageFetcher(source, paths string) (NonCompliantPageFetcher, error) {
+	switch source {
+	case "", NonCompliantSourceChromedp:
+		s, err := NewScraper()
+		if err != nil {
+			return nil, err
+		}
+		return &chromedpNonCompliantFetcher{scraper: s}, nil
+	case NonCompliantSourceHTTP:
+		return NewHTTPNonCompliantFetcher(nonCompliantURL), nil
+	case NonCompliantSourceFile:
+		if strings.TrimSpace(paths) == "" {
+			return nil, fmt.Errorf("no saved non-compliant pages configured")
+		}
+		var files []string
+		for _, path := range strings.Split(paths, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				files = append(files, path)
+			}
+		}
+		return NewFileNonCompliantFetcher(files...), nil
+	default:
+		return nil, fmt.Errorf("unknown non-compliant page source: %s", source)
+	}
+}
+
+// ScrapeNonCompliantPages fetches every page and merges the parsed employers and reasons
+func ScrapeNonCompliantPages(fetcher NonCompliantPageFetcher) (*NonCompliantPage, error) {
+	pages, err := fetcher.FetchPages()
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &NonCompliantPage{ReasonDescriptions: make(map[string]string)}
+	for i, html := range pages {
+		page, err := ParseNonCompliantPage(strings.NewReader(html))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse page %d: %w", i+1, err)
+		}
+
+		merged.Employers = append(merged.Employers, page.Employers...)
+		merged.Warnings = append(merged.Warnings, page.Warnings...)
+		for code, description := range page.ReasonDescriptions {
+			if _, exists := merged.ReasonDescriptions[code]; !exists {
+				merged.ReasonDescriptions[code] = description
+			}
+		}
+	}
+
+	return merged, nil
+}
+
+// chromedpNonCompliantFetcher renders the page in a headless browser and pages through the table
+type chromedpNonCompliantFetcher struct {
+	scraper *Scraper
+}
+
+func (f *chromedpNonCompliantFetcher) FetchPages() ([]string, error) {
+	return f.scraper.FetchNonCompliantPages()
+}
+
+func (f *chromedpNonCompliantFetcher) Close() {
+	f.scraper.Close()
+}
+
+// HTTPNonCompliantFetcher downloads the page without a browser. The table is rendered server side
+// and paginated in the browser, so a single request returns every row.
+type HTTPNonCompliantFetcher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPNonCompliantFetcher(url string) *HTTPNonCompliantFetcher {
+	return &HTTPNonCompliantFetcher{
+		url:    url,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+func (f *HTTPNonCompliantFetcher) FetchPages() ([]string, error) {
+	resp, err := f.client.Get(f.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download non-compliant page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("non-compliant page returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read non-compliant page: %w", err)
+	}
+
+	return []string{string(body)}, nil
+}
+
+func (f *HTTPNonCompliantFetcher) Close() {}
+
+// FileNonCompliantFetcher reads saved copies of the page, e.g. fixtures or a manually downloaded page
+type FileNonCompliantFetcher struct {
+	paths []string
+}
+
+func NewFileNonCompliantFetcher(paths ...string) *FileNonCompliantFetcher {
+	return &FileNonCompliantFetcher{paths: paths}
+}
+
+func (f *FileNonCompliantFetcher) FetchPages() ([]string, error) {
+	var pages []string
+	for _, path := range f.paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read saved non-compliant page %s: %w", path, err)
+		}
+		pages = append(pages, string(content))
+	}
+	return pages, nil
+}
+
+func (f *FileNonCompliantFetcher) Close() {}
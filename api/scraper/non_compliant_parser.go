@@ -0,0 +1,211 @@
+package scraper
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"canada-hires/address"
+	scraper_types "canada-hires/scraper-types"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// NonCompliantPage is the parsed content of one page of the non-compliant employers list
+type NonCompliantPage struct {
+	Employers          []scraper_types.NonCompliantEmployerData
+	ReasonDescriptions map[string]string // Reason code -> description, e.g. "6" -> "..."
+	Warnings           []string          // Rows or values that were skipped or corrected
+}
+
+var (
+	penaltyAmountRegex = regexp.MustCompile(`\$?([\d,]+)`)
+	isoDateRegex       = regexp.MustCompile(`(\d{4})-(\d{2})-(\d{2})`)
+	reasonListIDRegex  = regexp.MustCompile(`^list(\d+)$`)
+)
+
+// ParseNonCompliantPage parses the employers table and reason list from the HTML of the
+// non-compliant employers page. It does not need a browser, so saved pages can be parsed directly.
+func ParseNonCompliantPage(r io.Reader) (*NonCompliantPage, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse non-compliant page HTML: %w", err)
+	}
+
+	page := &NonCompliantPage{}
+	page.Employers, page.Warnings = parseNonCompliantEmployers(doc)
+	page.ReasonDescriptions = parseReasonDescriptions(doc)
+
+	return page, nil
+}
+
+// parseNonCompliantEmployers reads the employer rows. Columns are operating name, legal name,
+// address, reasons, date of final decision, penalty and status.
+func parseNonCompliantEmployers(doc *goquery.Document) ([]scraper_types.NonCompliantEmployerData, []string) {
+	var employers []scraper_types.NonCompliantEmployerData
+	var warnings []string
+
+	doc.Find("table tbody tr").Each(func(i int, row *goquery.Selection) {
+		cells := row.Find("td")
+		if cells.Length() < 7 {
+			return
+		}
+
+		cellText := func(index int) string {
+			return strings.TrimSpace(cells.Eq(index).Text())
+		}
+
+		employer := scraper_types.NonCompliantEmployerData{
+			BusinessOperatingName: cellText(0),
+			BusinessLegalName:     cellText(1),
+			Address:               address.Standardize(cellText(2)),
+			PenaltyCurrency:       "CAD",
+			Status:                cellText(6),
+		}
+
+		// Skip empty rows
+		if employer.BusinessOperatingName == "" {
+			return
+		}
+
+		// Reason links point at the reason list, e.g. "#list6" -> "6"
+		cells.Eq(3).Find("a").Each(func(_ int, link *goquery.Selection) {
+			code := strings.TrimSpace(link.Text())
+			if href, ok := link.Attr("href"); ok && strings.HasPrefix(href, "#list") {
+				code = strings.TrimPrefix(href, "#list")
+			}
+			if code != "" {
+				employer.ReasonCodes = append(employer.ReasonCodes, code)
+			}
+		})
+
+		if match := penaltyAmountRegex.FindStringSubmatch(cellText(5)); match != nil {
+			employer.PenaltyAmount, _ = strconv.Atoi(strings.ReplaceAll(match[1], ",", ""))
+		}
+
+		if rawDate := cellText(4); rawDate != "" {
+			date, warning := cleanDate(rawDate)
+			employer.DateOfFinalDecision = date
+			if warning != "" {
+				warnings = append(warnings, fmt.Sprintf("%s: %s", employer.BusinessOperatingName, warning))
+			}
+		}
+
+		employers = append(employers, employer)
+	})
+
+	return employers, warnings
+}
+
+// parseReasonDescriptions reads the numbered reason list. Items with ids like "list6" are used when
+// present, otherwise the position in the first ordered list, then in the first long list.
+func parseReasonDescriptions(doc *goquery.Document) map[string]string {
+	reasons := make(map[string]string)
+
+	doc.Find("[id^='list']").Each(func(_ int, item *goquery.Selection) {
+		id, _ := item.Attr("id")
+		match := reasonListIDRegex.FindStringSubmatch(id)
+		if match == nil {
+			return
+		}
+		if description := strings.TrimSpace(item.Text()); description != "" {
+			reasons[match[1]] = description
+		}
+	})
+	if len(reasons) > 0 {
+		return reasons
+	}
+
+	addItems := func(items *goquery.Selection) {
+		items.Each(func(i int, item *goquery.Selection) {
+			if description := strings.TrimSpace(item.Text()); description != "" {
+				// Use 1-based indexing to match reason codes
+				reasons[strconv.Itoa(i+1)] = description
+			}
+		})
+	}
+
+	addItems(doc.Find("ol").First().Find("li"))
+	if len(reasons) > 0 {
+		return reasons
+	}
+
+	doc.Find("ol, ul").EachWithBreak(func(_ int, list *goquery.Selection) bool {
+		items := list.Find("li")
+		if items.Length() > 10 { // Likely the main reasons list
+			addItems(items)
+			return false
+		}
+		return true
+	})
+
+	return reasons
+}
+
+// cleanDate normalizes the decision dates to YYYY-MM-DD. The published list contains impossible
+// dates such as 2019-02-29, which are moved to the last day of the month. The warning describes
+// any date that was corrected or dropped.
+func cleanDate(dateStr string) (string, string) {
+	dateStr = strings.TrimSpace(dateStr)
+	if dateStr == "" {
+		return "", ""
+	}
+
+	// Try different date formats that might appear on the page
+	formats := []string{
+		"2006-01-02", // YYYY-MM-DD (already correct)
+		"2006/01/02", // YYYY/MM/DD
+		"01/02/2006", // MM/DD/YYYY
+		"02/01/2006", // DD/MM/YYYY
+		"January 2, 2006",
+		"Jan 2, 2006",
+		"2 January 2006",
+		"2 Jan 2006",
+	}
+
+	for _, format := range formats {
+		if parsedTime, err := time.Parse(format, dateStr); err == nil {
+			return parsedTime.Format("2006-01-02"), ""
+		}
+	}
+
+	if matched := isoDateRegex.FindStringSubmatch(dateStr); matched != nil {
+		if fixed, ok := fixInvalidDate(matched[1], matched[2], matched[3]); ok {
+			return fixed, fmt.Sprintf("fixed invalid date %s -> %s", dateStr, fixed)
+		}
+	}
+
+	// If no format matches, drop the date instead of storing an invalid one
+	return "", fmt.Sprintf("could not parse date %q", dateStr)
+}
+
+// fixInvalidDate clamps a day past the end of its month, e.g. Feb 29 in a non-leap year, and
+// reports whether the date was changed
+func fixInvalidDate(year, month, day string) (string, bool) {
+	yearInt, _ := strconv.Atoi(year)
+	monthInt, _ := strconv.Atoi(month)
+	dayInt, _ := strconv.Atoi(day)
+
+	if monthInt < 1 || monthInt > 12 || dayInt < 1 {
+		return "", false
+	}
+
+	daysInMonth := []int{31, 28, 31, 30, 31, 30, 31, 31, 30, 31, 30, 31}
+	if isLeapYear(yearInt) {
+		daysInMonth[1] = 29 // February in leap year
+	}
+
+	if dayInt > daysInMonth[monthInt-1] {
+		return fmt.Sprintf("%04d-%02d-%02d", yearInt, monthInt, daysInMonth[monthInt-1]), true
+	}
+
+	return "", false
+}
+
+// isLeapYear checks if a year is a leap year
+func isLeapYear(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
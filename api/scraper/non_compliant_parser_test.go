@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseNonCompliantPage(t *testing.T) {
+	file, err := os.Open("testdata/non_compliant_page.html")
+	if err != nil {
+		t.Fatalf("failed to open fixture: %v", err)
+	}
+	defer file.Close()
+
+	page, err := ParseNonCompliantPage(file)
+	if err != nil {
+		t.Fatalf("ParseNonCompliantPage returned an error: %v", err)
+	}
+
+	// The empty row is skipped
+	if len(page.Employers) != 3 {
+		t.Fatalf("expected 3 employers, got %d", len(page.Employers))
+	}
+
+	tests := []struct {
+		operatingName string
+		legalName     string
+		postalCode    string
+		reasonCodes   []string
+		date          string
+		penalty       int
+		status        string
+	}{
+		{"Maple Leaf Diner", "1234567 Ontario Inc.", "M5V 2T6", []string{"5", "6"}, "2023-05-10", 12500, "Eligible to apply"},
+		{"Prairie Farms", "Prairie Farms Ltd.", "T1J 4A1", []string{"10"}, "2019-02-28", 0, "Ineligible to apply for 1 year"},
+		{"Coastal Seafood", "Coastal Seafood Processing Corp.", "B3H 1A1", []string{"6"}, "", 0, "Ineligible to apply for 2 years"},
+	}
+
+	for i, tt := range tests {
+		employer := page.Employers[i]
+		if employer.BusinessOperatingName != tt.operatingName {
+			t.Errorf("employer %d: operating name = %q, want %q", i, employer.BusinessOperatingName, tt.operatingName)
+		}
+		if employer.BusinessLegalName != tt.legalName {
+			t.Errorf("employer %d: legal name = %q, want %q", i, employer.BusinessLegalName, tt.legalName)
+		}
+		if !strings.Contains(employer.Address, tt.postalCode) {
+			t.Errorf("employer %d: address %q does not contain postal code %q", i, employer.Address, tt.postalCode)
+		}
+		if !reflect.DeepEqual(employer.ReasonCodes, tt.reasonCodes) {
+			t.Errorf("employer %d: reason codes = %v, want %v", i, employer.ReasonCodes, tt.reasonCodes)
+		}
+		if employer.DateOfFinalDecision != tt.date {
+			t.Errorf("employer %d: date = %q, want %q", i, employer.DateOfFinalDecision, tt.date)
+		}
+		if employer.PenaltyAmount != tt.penalty {
+			t.Errorf("employer %d: penalty = %d, want %d", i, employer.PenaltyAmount, tt.penalty)
+		}
+		if employer.PenaltyCurrency != "CAD" {
+			t.Errorf("employer %d: currency = %q, want CAD", i, employer.PenaltyCurrency)
+		}
+		if employer.Status != tt.status {
+			t.Errorf("employer %d: status = %q, want %q", i, employer.Status, tt.status)
+		}
+	}
+
+	// One warning for the corrected Feb 29 and one for the unparseable date
+	if len(page.Warnings) != 2 {
+		t.Errorf("expected 2 warnings, got %d: %v", len(page.Warnings), page.Warnings)
+	}
+
+	if len(page.ReasonDescriptions) != 3 {
+		t.Fatalf("expected 3 reason descriptions, got %d", len(page.ReasonDescriptions))
+	}
+	for _, code := range []string{"5", "6", "10"} {
+		if page.ReasonDescriptions[code] == "" {
+			t.Errorf("missing description for reason %s", code)
+		}
+	}
+	if !strings.HasPrefix(page.ReasonDescriptions["10"], "The employer did not make reasonable efforts") {
+		t.Errorf("reason 10 description = %q", page.ReasonDescriptions["10"])
+	}
+}
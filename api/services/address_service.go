@@ -0,0 +1,132 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Number of distinct addresses parsed and linked per batch
+const addressParseBatchSize = 500
+
+type AddressService interface {
+	Parse(rawAddress string) address.Components
+	ParseAndStore(rawAddress string) (*models.ParsedAddress, error)
+	LinkAddress(source, rawAddress string) error
+	ParseSourceAddresses(source string) (int, error)
+	ParseAllAddresses() (map[string]int, error)
+	GetParsedAddress(id string) (*models.ParsedAddress, error)
+	GetParseSummary() ([]models.AddressParseSummary, error)
+}
+
+type addressService struct {
+	repo repos.AddressRepository
+}
+
+func NewAddressService(repo repos.AddressRepository) AddressService {
+	return &addressService{repo: repo}
+}
+
+// Parse splits an address into its components without storing it
+func (s *addressService) Parse(rawAddress string) address.Components {
+	return address.Parse(rawAddress)
+}
+
+// ParseAndStore parses an address and stores its components, shared with every other address
+// that normalizes to the same text
+func (s *addressService) ParseAndStore(rawAddress string) (*models.ParsedAddress, error) {
+	rawAddress = strings.TrimSpace(rawAddress)
+	if rawAddress == "" {
+		return nil, fmt.Errorf("address is empty")
+	}
+
+	parsed := models.NewParsedAddress(rawAddress, address.Parse(rawAddress))
+	if err := s.repo.UpsertParsedAddress(parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// LinkAddress parses a single address and links the records of a source that use it, e.g. right
+// after a report is created
+func (s *addressService) LinkAddress(source, rawAddress string) error {
+	if strings.TrimSpace(rawAddress) == "" {
+		return nil
+	}
+
+	parsed, err := s.ParseAndStore(rawAddress)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.repo.LinkParsedAddresses(source, map[string]string{rawAddress: parsed.ID})
+	return err
+}
+
+// ParseSourceAddresses parses every address of a source that isn't linked to its components yet
+// and returns the number of records linked
+func (s *addressService) ParseSourceAddresses(source string) (int, error) {
+	log.Info("Parsing addresses", "source", source)
+
+	total := 0
+	for {
+		addresses, err := s.repo.GetUnparsedAddresses(source, addressParseBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(addresses) == 0 {
+			break
+		}
+
+		links := make(map[string]string, len(addresses))
+		for _, rawAddress := range addresses {
+			parsed, err := s.ParseAndStore(rawAddress)
+			if err != nil {
+				log.Warn("Failed to parse address", "source", source, "address", rawAddress, "error", err)
+				continue
+			}
+			links[rawAddress] = parsed.ID
+		}
+
+		linked, err := s.repo.LinkParsedAddresses(source, links)
+		if err != nil {
+			return total, err
+		}
+		total += int(linked)
+
+		// Stop rather than fetching the same failing addresses again
+		if linked == 0 {
+			log.Warn("No addresses could be linked in batch, stopping", "source", source, "batch_size", len(addresses))
+			break
+		}
+	}
+
+	log.Info("Address parsing completed", "source", source, "records_linked", total)
+	return total, nil
+}
+
+// ParseAllAddresses parses the unparsed addresses of every source
+func (s *addressService) ParseAllAddresses() (map[string]int, error) {
+	results := make(map[string]int, len(models.AddressSources))
+	for _, source := range models.AddressSources {
+		linked, err := s.ParseSourceAddresses(source)
+		results[source] = linked
+		if err != nil {
+			return results, fmt.Errorf("failed to parse %s addresses: %w", source, err)
+		}
+	}
+	return results, nil
+}
+
+func (s *addressService) GetParsedAddress(id string) (*models.ParsedAddress, error) {
+	return s.repo.GetParsedAddressByID(id)
+}
+
+func (s *addressService) GetParseSummary() ([]models.AddressParseSummary, error) {
+	return s.repo.GetParseSummary()
+}
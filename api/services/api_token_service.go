@@ -0,0 +1,203 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Prefix of every API token, so leaked tokens are easy to recognize
+const apiTokenPrefix = "jwc_"
+
+// Token lifetime in days when none is requested, and the longest one allowed
+const (
+	defaultAPITokenDays = 90
+	maxAPITokenDays     = 365
+)
+
+// Last used times are only written once per interval, so busy scripts don't update the token on
+// every request
+const apiTokenLastUsedInterval = time.Minute
+
+// Longest API token name
+const maxAPITokenNameLength = 100
+
+var (
+	// ErrInvalidAPIToken is returned for a token request without a name or with unknown scopes
+	ErrInvalidAPIToken = errors.New("invalid API token")
+	// ErrAPITokenScopeNotAllowed is returned when a user asks for a scope their role doesn't have
+	ErrAPITokenScopeNotAllowed = errors.New("scope is not allowed for your account")
+	// ErrAPITokenUnauthorized is returned for tokens that are unknown, expired or revoked
+	ErrAPITokenUnauthorized = errors.New("invalid or expired API token")
+)
+
+// CreateAPITokenRequest asks for a new API token
+type CreateAPITokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expires_in_days"` // Defaults to 90, at most 365
+}
+
+type APITokenService interface {
+	CreateToken(user *models.User, req *CreateAPITokenRequest) (*models.APIToken, error)
+	GetUserTokens(userID string) ([]*models.APIToken, error)
+	RevokeToken(userID, tokenID string) error
+	Authenticate(token, ipAddress string) (*models.APIToken, *models.User, error)
+}
+
+type apiTokenService struct {
+	repo     repos.APITokenRepository
+	userRepo repos.UserRepository
+}
+
+func NewAPITokenService(repo repos.APITokenRepository, userRepo repos.UserRepository) APITokenService {
+	return &apiTokenService{
+		repo:     repo,
+		userRepo: userRepo,
+	}
+}
+
+// CreateToken creates a token with the requested scopes. The admin scope is only available to
+// moderators and admins. The returned token is the only time it can be read.
+func (s *apiTokenService) CreateToken(user *models.User, req *CreateAPITokenRequest) (*models.APIToken, error) {
+	if user == nil {
+		return nil, fmt.Errorf("user is required")
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len([]rune(name)) > maxAPITokenNameLength {
+		return nil, fmt.Errorf("%w: name is required and at most %d characters", ErrInvalidAPIToken, maxAPITokenNameLength)
+	}
+
+	var scopes []string
+	for _, scope := range req.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !slices.Contains(models.APITokenScopes, scope) {
+			return nil, fmt.Errorf("%w: unknown scope %q, expected one of %s", ErrInvalidAPIToken, scope, strings.Join(models.APITokenScopes, ", "))
+		}
+		if scope == models.ScopeAdmin && !user.IsModerator() {
+			return nil, fmt.Errorf("%w: %s", ErrAPITokenScopeNotAllowed, scope)
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: at least one scope is required", ErrInvalidAPIToken)
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPITokenDays
+	}
+	if days < 0 || days > maxAPITokenDays {
+		return nil, fmt.Errorf("%w: expiry must be between 1 and %d days", ErrInvalidAPIToken, maxAPITokenDays)
+	}
+
+	secret, err := generateAPITokenSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API token: %w", err)
+	}
+	raw := apiTokenPrefix + secret
+
+	token := &models.APIToken{
+		UserID:      user.ID,
+		Name:        name,
+		TokenPrefix: raw[:len(apiTokenPrefix)+8],
+		TokenHash:   hashAPIToken(raw),
+		Scopes:      scopes,
+		ExpiresAt:   time.Now().UTC().Add(time.Duration(days) * 24 * time.Hour),
+	}
+	if err := s.repo.Create(token); err != nil {
+		return nil, err
+	}
+	token.Token = raw
+
+	log.Info("API token created",
+		"user_id", user.ID,
+		"token_id", token.ID,
+		"scopes", strings.Join(scopes, ","),
+		"expires_at", token.ExpiresAt)
+
+	return token, nil
+}
+
+// GetUserTokens returns the tokens of a user, newest first
+func (s *apiTokenService) GetUserTokens(userID string) ([]*models.APIToken, error) {
+	tokens, err := s.repo.GetByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if tokens == nil {
+		tokens = []*models.APIToken{}
+	}
+
+	return tokens, nil
+}
+
+// RevokeToken revokes a token of the user right away
+func (s *apiTokenService) RevokeToken(userID, tokenID string) error {
+	if err := s.repo.Revoke(tokenID, userID); err != nil {
+		return err
+	}
+
+	log.Info("API token revoked", "user_id", userID, "token_id", tokenID)
+	return nil
+}
+
+// Authenticate returns an active token and its user, and records that it was used
+func (s *apiTokenService) Authenticate(raw, ipAddress string) (*models.APIToken, *models.User, error) {
+	if !strings.HasPrefix(raw, apiTokenPrefix) {
+		return nil, nil, ErrAPITokenUnauthorized
+	}
+
+	token, err := s.repo.GetByHash(hashAPIToken(raw))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, ErrAPITokenUnauthorized
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if !token.IsActive(now) {
+		return nil, nil, ErrAPITokenUnauthorized
+	}
+
+	user, err := s.userRepo.GetByID(token.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get API token user: %w", err)
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenLastUsedInterval {
+		// A missed update only makes the last used time stale
+		if err := s.repo.UpdateLastUsed(token.ID, ipAddress); err != nil {
+			log.Warn("Failed to update API token last used", "token_id", token.ID, "error", err)
+		}
+	}
+
+	return token, user, nil
+}
+
+func generateAPITokenSecret() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// Only a hash of the token is stored, so the database alone can't be used to call the API
+func hashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
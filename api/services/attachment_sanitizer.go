@@ -0,0 +1,151 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+// ErrUnsupportedAttachment is returned for files that aren't one of the
+// models.AttachmentContentTypes, or that are damaged
+var ErrUnsupportedAttachment = errors.New("unsupported attachment type")
+
+// JPEG segments kept when stripping metadata: JFIF header, ICC colour profile and the Adobe
+// segment, which tells decoders how colours are encoded. EXIF, XMP, IPTC and comments are dropped.
+var keptJPEGSegments = map[byte]bool{0xE0: true, 0xE2: true, 0xEE: true}
+
+// PNG chunks that hold metadata rather than image data
+var droppedPNGChunks = map[string]bool{"eXIf": true, "tEXt": true, "zTXt": true, "iTXt": true, "tIME": true}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// sanitizeAttachment detects the type of a file from its content and removes metadata that can
+// identify the reporter, like EXIF camera details and GPS coordinates. Images are decoded to make
+// sure they are valid. PDFs are only checked for an end of file marker and kept as is, including
+// their Info dictionary and XMP metadata (author, creator tool, dates), so stripped is false for them.
+func sanitizeAttachment(data []byte) (contentType string, cleaned []byte, stripped bool, err error) {
+	switch contentType = http.DetectContentType(data); contentType {
+	case "image/jpeg":
+		cleaned, err = stripJPEGMetadata(data)
+		if err == nil {
+			_, err = jpeg.DecodeConfig(bytes.NewReader(cleaned))
+		}
+		stripped = true
+	case "image/png":
+		cleaned, err = stripPNGMetadata(data)
+		if err == nil {
+			_, err = png.DecodeConfig(bytes.NewReader(cleaned))
+		}
+		stripped = true
+	case "application/pdf":
+		cleaned = data
+		if !bytes.Contains(data[max(0, len(data)-1024):], []byte("%%EOF")) {
+			err = errors.New("missing end of file marker")
+		}
+	default:
+		return "", nil, false, fmt.Errorf("%w: %s", ErrUnsupportedAttachment, contentType)
+	}
+
+	if err != nil {
+		return "", nil, false, fmt.Errorf("%w: damaged %s file: %v", ErrUnsupportedAttachment, contentType, err)
+	}
+	return contentType, cleaned, stripped, nil
+}
+
+// stripJPEGMetadata copies a JPEG without its application and comment segments, except the ones
+// in keptJPEGSegments. Everything from the start of scan on is image data and copied unchanged.
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errors.New("missing start of image marker")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+
+	for i := 2; i < len(data); {
+		if data[i] != 0xFF {
+			return nil, fmt.Errorf("expected marker at offset %d", i)
+		}
+		// Markers can be padded with any number of 0xFF fill bytes
+		for i < len(data) && data[i] == 0xFF {
+			i++
+		}
+		if i >= len(data) {
+			return nil, errors.New("truncated marker")
+		}
+		marker := data[i]
+		i++
+
+		// Markers without a length
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write([]byte{0xFF, marker})
+			continue
+		}
+		if marker == 0xD9 {
+			out.Write([]byte{0xFF, marker})
+			return out.Bytes(), nil
+		}
+
+		if i+2 > len(data) {
+			return nil, errors.New("truncated segment")
+		}
+		length := int(binary.BigEndian.Uint16(data[i:]))
+		if length < 2 || i+length > len(data) {
+			return nil, fmt.Errorf("invalid segment length at offset %d", i)
+		}
+		segment := data[i : i+length]
+		i += length
+
+		isMetadata := (marker >= 0xE0 && marker <= 0xEF && !keptJPEGSegments[marker]) || marker == 0xFE
+		if !isMetadata {
+			out.Write([]byte{0xFF, marker})
+			out.Write(segment)
+		}
+
+		// Start of scan, the compressed image follows
+		if marker == 0xDA {
+			out.Write(data[i:])
+			return out.Bytes(), nil
+		}
+	}
+
+	return nil, errors.New("missing image data")
+}
+
+// stripPNGMetadata copies a PNG without its text, EXIF and timestamp chunks. Chunks are copied
+// whole, so their checksums stay valid.
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errors.New("missing PNG signature")
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+
+	for i := len(pngSignature); i < len(data); {
+		if i+8 > len(data) {
+			return nil, errors.New("truncated chunk")
+		}
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		chunkType := string(data[i+4 : i+8])
+		end := i + 12 + length
+		if length < 0 || end > len(data) || end < i {
+			return nil, fmt.Errorf("invalid %s chunk length", chunkType)
+		}
+
+		if !droppedPNGChunks[chunkType] {
+			out.Write(data[i:end])
+		}
+		i = end
+
+		if chunkType == "IEND" {
+			return out.Bytes(), nil
+		}
+	}
+
+	return nil, errors.New("missing IEND chunk")
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testImage is a small image with a few colours, so the encoded data isn't trivial
+func testImage() image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, 8, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 8; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 30), G: uint8(y * 40), B: 120, A: 255})
+		}
+	}
+	return img
+}
+
+// jpegSegment builds a JPEG marker segment with its length
+func jpegSegment(marker byte, payload []byte) []byte {
+	segment := []byte{0xFF, marker, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+// jpegWithSegments encodes the test image and inserts the segments right after the start of image
+func jpegWithSegments(t *testing.T, segments ...[]byte) []byte {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, testImage(), nil); err != nil {
+		t.Fatalf("failed to encode JPEG: %v", err)
+	}
+	encoded := buf.Bytes()
+
+	out := append([]byte{}, encoded[:2]...)
+	for _, segment := range segments {
+		out = append(out, segment...)
+	}
+	return append(out, encoded[2:]...)
+}
+
+// pngChunk builds a PNG chunk with its length and checksum
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := make([]byte, 8, 12+len(data))
+	binary.BigEndian.PutUint32(chunk, uint32(len(data)))
+	copy(chunk[4:], chunkType)
+	chunk = append(chunk, data...)
+	sum := make([]byte, 4)
+	binary.BigEndian.PutUint32(sum, crc32.ChecksumIEEE(chunk[4:]))
+	return append(chunk, sum...)
+}
+
+// pngWithChunks encodes the test image and inserts the chunks right after the IHDR chunk
+func pngWithChunks(t *testing.T, chunks ...[]byte) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, testImage()); err != nil {
+		t.Fatalf("failed to encode PNG: %v", err)
+	}
+	encoded := buf.Bytes()
+
+	// The signature is followed by the 13 byte IHDR chunk, 25 bytes with length, type and checksum
+	headerEnd := len(pngSignature) + 25
+	out := append([]byte{}, encoded[:headerEnd]...)
+	for _, chunk := range chunks {
+		out = append(out, chunk...)
+	}
+	return append(out, encoded[headerEnd:]...)
+}
+
+// exifGPS is an APP1 payload with a GPS tag, the coordinates aren't parsed so they can be any bytes
+var exifGPS = append([]byte("Exif\x00\x00"), []byte("MM\x00\x2aGPSLatitude=45.4215,GPSLongitude=-75.6972")...)
+
+func TestStripJPEGMetadata(t *testing.T) {
+	iccProfile := append([]byte("ICC_PROFILE\x00"), []byte("profile-data")...)
+
+	tests := []struct {
+		name    string
+		input   []byte
+		removed [][]byte
+		kept    [][]byte
+	}{
+		{
+			name:    "EXIF with GPS",
+			input:   jpegWithSegments(t, jpegSegment(0xE1, exifGPS)),
+			removed: [][]byte{[]byte("Exif"), []byte("GPSLatitude")},
+		},
+		{
+			name: "XMP, IPTC and comment",
+			input: jpegWithSegments(t,
+				jpegSegment(0xE1, []byte("http://ns.adobe.com/xap/1.0/\x00<x:xmpmeta>author</x:xmpmeta>")),
+				jpegSegment(0xED, []byte("Photoshop 3.0\x00iptc-byline")),
+				jpegSegment(0xFE, []byte("taken by the reporter"))),
+			removed: [][]byte{[]byte("xmpmeta"), []byte("iptc-byline"), []byte("taken by the reporter")},
+		},
+		{
+			name:    "ICC profile is kept",
+			input:   jpegWithSegments(t, jpegSegment(0xE2, iccProfile), jpegSegment(0xE1, exifGPS)),
+			removed: [][]byte{[]byte("GPSLatitude")},
+			kept:    [][]byte{iccProfile},
+		},
+		{
+			name:  "no metadata",
+			input: jpegWithSegments(t),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, err := stripJPEGMetadata(tt.input)
+			if err != nil {
+				t.Fatalf("stripJPEGMetadata returned an error: %v", err)
+			}
+
+			for _, value := range tt.removed {
+				if bytes.Contains(cleaned, value) {
+					t.Errorf("cleaned JPEG still contains %q", value)
+				}
+			}
+			for _, value := range tt.kept {
+				if !bytes.Contains(cleaned, value) {
+					t.Errorf("cleaned JPEG lost %q", value)
+				}
+			}
+
+			img, err := jpeg.Decode(bytes.NewReader(cleaned))
+			if err != nil {
+				t.Fatalf("cleaned JPEG doesn't decode: %v", err)
+			}
+			if img.Bounds() != testImage().Bounds() {
+				t.Errorf("cleaned JPEG bounds = %v, want %v", img.Bounds(), testImage().Bounds())
+			}
+		})
+	}
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		removed [][]byte
+		kept    [][]byte
+	}{
+		{
+			name:    "eXIf with GPS",
+			input:   pngWithChunks(t, pngChunk("eXIf", exifGPS[6:])),
+			removed: [][]byte{[]byte("eXIf"), []byte("GPSLatitude")},
+		},
+		{
+			name: "text and timestamp",
+			input: pngWithChunks(t,
+				pngChunk("tEXt", []byte("Author\x00the reporter")),
+				pngChunk("iTXt", []byte("Comment\x00\x00\x00\x00\x00taken at work")),
+				pngChunk("zTXt", []byte("Software\x00\x00compressed")),
+				pngChunk("tIME", []byte{0x07, 0xE8, 1, 2, 3, 4, 5})),
+			removed: [][]byte{[]byte("tEXt"), []byte("the reporter"), []byte("iTXt"), []byte("zTXt"), []byte("tIME")},
+		},
+		{
+			name:    "gamma is kept",
+			input:   pngWithChunks(t, pngChunk("gAMA", []byte{0, 0, 0xB1, 0x8F}), pngChunk("tEXt", []byte("Author\x00someone"))),
+			removed: [][]byte{[]byte("someone")},
+			kept:    [][]byte{[]byte("gAMA")},
+		},
+		{
+			name:  "no metadata",
+			input: pngWithChunks(t),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cleaned, err := stripPNGMetadata(tt.input)
+			if err != nil {
+				t.Fatalf("stripPNGMetadata returned an error: %v", err)
+			}
+
+			for _, value := range tt.removed {
+				if bytes.Contains(cleaned, value) {
+					t.Errorf("cleaned PNG still contains %q", value)
+				}
+			}
+			for _, value := range tt.kept {
+				if !bytes.Contains(cleaned, value) {
+					t.Errorf("cleaned PNG lost %q", value)
+				}
+			}
+
+			img, err := png.Decode(bytes.NewReader(cleaned))
+			if err != nil {
+				t.Fatalf("cleaned PNG doesn't decode: %v", err)
+			}
+			if img.Bounds() != testImage().Bounds() {
+				t.Errorf("cleaned PNG bounds = %v, want %v", img.Bounds(), testImage().Bounds())
+			}
+		})
+	}
+}
+
+func TestSanitizeAttachment(t *testing.T) {
+	jpegData := jpegWithSegments(t, jpegSegment(0xE1, exifGPS))
+	pngData := pngWithChunks(t, pngChunk("tEXt", []byte("Author\x00the reporter")))
+	pdfData := []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\ntrailer\n<< /Root 1 0 R >>\n%%EOF\n")
+
+	tests := []struct {
+		name          string
+		input         []byte
+		contentType   string
+		stripped      bool
+		unsupported   bool
+		wantUnchanged bool
+	}{
+		{name: "JPEG", input: jpegData, contentType: "image/jpeg", stripped: true},
+		{name: "PNG", input: pngData, contentType: "image/png", stripped: true},
+		// PDFs keep their metadata, they are reported as not stripped
+		{name: "PDF", input: pdfData, contentType: "application/pdf", wantUnchanged: true},
+		{name: "PDF without end of file marker", input: []byte("%PDF-1.4\n1 0 obj\n"), unsupported: true},
+		{name: "truncated JPEG", input: jpegData[:len(jpegData)/3], unsupported: true},
+		{name: "truncated PNG", input: pngData[:40], unsupported: true},
+		{name: "HTML", input: []byte("<html><script>alert(1)</script></html>"), unsupported: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, cleaned, stripped, err := sanitizeAttachment(tt.input)
+			if tt.unsupported {
+				if !errors.Is(err, ErrUnsupportedAttachment) {
+					t.Fatalf("expected ErrUnsupportedAttachment, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("sanitizeAttachment returned an error: %v", err)
+			}
+
+			if contentType != tt.contentType {
+				t.Errorf("content type = %q, want %q", contentType, tt.contentType)
+			}
+			if stripped != tt.stripped {
+				t.Errorf("stripped = %v, want %v", stripped, tt.stripped)
+			}
+			if tt.wantUnchanged && !bytes.Equal(cleaned, tt.input) {
+				t.Errorf("expected the file to be kept as is")
+			}
+			if bytes.Contains(cleaned, []byte("GPSLatitude")) || bytes.Contains(cleaned, []byte("the reporter")) {
+				t.Errorf("cleaned file still contains metadata")
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Storage backends that can be set in ATTACHMENT_STORAGE
+const (
+	AttachmentStorageLocal = "local"
+	AttachmentStorageS3    = "s3"
+)
+
+// Directory of the local backend when ATTACHMENT_DIR is not set
+const defaultAttachmentDir = "./data/attachments"
+
+// ErrAttachmentNotStored is returned by a storage backend when it has no file under a key
+var ErrAttachmentNotStored = errors.New("attachment not found in storage")
+
+// AttachmentStorage keeps attachment files under keys made of letters, digits, dashes, dots and
+// slashes, e.g. "reports/<report id>/<attachment id>.jpg"
+type AttachmentStorage interface {
+	Name() string
+	Put(key, contentType string, data []byte) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// NewAttachmentStorageFromConfig creates the backend named in ATTACHMENT_STORAGE, local disk by
+// default
+func NewAttachmentStorageFromConfig() (AttachmentStorage, error) {
+	backend := strings.ToLower(strings.TrimSpace(os.Getenv("ATTACHMENT_STORAGE")))
+	switch backend {
+	case "", AttachmentStorageLocal:
+		dir := os.Getenv("ATTACHMENT_DIR")
+		if dir == "" {
+			dir = defaultAttachmentDir
+		}
+		return NewLocalAttachmentStorage(dir)
+	case AttachmentStorageS3:
+		return NewS3AttachmentStorage(S3StorageConfig{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			// Most self-hosted S3 compatible services only support path style URLs
+			PathStyle: os.Getenv("S3_PATH_STYLE") != "false",
+		})
+	default:
+		return nil, fmt.Errorf("unknown attachment storage: %s", backend)
+	}
+}
+
+// validStorageKey reports whether a key is safe to use as a file path and in a URL
+func validStorageKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "..") {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalAttachmentStorage keeps attachments in a directory on disk
+type LocalAttachmentStorage struct {
+	dir string
+}
+
+// NewLocalAttachmentStorage creates the directory if needed
+func NewLocalAttachmentStorage(dir string) (*LocalAttachmentStorage, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create attachment directory %s: %w", dir, err)
+	}
+	return &LocalAttachmentStorage{dir: dir}, nil
+}
+
+func (s *LocalAttachmentStorage) Name() string {
+	return AttachmentStorageLocal
+}
+
+func (s *LocalAttachmentStorage) path(key string) (string, error) {
+	if !validStorageKey(key) {
+		return "", fmt.Errorf("invalid storage key: %s", key)
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put writes the file next to its final path first so readers never see a partial file
+func (s *LocalAttachmentStorage) Put(key, contentType string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create attachment directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create attachment file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write attachment file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write attachment file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store attachment file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *LocalAttachmentStorage) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrAttachmentNotStored
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment file: %w", err)
+	}
+
+	return file, nil
+}
+
+// Delete succeeds when the file is already gone
+func (s *LocalAttachmentStorage) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete attachment file: %w", err)
+	}
+	return nil
+}
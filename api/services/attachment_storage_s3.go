@@ -0,0 +1,195 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Hash of an empty request body, signed on GET and DELETE requests
+const emptyPayloadSHA256 = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3StorageConfig configures an S3 compatible backend, e.g. AWS S3, MinIO or Cloudflare R2
+type S3StorageConfig struct {
+	Endpoint        string // e.g. https://s3.ca-central-1.amazonaws.com or http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // endpoint/bucket/key rather than bucket.endpoint/key
+}
+
+// S3AttachmentStorage keeps attachments in an S3 compatible bucket. Requests are signed with AWS
+// Signature Version 4.
+type S3AttachmentStorage struct {
+	config   S3StorageConfig
+	endpoint *url.URL
+	client   *http.Client
+}
+
+func NewS3AttachmentStorage(config S3StorageConfig) (*S3AttachmentStorage, error) {
+	if config.Endpoint == "" || config.Bucket == "" || config.AccessKeyID == "" || config.SecretAccessKey == "" {
+		return nil, fmt.Errorf("s3 attachment storage requires S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid S3_ENDPOINT: %s", config.Endpoint)
+	}
+
+	return &S3AttachmentStorage{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (s *S3AttachmentStorage) Name() string {
+	return AttachmentStorageS3
+}
+
+func (s *S3AttachmentStorage) Put(key, contentType string, data []byte) error {
+	sum := sha256.Sum256(data)
+	resp, err := s.do(http.MethodPut, key, data, hex.EncodeToString(sum[:]), contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError("upload", key, resp)
+	}
+	return nil
+}
+
+func (s *S3AttachmentStorage) Get(key string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, key, nil, emptyPayloadSHA256, "")
+	if err != nil {
+		return nil, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrAttachmentNotStored
+	default:
+		defer resp.Body.Close()
+		return nil, s.responseError("download", key, resp)
+	}
+}
+
+// Delete succeeds when the object is already gone, S3 answers 204 either way
+func (s *S3AttachmentStorage) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, key, nil, emptyPayloadSHA256, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s.responseError("delete", key, resp)
+	}
+	return nil
+}
+
+func (s *S3AttachmentStorage) responseError(action, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("failed to %s attachment %s: s3 returned %d: %s", action, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// objectURL returns the URL of an object in path or virtual hosted style
+func (s *S3AttachmentStorage) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	if s.config.PathStyle {
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + s.config.Bucket + "/" + key
+	} else {
+		u.Host = s.config.Bucket + "." + u.Host
+		u.Path = strings.TrimRight(u.Path, "/") + "/" + key
+	}
+	return &u
+}
+
+func (s *S3AttachmentStorage) do(method, key string, body []byte, payloadHash, contentType string) (*http.Response, error) {
+	if !validStorageKey(key) {
+		return nil, fmt.Errorf("invalid storage key: %s", key)
+	}
+
+	req, err := http.NewRequest(method, s.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 request: %w", err)
+	}
+	if body == nil {
+		req.Body = http.NoBody
+	}
+	req.ContentLength = int64(len(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.sign(req, payloadHash, time.Now().UTC())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds the Signature Version 4 authorization header. Storage keys only contain characters
+// that don't need escaping, so the request path is already canonical.
+func (s *S3AttachmentStorage) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signedHeaders = "content-type;" + signedHeaders
+		canonicalHeaders = "content-type:" + contentType + "\n" + canonicalHeaders
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Limits on boycott campaigns
+const (
+	maxCampaignTargets     = 50
+	maxCampaignGoal        = 1000000
+	maxCampaignDuration    = 365 * 24 * time.Hour
+	maxCampaignDescription = 5000
+)
+
+var (
+	// ErrInvalidCampaign is returned for a campaign that can't be created
+	ErrInvalidCampaign = errors.New("invalid boycott campaign")
+	// ErrCampaignNotAllowed is returned when a user may not create or manage a campaign
+	ErrCampaignNotAllowed = errors.New("only admins and trusted users can create boycott campaigns")
+	// ErrCampaignClosed is returned when pledging to a campaign that closed or ended
+	ErrCampaignClosed = errors.New("boycott campaign is no longer taking pledges")
+)
+
+// CampaignTarget is a business targeted by a new campaign
+type CampaignTarget struct {
+	BusinessName    string `json:"business_name"`
+	BusinessAddress string `json:"business_address"`
+}
+
+// CreateCampaignRequest creates a boycott campaign against businesses or a brand
+type CreateCampaignRequest struct {
+	Creator     *models.User
+	Title       string
+	Description string
+	TargetType  string  // businesses or brand
+	BrandName   *string // Required for brand campaigns
+	Targets     []CampaignTarget
+	GoalPledges int
+	EndsAt      time.Time
+}
+
+type BoycottCampaignService interface {
+	CreateCampaign(req *CreateCampaignRequest) (*models.BoycottCampaign, error)
+	GetCampaign(id string, viewer *models.User) (*models.BoycottCampaign, error)
+	ListCampaigns(status string, viewer *models.User, limit, offset int) ([]*models.BoycottCampaign, int, error)
+	GetUserCampaigns(userID string, limit, offset int) ([]*models.BoycottCampaign, error)
+	CloseCampaign(id string, user *models.User) (*models.BoycottCampaign, error)
+	Pledge(id string, user *models.User) (*models.BoycottCampaign, []*models.BoycottCampaignMilestone, error)
+	WithdrawPledge(id string, user *models.User) (*models.BoycottCampaign, error)
+	GetCampaignStats(id string) (*models.BoycottCampaignStats, error)
+}
+
+type boycottCampaignService struct {
+	repo repos.BoycottCampaignRepository
+}
+
+func NewBoycottCampaignService(repo repos.BoycottCampaignRepository) BoycottCampaignService {
+	return &boycottCampaignService{repo: repo}
+}
+
+// canCreateCampaigns reports whether a user may create campaigns
+func canCreateCampaigns(user *models.User) bool {
+	return user.IsAdmin() || user.VerificationTier == models.VerificationTrusted
+}
+
+// CreateCampaign creates a campaign. Only admins and trusted users can create campaigns.
+func (s *boycottCampaignService) CreateCampaign(req *CreateCampaignRequest) (*models.BoycottCampaign, error) {
+	if req.Creator == nil {
+		return nil, fmt.Errorf("creator is required")
+	}
+	if !canCreateCampaigns(req.Creator) {
+		return nil, ErrCampaignNotAllowed
+	}
+
+	campaign := &models.BoycottCampaign{
+		Title:       strings.TrimSpace(req.Title),
+		Description: strings.TrimSpace(req.Description),
+		TargetType:  req.TargetType,
+		GoalPledges: req.GoalPledges,
+		EndsAt:      req.EndsAt.UTC(),
+		CreatedBy:   &req.Creator.ID,
+	}
+
+	if len(campaign.Title) < 3 || len(campaign.Title) > 200 {
+		return nil, fmt.Errorf("%w: title must be between 3 and 200 characters", ErrInvalidCampaign)
+	}
+	if campaign.Description == "" || len(campaign.Description) > maxCampaignDescription {
+		return nil, fmt.Errorf("%w: description is required, up to %d characters", ErrInvalidCampaign, maxCampaignDescription)
+	}
+	if campaign.GoalPledges < 1 || campaign.GoalPledges > maxCampaignGoal {
+		return nil, fmt.Errorf("%w: goal must be between 1 and %d pledges", ErrInvalidCampaign, maxCampaignGoal)
+	}
+	now := time.Now().UTC()
+	if !campaign.EndsAt.After(now) || campaign.EndsAt.Sub(now) > maxCampaignDuration {
+		return nil, fmt.Errorf("%w: end date must be in the next year", ErrInvalidCampaign)
+	}
+
+	switch req.TargetType {
+	case models.CampaignTargetBrand:
+		if req.BrandName == nil || strings.TrimSpace(*req.BrandName) == "" {
+			return nil, fmt.Errorf("%w: brand name is required", ErrInvalidCampaign)
+		}
+		brandName := strings.TrimSpace(*req.BrandName)
+		campaign.BrandName = &brandName
+	case models.CampaignTargetBusinesses:
+		for _, target := range req.Targets {
+			name := strings.TrimSpace(target.BusinessName)
+			if name == "" {
+				return nil, fmt.Errorf("%w: every target needs a business name", ErrInvalidCampaign)
+			}
+			address := strings.TrimSpace(target.BusinessAddress)
+			duplicate := slices.ContainsFunc(campaign.Targets, func(t *models.BoycottCampaignTarget) bool {
+				return t.BusinessName == name && t.BusinessAddress == address
+			})
+			if !duplicate {
+				campaign.Targets = append(campaign.Targets, &models.BoycottCampaignTarget{BusinessName: name, BusinessAddress: address})
+			}
+		}
+		if len(campaign.Targets) == 0 || len(campaign.Targets) > maxCampaignTargets {
+			return nil, fmt.Errorf("%w: between 1 and %d target businesses are required", ErrInvalidCampaign, maxCampaignTargets)
+		}
+	default:
+		return nil, fmt.Errorf("%w: target type must be 'businesses' or 'brand'", ErrInvalidCampaign)
+	}
+
+	if err := s.repo.Create(campaign); err != nil {
+		return nil, fmt.Errorf("failed to create boycott campaign: %w", err)
+	}
+	if campaign.Targets == nil {
+		campaign.Targets = []*models.BoycottCampaignTarget{}
+	}
+
+	log.Info("Boycott campaign created", "campaign_id", campaign.ID, "user_id", req.Creator.ID, "target_type", campaign.TargetType)
+
+	return campaign, nil
+}
+
+// GetCampaign returns a campaign with its targets, and whether the viewer pledged to it
+func (s *boycottCampaignService) GetCampaign(id string, viewer *models.User) (*models.BoycottCampaign, error) {
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign: %w", err)
+	}
+
+	if err := s.attachDetails([]*models.BoycottCampaign{campaign}, viewer); err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns returns campaigns, newest first, and their total. Status is "active", "ended" or
+// empty for every campaign.
+func (s *boycottCampaignService) ListCampaigns(status string, viewer *models.User, limit, offset int) ([]*models.BoycottCampaign, int, error) {
+	if status != "" && status != "active" && status != "ended" {
+		return nil, 0, fmt.Errorf("%w: status must be 'active' or 'ended'", ErrInvalidCampaign)
+	}
+	limit, offset = campaignPage(limit, offset)
+
+	campaigns, total, err := s.repo.List(status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.attachDetails(campaigns, viewer); err != nil {
+		return nil, 0, err
+	}
+
+	return campaigns, total, nil
+}
+
+// GetUserCampaigns returns the campaigns a user currently pledges to
+func (s *boycottCampaignService) GetUserCampaigns(userID string, limit, offset int) ([]*models.BoycottCampaign, error) {
+	limit, offset = campaignPage(limit, offset)
+
+	campaigns, err := s.repo.GetByPledger(userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	pledged := true
+	for _, campaign := range campaigns {
+		campaign.Pledged = &pledged
+	}
+	if err := s.attachDetails(campaigns, nil); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// CloseCampaign stops a campaign from taking pledges before its end date. Its creator and admins
+// can close it.
+func (s *boycottCampaignService) CloseCampaign(id string, user *models.User) (*models.BoycottCampaign, error) {
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign: %w", err)
+	}
+	if !user.IsAdmin() && (campaign.CreatedBy == nil || *campaign.CreatedBy != user.ID) {
+		return nil, ErrCampaignNotAllowed
+	}
+
+	if err := s.repo.Close(id); err != nil {
+		return nil, err
+	}
+
+	log.Info("Boycott campaign closed", "campaign_id", id, "user_id", user.ID)
+
+	return s.GetCampaign(id, user)
+}
+
+// Pledge adds a user's pledge to an open campaign and returns the milestones it made the
+// campaign reach
+func (s *boycottCampaignService) Pledge(id string, user *models.User) (*models.BoycottCampaign, []*models.BoycottCampaignMilestone, error) {
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get boycott campaign: %w", err)
+	}
+	if !campaign.IsOpen(time.Now()) {
+		return nil, nil, ErrCampaignClosed
+	}
+
+	campaign, reached, err := s.repo.Pledge(id, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, milestone := range reached {
+		log.Info("Boycott campaign reached a milestone", "campaign_id", id, "percent", milestone.Percent, "pledges", campaign.PledgeCount)
+	}
+
+	pledged := true
+	campaign.Pledged = &pledged
+	if err := s.attachDetails([]*models.BoycottCampaign{campaign}, nil); err != nil {
+		return nil, nil, err
+	}
+
+	return campaign, reached, nil
+}
+
+// WithdrawPledge withdraws a user's pledge. It can be withdrawn after the campaign ended, the
+// campaign's history keeps it.
+func (s *boycottCampaignService) WithdrawPledge(id string, user *models.User) (*models.BoycottCampaign, error) {
+	if _, err := s.repo.Withdraw(id, user.ID); err != nil {
+		return nil, err
+	}
+
+	return s.GetCampaign(id, user)
+}
+
+// GetCampaignStats returns a campaign's progress towards its goal, with its daily pledges since
+// it started, up to a year
+func (s *boycottCampaignService) GetCampaignStats(id string) (*models.BoycottCampaignStats, error) {
+	campaign, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boycott campaign: %w", err)
+	}
+
+	stats := &models.BoycottCampaignStats{
+		CampaignID:    campaign.ID,
+		PledgeCount:   campaign.PledgeCount,
+		GoalPledges:   campaign.GoalPledges,
+		PercentOfGoal: math.Round(float64(campaign.PledgeCount)/float64(campaign.GoalPledges)*1000) / 10,
+		GoalReached:   campaign.PledgeCount >= campaign.GoalPledges,
+	}
+
+	now := time.Now().UTC()
+	if campaign.IsOpen(now) {
+		stats.DaysRemaining = int(math.Ceil(campaign.EndsAt.Sub(now).Hours() / 24))
+	}
+
+	if stats.TotalPledges, stats.WithdrawnCount, err = s.repo.GetPledgeTotals(id); err != nil {
+		return nil, err
+	}
+
+	if stats.Milestones, err = s.repo.GetMilestones(id); err != nil {
+		return nil, err
+	}
+	if stats.Milestones == nil {
+		stats.Milestones = []*models.BoycottCampaignMilestone{}
+	}
+	for _, percent := range models.CampaignMilestonePercents {
+		if campaign.PledgeCount < campaign.MilestoneThreshold(percent) {
+			stats.NextMilestone = &percent
+			break
+		}
+	}
+
+	to := now
+	if campaign.EndsAt.Before(to) {
+		to = campaign.EndsAt
+	}
+	from := campaign.CreatedAt
+	if to.Sub(from) > maxCampaignDuration {
+		from = to.Add(-maxCampaignDuration)
+	}
+	if stats.DailyProgress, err = s.repo.GetDailyProgress(id, from, to); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// attachDetails adds their targets to campaigns, and whether the viewer pledged to them
+func (s *boycottCampaignService) attachDetails(campaigns []*models.BoycottCampaign, viewer *models.User) error {
+	ids := make([]string, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		ids = append(ids, campaign.ID)
+	}
+
+	targets, err := s.repo.GetTargets(ids)
+	if err != nil {
+		return err
+	}
+
+	var pledgedIDs []string
+	if viewer != nil {
+		if pledgedIDs, err = s.repo.GetPledgedCampaignIDs(viewer.ID, ids); err != nil {
+			return err
+		}
+	}
+
+	for _, campaign := range campaigns {
+		campaign.Targets = targets[campaign.ID]
+		if campaign.Targets == nil {
+			campaign.Targets = []*models.BoycottCampaignTarget{}
+		}
+		if viewer != nil {
+			pledged := slices.Contains(pledgedIDs, campaign.ID)
+			campaign.Pledged = &pledged
+		}
+	}
+
+	return nil
+}
+
+// campaignPage bounds the pagination of campaign lists
+func campaignPage(limit, offset int) (int, int) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
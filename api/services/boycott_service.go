@@ -3,10 +3,23 @@ package services
 import (
 	"canada-hires/models"
 	"canada-hires/repos"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
 )
 
+// Trending boycotts compare the last week with the week before it
+const trendingWindow = 7 * 24 * time.Hour
+
+// Longest period boycott trends cover
+const maxTrendDays = 366
+
+// ErrInvalidTrendQuery is returned for boycott trend parameters that aren't supported
+var ErrInvalidTrendQuery = errors.New("invalid boycott trend query")
+
 type ToggleBoycottRequest struct {
 	UserID          string `json:"user_id"`
 	BusinessName    string `json:"business_name"`
@@ -19,14 +32,23 @@ type BoycottService interface {
 	GetTopBoycottedBusinesses(limit int) ([]*models.BoycottStats, error)
 	GetBoycottCount(businessName, businessAddress string) (int, error)
 	IsBoycottedByUser(userID, businessName, businessAddress string) (bool, error)
+
+	// Trends
+	GetBoycottActivity(businessName, businessAddress, interval string, days int) ([]*models.BoycottActivityPoint, error)
+	GetTrendingBoycotts(limit int) ([]*models.TrendingBoycott, error)
+	GetRegionalBoycotts(groupBy, provinceCode string, days int) ([]*models.BoycottRegionStats, error)
 }
 
 type boycottService struct {
-	repo repos.BoycottRepository
+	repo           repos.BoycottRepository
+	addressService AddressService
 }
 
-func NewBoycottService(repo repos.BoycottRepository) BoycottService {
-	return &boycottService{repo: repo}
+func NewBoycottService(repo repos.BoycottRepository, addressService AddressService) BoycottService {
+	return &boycottService{
+		repo:           repo,
+		addressService: addressService,
+	}
 }
 
 func (s *boycottService) ToggleBoycott(req *ToggleBoycottRequest) (*models.Boycott, error) {
@@ -42,6 +64,13 @@ func (s *boycottService) ToggleBoycott(req *ToggleBoycottRequest) (*models.Boyco
 		return nil, fmt.Errorf("failed to toggle boycott: %w", err)
 	}
 
+	// A boycott was created, the address can be parsed again later if this fails
+	if boycott != nil {
+		if err := s.addressService.LinkAddress(models.AddressSourceBoycotts, businessAddress); err != nil {
+			log.Warn("Failed to parse boycott address", "boycott_id", boycott.ID, "error", err)
+		}
+	}
+
 	return boycott, nil
 }
 
@@ -120,4 +149,72 @@ func (s *boycottService) IsBoycottedByUser(userID, businessName, businessAddress
 	}
 
 	return isBoycotted, nil
-}
\ No newline at end of file
+}
+
+// GetBoycottActivity returns the daily or weekly new boycotts and cancellations over the last
+// days, for one business or every business when the name is empty
+func (s *boycottService) GetBoycottActivity(businessName, businessAddress, interval string, days int) ([]*models.BoycottActivityPoint, error) {
+	if interval == "" {
+		interval = "day"
+	}
+	if interval != "day" && interval != "week" {
+		return nil, fmt.Errorf("%w: interval must be 'day' or 'week'", ErrInvalidTrendQuery)
+	}
+	if days <= 0 {
+		days = 30
+	}
+	if days > maxTrendDays {
+		return nil, fmt.Errorf("%w: at most %d days are available", ErrInvalidTrendQuery, maxTrendDays)
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days+1)
+	points, err := s.repo.GetActivity(strings.TrimSpace(businessName), strings.TrimSpace(businessAddress), interval, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get boycott activity: %w", err)
+	}
+
+	return points, nil
+}
+
+// GetTrendingBoycotts returns the businesses gaining the most boycotts this week
+func (s *boycottService) GetTrendingBoycotts(limit int) ([]*models.TrendingBoycott, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	since := time.Now().UTC().Add(-trendingWindow)
+	trending, err := s.repo.GetTrending(since, since.Add(-trendingWindow), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trending boycotts: %w", err)
+	}
+
+	return trending, nil
+}
+
+// GetRegionalBoycotts rolls up boycotts by province, or by municipality, with the new boycotts and
+// cancellations of the last days
+func (s *boycottService) GetRegionalBoycotts(groupBy, provinceCode string, days int) ([]*models.BoycottRegionStats, error) {
+	if groupBy == "" {
+		groupBy = "province"
+	}
+	if groupBy != "province" && groupBy != "municipality" {
+		return nil, fmt.Errorf("%w: group_by must be 'province' or 'municipality'", ErrInvalidTrendQuery)
+	}
+	if days <= 0 {
+		days = 30
+	}
+	if days > maxTrendDays {
+		return nil, fmt.Errorf("%w: at most %d days are available", ErrInvalidTrendQuery, maxTrendDays)
+	}
+
+	since := time.Now().UTC().AddDate(0, 0, -days)
+	stats, err := s.repo.GetRegionalStats(groupBy, strings.ToUpper(strings.TrimSpace(provinceCode)), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get regional boycotts: %w", err)
+	}
+
+	return stats, nil
+}
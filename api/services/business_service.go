@@ -1,13 +1,425 @@
 package services
 
-import "canada-hires/repos"
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
-type BusinessService interface{}
+	"github.com/charmbracelet/log"
+	"github.com/lib/pq"
+)
 
-type businessService struct{
-	repo repos.BusinessRepository
+// How long a business claim verification link can be used
+const businessClaimTokenDuration = 24 * time.Hour
+
+// Longest business reply or statement
+const maxBusinessReplyLength = 2000
+
+// Lowest verification tier that can claim a business, unless BUSINESS_CLAIM_MIN_TIER is set
+const defaultClaimMinTier = models.VerificationEnhanced
+
+// Moderation actions on business replies and the status they give a reply
+var businessReplyActions = map[string]string{
+	"publish": models.BusinessReplyPublished,
+	"reject":  models.BusinessReplyRejected,
+}
+
+// Free and ISP webmail domains, which anyone can get an address at, so they don't prove a user
+// works for a business
+var freeEmailDomains = []string{
+	"gmail.com", "googlemail.com", "outlook.com", "hotmail.com", "hotmail.ca", "live.com", "live.ca",
+	"msn.com", "yahoo.com", "yahoo.ca", "icloud.com", "me.com", "mac.com", "aol.com", "gmx.com",
+	"mail.com", "proton.me", "protonmail.com", "yandex.com", "zoho.com",
+	"shaw.ca", "rogers.com", "sympatico.ca", "bell.net", "telus.net", "videotron.ca", "cogeco.ca",
+	"eastlink.ca", "sasktel.net",
+}
+
+var (
+	// ErrInvalidClaim is returned for a claim that can't be started, e.g. with a webmail address
+	ErrInvalidClaim = errors.New("invalid business claim")
+	// ErrInvalidClaimToken is returned for verification links that are unknown, expired or used
+	ErrInvalidClaimToken = errors.New("invalid or expired verification link")
+	// ErrBusinessAlreadyClaimed is returned when another user already verified the business
+	ErrBusinessAlreadyClaimed = errors.New("business is already claimed")
+	// ErrClaimAwaitingReview is returned when the user's claim on the business already waits for a moderator
+	ErrClaimAwaitingReview = errors.New("your claim on this business is awaiting review")
+	// ErrNotBusinessOwner is returned when a user replies for a business they haven't verified
+	ErrNotBusinessOwner = errors.New("you need a verified claim on this business")
+	// ErrInvalidBusinessReply is returned for an empty or too long reply, or an unknown action
+	ErrInvalidBusinessReply = errors.New("invalid business reply")
+	// ErrClaimNotEligible is returned when the user's verification tier is too low to claim a business
+	ErrClaimNotEligible = errors.New("your account isn't verified enough to claim a business yet")
+)
+
+// StartClaimRequest asks to represent a business
+type StartClaimRequest struct {
+	User            *models.User
+	BusinessName    string
+	BusinessAddress string
+	Email           string // Address at the business's own domain
+}
+
+type BusinessService interface {
+	// Claims
+	StartClaim(req *StartClaimRequest) (*models.BusinessClaim, error)
+	VerifyClaim(token string) (*models.BusinessClaim, error)
+	GetUserClaims(userID string) ([]*models.BusinessClaim, error)
+	GetClaimQueue(limit, offset int) ([]*models.BusinessClaim, int, error)
+	ApproveClaim(claimID, moderatorID string) (*models.BusinessClaim, error)
+	RevokeClaim(claimID, moderatorID string, reason *string) (*models.BusinessClaim, error)
+
+	// Replies
+	ReplyToReport(user *models.User, reportID, body string) (*models.BusinessReply, error)
+	SetStatement(user *models.User, claimID, body string) (*models.BusinessReply, error)
+	GetStatement(businessName, businessAddress string) (*models.BusinessReply, error)
+	GetReplyQueue(status string, limit, offset int) ([]*models.BusinessReply, int, error)
+	ModerateReply(replyID, action, moderatorID string, notes *string) (*models.BusinessReply, error)
+}
+
+type businessService struct {
+	repo          repos.BusinessRepository
+	reportService ReportService
+	emailService  EmailService
+
+	claimMinTier models.VerificationTier
+}
+
+func NewBusinessService(repo repos.BusinessRepository, reportService ReportService, emailService EmailService) BusinessService {
+	claimMinTier := defaultClaimMinTier
+	if value := os.Getenv("BUSINESS_CLAIM_MIN_TIER"); value != "" {
+		switch tier := models.VerificationTier(strings.ToLower(strings.TrimSpace(value))); tier {
+		case models.VerificationBasic, models.VerificationEnhanced, models.VerificationTrusted:
+			claimMinTier = tier
+		default:
+			log.Error("Unknown verification tier in BUSINESS_CLAIM_MIN_TIER, using default", "tier", value, "default", defaultClaimMinTier)
+		}
+	}
+
+	return &businessService{
+		repo:          repo,
+		reportService: reportService,
+		emailService:  emailService,
+		claimMinTier:  claimMinTier,
+	}
 }
 
-func NewBusinessService(repo repos.BusinessRepository) BusinessService {
-	return &businessService{repo: repo}
+// StartClaim records a claim and emails its verification link. Claiming a business again while
+// the first claim is pending sends a new link, one awaiting review has to be reviewed first.
+// Claims need an account of at least the configured verification tier, moderators can always claim.
+func (s *businessService) StartClaim(req *StartClaimRequest) (*models.BusinessClaim, error) {
+	if req.User == nil {
+		return nil, fmt.Errorf("user is required")
+	}
+	if !req.User.VerificationTier.AtLeast(s.claimMinTier) && !req.User.IsModerator() {
+		return nil, fmt.Errorf("%w: the %s tier is required", ErrClaimNotEligible, s.claimMinTier)
+	}
+
+	businessName := strings.TrimSpace(req.BusinessName)
+	businessAddress := strings.TrimSpace(req.BusinessAddress)
+	if businessName == "" || businessAddress == "" {
+		return nil, fmt.Errorf("%w: business name and address are required", ErrInvalidClaim)
+	}
+
+	email, domain, err := businessEmail(req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &models.BusinessClaim{
+		UserID:            req.User.ID,
+		BusinessName:      businessName,
+		NormalizedName:    models.NormalizeEmployerName(businessName),
+		BusinessAddress:   businessAddress,
+		NormalizedAddress: address.Normalize(businessAddress),
+		Email:             email,
+		EmailDomain:       domain,
+		TokenExpiresAt:    time.Now().UTC().Add(businessClaimTokenDuration),
+	}
+	if claim.NormalizedName == "" {
+		return nil, fmt.Errorf("%w: business name is required", ErrInvalidClaim)
+	}
+
+	if _, err := s.repo.GetVerifiedClaim(claim.NormalizedName, claim.NormalizedAddress); err == nil {
+		return nil, ErrBusinessAlreadyClaimed
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check business claims: %w", err)
+	}
+
+	claims, err := s.repo.GetClaimsByUserID(claim.UserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range claims {
+		if existing.Status == models.BusinessClaimAwaitingReview &&
+			existing.NormalizedName == claim.NormalizedName && existing.NormalizedAddress == claim.NormalizedAddress {
+			return nil, ErrClaimAwaitingReview
+		}
+	}
+
+	token, err := generateClaimToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+	claim.TokenHash = hashClaimToken(token)
+
+	if err := s.repo.SaveClaim(claim); err != nil {
+		return nil, err
+	}
+
+	if err := s.emailService.SendBusinessClaimLink(email, businessName, token); err != nil {
+		return nil, fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	log.Info("Business claim started", "claim_id", claim.ID, "user_id", claim.UserID, "domain", domain)
+
+	return claim, nil
+}
+
+// businessEmail validates a claim email address and returns it with its domain
+func businessEmail(value string) (string, string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(value))
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid email address", ErrInvalidClaim)
+	}
+
+	email := strings.ToLower(parsed.Address)
+	_, domain, _ := strings.Cut(email, "@")
+	if domain == "" || !strings.Contains(domain, ".") {
+		return "", "", fmt.Errorf("%w: invalid email address", ErrInvalidClaim)
+	}
+	if slices.Contains(freeEmailDomains, domain) {
+		return "", "", fmt.Errorf("%w: use an address at your business's own domain, not %s", ErrInvalidClaim, domain)
+	}
+
+	return email, domain, nil
+}
+
+func generateClaimToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// Only a hash of the token is stored, so the database alone can't be used to verify a claim
+func hashClaimToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyClaim confirms the email address of a claim from its emailed link. Receiving email at a
+// domain doesn't show the domain belongs to the business, so the claim then waits for a moderator
+// to approve it.
+func (s *businessService) VerifyClaim(token string) (*models.BusinessClaim, error) {
+	claim, err := s.repo.GetClaimByTokenHash(hashClaimToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidClaimToken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business claim: %w", err)
+	}
+	if claim.Status != models.BusinessClaimPending || claim.TokenExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidClaimToken
+	}
+
+	if _, err := s.repo.GetVerifiedClaim(claim.NormalizedName, claim.NormalizedAddress); err == nil {
+		return nil, ErrBusinessAlreadyClaimed
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check business claims: %w", err)
+	}
+
+	confirmed, err := s.repo.ConfirmClaimEmail(claim.ID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrClaimAwaitingReview
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrInvalidClaimToken
+		}
+		return nil, fmt.Errorf("failed to confirm business claim email: %w", err)
+	}
+
+	log.Info("Business claim email confirmed", "claim_id", confirmed.ID, "user_id", confirmed.UserID, "business", confirmed.BusinessName)
+
+	return confirmed, nil
+}
+
+func (s *businessService) GetUserClaims(userID string) ([]*models.BusinessClaim, error) {
+	return s.repo.GetClaimsByUserID(userID)
+}
+
+// GetClaimQueue returns the claims awaiting review, oldest first
+func (s *businessService) GetClaimQueue(limit, offset int) ([]*models.BusinessClaim, int, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.GetClaimQueue(limit, offset)
+}
+
+// ApproveClaim verifies a claim once a moderator has checked that its email domain belongs to the
+// business. Claims that shouldn't be approved are revoked.
+func (s *businessService) ApproveClaim(claimID, moderatorID string) (*models.BusinessClaim, error) {
+	claim, err := s.repo.ApproveClaim(claimID, moderatorID)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return nil, ErrBusinessAlreadyClaimed
+		}
+		return nil, fmt.Errorf("failed to approve business claim: %w", err)
+	}
+
+	log.Info("Business claim verified", "claim_id", claim.ID, "user_id", claim.UserID, "business", claim.BusinessName, "moderator_id", moderatorID)
+
+	return claim, nil
+}
+
+// RevokeClaim withdraws a claim, e.g. when the domain turns out not to belong to the business
+func (s *businessService) RevokeClaim(claimID, moderatorID string, reason *string) (*models.BusinessClaim, error) {
+	claim, err := s.repo.RevokeClaim(claimID, moderatorID, reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke business claim: %w", err)
+	}
+
+	log.Info("Business claim revoked", "claim_id", claimID, "moderator_id", moderatorID)
+
+	return claim, nil
+}
+
+// ReplyToReport posts or replaces the business's public response to a published report about it.
+// The response is shown once a moderator publishes it.
+func (s *businessService) ReplyToReport(user *models.User, reportID, body string) (*models.BusinessReply, error) {
+	body, err := validateBusinessReply(body)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := s.reportService.GetVisibleReport(reportID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := s.repo.GetVerifiedClaim(models.NormalizeEmployerName(report.BusinessName), address.Normalize(report.BusinessAddress))
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && claim.UserID != user.ID) {
+		return nil, ErrNotBusinessOwner
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business claim: %w", err)
+	}
+
+	reply := &models.BusinessReply{ClaimID: claim.ID, ReportID: &report.ID, Body: body}
+	if err := s.repo.SaveReply(reply); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReplyByID(reply.ID)
+}
+
+// SetStatement posts or replaces the general statement shown with a business's reports
+func (s *businessService) SetStatement(user *models.User, claimID, body string) (*models.BusinessReply, error) {
+	body, err := validateBusinessReply(body)
+	if err != nil {
+		return nil, err
+	}
+
+	claim, err := s.repo.GetClaimByID(claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get business claim: %w", err)
+	}
+	if claim.UserID != user.ID || claim.Status != models.BusinessClaimVerified {
+		return nil, ErrNotBusinessOwner
+	}
+
+	reply := &models.BusinessReply{ClaimID: claim.ID, Body: body}
+	if err := s.repo.SaveReply(reply); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetReplyByID(reply.ID)
+}
+
+func validateBusinessReply(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", fmt.Errorf("%w: body is required", ErrInvalidBusinessReply)
+	}
+	if len([]rune(body)) > maxBusinessReplyLength {
+		return "", fmt.Errorf("%w: body must be at most %d characters", ErrInvalidBusinessReply, maxBusinessReplyLength)
+	}
+	return body, nil
+}
+
+// GetStatement returns the published statement of a verified business
+func (s *businessService) GetStatement(businessName, businessAddress string) (*models.BusinessReply, error) {
+	statements, err := s.repo.GetPublishedStatements(
+		[]string{models.NormalizeEmployerName(businessName)},
+		[]string{address.Normalize(businessAddress)})
+	if err != nil {
+		return nil, err
+	}
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("failed to get business statement: %w", sql.ErrNoRows)
+	}
+	return statements[0], nil
+}
+
+// GetReplyQueue returns business replies in a status, pending by default, oldest first
+func (s *businessService) GetReplyQueue(status string, limit, offset int) ([]*models.BusinessReply, int, error) {
+	if status == "" {
+		status = models.BusinessReplyPending
+	}
+	if !slices.Contains(models.BusinessReplyStatuses, status) {
+		return nil, 0, fmt.Errorf("%w: unknown status %s", ErrInvalidBusinessReply, status)
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.GetReplyQueue(status, limit, offset)
+}
+
+// ModerateReply publishes or rejects a business reply
+func (s *businessService) ModerateReply(replyID, action, moderatorID string, notes *string) (*models.BusinessReply, error) {
+	status, ok := businessReplyActions[action]
+	if !ok {
+		return nil, fmt.Errorf("%w: action must be 'publish' or 'reject'", ErrInvalidBusinessReply)
+	}
+
+	reply, err := s.repo.ModerateReply(replyID, models.BusinessReplyModeration{
+		Status:      status,
+		ModeratorID: moderatorID,
+		Notes:       notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Business reply moderated", "reply_id", replyID, "status", status, "moderator_id", moderatorID)
+
+	return reply, nil
 }
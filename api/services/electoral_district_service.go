@@ -0,0 +1,284 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/geo"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/charmbracelet/log"
+)
+
+// Number of geocoded points assigned to electoral districts per batch
+const districtAssignBatchSize = 5000
+
+// ErrDistrictNotFound is returned when a point or postal code isn't in any electoral district
+var ErrDistrictNotFound = errors.New("no electoral district found")
+
+// Province of a federal electoral district, from the first two digits of its number, which are the
+// Statistics Canada code of the province
+var districtProvinceCodes = map[string]string{
+	"10": "NL", "11": "PE", "12": "NS", "13": "NB", "24": "QC", "35": "ON", "46": "MB",
+	"47": "SK", "48": "AB", "59": "BC", "60": "YT", "61": "NT", "62": "NU",
+}
+
+type ElectoralDistrictService interface {
+	ImportBoundaries(filePath string) (int, error)
+	AssignDistricts() (map[string]int, error)
+	ListDistricts(provinceCode string) ([]*models.ElectoralDistrict, error)
+	GetDistrict(id string) (*models.ElectoralDistrict, error)
+	GetSummary(id string, year int) (*models.ElectoralDistrictSummary, error)
+	LookupPostalCode(postalCode string) (*models.ElectoralDistrict, error)
+}
+
+type electoralDistrictService struct {
+	repo             repos.ElectoralDistrictRepository
+	geocodingService PostalCodeGeocodingService
+
+	// Boundaries are loaded once and kept until the next import
+	mu        sync.Mutex
+	districts []*models.ElectoralDistrict
+}
+
+func NewElectoralDistrictService(repo repos.ElectoralDistrictRepository, geocodingService PostalCodeGeocodingService) ElectoralDistrictService {
+	return &electoralDistrictService{
+		repo:             repo,
+		geocodingService: geocodingService,
+	}
+}
+
+// ImportBoundaries replaces the electoral districts with those of a boundary file, GeoJSON or an
+// ESRI shapefile in longitude and latitude, then assigns every geocoded point to its district.
+// Attribute names of the Elections Canada, Statistics Canada and Represent files are recognized.
+func (s *electoralDistrictService) ImportBoundaries(filePath string) (int, error) {
+	log.Info("Importing electoral district boundaries", "file_path", filePath)
+
+	boundaries, err := geo.ReadBoundaries(filePath)
+	if err != nil {
+		return 0, err
+	}
+
+	sourceFile := filepath.Base(filePath)
+	byID := make(map[string]*models.ElectoralDistrict)
+	var districts []*models.ElectoralDistrict
+	for i, boundary := range boundaries {
+		id := boundary.Property("FED_NUM", "FEDNUM", "FEDUID", "FED_CODE", "external_id", "id")
+		name := boundary.Property("ED_NAMEE", "ENNAME", "FEDENAME", "name_en", "FEDNAME", "name")
+		if id == "" || name == "" {
+			return 0, fmt.Errorf("boundary %d has no district number or name (properties: %v)", i, boundary.Properties)
+		}
+
+		// A district split over several features, e.g. one per island, is merged into one
+		if district, ok := byID[id]; ok {
+			district.Boundary = append(district.Boundary, boundary.Shape...)
+			setDistrictBounds(district)
+			continue
+		}
+
+		district := &models.ElectoralDistrict{
+			ID:         id,
+			NameEN:     name,
+			Boundary:   boundary.Shape,
+			SourceFile: &sourceFile,
+		}
+		if nameFR := boundary.Property("ED_NAMEF", "FRNAME", "FEDFNAME", "name_fr"); nameFR != "" {
+			district.NameFR = &nameFR
+		}
+		if len(id) >= 2 {
+			if provinceCode, ok := districtProvinceCodes[id[:2]]; ok {
+				district.ProvinceCode = &provinceCode
+			}
+		}
+		setDistrictBounds(district)
+
+		byID[id] = district
+		districts = append(districts, district)
+	}
+
+	if len(districts) == 0 {
+		return 0, fmt.Errorf("boundary file contains no polygons")
+	}
+
+	if err := s.repo.ReplaceAll(districts); err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.districts = nil
+	s.mu.Unlock()
+
+	log.Info("Electoral district boundaries imported", "districts", len(districts))
+
+	if _, err := s.AssignDistricts(); err != nil {
+		return len(districts), err
+	}
+
+	return len(districts), nil
+}
+
+func setDistrictBounds(district *models.ElectoralDistrict) {
+	bounds := district.Boundary.Bounds()
+	district.MinLatitude = bounds.MinLatitude
+	district.MinLongitude = bounds.MinLongitude
+	district.MaxLatitude = bounds.MaxLatitude
+	district.MaxLongitude = bounds.MaxLongitude
+}
+
+// loadDistricts returns the districts with their boundaries, reading them on first use
+func (s *electoralDistrictService) loadDistricts() ([]*models.ElectoralDistrict, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.districts == nil {
+		districts, err := s.repo.GetAllWithBoundaries()
+		if err != nil {
+			return nil, err
+		}
+		s.districts = districts
+	}
+
+	return s.districts, nil
+}
+
+// locateDistrict returns the district containing a point
+func locateDistrict(districts []*models.ElectoralDistrict, latitude, longitude float64) *models.ElectoralDistrict {
+	for _, district := range districts {
+		if latitude < district.MinLatitude || latitude > district.MaxLatitude ||
+			longitude < district.MinLongitude || longitude > district.MaxLongitude {
+			continue
+		}
+		if district.Boundary.Contains(latitude, longitude) {
+			return district
+		}
+	}
+	return nil
+}
+
+// AssignDistricts assigns the geocoded points without a district, postal codes, geocoded
+// addresses and job posting locations, to the district they fall in. Returns the number of
+// records assigned for each dataset.
+func (s *electoralDistrictService) AssignDistricts() (map[string]int, error) {
+	districts, err := s.loadDistricts()
+	if err != nil {
+		return nil, err
+	}
+
+	assigned := make(map[string]int)
+	if len(districts) == 0 {
+		log.Info("No electoral districts imported, skipping assignment")
+		return assigned, nil
+	}
+
+	for _, dataset := range models.DistrictDatasets {
+		total, outside := 0, 0
+		after := ""
+		for {
+			points, err := s.repo.GetUnassignedPoints(dataset, after, districtAssignBatchSize)
+			if err != nil {
+				return assigned, err
+			}
+			if len(points) == 0 {
+				break
+			}
+			after = points[len(points)-1].Key
+
+			assignments := make([]models.ElectoralDistrictAssignment, 0, len(points))
+			for _, point := range points {
+				if district := locateDistrict(districts, point.Latitude, point.Longitude); district != nil {
+					assignments = append(assignments, models.ElectoralDistrictAssignment{Key: point.Key, DistrictID: district.ID})
+				} else {
+					outside++
+				}
+			}
+
+			updated, err := s.repo.AssignPoints(dataset, assignments)
+			if err != nil {
+				return assigned, err
+			}
+			total += int(updated)
+		}
+
+		assigned[dataset] = total
+		log.Info("Assigned electoral districts", "dataset", dataset, "assigned", total, "outside_districts", outside)
+	}
+
+	return assigned, nil
+}
+
+func (s *electoralDistrictService) ListDistricts(provinceCode string) ([]*models.ElectoralDistrict, error) {
+	return s.repo.List(provinceCode)
+}
+
+func (s *electoralDistrictService) GetDistrict(id string) (*models.ElectoralDistrict, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetSummary totals the LMIA approvals, non-compliant employers and job postings in a district
+func (s *electoralDistrictService) GetSummary(id string, year int) (*models.ElectoralDistrictSummary, error) {
+	district, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.GetSummary(id, year)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.District = district
+	if year > 0 {
+		summary.Year = &year
+	}
+	if summary.LMIAByYear == nil {
+		summary.LMIAByYear = []models.ElectoralDistrictLMIAYear{}
+	}
+	if summary.TopEmployers == nil {
+		summary.TopEmployers = []models.ElectoralDistrictEmployer{}
+	}
+
+	return summary, nil
+}
+
+// LookupPostalCode returns the district of a postal code. Postal codes that weren't geocoded yet
+// are geocoded and assigned on the spot.
+func (s *electoralDistrictService) LookupPostalCode(postalCode string) (*models.ElectoralDistrict, error) {
+	cleaned := address.ExtractPostalCode(postalCode)
+	if cleaned == "" {
+		return nil, fmt.Errorf("invalid postal code: %s", postalCode)
+	}
+
+	district, err := s.repo.GetByPostalCode(cleaned)
+	if err == nil {
+		return district, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	latitude, longitude, err := s.geocodingService.GeocodePostalCode(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("failed to geocode postal code %s: %w", cleaned, err)
+	}
+
+	districts, err := s.loadDistricts()
+	if err != nil {
+		return nil, err
+	}
+
+	located := locateDistrict(districts, latitude, longitude)
+	if located == nil {
+		return nil, ErrDistrictNotFound
+	}
+
+	// The geocoding service stored the postal code, remember its district for the next lookup
+	if _, err := s.repo.AssignPoints(models.DistrictDatasetPostalCodes, []models.ElectoralDistrictAssignment{{Key: cleaned, DistrictID: located.ID}}); err != nil {
+		log.Warn("Failed to store postal code electoral district", "postal_code", cleaned, "error", err)
+	}
+
+	return s.repo.GetByID(located.ID)
+}
@@ -3,6 +3,7 @@ package services
 import (
 	"canada-hires/utils"
 	"fmt"
+	"html"
 	"strconv"
 
 	"github.com/charmbracelet/log"
@@ -11,6 +12,7 @@ import (
 
 type EmailService interface {
 	SendLoginLink(email, token string) error
+	SendBusinessClaimLink(email, businessName, token string) error
 }
 
 type emailService struct {
@@ -68,3 +70,36 @@ func (s *emailService) SendLoginLink(email, token string) error {
 	return nil
 }
 
+// SendBusinessClaimLink sends the link that confirms a user can receive email at a business's
+// domain
+func (s *emailService) SendBusinessClaimLink(email, businessName, token string) error {
+	verifyURL := fmt.Sprintf("%s/api/businesses/claims/verify/%s", s.backendURL, token)
+
+	m := gomail.NewMessage()
+	m.SetHeader("From", s.fromEmail)
+	m.SetHeader("To", email)
+	m.SetHeader("Subject", "Confirm your business on JobWatch Canada")
+
+	body := fmt.Sprintf(`
+		<html>
+		<body>
+			<h2>Confirm your business</h2>
+			<p>Someone asked to represent <strong>%s</strong> on JobWatch Canada using this email address.</p>
+			<p>Click the link below to confirm. Once confirmed and reviewed by our moderators, you can respond to reports about your business.</p>
+			<p><a href="%s">Confirm %s</a></p>
+			<p>This link will expire in 24 hours.</p>
+			<p>If you didn't make this request, please ignore this email.</p>
+		</body>
+		</html>
+	`, html.EscapeString(businessName), verifyURL, html.EscapeString(businessName))
+
+	m.SetBody("text/html", body)
+
+	d := gomail.NewDialer(s.smtpHost, s.smtpPort, s.smtpUser, s.smtpPassword)
+
+	if err := d.DialAndSend(m); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
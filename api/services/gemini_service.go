@@ -115,6 +115,15 @@ func (g *GeminiService) buildPrompt(job models.JobPosting) string {
 		salaryInfo = *job.SalaryRaw
 	}
 
+	// Include the annualized equivalent so hourly and yearly postings read the same way
+	if job.SalaryAnnualMin != nil && (job.SalaryType == nil || *job.SalaryType != "yearly") {
+		if job.SalaryAnnualMax != nil && *job.SalaryAnnualMax != *job.SalaryAnnualMin {
+			salaryInfo += fmt.Sprintf(" (about $%.0f - $%.0f per year)", *job.SalaryAnnualMin, *job.SalaryAnnualMax)
+		} else {
+			salaryInfo += fmt.Sprintf(" (about $%.0f per year)", *job.SalaryAnnualMin)
+		}
+	}
+
 	// Format posting date
 	postingDate := "Recently posted"
 	if job.PostingDate != nil {
@@ -0,0 +1,247 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Geocoding providers that can be listed in GEOCODER_CHAIN
+const (
+	GeocoderPelias    = "pelias"
+	GeocoderNominatim = "nominatim"
+	GeocoderLocal     = "local"
+)
+
+// DefaultGeocoderChain is used when GEOCODER_CHAIN is not set
+const DefaultGeocoderChain = "pelias,local"
+
+// How long a provider is skipped after it failed to respond
+const geocoderCooldown = time.Minute
+
+// ErrGeocoderUnavailable is wrapped by providers when the service itself failed, e.g. a timeout
+// or a server error, as opposed to finding no match
+var ErrGeocoderUnavailable = errors.New("geocoder unavailable")
+
+// GeocodeResult is a coordinate found by a provider
+type GeocodeResult struct {
+	Latitude     float64
+	Longitude    float64
+	Confidence   float64 // 0 to 1, as reported or estimated by the provider
+	Precision    string  // One of the models.Precision levels
+	Provider     string
+	PostalCode   string // Postal code of the match when the provider returns one
+	ProvinceCode string // Province of the match when the provider returns one
+	MatchType    string // How the provider matched the query, e.g. Pelias "exact" or "fallback"
+	Layer        string // Kind of place matched, e.g. Pelias "postalcode" or "locality"
+}
+
+// GeocodeRejection is a result the chain discarded for a reason a reviewer can judge, like a
+// low confidence or a match in another province. Results outside Canada aren't kept.
+type GeocodeRejection struct {
+	Result *GeocodeResult
+	Reason string // One of the models.GeocodingReview reasons
+	Detail string
+}
+
+func (r *GeocodeRejection) Error() string {
+	return r.Detail
+}
+
+// Geocoder resolves postal codes and addresses to coordinates
+type Geocoder interface {
+	Name() string
+	GeocodePostalCode(postalCode, expectedProvince string) (*GeocodeResult, error)
+	GeocodeAddress(fullAddress string) (*GeocodeResult, error)
+}
+
+// GeocoderChain tries its providers in order and returns the first result that meets the
+// provider's minimum confidence
+type GeocoderChain struct {
+	entries []*geocoderChainEntry
+	mu      sync.Mutex
+}
+
+type geocoderChainEntry struct {
+	geocoder         Geocoder
+	minConfidence    float64
+	unavailableUntil time.Time
+}
+
+// NewGeocoderChain creates an empty chain, providers are tried in the order they are added
+func NewGeocoderChain() *GeocoderChain {
+	return &GeocoderChain{}
+}
+
+// Add appends a provider and the minimum confidence its results need to be accepted
+func (c *GeocoderChain) Add(geocoder Geocoder, minConfidence float64) *GeocoderChain {
+	c.entries = append(c.entries, &geocoderChainEntry{geocoder: geocoder, minConfidence: minConfidence})
+	return c
+}
+
+// Providers returns the names of the providers in the chain, in order
+func (c *GeocoderChain) Providers() []string {
+	names := make([]string, 0, len(c.entries))
+	for _, entry := range c.entries {
+		names = append(names, entry.geocoder.Name())
+	}
+	return names
+}
+
+// NewGeocoderChainFromConfig builds a chain from a comma separated list of providers, each
+// optionally followed by its minimum confidence, e.g. "pelias:0.8,nominatim:0.6,local"
+func NewGeocoderChainFromConfig(config string, peliasURL, nominatimURL string, postalCodeRepo repos.PostalCodeRepository) (*GeocoderChain, error) {
+	chain := NewGeocoderChain()
+	for _, item := range strings.Split(config, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, threshold, hasThreshold := strings.Cut(item, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		var minConfidence float64
+		if hasThreshold {
+			value, err := strconv.ParseFloat(strings.TrimSpace(threshold), 64)
+			if err != nil || value < 0 || value > 1 {
+				return nil, fmt.Errorf("invalid minimum confidence for geocoder %s: %s", name, threshold)
+			}
+			minConfidence = value
+		}
+
+		switch name {
+		case GeocoderPelias:
+			chain.Add(NewPeliasGeocoder(peliasURL), minConfidence)
+		case GeocoderNominatim:
+			if nominatimURL == "" {
+				return nil, fmt.Errorf("nominatim geocoder requires NOMINATIM_URL")
+			}
+			chain.Add(NewNominatimGeocoder(nominatimURL), minConfidence)
+		case GeocoderLocal:
+			chain.Add(NewLocalGeocoder(postalCodeRepo), minConfidence)
+		default:
+			return nil, fmt.Errorf("unknown geocoder: %s", name)
+		}
+	}
+
+	if len(chain.entries) == 0 {
+		return nil, fmt.Errorf("no geocoders configured")
+	}
+
+	return chain, nil
+}
+
+// GeocodePostalCode resolves a postal code through the chain. expectedProvince may be empty.
+// Results discarded for a low confidence or a mismatched postal code or province are returned as
+// rejections, in provider order, whether or not another provider found a result.
+func (c *GeocoderChain) GeocodePostalCode(postalCode, expectedProvince string) (*GeocodeResult, []*GeocodeRejection, error) {
+	return c.resolve(postalCode, func(g Geocoder) (*GeocodeResult, error) {
+		result, err := g.GeocodePostalCode(postalCode, expectedProvince)
+		if err != nil {
+			return nil, err
+		}
+		if result.PostalCode != "" && result.PostalCode != postalCode {
+			return nil, &GeocodeRejection{
+				Result: result,
+				Reason: models.GeocodingReviewPostalCodeMismatch,
+				Detail: fmt.Sprintf("geocoded postal code %s does not match requested postal code %s", result.PostalCode, postalCode),
+			}
+		}
+		if code, ok := address.ProvinceCode(expectedProvince); ok && result.ProvinceCode != "" && result.ProvinceCode != code {
+			return nil, &GeocodeRejection{
+				Result: result,
+				Reason: models.GeocodingReviewProvinceMismatch,
+				Detail: fmt.Sprintf("geocoded location for postal code %s does not match expected province %s (got: %s)",
+					postalCode, expectedProvince, result.ProvinceCode),
+			}
+		}
+		return result, nil
+	})
+}
+
+// GeocodeAddress resolves a full address through the chain, returning discarded results like
+// GeocodePostalCode does
+func (c *GeocoderChain) GeocodeAddress(fullAddress string) (*GeocodeResult, []*GeocodeRejection, error) {
+	return c.resolve(fullAddress, func(g Geocoder) (*GeocodeResult, error) {
+		return g.GeocodeAddress(fullAddress)
+	})
+}
+
+func (c *GeocoderChain) resolve(query string, geocode func(Geocoder) (*GeocodeResult, error)) (*GeocodeResult, []*GeocodeRejection, error) {
+	var errs []error
+	var rejections []*GeocodeRejection
+	for _, entry := range c.entries {
+		name := entry.geocoder.Name()
+		if !c.available(entry) {
+			errs = append(errs, fmt.Errorf("%s: skipped after recent failure", name))
+			continue
+		}
+
+		result, err := geocode(entry.geocoder)
+		if err != nil {
+			if errors.Is(err, ErrGeocoderUnavailable) {
+				c.markUnavailable(entry, err)
+			}
+			var rejection *GeocodeRejection
+			if errors.As(err, &rejection) {
+				rejection.Result.Provider = name
+				rejections = append(rejections, rejection)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		result.Provider = name
+
+		if !InCanada(result.Latitude, result.Longitude) {
+			errs = append(errs, fmt.Errorf("%s: coordinates outside Canada bounds: lat=%.6f, lng=%.6f", name, result.Latitude, result.Longitude))
+			continue
+		}
+
+		if result.Confidence < entry.minConfidence {
+			rejection := &GeocodeRejection{
+				Result: result,
+				Reason: models.GeocodingReviewLowConfidence,
+				Detail: fmt.Sprintf("confidence %.2f below minimum %.2f", result.Confidence, entry.minConfidence),
+			}
+			rejections = append(rejections, rejection)
+			errs = append(errs, fmt.Errorf("%s: %w", name, rejection))
+			continue
+		}
+
+		return result, rejections, nil
+	}
+
+	return nil, rejections, fmt.Errorf("no geocoding results found for %s: %w", query, errors.Join(errs...))
+}
+
+// InCanada reports whether coordinates are within reasonable bounds for Canada
+func InCanada(latitude, longitude float64) bool {
+	// Canada latitude: approximately 41.7 to 83.1
+	// Canada longitude: approximately -141.0 to -52.6
+	return latitude >= 41.0 && latitude <= 84.0 && longitude >= -142.0 && longitude <= -52.0
+}
+
+func (c *GeocoderChain) available(entry *geocoderChainEntry) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return time.Now().After(entry.unavailableUntil)
+}
+
+func (c *GeocoderChain) markUnavailable(entry *geocoderChainEntry, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if time.Now().Before(entry.unavailableUntil) {
+		return
+	}
+	entry.unavailableUntil = time.Now().Add(geocoderCooldown)
+	log.Warn("Geocoder unavailable, falling back to the next provider", "geocoder", entry.geocoder.Name(), "retry_in", geocoderCooldown, "error", err)
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"strings"
+)
+
+// Confidence of local results, by what they were resolved to
+const (
+	localPostalCodeConfidence = 1
+	localFSAConfidence        = 0.5
+	// Addresses are placed at the centroid of their postal code, so they never count as precise
+	localAddressConfidence      = 0.5
+	localAddressFSAConfidence   = 0.3
+	localMunicipalityConfidence = 0.2
+)
+
+// localGeocoder resolves coordinates from postal codes and FSA centroids stored in the database,
+// either imported from a centroid file or geocoded earlier, so it works without any network
+// provider. Full postal codes are tried first, then the centroid of their FSA.
+type localGeocoder struct {
+	postalCodeRepo repos.PostalCodeRepository
+}
+
+func NewLocalGeocoder(postalCodeRepo repos.PostalCodeRepository) Geocoder {
+	return &localGeocoder{postalCodeRepo: postalCodeRepo}
+}
+
+func (g *localGeocoder) Name() string {
+	return GeocoderLocal
+}
+
+func (g *localGeocoder) GeocodePostalCode(postalCode, expectedProvince string) (*GeocodeResult, error) {
+	var stored *GeocodeResult
+	coords, err := g.postalCodeRepo.GetByPostalCode(postalCode)
+	if err == nil && coords.Latitude.Valid && coords.Longitude.Valid {
+		// Rows stored before precision was recorded were geocoded from the postal code
+		if coords.Precision == nil || *coords.Precision == models.PrecisionPostalCode {
+			return newLocalResult(coords, postalCode, models.PrecisionPostalCode, localPostalCodeConfidence), nil
+		}
+		stored = newLocalResult(coords, "", *coords.Precision, localFSAConfidence)
+	}
+
+	// Fall back to the centroid of the forward sortation area
+	fsa := strings.ToUpper(strings.ReplaceAll(postalCode, " ", ""))
+	if len(fsa) >= 3 {
+		centroid, err := g.postalCodeRepo.GetByPostalCode(fsa[:3])
+		if err == nil && centroid.Latitude.Valid && centroid.Longitude.Valid {
+			// The FSA centroid isn't a match for the postal code itself
+			return newLocalResult(centroid, "", models.PrecisionFSA, localFSAConfidence), nil
+		}
+	}
+
+	// A less precise stored coordinate, e.g. a locality, beats nothing
+	if stored != nil {
+		return stored, nil
+	}
+
+	return nil, fmt.Errorf("neither postal code %s nor its FSA is in the local dataset", postalCode)
+}
+
+// GeocodeAddress places an address at the centroid of its postal code, or of its FSA. Addresses
+// without a known postal code, e.g. "Toronto, ON", are placed in the middle of their municipality.
+func (g *localGeocoder) GeocodeAddress(fullAddress string) (*GeocodeResult, error) {
+	postalCode := address.ExtractPostalCode(fullAddress)
+	if postalCode == "" {
+		return g.geocodeMunicipality(fullAddress)
+	}
+
+	result, err := g.GeocodePostalCode(postalCode, "")
+	if err != nil {
+		if municipalityResult, municipalityErr := g.geocodeMunicipality(fullAddress); municipalityErr == nil {
+			return municipalityResult, nil
+		}
+		return nil, err
+	}
+
+	result.Confidence = localAddressConfidence
+	if result.Precision != models.PrecisionPostalCode {
+		result.Confidence = localAddressFSAConfidence
+	}
+
+	return result, nil
+}
+
+func (g *localGeocoder) geocodeMunicipality(fullAddress string) (*GeocodeResult, error) {
+	components := address.Parse(fullAddress)
+	if components.Municipality == "" || components.ProvinceCode == "" {
+		return nil, fmt.Errorf("address has no postal code or municipality to resolve locally")
+	}
+
+	centroid, err := g.postalCodeRepo.GetMunicipalityCentroid(components.Municipality, components.ProvinceCode)
+	if err != nil || !centroid.Latitude.Valid || !centroid.Longitude.Valid {
+		return nil, fmt.Errorf("municipality %s, %s is not in the local dataset", components.Municipality, components.ProvinceCode)
+	}
+
+	return &GeocodeResult{
+		Latitude:     centroid.Latitude.Float64,
+		Longitude:    centroid.Longitude.Float64,
+		Confidence:   localMunicipalityConfidence,
+		Precision:    models.PrecisionLocality,
+		ProvinceCode: components.ProvinceCode,
+	}, nil
+}
+
+func newLocalResult(coords *models.PostalCodeCoordinates, postalCode, precision string, confidence float64) *GeocodeResult {
+	return &GeocodeResult{
+		Latitude:     coords.Latitude.Float64,
+		Longitude:    coords.Longitude.Float64,
+		Confidence:   confidence,
+		Precision:    precision,
+		PostalCode:   postalCode,
+		ProvinceCode: address.ProvinceForPostalCode(coords.PostalCode),
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NominatimPlace is a search result of a Nominatim compatible API in jsonv2 format
+type NominatimPlace struct {
+	PlaceID     int64            `json:"place_id"`
+	Lat         string           `json:"lat"`
+	Lon         string           `json:"lon"`
+	Category    string           `json:"category"`
+	Type        string           `json:"type"`
+	AddressType string           `json:"addresstype"`
+	PlaceRank   int              `json:"place_rank"`
+	Importance  float64          `json:"importance"`
+	DisplayName string           `json:"display_name"`
+	Address     NominatimAddress `json:"address"`
+}
+
+type NominatimAddress struct {
+	Postcode    string `json:"postcode,omitempty"`
+	City        string `json:"city,omitempty"`
+	State       string `json:"state,omitempty"`
+	StateCode   string `json:"ISO3166-2-lvl4,omitempty"` // e.g. "CA-ON"
+	Country     string `json:"country,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
+}
+
+// nominatimGeocoder queries a Nominatim compatible API, e.g. a self-hosted Nominatim or a
+// commercial service exposing the same search endpoint
+type nominatimGeocoder struct {
+	serverURL string
+	userAgent string
+	client    *http.Client
+}
+
+func NewNominatimGeocoder(serverURL string) Geocoder {
+	// Nominatim's usage policy requires an identifying user agent
+	userAgent := os.Getenv("NOMINATIM_USER_AGENT")
+	if userAgent == "" {
+		userAgent = "JobWatchCanada/1.0"
+	}
+
+	return &nominatimGeocoder{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		userAgent: userAgent,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (g *nominatimGeocoder) Name() string {
+	return GeocoderNominatim
+}
+
+func (g *nominatimGeocoder) GeocodePostalCode(postalCode, expectedProvince string) (*GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("postalcode", postalCode)
+	if code, ok := address.ProvinceCode(expectedProvince); ok {
+		params.Set("state", address.ProvinceName(code))
+	}
+
+	place, err := g.search(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := newNominatimResult(place)
+	if err != nil {
+		return nil, err
+	}
+
+	// A postcode match is as precise as a postal code lookup gets
+	if result.PostalCode == postalCode {
+		result.Confidence = 1
+		result.Precision = models.PrecisionPostalCode
+	}
+
+	return result, nil
+}
+
+func (g *nominatimGeocoder) GeocodeAddress(fullAddress string) (*GeocodeResult, error) {
+	params := url.Values{}
+	params.Set("q", fullAddress)
+
+	place, err := g.search(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return newNominatimResult(place)
+}
+
+// search runs a search restricted to Canada and returns the first place
+func (g *nominatimGeocoder) search(params url.Values) (*NominatimPlace, error) {
+	params.Set("countrycodes", "ca")
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("limit", "1")
+
+	req, err := http.NewRequest(http.MethodGet, g.serverURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Nominatim request: %w", err)
+	}
+	req.Header.Set("User-Agent", g.userAgent)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to make Nominatim geocoding request: %v", ErrGeocoderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	// 429 means we are being rate limited, treat it like an outage and back off
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("%w: Nominatim API returned status %d", ErrGeocoderUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Nominatim API returned status %d", resp.StatusCode)
+	}
+
+	var places []NominatimPlace
+	if err := json.NewDecoder(resp.Body).Decode(&places); err != nil {
+		return nil, fmt.Errorf("failed to decode Nominatim response: %w", err)
+	}
+
+	if len(places) == 0 {
+		return nil, fmt.Errorf("no results from Nominatim")
+	}
+
+	return &places[0], nil
+}
+
+func newNominatimResult(place *NominatimPlace) (*GeocodeResult, error) {
+	if place.Address.CountryCode != "" && place.Address.CountryCode != "ca" {
+		return nil, fmt.Errorf("geocoding result is not in Canada: %s", place.Address.Country)
+	}
+
+	latitude, err := strconv.ParseFloat(place.Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude in Nominatim response: %s", place.Lat)
+	}
+	longitude, err := strconv.ParseFloat(place.Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude in Nominatim response: %s", place.Lon)
+	}
+
+	provinceCode, ok := address.ProvinceCode(strings.TrimPrefix(place.Address.StateCode, "CA-"))
+	if !ok {
+		provinceCode, _ = address.ProvinceCode(place.Address.State)
+	}
+
+	return &GeocodeResult{
+		Latitude:     latitude,
+		Longitude:    longitude,
+		Confidence:   nominatimConfidence(place.PlaceRank),
+		Precision:    nominatimPrecision(place.PlaceRank),
+		PostalCode:   address.ExtractPostalCode(place.Address.Postcode),
+		ProvinceCode: provinceCode,
+		Layer:        place.AddressType,
+	}, nil
+}
+
+// nominatimConfidence estimates a confidence from how precise the matched place is, since
+// Nominatim doesn't report one. See https://nominatim.org/release-docs/latest/customize/Ranking/
+func nominatimConfidence(placeRank int) float64 {
+	switch {
+	case placeRank >= 30: // Buildings and house numbers
+		return 1
+	case placeRank >= 26: // Streets
+		return 0.8
+	case placeRank >= 21: // Postcodes and neighbourhoods
+		return 0.6
+	case placeRank >= 13: // Municipalities
+		return 0.4
+	default:
+		return 0.2
+	}
+}
+
+// nominatimPrecision maps the rank of a place to a precision level
+func nominatimPrecision(placeRank int) string {
+	switch {
+	case placeRank >= 30:
+		return models.PrecisionAddress
+	case placeRank >= 26:
+		return models.PrecisionStreet
+	case placeRank >= 21:
+		return models.PrecisionPostalCode
+	default:
+		return models.PrecisionLocality
+	}
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Pelias geocoding response structure
+type PeliasGeocodingInfo struct {
+	Version     string                 `json:"version"`
+	Attribution string                 `json:"attribution"`
+	Query       map[string]interface{} `json:"query"`
+	Warnings    []string               `json:"warnings,omitempty"`
+	Errors      []string               `json:"errors,omitempty"`
+	Engine      map[string]interface{} `json:"engine"`
+	Timestamp   int64                  `json:"timestamp"`
+}
+
+type PeliasGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"` // [longitude, latitude]
+}
+
+type PeliasProperties struct {
+	ID          string  `json:"id"`
+	GID         string  `json:"gid"`
+	Layer       string  `json:"layer"`
+	Source      string  `json:"source"`
+	SourceID    string  `json:"source_id"`
+	CountryCode string  `json:"country_code,omitempty"`
+	Name        string  `json:"name"`
+	PostalCode  string  `json:"postalcode,omitempty"`
+	Confidence  float64 `json:"confidence"`
+	MatchType   string  `json:"match_type,omitempty"`
+	Distance    float64 `json:"distance,omitempty"`
+	Accuracy    string  `json:"accuracy,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	CountryGID  string  `json:"country_gid,omitempty"`
+	CountryA    string  `json:"country_a,omitempty"`
+	Region      string  `json:"region,omitempty"`
+	RegionGID   string  `json:"region_gid,omitempty"`
+	RegionA     string  `json:"region_a,omitempty"`
+	Locality    string  `json:"locality,omitempty"`
+	LocalityGID string  `json:"locality_gid,omitempty"`
+	Label       string  `json:"label,omitempty"`
+}
+
+type PeliasFeature struct {
+	Type       string           `json:"type"`
+	Geometry   PeliasGeometry   `json:"geometry"`
+	Properties PeliasProperties `json:"properties"`
+	Bbox       []float64        `json:"bbox,omitempty"`
+}
+
+type PeliasResponse struct {
+	Geocoding PeliasGeocodingInfo `json:"geocoding"`
+	Type      string              `json:"type"`
+	Features  []PeliasFeature     `json:"features"`
+	Bbox      []float64           `json:"bbox,omitempty"`
+}
+
+// peliasGeocoder queries a Pelias instance, by default the one on the homeserver
+type peliasGeocoder struct {
+	serverURL string
+	client    *http.Client
+}
+
+func NewPeliasGeocoder(serverURL string) Geocoder {
+	return &peliasGeocoder{
+		serverURL: serverURL,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (g *peliasGeocoder) Name() string {
+	return GeocoderPelias
+}
+
+func (g *peliasGeocoder) GeocodePostalCode(postalCode, expectedProvince string) (*GeocodeResult, error) {
+	// Search by postal code text
+	apiURL := fmt.Sprintf("%s/v1/search?text=%s",
+		g.serverURL,
+		url.QueryEscape(postalCode))
+
+	feature, err := g.search(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPeliasResult(feature), nil
+}
+
+func (g *peliasGeocoder) GeocodeAddress(fullAddress string) (*GeocodeResult, error) {
+	// Use boundary.country=CAN to restrict results to Canada
+	apiURL := fmt.Sprintf("%s/v1/search?text=%s&boundary.country=CAN&size=1",
+		g.serverURL,
+		url.QueryEscape(fullAddress))
+
+	feature, err := g.search(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the result is in Canada
+	if feature.Properties.CountryCode != "CA" && feature.Properties.CountryA != "CAN" {
+		return nil, fmt.Errorf("geocoding result is not in Canada: %s", feature.Properties.Country)
+	}
+
+	return newPeliasResult(feature), nil
+}
+
+// search runs a Pelias search and returns the first feature
+func (g *peliasGeocoder) search(apiURL string) (*PeliasFeature, error) {
+	resp, err := g.client.Get(apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to make Pelias geocoding request: %v", ErrGeocoderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return nil, fmt.Errorf("%w: Pelias API returned status %d", ErrGeocoderUnavailable, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Pelias API returned status %d", resp.StatusCode)
+	}
+
+	var peliasResult PeliasResponse
+	if err := json.NewDecoder(resp.Body).Decode(&peliasResult); err != nil {
+		return nil, fmt.Errorf("failed to decode Pelias response: %w", err)
+	}
+
+	if len(peliasResult.Features) == 0 {
+		return nil, fmt.Errorf("no results from Pelias")
+	}
+
+	feature := peliasResult.Features[0]
+	if len(feature.Geometry.Coordinates) < 2 {
+		return nil, fmt.Errorf("invalid coordinates in Pelias response")
+	}
+
+	return &feature, nil
+}
+
+func newPeliasResult(feature *PeliasFeature) *GeocodeResult {
+	provinceCode, ok := address.ProvinceCode(feature.Properties.RegionA)
+	if !ok {
+		provinceCode, _ = address.ProvinceCode(feature.Properties.Region)
+	}
+
+	// Pelias returns coordinates as [longitude, latitude]
+	return &GeocodeResult{
+		Latitude:     feature.Geometry.Coordinates[1],
+		Longitude:    feature.Geometry.Coordinates[0],
+		Confidence:   feature.Properties.Confidence,
+		Precision:    peliasPrecision(feature.Properties.Layer),
+		PostalCode:   address.ExtractPostalCode(feature.Properties.PostalCode),
+		ProvinceCode: provinceCode,
+		MatchType:    feature.Properties.MatchType,
+		Layer:        feature.Properties.Layer,
+	}
+}
+
+// peliasPrecision maps the Pelias layer of a match to a precision level
+func peliasPrecision(layer string) string {
+	switch layer {
+	case "address", "venue":
+		return models.PrecisionAddress
+	case "street":
+		return models.PrecisionStreet
+	case "postalcode":
+		return models.PrecisionPostalCode
+	default:
+		return models.PrecisionLocality
+	}
+}
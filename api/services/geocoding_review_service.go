@@ -0,0 +1,103 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"errors"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+)
+
+// ErrReviewResolved is returned when a decision is made on a review that isn't pending anymore
+var ErrReviewResolved = errors.New("geocoding review was already resolved")
+
+// ErrInvalidPin is returned when pinned coordinates are outside Canada
+var ErrInvalidPin = errors.New("pinned coordinates are outside Canada")
+
+type GeocodingReviewService interface {
+	ListReviews(filter models.GeocodingReviewFilter) ([]*models.GeocodingReview, int, error)
+	GetReview(id int) (*models.GeocodingReview, error)
+	GetStatusCounts() (map[string]int, error)
+	AcceptReview(id int, reviewerID string, notes *string) (*models.GeocodingReview, error)
+	RejectReview(id int, reviewerID string, notes *string) (*models.GeocodingReview, error)
+	PinReview(id int, reviewerID string, latitude, longitude float64, notes *string) (*models.GeocodingReview, error)
+}
+
+type geocodingReviewService struct {
+	repo repos.GeocodingReviewRepository
+}
+
+func NewGeocodingReviewService(repo repos.GeocodingReviewRepository) GeocodingReviewService {
+	return &geocodingReviewService{repo: repo}
+}
+
+func (s *geocodingReviewService) ListReviews(filter models.GeocodingReviewFilter) ([]*models.GeocodingReview, int, error) {
+	return s.repo.List(filter)
+}
+
+func (s *geocodingReviewService) GetReview(id int) (*models.GeocodingReview, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *geocodingReviewService) GetStatusCounts() (map[string]int, error) {
+	return s.repo.GetStatusCounts()
+}
+
+// AcceptReview confirms the reviewed result, which is stored as a manual override
+func (s *geocodingReviewService) AcceptReview(id int, reviewerID string, notes *string) (*models.GeocodingReview, error) {
+	return s.resolve(id, models.GeocodingReviewDecision{
+		Status:     models.GeocodingReviewAccepted,
+		ReviewerID: reviewerID,
+		Notes:      notes,
+	})
+}
+
+// RejectReview discards the reviewed result. It is removed if it was stored and won't be stored
+// when a geocoder returns it again.
+func (s *geocodingReviewService) RejectReview(id int, reviewerID string, notes *string) (*models.GeocodingReview, error) {
+	return s.resolve(id, models.GeocodingReviewDecision{
+		Status:     models.GeocodingReviewRejected,
+		ReviewerID: reviewerID,
+		Notes:      notes,
+	})
+}
+
+// PinReview stores coordinates placed by the reviewer as a manual override
+func (s *geocodingReviewService) PinReview(id int, reviewerID string, latitude, longitude float64, notes *string) (*models.GeocodingReview, error) {
+	if !InCanada(latitude, longitude) {
+		return nil, ErrInvalidPin
+	}
+
+	return s.resolve(id, models.GeocodingReviewDecision{
+		Status:     models.GeocodingReviewPinned,
+		ReviewerID: reviewerID,
+		Notes:      notes,
+		Latitude:   latitude,
+		Longitude:  longitude,
+	})
+}
+
+func (s *geocodingReviewService) resolve(id int, decision models.GeocodingReviewDecision) (*models.GeocodingReview, error) {
+	review, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if review.Status != models.GeocodingReviewPending {
+		return nil, ErrReviewResolved
+	}
+
+	resolved, err := s.repo.Resolve(id, decision)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve geocoding review %d: %w", id, err)
+	}
+
+	log.Info("Geocoding review resolved",
+		"review_id", id,
+		"target_type", resolved.TargetType,
+		"target", resolved.TargetKey,
+		"status", resolved.Status,
+		"reviewer_id", decision.ReviewerID)
+
+	return resolved, nil
+}
@@ -240,7 +240,7 @@ func convertBrowserJobToModel(jobData map[string]interface{}, scrapingRunID stri
 	// Check if LMIA flag is present
 	hasLMIA := lmiaFlag != ""
 
-	return &models.JobPosting{
+	job := &models.JobPosting{
 		JobBankID:     &jobID,
 		Title:         title,
 		Employer:      employer,
@@ -256,4 +256,7 @@ func convertBrowserJobToModel(jobData map[string]interface{}, scrapingRunID stri
 		HasLMIA:       hasLMIA,
 		ScrapingRunID: scrapingRunID,
 	}
+	job.NormalizeSalary()
+
+	return job
 }
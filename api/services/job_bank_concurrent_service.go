@@ -423,7 +423,9 @@ func (s *jobBankConcurrentService) printFinalSummary(scrapingRunID string, total
 		fmt.Printf("\n=== SAMPLE JOBS (First 3) ===\n")
 		for i, job := range recentJobs[:min(3, len(recentJobs))] {
 			fmt.Printf("\nJob %d:\n", i+1)
-			fmt.Printf("  ID: %s\n", job.JobBankID)
+			if job.JobBankID != nil {
+				fmt.Printf("  ID: %s\n", *job.JobBankID)
+			}
 			fmt.Printf("  Title: %s\n", job.Title)
 			fmt.Printf("  Employer: %s\n", job.Employer)
 			fmt.Printf("  Location: %s\n", job.Location)
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"canada-hires/address"
 	"canada-hires/models"
 	"canada-hires/repos"
 	"fmt"
@@ -19,6 +20,8 @@ type JobBankService interface {
 	ScrapeTFWJobs() error
 	ScrapeJobsFromPage(pageNum int, scrapingRunID string) ([]*models.JobPosting, error)
 	ParseJobDetails(jobURL string) (*JobDetails, error)
+	EnrichFromDetails(posting *models.JobPosting) error
+	EnrichJobPostings(postings []*models.JobPosting) int
 	GetTotalJobCount() (int, error)
 	GetScrapingStatus() (*models.JobScrapingRun, error)
 }
@@ -29,16 +32,18 @@ type jobBankService struct {
 }
 
 type JobDetails struct {
-	Title       string
-	Employer    string
-	Location    string
-	Province    string
-	City        string
-	SalaryMin   *float64
-	SalaryMax   *float64
-	SalaryType  string
-	PostingDate *time.Time
-	Description string
+	Title        string
+	Employer     string
+	Location     string
+	Province     string
+	City         string
+	SalaryMin    *float64
+	SalaryMax    *float64
+	SalaryType   string
+	HoursPerWeek *float64
+	NOCCode      string
+	PostingDate  *time.Time
+	Description  string
 }
 
 const (
@@ -340,7 +345,7 @@ func parseJobFromListing(s *goquery.Selection, scrapingRunID string) *models.Job
 	salaryText := s.Find(".salary, .wage, .pay").First().Text()
 	salaryMin, salaryMax, salaryType := parseSalary(salaryText)
 
-	return &models.JobPosting{
+	job := &models.JobPosting{
 		JobBankID:     &jobID,
 		Title:         title,
 		Employer:      employer,
@@ -354,6 +359,9 @@ func parseJobFromListing(s *goquery.Selection, scrapingRunID string) *models.Job
 		IsTFW:         true,
 		ScrapingRunID: scrapingRunID,
 	}
+	job.NormalizeSalary()
+
+	return job
 }
 
 func extractJobIDFromURL(jobURL string) string {
@@ -376,19 +384,7 @@ func extractJobIDFromURL(jobURL string) string {
 }
 
 func parseLocation(location string) (province, city string) {
-	if location == "" {
-		return "", ""
-	}
-
-	// Canadian locations are typically "City, Province" or "City, XX"
-	parts := strings.Split(location, ",")
-	if len(parts) >= 2 {
-		city = strings.TrimSpace(parts[0])
-		province = strings.TrimSpace(parts[len(parts)-1])
-	} else {
-		city = strings.TrimSpace(location)
-	}
-
+	city, province = address.ParseLocation(location)
 	return province, city
 }
 
@@ -481,6 +477,10 @@ func (s *jobBankService) ParseJobDetails(jobURL string) (*JobDetails, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("job details returned status %d", resp.StatusCode)
+	}
+
 	doc, err := goquery.NewDocumentFromReader(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse job details HTML: %w", err)
@@ -495,10 +495,91 @@ func (s *jobBankService) ParseJobDetails(jobURL string) (*JobDetails, error) {
 	}
 
 	details.Province, details.City = parseLocation(details.Location)
+	details.HoursPerWeek = parseHoursPerWeek(doc.Text())
+	details.NOCCode = parseNOCCode(doc.Text())
 
 	return details, nil
 }
 
+// EnrichFromDetails fetches the posting's detail page and stores the NOC code and hours per week,
+// recomputing the normalized salary when the page states the weekly hours
+func (s *jobBankService) EnrichFromDetails(posting *models.JobPosting) error {
+	details, err := s.ParseJobDetails(posting.URL)
+	if err != nil {
+		return err
+	}
+
+	if details.NOCCode != "" {
+		posting.NOCCode = &details.NOCCode
+	}
+	if details.HoursPerWeek != nil {
+		posting.HoursPerWeek = details.HoursPerWeek
+		posting.NormalizeSalary()
+	}
+
+	if err := s.repo.UpdateJobPostingDetails(posting); err != nil {
+		return fmt.Errorf("failed to update job posting details: %w", err)
+	}
+
+	return nil
+}
+
+// EnrichJobPostings fetches the detail page of each posting, waiting between requests, and
+// returns the number of postings enriched. A page that can't be read is logged and skipped.
+func (s *jobBankService) EnrichJobPostings(postings []*models.JobPosting) int {
+	enriched := 0
+	for i, posting := range postings {
+		if i > 0 {
+			time.Sleep(requestDelay)
+		}
+		if posting.URL == "" {
+			continue
+		}
+
+		if err := s.EnrichFromDetails(posting); err != nil {
+			log.Warn("Failed to enrich job posting from its detail page", "job_id", posting.ID, "url", posting.URL, "error", err)
+			continue
+		}
+		enriched++
+	}
+
+	log.Info("Job postings enriched from detail pages", "postings", len(postings), "enriched", enriched)
+	return enriched
+}
+
+// parseNOCCode extracts the five digit NOC 2021 code from detail page text such as "NOC 65201"
+// or "NOC 2021 version 1.0: 65201"
+func parseNOCCode(text string) string {
+	re := regexp.MustCompile(`(?i)\bNOC\b[^\n]{0,40}?\b(\d{5})\b`)
+	matches := re.FindStringSubmatch(text)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// parseHoursPerWeek extracts the weekly hours from detail page text such as "40 hours per week"
+// or "30 to 40 hours per week". Ranges use the upper bound.
+func parseHoursPerWeek(text string) *float64 {
+	re := regexp.MustCompile(`(?i)(\d+(?:\.\d+)?)(?:\s*(?:to|-)\s*(\d+(?:\.\d+)?))?\s*hours?\s*(?:per|a|/)\s*week`)
+	matches := re.FindStringSubmatch(text)
+	if len(matches) < 2 {
+		return nil
+	}
+
+	hoursStr := matches[1]
+	if matches[2] != "" {
+		hoursStr = matches[2]
+	}
+
+	hours, err := strconv.ParseFloat(hoursStr, 64)
+	if err != nil || hours <= 0 || hours > 168 {
+		return nil
+	}
+
+	return &hours
+}
+
 func (s *jobBankService) GetScrapingStatus() (*models.JobScrapingRun, error) {
 	return s.repo.GetLatestScrapingRun()
 }
\ No newline at end of file
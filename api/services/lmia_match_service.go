@@ -0,0 +1,168 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Confidence weights for linking a posting to an LMIA approval row. A name match alone is enough
+// to link, the location and NOC raise the confidence.
+const (
+	lmiaMatchExactNameWeight      = 0.6
+	lmiaMatchNormalizedNameWeight = 0.5
+	lmiaMatchPostalCodeWeight     = 0.3
+	lmiaMatchCityWeight           = 0.25
+	lmiaMatchProvinceWeight       = 0.1
+	lmiaMatchNOCWeight            = 0.15
+)
+
+var lmiaOccupationCodeRegex = regexp.MustCompile(`^\s*(\d{4,5})`)
+
+type LMIAMatchService interface {
+	MatchJobPosting(posting *models.JobPosting) (int, error)
+	MatchAllJobPostings() (int, error)
+	GetApprovalHistory(jobPostingID string) ([]*models.LMIAApprovalHistory, error)
+}
+
+type lmiaMatchService struct {
+	matchRepo         repos.JobLMIAMatchRepository
+	jobBankRepo       repos.JobBankRepository
+	postalCodeService PostalCodeService
+}
+
+func NewLMIAMatchService(matchRepo repos.JobLMIAMatchRepository, jobBankRepo repos.JobBankRepository, postalCodeService PostalCodeService) LMIAMatchService {
+	return &lmiaMatchService{
+		matchRepo:         matchRepo,
+		jobBankRepo:       jobBankRepo,
+		postalCodeService: postalCodeService,
+	}
+}
+
+// MatchJobPosting links a posting to the LMIA approval rows of its employer and returns the number of matches
+func (s *lmiaMatchService) MatchJobPosting(posting *models.JobPosting) (int, error) {
+	normalizedName := models.NormalizeEmployerName(posting.Employer)
+	if normalizedName == "" {
+		return 0, nil
+	}
+
+	candidates, err := s.matchRepo.GetLMIAEmployersByNormalizedName(normalizedName)
+	if err != nil {
+		return 0, err
+	}
+
+	var matches []*models.JobLMIAMatch
+	for _, candidate := range candidates {
+		if match := s.scoreCandidate(posting, candidate); match != nil {
+			matches = append(matches, match)
+		}
+	}
+
+	if err := s.matchRepo.ReplaceMatchesForJobPosting(posting.ID, matches); err != nil {
+		return 0, fmt.Errorf("failed to store LMIA matches: %w", err)
+	}
+
+	return len(matches), nil
+}
+
+// scoreCandidate scores an LMIA approval row against a posting. Rows from another province are
+// skipped, since a shared name across provinces is more often a different business.
+func (s *lmiaMatchService) scoreCandidate(posting *models.JobPosting, candidate *models.LMIAEmployer) *models.JobLMIAMatch {
+	match := &models.JobLMIAMatch{
+		LMIAEmployerID: candidate.ID,
+		NameMatch:      models.LMIANameMatchNormalized,
+		Confidence:     lmiaMatchNormalizedNameWeight,
+		LocationMatch:  models.LMIALocationMatchNone,
+	}
+
+	if strings.EqualFold(strings.TrimSpace(posting.Employer), strings.TrimSpace(candidate.Employer)) {
+		match.NameMatch = models.LMIANameMatchExact
+		match.Confidence = lmiaMatchExactNameWeight
+	}
+
+	postingProvince := ""
+	if posting.Province != nil {
+		postingProvince = models.NormalizeProvince(*posting.Province)
+	}
+	candidateProvince := ""
+	if candidate.ProvinceTerritory != nil {
+		candidateProvince = models.NormalizeProvince(*candidate.ProvinceTerritory)
+	}
+	if postingProvince != "" && candidateProvince != "" && postingProvince != candidateProvince {
+		return nil
+	}
+
+	postingPostalCode := s.postalCodeService.ExtractPostalCode(posting.Location)
+	address := ""
+	if candidate.Address != nil {
+		address = strings.ToLower(*candidate.Address)
+	}
+
+	switch {
+	case postingPostalCode != "" && candidate.PostalCode != nil &&
+		s.postalCodeService.FormatPostalCode(*candidate.PostalCode) == postingPostalCode:
+		match.LocationMatch = models.LMIALocationMatchPostalCode
+		match.Confidence += lmiaMatchPostalCodeWeight
+	case posting.City != nil && *posting.City != "" && strings.Contains(address, strings.ToLower(*posting.City)):
+		match.LocationMatch = models.LMIALocationMatchCity
+		match.Confidence += lmiaMatchCityWeight
+	case postingProvince != "" && postingProvince == candidateProvince:
+		match.LocationMatch = models.LMIALocationMatchProvince
+		match.Confidence += lmiaMatchProvinceWeight
+	}
+
+	// LMIA occupations look like "65201-Food counter attendants". Older files use four digit
+	// NOC 2016 codes, which can't be compared with NOC 2021 codes.
+	if posting.NOCCode != nil && candidate.Occupation != nil {
+		if codes := lmiaOccupationCodeRegex.FindStringSubmatch(*candidate.Occupation); len(codes) > 1 && codes[1] == *posting.NOCCode {
+			match.NOCMatch = true
+			match.Confidence += lmiaMatchNOCWeight
+		}
+	}
+
+	if match.Confidence > 1 {
+		match.Confidence = 1
+	}
+
+	return match
+}
+
+// MatchAllJobPostings links every stored posting to LMIA approvals and returns the number of postings matched
+func (s *lmiaMatchService) MatchAllJobPostings() (int, error) {
+	backfilled, err := s.matchRepo.BackfillNormalizedEmployers()
+	if err != nil {
+		return 0, err
+	}
+	if backfilled > 0 {
+		log.Info("Backfilled normalized LMIA employer names", "employers", backfilled)
+	}
+
+	postings, err := s.jobBankRepo.GetRecentJobPostings(0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get job postings: %w", err)
+	}
+
+	matched := 0
+	for _, posting := range postings {
+		count, err := s.MatchJobPosting(posting)
+		if err != nil {
+			log.Error("Failed to match job posting to LMIA approvals", "job_id", posting.ID, "error", err)
+			continue
+		}
+		if count > 0 {
+			matched++
+		}
+	}
+
+	log.Info("LMIA approval matching completed", "postings", len(postings), "matched", matched)
+	return matched, nil
+}
+
+// GetApprovalHistory returns the LMIA approvals matched to a posting
+func (s *lmiaMatchService) GetApprovalHistory(jobPostingID string) ([]*models.LMIAApprovalHistory, error) {
+	return s.matchRepo.GetApprovalHistoryForJobPosting(jobPostingID)
+}
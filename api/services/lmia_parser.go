@@ -1,6 +1,7 @@
 package services
 
 import (
+	"canada-hires/address"
 	"canada-hires/models"
 	"encoding/csv"
 	"fmt"
@@ -8,7 +9,6 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -403,18 +403,10 @@ func (p *lmiaParser) parseEmployerRecord(record []string, columnMap map[string]i
 	if val := getField("address"); val != "" {
 		employer.Address = &val
 
-		// Extract postal code from address (format: A1A 1A1)
-		// Pattern matches: Letter-Digit-Letter space Digit-Letter-Digit
-		postalCodeRegex := regexp.MustCompile(`[A-Za-z]\d[A-Za-z]\s+\d[A-Za-z]\d`)
-		if match := postalCodeRegex.FindString(val); match != "" {
-			// Clean and format postal code to A1A 1A1 format
-			cleaned := strings.ToUpper(strings.ReplaceAll(match, " ", ""))
-			if len(cleaned) == 6 {
-				formatted := cleaned[:3] + " " + cleaned[3:]
-				employer.PostalCode = &formatted
-
-				// Note: Geocoding will be done in a separate batch job later
-			}
+		// Extract postal code from address, formatted as A1A 1A1
+		// Note: Geocoding will be done in a separate batch job later
+		if postalCode := address.ExtractPostalCode(val); postalCode != "" {
+			employer.PostalCode = &postalCode
 		}
 	}
 	if val := getField("occupation"); val != "" {
@@ -32,6 +32,7 @@ type lmiaService struct {
 	client             *http.Client
 	geocodingService   PostalCodeGeocodingService
 	postalCodeService  PostalCodeService
+	addressService     AddressService
 }
 
 type OpenDataResponse struct {
@@ -49,7 +50,7 @@ type OpenDataResponse struct {
 	} `json:"result"`
 }
 
-func NewLMIAService(repo repos.LMIARepository, geocodingService PostalCodeGeocodingService, postalCodeService PostalCodeService) LMIAService {
+func NewLMIAService(repo repos.LMIARepository, geocodingService PostalCodeGeocodingService, postalCodeService PostalCodeService, addressService AddressService) LMIAService {
 	return &lmiaService{
 		repo:              repo,
 		parser:            NewLMIAParser(),
@@ -58,6 +59,7 @@ func NewLMIAService(repo repos.LMIARepository, geocodingService PostalCodeGeocod
 		},
 		geocodingService:  geocodingService,
 		postalCodeService: postalCodeService,
+		addressService:    addressService,
 	}
 }
 
@@ -335,6 +337,11 @@ func (s *lmiaService) RunFullUpdate() error {
 		return fmt.Errorf("failed to process resources: %w", err)
 	}
 
+	// Split the new employer addresses into their components
+	if _, err := s.addressService.ParseSourceAddresses(models.AddressSourceLMIA); err != nil {
+		log.Error("Failed to parse LMIA employer addresses", "error", err)
+	}
+
 	log.Info("Full LMIA data update completed")
 	return nil
 }
@@ -440,14 +447,14 @@ func (s *lmiaService) GeocodeUnprocessedEmployers() error {
 		"total_postal_codes", len(ungeocodedPostalCodes),
 		"found_in_database", foundInDatabaseCount,
 		"invalid_format", invalidFormatCount,
-		"need_pelias_geocoding", len(unmatchedPostalCodes))
+		"need_geocoding", len(unmatchedPostalCodes))
 
-	// Phase 2: Parallel geocode unmatched postal codes via Pelias server
-	var peliasSuccessCount, peliasFailedCount int64
+	// Phase 2: Parallel geocode unmatched postal codes through the geocoder chain
+	var geocodedSuccessCount, geocodedFailedCount int64
 	totalToGeocode := len(unmatchedPostalCodes)
 	
 	if totalToGeocode > 0 {
-		log.Info("Phase 2 - Starting parallel Pelias geocoding", 
+		log.Info("Phase 2 - Starting parallel geocoding", 
 			"postal_codes_to_geocode", totalToGeocode,
 			"workers", 12,
 			"found_in_db", foundInDatabaseCount,
@@ -512,9 +519,9 @@ func (s *lmiaService) GeocodeUnprocessedEmployers() error {
 			results[result.postalCode] = result.coords
 			
 			if result.success {
-				peliasSuccessCount++
+				geocodedSuccessCount++
 			} else {
-				peliasFailedCount++
+				geocodedFailedCount++
 			}
 			
 			processedCount++
@@ -529,8 +536,8 @@ func (s *lmiaService) GeocodeUnprocessedEmployers() error {
 					"remaining", remaining,
 					"percentage", fmt.Sprintf("%.1f%%", percentage),
 					"db_found", foundInDatabaseCount,
-					"pelias_success", peliasSuccessCount,
-					"pelias_failed", peliasFailedCount,
+					"geocoded_success", geocodedSuccessCount,
+					"geocoded_failed", geocodedFailedCount,
 					"invalid_format", invalidFormatCount)
 			}
 		}
@@ -539,21 +546,21 @@ func (s *lmiaService) GeocodeUnprocessedEmployers() error {
 		wg.Wait()
 		close(resultsChan)
 		
-		log.Info("Phase 2 - Parallel Pelias geocoding completed",
-			"successful", peliasSuccessCount,
-			"failed", peliasFailedCount)
+		log.Info("Phase 2 - Parallel geocoding completed",
+			"successful", geocodedSuccessCount,
+			"failed", geocodedFailedCount)
 	}
 
 	// Final summary with processing rate
-	totalFoundCount := foundInDatabaseCount + int(peliasSuccessCount)
-	totalErrorCount := invalidFormatCount + int(peliasFailedCount)
+	totalFoundCount := foundInDatabaseCount + int(geocodedSuccessCount)
+	totalErrorCount := invalidFormatCount + int(geocodedFailedCount)
 	processingRate := float64(len(ungeocodedPostalCodes)) / time.Since(startTime).Seconds()
 	
 	log.Info("Final geocoding results summary",
 		"total_processed", len(ungeocodedPostalCodes),
 		"found_in_database", foundInDatabaseCount,
-		"pelias_success", peliasSuccessCount,
-		"pelias_failed", peliasFailedCount,
+		"geocoded_success", geocodedSuccessCount,
+		"geocoded_failed", geocodedFailedCount,
 		"invalid_format", invalidFormatCount,
 		"total_successful", totalFoundCount,
 		"total_failed", totalErrorCount,
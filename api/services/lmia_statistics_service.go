@@ -23,6 +23,8 @@ type LMIAStatisticsService interface {
 	GetLatestStatistics(periodType models.PeriodType, limit int) ([]*models.LMIAStatistics, error)
 	GetTrendsSummary() (*TrendsSummary, error)
 	GetRegionalStatsByTimeframe(startDate, endDate time.Time) (*RegionalStats, error)
+	GetWageComparisonByTimeframe(startDate, endDate time.Time) (*models.WageComparisonStats, error)
+	GetLMIAMatchStats() (*models.LMIAMatchStats, error)
 	
 	// Daily aggregation job
 	RunDailyAggregation() error
@@ -36,6 +38,7 @@ type TrendsSummary struct {
 	TopProvincesToday   []models.RegionData   `json:"top_provinces_today"`
 	TopCitiesToday      []models.RegionData   `json:"top_cities_today"`
 	RecentTrends        []*models.LMIAStatistics `json:"recent_trends"`
+	LMIAMatchStats      *models.LMIAMatchStats   `json:"lmia_match_stats"`
 }
 
 type RegionalStats struct {
@@ -209,6 +212,12 @@ func (s *lmiaStatisticsService) GetTrendsSummary() (*TrendsSummary, error) {
 		recentTrends = []*models.LMIAStatistics{}
 	}
 
+	// Share of current postings from employers with LMIA approvals
+	matchStats, err := s.repo.GetLMIAMatchStats()
+	if err != nil {
+		log.Error("Failed to get LMIA match stats", "error", err)
+	}
+
 	return &TrendsSummary{
 		TotalJobsToday:     totalJobsToday,
 		TotalJobsThisMonth: totalJobsThisMonth,
@@ -217,6 +226,7 @@ func (s *lmiaStatisticsService) GetTrendsSummary() (*TrendsSummary, error) {
 		TopProvincesToday:  topProvincesToday,
 		TopCitiesToday:     topCitiesToday,
 		RecentTrends:       recentTrends,
+		LMIAMatchStats:     matchStats,
 	}, nil
 }
 
@@ -310,4 +320,23 @@ func sortAndLimitRegions(regions []models.RegionData, limit int) []models.Region
 		return regions[:limit]
 	}
 	return regions
-}
\ No newline at end of file
+}
+
+// GetWageComparisonByTimeframe compares offered LMIA wages with the prevailing median for a specific timeframe
+func (s *lmiaStatisticsService) GetWageComparisonByTimeframe(startDate, endDate time.Time) (*models.WageComparisonStats, error) {
+	stats, err := s.repo.GetWageComparisonStats(startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wage comparison stats: %w", err)
+	}
+	return stats, nil
+}
+
+// GetLMIAMatchStats reports what share of current postings come from employers with LMIA approvals,
+// and from repeat LMIA users
+func (s *lmiaStatisticsService) GetLMIAMatchStats() (*models.LMIAMatchStats, error) {
+	stats, err := s.repo.GetLMIAMatchStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LMIA match stats: %w", err)
+	}
+	return stats, nil
+}
@@ -14,17 +14,19 @@ type NonCompliantCronService struct {
 	cron                    *cron.Cron
 	logger                  *log.Logger
 	nonCompliantService     NonCompliantService
+	matchService            NonCompliantMatchService
 	scraperJobRepo          repos.ScraperJobRepository
 	jobType                 string
 }
 
-func NewNonCompliantCronService(logger *log.Logger, nonCompliantService NonCompliantService, scraperJobRepo repos.ScraperJobRepository) *NonCompliantCronService {
+func NewNonCompliantCronService(logger *log.Logger, nonCompliantService NonCompliantService, matchService NonCompliantMatchService, scraperJobRepo repos.ScraperJobRepository) *NonCompliantCronService {
 	c := cron.New(cron.WithLocation(time.UTC))
 
 	return &NonCompliantCronService{
 		cron:                c,
 		logger:              logger,
 		nonCompliantService: nonCompliantService,
+		matchService:        matchService,
 		scraperJobRepo:      scraperJobRepo,
 		jobType:             "non_compliant_scraper",
 	}
@@ -112,6 +114,12 @@ func (ncs *NonCompliantCronService) executeScraper() error {
 	ncs.logger.Info("Non-compliant scraper execution completed",
 		"status", cronJob.Status,
 		"records_processed", cronJob.RecordsProcessed)
+
+	// Refresh the still hiring flags on job postings against the updated list
+	if _, err := ncs.matchService.MatchAllJobPostings(); err != nil {
+		ncs.logger.Error("Failed to match job postings to non-compliant employers", "error", err)
+	}
+
 	return nil
 }
 
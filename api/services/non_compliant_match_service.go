@@ -0,0 +1,141 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// NonCompliantMatchService cross-references the IRCC non-compliant list with Job Bank postings and
+// LMIA approvals to find employers that are still hiring through the program
+type NonCompliantMatchService interface {
+	MatchAllJobPostings() (int, error)
+	GetStillHiringEmployers(limit, offset int) ([]models.StillHiringEmployer, error)
+	GetStillHiringCount() (int, error)
+	FlagReportGroups(groups []*models.ReportsByAddress) error
+}
+
+type nonCompliantMatchService struct {
+	nonCompliantRepo repos.NonCompliantRepository
+	jobBankRepo      repos.JobBankRepository
+}
+
+func NewNonCompliantMatchService(nonCompliantRepo repos.NonCompliantRepository, jobBankRepo repos.JobBankRepository) NonCompliantMatchService {
+	return &nonCompliantMatchService{
+		nonCompliantRepo: nonCompliantRepo,
+		jobBankRepo:      jobBankRepo,
+	}
+}
+
+// indexCandidates maps normalized operating and legal names to the employer's most recent decision
+func indexCandidates(candidates []models.NonCompliantMatchCandidate) map[string]models.NonCompliantMatchCandidate {
+	index := make(map[string]models.NonCompliantMatchCandidate)
+	add := func(name *string, candidate models.NonCompliantMatchCandidate) {
+		if name == nil || *name == "" {
+			return
+		}
+		existing, ok := index[*name]
+		if !ok || isLaterDecision(candidate.DateOfFinalDecision, existing.DateOfFinalDecision) {
+			index[*name] = candidate
+		}
+	}
+
+	for _, candidate := range candidates {
+		add(candidate.NormalizedName, candidate)
+		add(candidate.NormalizedLegalName, candidate)
+	}
+
+	return index
+}
+
+func isLaterDecision(date, than *time.Time) bool {
+	if date == nil {
+		return false
+	}
+	return than == nil || date.After(*than)
+}
+
+// MatchAllJobPostings flags every stored posting whose employer is on the non-compliant list and
+// returns the number of postings flagged
+func (s *nonCompliantMatchService) MatchAllJobPostings() (int, error) {
+	backfilled, err := s.nonCompliantRepo.BackfillNormalizedNames()
+	if err != nil {
+		return 0, err
+	}
+	if backfilled > 0 {
+		log.Info("Backfilled normalized non-compliant employer names", "employers", backfilled)
+	}
+
+	candidates, err := s.nonCompliantRepo.GetMatchCandidates()
+	if err != nil {
+		return 0, err
+	}
+	index := indexCandidates(candidates)
+
+	postings, err := s.jobBankRepo.GetRecentJobPostings(0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get job postings: %w", err)
+	}
+
+	var flags []models.NonCompliantFlag
+	for _, posting := range postings {
+		candidate, ok := index[models.NormalizeEmployerName(posting.Employer)]
+		if !ok {
+			continue
+		}
+		flags = append(flags, models.NonCompliantFlag{
+			JobPostingID:           posting.ID,
+			NonCompliantEmployerID: candidate.ID,
+			DecisionDate:           candidate.DateOfFinalDecision,
+			PenaltyAmount:          candidate.PenaltyAmount,
+		})
+	}
+
+	if err := s.nonCompliantRepo.ReplaceJobPostingFlags(flags); err != nil {
+		return 0, err
+	}
+
+	log.Info("Non-compliant employer matching completed", "postings", len(postings), "flagged", len(flags))
+	return len(flags), nil
+}
+
+// GetStillHiringEmployers returns non-compliant employers with current postings or later LMIA approvals
+func (s *nonCompliantMatchService) GetStillHiringEmployers(limit, offset int) ([]models.StillHiringEmployer, error) {
+	return s.nonCompliantRepo.GetStillHiringEmployers(limit, offset)
+}
+
+func (s *nonCompliantMatchService) GetStillHiringCount() (int, error) {
+	return s.nonCompliantRepo.GetStillHiringCount()
+}
+
+// FlagReportGroups marks grouped business reports whose business is on the non-compliant list
+func (s *nonCompliantMatchService) FlagReportGroups(groups []*models.ReportsByAddress) error {
+	var names []string
+	for _, group := range groups {
+		if name := models.NormalizeEmployerName(group.BusinessName); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	candidates, err := s.nonCompliantRepo.GetMatchCandidatesByNormalizedNames(names)
+	if err != nil {
+		return err
+	}
+	index := indexCandidates(candidates)
+
+	for _, group := range groups {
+		candidate, ok := index[models.NormalizeEmployerName(group.BusinessName)]
+		if !ok {
+			continue
+		}
+		employerID := candidate.ID
+		group.IsNonCompliantEmployer = true
+		group.NonCompliantEmployerID = &employerID
+		group.NonCompliantDecisionDate = candidate.DateOfFinalDecision
+	}
+
+	return nil
+}
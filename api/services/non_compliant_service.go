@@ -1,13 +1,14 @@
 package services
 
 import (
+	"canada-hires/address"
 	"canada-hires/models"
 	"canada-hires/repos"
 	"canada-hires/scraper"
 	scraper_types "canada-hires/scraper-types"
 	"fmt"
-	"regexp"
-	"strings"
+	"io"
+	"os"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -15,6 +16,7 @@ import (
 
 type NonCompliantService interface {
 	ScrapeAndStoreNonCompliantEmployers() (*models.CronJob, error)
+	ImportNonCompliantPage(r io.Reader) (*models.CronJob, error)
 	GetNonCompliantEmployers(limit, offset int) ([]models.NonCompliantEmployerWithReasons, error)
 	GetNonCompliantEmployersCount() (int, error)
 	GetNonCompliantReasons() ([]models.NonCompliantReason, error)
@@ -26,6 +28,13 @@ type NonCompliantService interface {
 	GetNonCompliantEmployersByPostalCode(postalCode string, limit, offset int) (*models.NonCompliantEmployersByPostalCodeResponse, error)
 	GetNonCompliantEmployersByCoordinates(lat, lng float64, limit, offset int) (*models.NonCompliantEmployersByPostalCodeResponse, error)
 	CleanExistingAddresses() error
+	GetNonCompliantChanges(filters models.NonCompliantChangeFilters, limit, offset int) ([]models.NonCompliantEmployerChange, error)
+	GetNonCompliantChangesCount(filters models.NonCompliantChangeFilters) (int, error)
+	GetNonCompliantEmployerHistory(employerID string) (*models.NonCompliantEmployerHistory, error)
+	GetPenaltyStats() (*models.NonCompliantPenaltyStats, error)
+	GetLargestPenalties(limit int) ([]models.NonCompliantEmployerWithReasons, error)
+	GetRepeatOffenders(minListings, limit int) ([]models.NonCompliantRepeatOffender, error)
+	GetPenaltyTrends(period string) ([]models.NonCompliantTrendPoint, error)
 }
 
 type nonCompliantService struct {
@@ -33,14 +42,16 @@ type nonCompliantService struct {
 	logger            *log.Logger
 	postalCodeService PostalCodeService
 	geocodingService  PostalCodeGeocodingService
+	addressService    AddressService
 }
 
-func NewNonCompliantService(repo repos.NonCompliantRepository, logger *log.Logger, postalCodeService PostalCodeService, geocodingService PostalCodeGeocodingService) NonCompliantService {
+func NewNonCompliantService(repo repos.NonCompliantRepository, logger *log.Logger, postalCodeService PostalCodeService, geocodingService PostalCodeGeocodingService, addressService AddressService) NonCompliantService {
 	return &nonCompliantService{
 		repo:              repo,
 		logger:            logger,
 		postalCodeService: postalCodeService,
 		geocodingService:  geocodingService,
+		addressService:    addressService,
 	}
 }
 
@@ -65,29 +76,54 @@ func (s *nonCompliantService) ScrapeAndStoreNonCompliantEmployers() (*models.Cro
 		}
 	}()
 
-	// Initialize scraper
-	scraperInstance, err := scraper.NewScraper()
+	// The page comes from a headless browser by default, NON_COMPLIANT_SOURCE switches to a plain
+	// HTTP download or to saved pages listed in NON_COMPLIANT_PAGE_PATHS
+	fetcher, err := scraper.NewNonCompliantPageFetcher(os.Getenv("NON_COMPLIANT_SOURCE"), os.Getenv("NON_COMPLIANT_PAGE_PATHS"))
 	if err != nil {
 		cronJob.Status = "failed"
 		cronJob.ErrorMessage = func() *string { msg := fmt.Sprintf("Failed to initialize scraper: %v", err); return &msg }()
 		return cronJob, fmt.Errorf("failed to create scraper: %w", err)
 	}
-	defer scraperInstance.Close()
+	defer fetcher.Close()
 
 	// Scrape non-compliant employers data with reason descriptions
-	employersData, reasonDescriptions, err := scraperInstance.ScrapeNonCompliantEmployersWithReasons()
+	page, err := scraper.ScrapeNonCompliantPages(fetcher)
 	if err != nil {
 		cronJob.Status = "failed"
 		cronJob.ErrorMessage = func() *string { msg := fmt.Sprintf("Scraping failed: %v", err); return &msg }()
 		return cronJob, fmt.Errorf("failed to scrape non-compliant employers: %w", err)
 	}
 
-	s.logger.Info("Scraping completed", "employers_found", len(employersData), "reason_descriptions_found", len(reasonDescriptions))
+	if err := s.storeNonCompliantPage(page, cronJob, true); err != nil {
+		return cronJob, err
+	}
+
+	// Complete the job
+	cronJob.Status = "completed"
+	completedAt := time.Now()
+	cronJob.CompletedAt = &completedAt
+	cronJob.ResourcesProcessed = 1 // Number of scrape operations
+
+	s.logger.Info("Non-compliant employers scraping completed successfully",
+		"employers_scraped", len(page.Employers),
+		"records_processed", cronJob.RecordsProcessed)
+
+	return cronJob, nil
+}
+
+// storeNonCompliantPage stores the reasons and employers parsed from the non-compliant page. Employers
+// missing from the page are only marked delisted when the page is known to be the full list.
+func (s *nonCompliantService) storeNonCompliantPage(page *scraper.NonCompliantPage, cronJob *models.CronJob, fullList bool) error {
+	s.logger.Info("Scraping completed", "employers_found", len(page.Employers), "reason_descriptions_found", len(page.ReasonDescriptions), "warnings", len(page.Warnings))
+
+	for _, warning := range page.Warnings {
+		s.logger.Warn("Non-compliant page parsing", "warning", warning)
+	}
 
 	// Update reason descriptions in the database
-	if len(reasonDescriptions) > 0 {
-		s.logger.Info("Updating reason descriptions", "count", len(reasonDescriptions))
-		for reasonCode, description := range reasonDescriptions {
+	if len(page.ReasonDescriptions) > 0 {
+		s.logger.Info("Updating reason descriptions", "count", len(page.ReasonDescriptions))
+		for reasonCode, description := range page.ReasonDescriptions {
 			_, err := s.repo.UpsertReason(reasonCode, description)
 			if err != nil {
 				s.logger.Error("Failed to upsert reason", "code", reasonCode, "error", err)
@@ -98,32 +134,67 @@ func (s *nonCompliantService) ScrapeAndStoreNonCompliantEmployers() (*models.Cro
 	}
 
 	// Convert scraper data to models
-	scraperData := make([]models.ScraperNonCompliantData, len(employersData))
-	for i, employer := range employersData {
+	scraperData := make([]models.ScraperNonCompliantData, len(page.Employers))
+	for i, employer := range page.Employers {
 		scraperData[i] = convertToScraperNonCompliantData(employer)
 	}
 
 	// Use upsert approach to prevent duplicates while preserving data
-	// Note: Uniqueness is based on business_name + address + date_of_final_decision
+	// Note: Uniqueness is based on business_name + date_of_final_decision
 	// This allows the same business to have multiple violations over time
+	// Field changes against the stored rows are recorded in the change history
 	s.logger.Info("Upserting scraped employers with reasons (prevents duplicates)", "count", len(scraperData))
-	err = s.repo.UpsertEmployersWithReasons(scraperData)
-	if err != nil {
+	seenAt := time.Now()
+	if err := s.repo.UpsertEmployersWithReasons(scraperData); err != nil {
 		cronJob.Status = "failed"
 		cronJob.ErrorMessage = func() *string { msg := fmt.Sprintf("Failed to upsert employers: %v", err); return &msg }()
-		return cronJob, fmt.Errorf("failed to upsert scraped data: %w", err)
+		return fmt.Errorf("failed to upsert scraped data: %w", err)
+	}
+
+	// Employers missing from a non-empty scrape have been removed from the published list.
+	// An empty scrape is more likely a broken page than an empty list, so nothing is delisted.
+	if fullList && len(scraperData) > 0 {
+		delisted, err := s.repo.MarkDelistedEmployers(seenAt)
+		if err != nil {
+			s.logger.Error("Failed to mark delisted employers", "error", err)
+		} else if delisted > 0 {
+			s.logger.Info("Marked employers removed from the list", "count", delisted)
+		}
+	}
+
+	// Split the addresses of newly listed employers into their components
+	if _, err := s.addressService.ParseSourceAddresses(models.AddressSourceNonCompliant); err != nil {
+		s.logger.Error("Failed to parse non-compliant employer addresses", "error", err)
+	}
+
+	cronJob.RecordsProcessed = len(scraperData)
+	return nil
+}
+
+// ImportNonCompliantPage stores employers from a saved copy of the non-compliant employers page.
+// A saved page may hold a single page of the table, so missing employers aren't marked delisted.
+func (s *nonCompliantService) ImportNonCompliantPage(r io.Reader) (*models.CronJob, error) {
+	cronJob := &models.CronJob{
+		JobName:   "non_compliant_import",
+		Status:    "running",
+		StartedAt: time.Now(),
+	}
+
+	page, err := scraper.ParseNonCompliantPage(r)
+	if err != nil {
+		cronJob.Status = "failed"
+		cronJob.ErrorMessage = func() *string { msg := err.Error(); return &msg }()
+		return cronJob, err
+	}
+
+	if err := s.storeNonCompliantPage(page, cronJob, false); err != nil {
+		return cronJob, err
 	}
 
-	// Complete the job
 	cronJob.Status = "completed"
 	completedAt := time.Now()
 	cronJob.CompletedAt = &completedAt
-	cronJob.ResourcesProcessed = 1 // Number of scrape operations
-	cronJob.RecordsProcessed = len(scraperData)
-
-	s.logger.Info("Non-compliant employers scraping completed successfully",
-		"employers_scraped", len(employersData),
-		"records_processed", len(scraperData))
+	cronJob.ResourcesProcessed = 1
 
 	return cronJob, nil
 }
@@ -170,6 +241,56 @@ func (s *nonCompliantService) GetLatestScrapeInfo() (*models.CronJob, error) {
 	return mockJob, nil
 }
 
+// GetNonCompliantChanges returns recorded list changes, most recent first
+func (s *nonCompliantService) GetNonCompliantChanges(filters models.NonCompliantChangeFilters, limit, offset int) ([]models.NonCompliantEmployerChange, error) {
+	return s.repo.GetChanges(filters, limit, offset)
+}
+
+func (s *nonCompliantService) GetNonCompliantChangesCount(filters models.NonCompliantChangeFilters) (int, error) {
+	return s.repo.GetChangesCount(filters)
+}
+
+// GetNonCompliantEmployerHistory returns an employer together with its change history
+func (s *nonCompliantService) GetNonCompliantEmployerHistory(employerID string) (*models.NonCompliantEmployerHistory, error) {
+	employer, err := s.repo.GetEmployerByID(employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.GetEmployerChanges(employerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NonCompliantEmployerHistory{
+		Employer: employer,
+		Changes:  changes,
+	}, nil
+}
+
+// GetPenaltyStats returns penalty totals broken down by reason, province, year, status and bracket
+func (s *nonCompliantService) GetPenaltyStats() (*models.NonCompliantPenaltyStats, error) {
+	return s.repo.GetPenaltyStats()
+}
+
+func (s *nonCompliantService) GetLargestPenalties(limit int) ([]models.NonCompliantEmployerWithReasons, error) {
+	return s.repo.GetLargestPenalties(limit)
+}
+
+// GetRepeatOffenders returns employers listed under more than one decision. At least two listings
+// are required for an employer to count as a repeat offender.
+func (s *nonCompliantService) GetRepeatOffenders(minListings, limit int) ([]models.NonCompliantRepeatOffender, error) {
+	if minListings < 2 {
+		minListings = 2
+	}
+	return s.repo.GetRepeatOffenders(minListings, limit)
+}
+
+// GetPenaltyTrends returns penalty trend lines by "month" or "year" of the final decision
+func (s *nonCompliantService) GetPenaltyTrends(period string) ([]models.NonCompliantTrendPoint, error) {
+	return s.repo.GetPenaltyTrends(period)
+}
+
 // convertToScraperNonCompliantData converts scraper output to model input format
 func convertToScraperNonCompliantData(employer scraper_types.NonCompliantEmployerData) models.ScraperNonCompliantData {
 	return models.ScraperNonCompliantData{
@@ -443,7 +564,7 @@ func (s *nonCompliantService) CleanExistingAddresses() error {
 		
 		// Clean the address
 		originalAddress := *employer.Address
-		cleanedAddress := s.cleanAddress(originalAddress)
+		cleanedAddress := address.Standardize(originalAddress)
 		
 		// Skip if no change needed
 		if cleanedAddress == originalAddress {
@@ -468,72 +589,11 @@ func (s *nonCompliantService) CleanExistingAddresses() error {
 		"successful", successCount,
 		"unchanged", unchangedCount,
 		"failed", errorCount)
-	
-	return nil
-}
 
-// cleanAddress parses and reformats malformed addresses
-func (s *nonCompliantService) cleanAddress(rawAddress string) string {
-	if rawAddress == "" {
-		return rawAddress
-	}
-	
-	// Canadian provinces/territories (both English and French)
-	provinces := []string{
-		"Alberta", "AB", "Colombie-Britannique", "British Columbia", "BC",
-		"Manitoba", "MB", "Nouveau-Brunswick", "New Brunswick", "NB",
-		"Terre-Neuve-et-Labrador", "Newfoundland and Labrador", "NL",
-		"Territoires du Nord-Ouest", "Northwest Territories", "NT",
-		"Nouvelle-Écosse", "Nova Scotia", "NS", "Nunavut", "NU",
-		"Ontario", "ON", "Île-du-Prince-Édouard", "Prince Edward Island", "PE",
-		"Québec", "Quebec", "QC", "Saskatchewan", "SK", "Yukon", "YT",
-	}
-	
-	// Create regex pattern for provinces
-	provincePattern := "(" + strings.Join(provinces, "|") + ")"
-	regex := regexp.MustCompile(provincePattern + `\s*$`)
-	
-	// Check if address ends with a province
-	provinceMatch := regex.FindStringSubmatch(rawAddress)
-	if len(provinceMatch) == 0 {
-		return rawAddress // No province found, return as-is
-	}
-	
-	province := provinceMatch[1]
-	addressWithoutProvince := strings.TrimSpace(regex.ReplaceAllString(rawAddress, ""))
-	
-	// Look for common patterns where city runs into street address
-	// Pattern 1: Street name directly followed by city name (no comma)
-	streetPatterns := []*regexp.Regexp{
-		regexp.MustCompile(`^(.+(?:rue|street|ave|avenue|blvd|boulevard|rd|road|dr|drive|place|pl|way)\s+\w+)([A-Z][a-z]+(?:\s+[A-Z][a-z]+)*)\s*,?\s*$`),
-		regexp.MustCompile(`^(.+\s+)([A-Z][a-z]+(?:\s+[A-Z][a-z]+)*)\s*,?\s*$`),
-	}
-	
-	for _, pattern := range streetPatterns {
-		match := pattern.FindStringSubmatch(addressWithoutProvince)
-		if len(match) > 2 && match[2] != "" {
-			streetAddress := strings.TrimSpace(match[1])
-			cityName := strings.TrimSpace(match[2])
-			
-			// Additional validation: city name should be reasonable length (2+ chars, not all caps)
-			if len(cityName) > 1 && !regexp.MustCompile(`^[A-Z]+$`).MatchString(cityName) {
-				return streetAddress + ", " + cityName + ", " + province
-			}
-		}
+	// Cleaned addresses lose their parsed components, parse them again
+	if _, err := s.addressService.ParseSourceAddresses(models.AddressSourceNonCompliant); err != nil {
+		s.logger.Error("Failed to parse cleaned addresses", "error", err)
 	}
 	
-	// Fallback: if we have a province but couldn't parse the city, just add commas where needed
-	if strings.Contains(addressWithoutProvince, ",") {
-		return rawAddress // Already has commas, probably formatted correctly
-	} else {
-		// Try to add comma before last word before province (assuming it's the city)
-		parts := strings.Fields(strings.TrimSpace(addressWithoutProvince))
-		if len(parts) > 1 {
-			cityPart := parts[len(parts)-1]
-			streetPart := strings.Join(parts[:len(parts)-1], " ")
-			return streetPart + ", " + cityPart + ", " + province
-		}
-	}
-	
-	return rawAddress // Return original if can't parse
-}
\ No newline at end of file
+	return nil
+}
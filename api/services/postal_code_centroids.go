@@ -0,0 +1,182 @@
+package services
+
+import (
+	"bufio"
+	"canada-hires/address"
+	"canada-hires/models"
+	"database/sql"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+var fsaRegex = regexp.MustCompile(`^[ABCEGHJ-NPRSTVXY]\d[ABCEGHJ-NPRSTV-Z]$`)
+
+// ImportPostalCodeCentroids loads postal code and FSA centroids from a local file into
+// postal_codes, then derives FSA centroids from the full postal codes for FSAs the file doesn't
+// cover. Two layouts are read:
+//   - CSV with a header naming the postal code (or FSA), latitude and longitude columns, e.g. the
+//     Statistics Canada FSA centroids with a CFSAUID column
+//   - the tab separated GeoNames postal code dump for Canada (CA.txt or CA_full.txt)
+func (g *postalCodeGeocodingService) ImportPostalCodeCentroids(filePath string) (int, error) {
+	log.Info("Importing postal code centroids", "file_path", filePath)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open postal code centroids: %w", err)
+	}
+	defer file.Close()
+
+	centroids, err := parsePostalCodeCentroids(file)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := g.postalCodeRepo.UpsertCentroidsBatch(centroids); err != nil {
+		return 0, fmt.Errorf("failed to store postal code centroids: %w", err)
+	}
+
+	derived, err := g.postalCodeRepo.DeriveFSACentroids()
+	if err != nil {
+		return len(centroids), err
+	}
+
+	log.Info("Postal code centroids imported", "rows", len(centroids), "derived_fsa_centroids", derived)
+	return len(centroids), nil
+}
+
+// parsePostalCodeCentroids reads a centroid file, keeping the last row of any repeated code
+func parsePostalCodeCentroids(r io.Reader) ([]*models.PostalCodeCoordinates, error) {
+	buffered := bufio.NewReader(r)
+	firstLine, err := buffered.Peek(256)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read postal code centroids: %w", err)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	// GeoNames dumps have no header: country code, postal code, place, admin names and codes,
+	// latitude, longitude, accuracy
+	columns := map[string]int{"postal_code": 1, "latitude": 9, "longitude": 10}
+	if strings.HasPrefix(string(firstLine), "CA\t") {
+		// Leading space trimming would also swallow the empty tab separated fields
+		reader.Comma = '\t'
+		reader.TrimLeadingSpace = false
+	} else {
+		headers, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read postal code centroids header: %w", err)
+		}
+		columns = mapCentroidColumns(headers)
+		for _, required := range []string{"postal_code", "latitude", "longitude"} {
+			if _, ok := columns[required]; !ok {
+				return nil, fmt.Errorf("postal code centroids are missing a %s column", required)
+			}
+		}
+	}
+
+	byCode := make(map[string]*models.PostalCodeCoordinates)
+	var order []string
+	lineNumber := 0
+	for {
+		record, err := reader.Read()
+		lineNumber++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Warn("Skipping malformed postal code centroid line", "line", lineNumber, "error", err.Error())
+			continue
+		}
+
+		centroid := parseCentroidRecord(record, columns)
+		if centroid == nil {
+			continue
+		}
+		if _, seen := byCode[centroid.PostalCode]; !seen {
+			order = append(order, centroid.PostalCode)
+		}
+		byCode[centroid.PostalCode] = centroid
+	}
+
+	if len(order) == 0 {
+		return nil, fmt.Errorf("postal code centroids contain no usable rows")
+	}
+
+	// A single upsert statement can't touch the same postal code twice
+	centroids := make([]*models.PostalCodeCoordinates, 0, len(order))
+	for _, code := range order {
+		centroids = append(centroids, byCode[code])
+	}
+
+	return centroids, nil
+}
+
+// mapCentroidColumns finds the column index of each field in the centroid file header
+func mapCentroidColumns(headers []string) map[string]int {
+	columns := make(map[string]int)
+	for i, header := range headers {
+		h := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+		switch {
+		case strings.Contains(h, "postal") || h == "fsa" || h == "cfsauid":
+			columns["postal_code"] = i
+		case strings.HasPrefix(h, "lat"):
+			columns["latitude"] = i
+		case strings.HasPrefix(h, "lon") || strings.HasPrefix(h, "lng"):
+			columns["longitude"] = i
+		}
+	}
+	return columns
+}
+
+// parseCentroidRecord converts a single row, returning nil for rows without a valid code or coordinates
+func parseCentroidRecord(record []string, columns map[string]int) *models.PostalCodeCoordinates {
+	field := func(name string) string {
+		i := columns[name]
+		if i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	code := strings.ToUpper(strings.ReplaceAll(field("postal_code"), " ", ""))
+	var postalCode, precision string
+	switch {
+	case len(code) == 6:
+		postalCode = address.ExtractPostalCode(code)
+		precision = models.PrecisionPostalCode
+	case fsaRegex.MatchString(code):
+		postalCode = code
+		precision = models.PrecisionFSA
+	}
+	if postalCode == "" {
+		return nil
+	}
+
+	latitude, err := strconv.ParseFloat(field("latitude"), 64)
+	if err != nil {
+		return nil
+	}
+	longitude, err := strconv.ParseFloat(field("longitude"), 64)
+	if err != nil {
+		return nil
+	}
+
+	provider := models.PostalCodeProviderDataset
+	return &models.PostalCodeCoordinates{
+		PostalCode: postalCode,
+		Latitude:   sql.NullFloat64{Float64: latitude, Valid: true},
+		Longitude:  sql.NullFloat64{Float64: longitude, Valid: true},
+		Provider:   &provider,
+		Precision:  &precision,
+	}
+}
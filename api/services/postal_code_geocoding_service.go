@@ -1,14 +1,13 @@
 package services
 
 import (
+	"canada-hires/address"
 	"canada-hires/models"
 	"canada-hires/repos"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"net/http"
-	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,71 +20,23 @@ type PostalCodeGeocodingService interface {
 	GeocodeFullAddress(address string) (latitude, longitude float64, err error)
 	GetAllPostalCodes() (map[string]models.PostalCodeCoordinates, error)
 	UpsertPostalCode(postalCode *models.PostalCodeCoordinates) error
+	ImportPostalCodeCentroids(filePath string) (int, error)
 }
 
-// Pelias geocoding response structure
-type PeliasGeocodingInfo struct {
-	Version     string                 `json:"version"`
-	Attribution string                 `json:"attribution"`
-	Query       map[string]interface{} `json:"query"`
-	Warnings    []string               `json:"warnings,omitempty"`
-	Errors      []string               `json:"errors,omitempty"`
-	Engine      map[string]interface{} `json:"engine"`
-	Timestamp   int64                  `json:"timestamp"`
-}
-
-type PeliasGeometry struct {
-	Type        string    `json:"type"`
-	Coordinates []float64 `json:"coordinates"` // [longitude, latitude]
-}
-
-type PeliasProperties struct {
-	ID           string  `json:"id"`
-	GID          string  `json:"gid"`
-	Layer        string  `json:"layer"`
-	Source       string  `json:"source"`
-	SourceID     string  `json:"source_id"`
-	CountryCode  string  `json:"country_code,omitempty"`
-	Name         string  `json:"name"`
-	PostalCode   string  `json:"postalcode,omitempty"`
-	Confidence   float64 `json:"confidence"`
-	MatchType    string  `json:"match_type,omitempty"`
-	Distance     float64 `json:"distance,omitempty"`
-	Accuracy     string  `json:"accuracy,omitempty"`
-	Country      string  `json:"country,omitempty"`
-	CountryGID   string  `json:"country_gid,omitempty"`
-	CountryA     string  `json:"country_a,omitempty"`
-	Region       string  `json:"region,omitempty"`
-	RegionGID    string  `json:"region_gid,omitempty"`
-	RegionA      string  `json:"region_a,omitempty"`
-	Locality     string  `json:"locality,omitempty"`
-	LocalityGID  string  `json:"locality_gid,omitempty"`
-	Label        string  `json:"label,omitempty"`
-}
-
-type PeliasFeature struct {
-	Type       string           `json:"type"`
-	Geometry   PeliasGeometry   `json:"geometry"`
-	Properties PeliasProperties `json:"properties"`
-	Bbox       []float64        `json:"bbox,omitempty"`
-}
-
-type PeliasResponse struct {
-	Geocoding PeliasGeocodingInfo `json:"geocoding"`
-	Type      string              `json:"type"`
-	Features  []PeliasFeature     `json:"features"`
-	Bbox      []float64           `json:"bbox,omitempty"`
-}
+// Results of network providers below this confidence are stored but sent to the review queue,
+// unless GEOCODER_REVIEW_CONFIDENCE is set
+const defaultReviewConfidence = 0.6
 
 type postalCodeGeocodingService struct {
-	peliasServerURL   string
-	client            *http.Client
+	geocoder          *GeocoderChain
 	postalCodeRepo    repos.PostalCodeRepository
 	postalCodeService PostalCodeService
 	addressCacheRepo  repos.AddressGeocodingCacheRepository
+	reviewRepo        repos.GeocodingReviewRepository
+	reviewConfidence  float64
 }
 
-func NewPostalCodeGeocodingService(postalCodeRepo repos.PostalCodeRepository, postalCodeService PostalCodeService, addressCacheRepo repos.AddressGeocodingCacheRepository) PostalCodeGeocodingService {
+func NewPostalCodeGeocodingService(postalCodeRepo repos.PostalCodeRepository, postalCodeService PostalCodeService, addressCacheRepo repos.AddressGeocodingCacheRepository, reviewRepo repos.GeocodingReviewRepository) PostalCodeGeocodingService {
 	homeserverURL := os.Getenv("HOMESERVER_URL")
 	if homeserverURL == "" {
 		homeserverURL = "http://homeserver:4000"
@@ -93,91 +44,43 @@ func NewPostalCodeGeocodingService(postalCodeRepo repos.PostalCodeRepository, po
 	} else {
 		log.Info("Homeserver configured", "url", homeserverURL)
 	}
-	
-	peliasServerURL := homeserverURL
+
+	// Providers are tried in the order listed in GEOCODER_CHAIN, each optionally with the minimum
+	// confidence its results need, e.g. "pelias:0.8,nominatim:0.6,local"
+	chainConfig := os.Getenv("GEOCODER_CHAIN")
+	if chainConfig == "" {
+		chainConfig = DefaultGeocoderChain
+	}
+
+	geocoder, err := NewGeocoderChainFromConfig(chainConfig, homeserverURL, os.Getenv("NOMINATIM_URL"), postalCodeRepo)
+	if err != nil {
+		log.Error("Invalid geocoder chain, using default", "chain", chainConfig, "error", err)
+		geocoder, _ = NewGeocoderChainFromConfig(DefaultGeocoderChain, homeserverURL, "", postalCodeRepo)
+	}
+	log.Info("Geocoder chain configured", "providers", geocoder.Providers())
+
+	reviewConfidence := defaultReviewConfidence
+	if value := os.Getenv("GEOCODER_REVIEW_CONFIDENCE"); value != "" {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			log.Error("Invalid GEOCODER_REVIEW_CONFIDENCE, using default", "value", value, "default", defaultReviewConfidence)
+		} else {
+			reviewConfidence = parsed
+		}
+	}
 
 	service := &postalCodeGeocodingService{
-		peliasServerURL: peliasServerURL,
-		client: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		geocoder:          geocoder,
 		postalCodeRepo:    postalCodeRepo,
 		postalCodeService: postalCodeService,
 		addressCacheRepo:  addressCacheRepo,
+		reviewRepo:        reviewRepo,
+		reviewConfidence:  reviewConfidence,
 	}
 
 	return service
 }
 
-// verifyLocationMatch checks if the geocoding result matches the expected province/territory
-func (g *postalCodeGeocodingService) verifyLocationMatch(result PeliasFeature, expectedProvince string) bool {
-	if expectedProvince == "" {
-		return true // No verification needed if no expected province provided
-	}
-	
-	// Normalize the expected province for comparison
-	expectedNormalized := strings.ToLower(strings.TrimSpace(expectedProvince))
-	
-	// Check various location fields from Pelias response
-	fieldsToCheck := []string{
-		result.Properties.Region,        // Primary province/territory field
-		result.Properties.RegionA,       // Abbreviated province (e.g., "AB", "ON")
-		result.Properties.Label,         // Full address label
-	}
-	
-	// Canadian province/territory mappings
-	provinceMap := map[string][]string{
-		"alberta":                    {"alberta", "ab"},
-		"british columbia":           {"british columbia", "bc"},
-		"manitoba":                   {"manitoba", "mb"},
-		"new brunswick":              {"new brunswick", "nb"},
-		"newfoundland and labrador":  {"newfoundland and labrador", "nl", "newfoundland"},
-		"northwest territories":      {"northwest territories", "nt"},
-		"nova scotia":                {"nova scotia", "ns"},
-		"nunavut":                    {"nunavut", "nu"},
-		"ontario":                    {"ontario", "on"},
-		"prince edward island":       {"prince edward island", "pei", "pe"},
-		"quebec":                     {"quebec", "qc", "québec"},
-		"saskatchewan":               {"saskatchewan", "sk"},
-		"yukon":                      {"yukon", "yt", "yukon territory"},
-	}
-	
-	// Find which province the expected province matches
-	var expectedVariants []string
-	for province, variants := range provinceMap {
-		for _, variant := range variants {
-			if variant == expectedNormalized {
-				expectedVariants = provinceMap[province]
-				break
-			}
-		}
-		if len(expectedVariants) > 0 {
-			break
-		}
-	}
-	
-	// If we couldn't map the expected province, just do a simple string match
-	if len(expectedVariants) == 0 {
-		expectedVariants = []string{expectedNormalized}
-	}
-	
-	// Check if any of the Pelias fields match any of the expected variants
-	for _, field := range fieldsToCheck {
-		if field == "" {
-			continue
-		}
-		fieldNormalized := strings.ToLower(strings.TrimSpace(field))
-		
-		for _, variant := range expectedVariants {
-			if strings.Contains(fieldNormalized, variant) {
-				return true
-			}
-		}
-	}
-	
-	return false
-}
-
 func (g *postalCodeGeocodingService) GeocodePostalCode(postalCode string, expectedProvince ...string) (latitude, longitude float64, err error) {
 	if postalCode == "" {
 		return 0, 0, fmt.Errorf("postal code is empty")
@@ -189,78 +92,46 @@ func (g *postalCodeGeocodingService) GeocodePostalCode(postalCode string, expect
 		return 0, 0, fmt.Errorf("invalid postal code format: %s", postalCode)
 	}
 
-	// First, check if we already have this postal code in our database
+	// First, check if we already have this postal code in our database. Postal codes that were
+	// only placed at their FSA centroid are resolved again in case a precise provider is reachable
+	// now, unless a reviewer placed them.
 	cachedCoords, err := g.postalCodeRepo.GetByPostalCode(cleanedPostalCode)
-	if err == nil && cachedCoords != nil && cachedCoords.Latitude.Valid && cachedCoords.Longitude.Valid {
+	if err == nil && cachedCoords != nil && cachedCoords.Latitude.Valid && cachedCoords.Longitude.Valid &&
+		(cachedCoords.ManualOverride || cachedCoords.Precision == nil || *cachedCoords.Precision != models.PrecisionFSA) {
 		return cachedCoords.Latitude.Float64, cachedCoords.Longitude.Float64, nil
 	}
 
-	// Build Pelias search URL - search by postal code text
-	apiURL := fmt.Sprintf("%s/v1/search?text=%s",
-		g.peliasServerURL,
-		url.QueryEscape(cleanedPostalCode))
-
-	// Make the request
-	resp, err := g.client.Get(apiURL)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to make Pelias geocoding request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("Pelias API returned status %d", resp.StatusCode)
-	}
-
-	// Parse the response
-	var peliasResult PeliasResponse
-	if err := json.NewDecoder(resp.Body).Decode(&peliasResult); err != nil {
-		return 0, 0, fmt.Errorf("failed to decode Pelias response: %w", err)
-	}
-
-	// Check if we got any results
-	if len(peliasResult.Features) == 0 {
-		return 0, 0, fmt.Errorf("no geocoding results found for postal code: %s", cleanedPostalCode)
-	}
-
-	// Get the coordinates from the first result
-	result := peliasResult.Features[0]
-	if len(result.Geometry.Coordinates) < 2 {
-		return 0, 0, fmt.Errorf("invalid coordinates in Pelias response")
-	}
-	
-	// Verify postal code match - the returned postal code should match our request
-	if result.Properties.PostalCode != "" && result.Properties.PostalCode != cleanedPostalCode {
-		return 0, 0, fmt.Errorf("geocoded postal code %s does not match requested postal code %s", 
-			result.Properties.PostalCode, cleanedPostalCode)
-	}
-	
-	// Verify location match if expected province is provided
 	var expectedProvinceStr string
 	if len(expectedProvince) > 0 {
 		expectedProvinceStr = expectedProvince[0]
 	}
-	
-	if !g.verifyLocationMatch(result, expectedProvinceStr) {
-		return 0, 0, fmt.Errorf("geocoded location for postal code %s does not match expected province %s (got: %s)", 
-			cleanedPostalCode, expectedProvinceStr, result.Properties.Region)
+
+	result, rejections, err := g.geocoder.GeocodePostalCode(cleanedPostalCode, expectedProvinceStr)
+	rejected := g.queueReview(models.GeocodingTargetPostalCode, cleanedPostalCode, cleanedPostalCode, expectedProvinceStr, result, rejections)
+	if err != nil {
+		return 0, 0, err
+	}
+	if rejected {
+		return 0, 0, fmt.Errorf("geocoded location for postal code %s was rejected in review", cleanedPostalCode)
 	}
-	
-	// Pelias returns coordinates as [longitude, latitude]
-	longitude = result.Geometry.Coordinates[0]
-	latitude = result.Geometry.Coordinates[1]
 
 	// Save the coordinates to the database for future use
 	coordsToSave := &models.PostalCodeCoordinates{
 		PostalCode: cleanedPostalCode,
-		Latitude:   sql.NullFloat64{Float64: latitude, Valid: true},
-		Longitude:  sql.NullFloat64{Float64: longitude, Valid: true},
+		Latitude:   sql.NullFloat64{Float64: result.Latitude, Valid: true},
+		Longitude:  sql.NullFloat64{Float64: result.Longitude, Valid: true},
+		Provider:   &result.Provider,
+		Confidence: &result.Confidence,
+		Precision:  &result.Precision,
+		MatchType:  optionalString(result.MatchType),
+		Layer:      optionalString(result.Layer),
 	}
 
 	if err := g.postalCodeRepo.Upsert(coordsToSave); err != nil {
 		// Don't fail the request, just continue silently
 	}
 
-	return latitude, longitude, nil
+	return result.Latitude, result.Longitude, nil
 }
 
 func (g *postalCodeGeocodingService) GeocodeMultiplePostalCodes(postalCodes []string) (map[string]models.PostalCodeCoordinates, error) {
@@ -330,84 +201,50 @@ func (g *postalCodeGeocodingService) UpsertPostalCode(postalCode *models.PostalC
 	return g.postalCodeRepo.Upsert(postalCode)
 }
 
-// GeocodeFullAddress geocodes a full address string using Pelias search API
-func (g *postalCodeGeocodingService) GeocodeFullAddress(address string) (latitude, longitude float64, err error) {
-	if address == "" {
+// GeocodeFullAddress geocodes a full address string through the geocoder chain
+func (g *postalCodeGeocodingService) GeocodeFullAddress(fullAddress string) (latitude, longitude float64, err error) {
+	if fullAddress == "" {
 		return 0, 0, fmt.Errorf("address is empty")
 	}
 
 	// Clean the address by trimming whitespace
-	cleanedAddress := strings.TrimSpace(address)
+	cleanedAddress := strings.TrimSpace(fullAddress)
 	if cleanedAddress == "" {
 		return 0, 0, fmt.Errorf("address is empty after cleaning")
 	}
 
 	// Check cache first
-	normalizedAddress := repos.NormalizeAddress(cleanedAddress)
+	normalizedAddress := address.Normalize(cleanedAddress)
 	if cached, err := g.addressCacheRepo.GetByNormalizedAddress(normalizedAddress); err == nil && cached != nil {
 		return cached.Latitude, cached.Longitude, nil
 	}
 
-	// Build Pelias search URL for full address search
-	// Use boundary.country=CAN to restrict results to Canada
-	apiURL := fmt.Sprintf("%s/v1/search?text=%s&boundary.country=CAN&size=1",
-		g.peliasServerURL,
-		url.QueryEscape(cleanedAddress))
-
-	// Make the request
-	resp, err := g.client.Get(apiURL)
+	result, rejections, err := g.geocoder.GeocodeAddress(cleanedAddress)
+	rejected := g.queueReview(models.GeocodingTargetAddress, normalizedAddress, cleanedAddress, "", result, rejections)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to make Pelias geocoding request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("Pelias API returned status %d", resp.StatusCode)
-	}
-
-	// Parse the response
-	var peliasResult PeliasResponse
-	if err := json.NewDecoder(resp.Body).Decode(&peliasResult); err != nil {
-		return 0, 0, fmt.Errorf("failed to decode Pelias response: %w", err)
-	}
-
-	// Check if we got any results
-	if len(peliasResult.Features) == 0 {
-		return 0, 0, fmt.Errorf("no geocoding results found for address: %s", cleanedAddress)
+		return 0, 0, err
 	}
-
-	// Get the first result
-	result := peliasResult.Features[0]
-	if len(result.Geometry.Coordinates) < 2 {
-		return 0, 0, fmt.Errorf("invalid coordinates in Pelias response")
-	}
-
-
-	// Ensure the result is in Canada
-	if result.Properties.CountryCode != "CA" && result.Properties.CountryA != "CAN" {
-		return 0, 0, fmt.Errorf("geocoding result is not in Canada: %s for address: %s", 
-			result.Properties.Country, cleanedAddress)
+	if rejected {
+		return 0, 0, fmt.Errorf("geocoded location for address %s was rejected in review", cleanedAddress)
 	}
 
-	// Pelias returns coordinates as [longitude, latitude]
-	longitude = result.Geometry.Coordinates[0]
-	latitude = result.Geometry.Coordinates[1]
-
-	// Validate coordinates are within reasonable bounds for Canada
-	// Canada latitude: approximately 41.7 to 83.1
-	// Canada longitude: approximately -141.0 to -52.6
-	if latitude < 41.0 || latitude > 84.0 || longitude < -142.0 || longitude > -52.0 {
-		return 0, 0, fmt.Errorf("coordinates outside Canada bounds: lat=%.6f, lng=%.6f for address: %s", 
-			latitude, longitude, cleanedAddress)
+	// Local results are postal code centroids that can be recomputed any time, caching them would
+	// keep a better match from a network provider out once it is reachable again
+	if result.Provider == GeocoderLocal {
+		return result.Latitude, result.Longitude, nil
 	}
 
 	// Save to cache for future use
 	cacheEntry := &models.AddressGeocodingCache{
 		Address:           cleanedAddress,
 		NormalizedAddress: normalizedAddress,
-		Latitude:          latitude,
-		Longitude:         longitude,
-		Confidence:        &result.Properties.Confidence,
+		Latitude:          result.Latitude,
+		Longitude:         result.Longitude,
+		Confidence:        &result.Confidence,
+		Provider:          &result.Provider,
+		Precision:         &result.Precision,
+		MatchType:         optionalString(result.MatchType),
+		Layer:             optionalString(result.Layer),
 		GeocodedAt:        time.Now(),
 	}
 
@@ -416,7 +253,85 @@ func (g *postalCodeGeocodingService) GeocodeFullAddress(address string) (latitud
 		// Just log the error and continue
 	}
 
-	return latitude, longitude, nil
+	return result.Latitude, result.Longitude, nil
+}
+
+
+
+// queueReview sends a geocoding result a reviewer should look at to the review queue: the result
+// that was stored when its confidence is below the review threshold, or else the first result
+// the chain discarded when no network provider found one. Local results are centroids that are
+// recomputed from the data, so they are never reviewed. Returns true when a reviewer already
+// rejected the result found, which must then not be stored.
+func (g *postalCodeGeocodingService) queueReview(targetType, targetKey, query, expectedProvince string, result *GeocodeResult, rejections []*GeocodeRejection) bool {
+	var review *models.GeocodingReview
+	switch {
+	case result != nil && result.Provider != GeocoderLocal:
+		rejected, err := g.reviewRepo.IsRejected(targetType, targetKey, result.Provider, result.Latitude, result.Longitude)
+		if err != nil {
+			log.Warn("Failed to check geocoding reviews", "target", targetKey, "error", err)
+		}
+		if rejected {
+			return true
+		}
+		if result.Confidence >= g.reviewConfidence {
+			return false
+		}
+		review = newGeocodingReview(targetType, targetKey, query, expectedProvince, result, models.GeocodingReviewLowConfidence,
+			fmt.Sprintf("confidence %.2f below review threshold %.2f", result.Confidence, g.reviewConfidence))
+		review.Stored = true
+	default:
+		for _, rejection := range rejections {
+			if rejection.Result.Provider != GeocoderLocal {
+				review = newGeocodingReview(targetType, targetKey, query, expectedProvince, rejection.Result, rejection.Reason, rejection.Detail)
+				break
+			}
+		}
+	}
+	if review == nil {
+		return false
+	}
+
+	queued, err := g.reviewRepo.Enqueue(review)
+	if err != nil {
+		log.Warn("Failed to queue geocoding review", "target", targetKey, "reason", review.Reason, "error", err)
+		return false
+	}
+	if queued {
+		log.Info("Geocoding result queued for review", "target_type", targetType, "target", targetKey, "provider", review.Provider, "reason", review.Reason)
+	}
+
+	return false
 }
 
+func newGeocodingReview(targetType, targetKey, query, expectedProvince string, result *GeocodeResult, reason, detail string) *models.GeocodingReview {
+	review := &models.GeocodingReview{
+		TargetType:         targetType,
+		TargetKey:          targetKey,
+		Query:              query,
+		Provider:           result.Provider,
+		Latitude:           result.Latitude,
+		Longitude:          result.Longitude,
+		Confidence:         &result.Confidence,
+		Precision:          optionalString(result.Precision),
+		MatchType:          optionalString(result.MatchType),
+		Layer:              optionalString(result.Layer),
+		ResultPostalCode:   optionalString(result.PostalCode),
+		ResultProvinceCode: optionalString(result.ProvinceCode),
+		Reason:             reason,
+		Detail:             optionalString(detail),
+		Status:             models.GeocodingReviewPending,
+	}
+	if code, ok := address.ProvinceCode(expectedProvince); ok {
+		review.ExpectedProvinceCode = &code
+	}
+	return review
+}
 
+// optionalString returns nil for an empty string, to store it as NULL
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -1,13 +1,11 @@
 package services
 
 import (
+	"canada-hires/address"
 	"regexp"
 	"strings"
 )
 
-// Canadian postal code regex pattern: A1A 1A1 or A1A1A1
-var postalCodeRegex = regexp.MustCompile(`([A-Za-z]\d[A-Za-z])\s*(\d[A-Za-z]\d)`)
-
 // PostalCodeService handles postal code extraction and validation
 type PostalCodeService interface {
 	ExtractPostalCode(address string) string
@@ -22,22 +20,8 @@ func NewPostalCodeService() PostalCodeService {
 }
 
 // ExtractPostalCode extracts a Canadian postal code from an address string
-func (p *postalCodeService) ExtractPostalCode(address string) string {
-	if address == "" {
-		return ""
-	}
-
-	// Clean the address - remove common prefixes and extra spaces
-	cleaned := strings.TrimSpace(address)
-	
-	// Find postal code pattern in the address
-	matches := postalCodeRegex.FindStringSubmatch(cleaned)
-	if len(matches) >= 3 {
-		// Format as A1A 1A1
-		return strings.ToUpper(matches[1] + " " + matches[2])
-	}
-	
-	return ""
+func (p *postalCodeService) ExtractPostalCode(text string) string {
+	return address.ExtractPostalCode(text)
 }
 
 // ValidatePostalCode checks if a postal code follows Canadian format
@@ -0,0 +1,310 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/uuid"
+)
+
+// Attachment limits used when ATTACHMENT_MAX_BYTES and ATTACHMENT_MAX_PER_REPORT are not set
+const (
+	DefaultAttachmentMaxBytes   = 10 << 20
+	defaultAttachmentsPerReport = 5
+)
+
+// File extensions of the stored attachment types
+var attachmentExtensions = map[string]string{
+	models.AttachmentTypeJPEG: ".jpg",
+	models.AttachmentTypePNG:  ".png",
+	models.AttachmentTypePDF:  ".pdf",
+}
+
+var (
+	// ErrAttachmentTooLarge is returned for files over the size limit
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	// ErrTooManyAttachments is returned when a report already has the most attachments allowed
+	ErrTooManyAttachments = errors.New("report has too many attachments")
+	// ErrDuplicateAttachment is returned when the same file is already attached to the report
+	ErrDuplicateAttachment = errors.New("file is already attached to this report")
+	// ErrAttachmentForbidden is returned when a user changes attachments of someone else's report
+	ErrAttachmentForbidden = errors.New("only the report author or a moderator can change its attachments")
+)
+
+// UploadAttachmentRequest attaches a file to a report
+type UploadAttachmentRequest struct {
+	ReportID string
+	Uploader *models.User
+	FileName string
+	Data     []byte
+}
+
+type ReportAttachmentService interface {
+	MaxBytes() int64
+	UploadAttachment(req *UploadAttachmentRequest) (*models.ReportAttachment, error)
+	GetAttachments(reportID string, viewer *models.User) ([]*models.ReportAttachment, error)
+	OpenAttachment(reportID, attachmentID string, viewer *models.User) (*models.ReportAttachment, io.ReadCloser, error)
+	SetVisibility(reportID, attachmentID, visibility, moderatorID string) (*models.ReportAttachment, error)
+	DeleteAttachment(reportID, attachmentID string, user *models.User) error
+}
+
+type reportAttachmentService struct {
+	repo          repos.ReportAttachmentRepository
+	reportService ReportService
+	storage       AttachmentStorage
+	maxBytes      int64
+	maxPerReport  int
+}
+
+func NewReportAttachmentService(repo repos.ReportAttachmentRepository, reportService ReportService) ReportAttachmentService {
+	storage, err := NewAttachmentStorageFromConfig()
+	if err != nil {
+		log.Error("Invalid attachment storage, storing attachments on local disk", "error", err, "dir", defaultAttachmentDir)
+		storage, err = NewLocalAttachmentStorage(defaultAttachmentDir)
+		if err != nil {
+			log.Fatal("Failed to create attachment storage", "error", err)
+		}
+	}
+	log.Info("Attachment storage configured", "backend", storage.Name())
+
+	return &reportAttachmentService{
+		repo:          repo,
+		reportService: reportService,
+		storage:       storage,
+		maxBytes:      int64(envPositiveInt("ATTACHMENT_MAX_BYTES", DefaultAttachmentMaxBytes)),
+		maxPerReport:  envPositiveInt("ATTACHMENT_MAX_PER_REPORT", defaultAttachmentsPerReport),
+	}
+}
+
+// envPositiveInt reads a positive integer setting, falling back to the default when it's missing
+// or invalid
+func envPositiveInt(name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+	parsed, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || parsed <= 0 {
+		log.Error("Invalid "+name+", using default", "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return parsed
+}
+
+// MaxBytes is the size limit of an uploaded file
+func (s *reportAttachmentService) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// UploadAttachment validates a file, strips its metadata and stores it. The checksum is computed
+// on the stored file, so uploading the same photo twice is detected even with different metadata.
+// Attachments are private until a moderator makes them public.
+func (s *reportAttachmentService) UploadAttachment(req *UploadAttachmentRequest) (*models.ReportAttachment, error) {
+	if req.Uploader == nil {
+		return nil, ErrAttachmentForbidden
+	}
+	if int64(len(req.Data)) > s.maxBytes {
+		return nil, ErrAttachmentTooLarge
+	}
+	if len(req.Data) == 0 {
+		return nil, fmt.Errorf("%w: file is empty", ErrUnsupportedAttachment)
+	}
+
+	report, err := s.reportService.GetReportByID(req.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	if report.UserID != req.Uploader.ID && !req.Uploader.IsModerator() {
+		return nil, ErrAttachmentForbidden
+	}
+
+	count, err := s.repo.CountByReportID(report.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= s.maxPerReport {
+		return nil, fmt.Errorf("%w: at most %d files can be attached", ErrTooManyAttachments, s.maxPerReport)
+	}
+
+	contentType, data, stripped, err := sanitizeAttachment(req.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+	if _, err := s.repo.GetByChecksum(report.ID, checksum); err == nil {
+		return nil, ErrDuplicateAttachment
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check for duplicate attachment: %w", err)
+	}
+
+	attachment := &models.ReportAttachment{
+		ID:               uuid.New().String(),
+		ReportID:         report.ID,
+		UploadedBy:       req.Uploader.ID,
+		FileName:         cleanAttachmentFileName(req.FileName, contentType),
+		ContentType:      contentType,
+		SizeBytes:        int64(len(data)),
+		ChecksumSHA256:   checksum,
+		StorageBackend:   s.storage.Name(),
+		MetadataStripped: stripped,
+		Visibility:       models.AttachmentPrivate,
+	}
+	attachment.StorageKey = "reports/" + report.ID + "/" + attachment.ID + attachmentExtensions[contentType]
+
+	if err := s.storage.Put(attachment.StorageKey, contentType, data); err != nil {
+		return nil, fmt.Errorf("failed to store attachment: %w", err)
+	}
+	if err := s.repo.Create(attachment); err != nil {
+		if deleteErr := s.storage.Delete(attachment.StorageKey); deleteErr != nil {
+			log.Warn("Failed to remove stored attachment", "key", attachment.StorageKey, "error", deleteErr)
+		}
+		return nil, err
+	}
+
+	log.Info("Report attachment uploaded",
+		"report_id", report.ID,
+		"attachment_id", attachment.ID,
+		"content_type", contentType,
+		"size", attachment.SizeBytes,
+		"removed_bytes", len(req.Data)-len(data))
+
+	return attachment, nil
+}
+
+// cleanAttachmentFileName keeps the base name of an uploaded file without control characters and
+// gives it the extension of its detected type
+func cleanAttachmentFileName(name, contentType string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' || r == '\\' {
+			return -1
+		}
+		return r
+	}, filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	name = strings.TrimSpace(strings.TrimSuffix(name, filepath.Ext(name)))
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+	return name + attachmentExtensions[contentType]
+}
+
+// GetAttachments returns the attachments of a report the viewer can see. Moderators and the
+// report author see every attachment, others only the public ones of published reports.
+func (s *reportAttachmentService) GetAttachments(reportID string, viewer *models.User) ([]*models.ReportAttachment, error) {
+	report, err := s.reportService.GetVisibleReport(reportID, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByReportID(report.ID, !canSeePrivateAttachments(report, viewer))
+}
+
+// OpenAttachment returns an attachment the viewer can see and its content, which the caller closes
+func (s *reportAttachmentService) OpenAttachment(reportID, attachmentID string, viewer *models.User) (*models.ReportAttachment, io.ReadCloser, error) {
+	report, err := s.reportService.GetVisibleReport(reportID, viewer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := s.getReportAttachment(report.ID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Private attachments are reported as not found so their existence isn't disclosed
+	if attachment.Visibility != models.AttachmentPublic && !canSeePrivateAttachments(report, viewer) {
+		return nil, nil, fmt.Errorf("failed to get attachment: %w", sql.ErrNoRows)
+	}
+
+	content, err := s.storage.Get(attachment.StorageKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read attachment %s: %w", attachment.ID, err)
+	}
+
+	return attachment, content, nil
+}
+
+// SetVisibility makes an attachment public or private again
+func (s *reportAttachmentService) SetVisibility(reportID, attachmentID, visibility, moderatorID string) (*models.ReportAttachment, error) {
+	if visibility != models.AttachmentPublic && visibility != models.AttachmentPrivate {
+		return nil, fmt.Errorf("invalid visibility: %s", visibility)
+	}
+	if _, err := s.getReportAttachment(reportID, attachmentID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.repo.SetVisibility(attachmentID, visibility, moderatorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update attachment visibility: %w", err)
+	}
+
+	log.Info("Report attachment visibility changed",
+		"report_id", reportID,
+		"attachment_id", attachmentID,
+		"visibility", visibility,
+		"moderator_id", moderatorID)
+
+	return attachment, nil
+}
+
+// DeleteAttachment removes an attachment and its file. Report authors can remove their own
+// attachments, moderators any attachment.
+func (s *reportAttachmentService) DeleteAttachment(reportID, attachmentID string, user *models.User) error {
+	if user == nil {
+		return ErrAttachmentForbidden
+	}
+
+	report, err := s.reportService.GetReportByID(reportID)
+	if err != nil {
+		return err
+	}
+	if report.UserID != user.ID && !user.IsModerator() {
+		return ErrAttachmentForbidden
+	}
+
+	attachment, err := s.getReportAttachment(report.ID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(attachment.ID); err != nil {
+		return err
+	}
+	// The record is gone either way, a file left behind is only wasted space
+	if err := s.storage.Delete(attachment.StorageKey); err != nil {
+		log.Warn("Failed to remove stored attachment", "key", attachment.StorageKey, "error", err)
+	}
+
+	return nil
+}
+
+// getReportAttachment returns an attachment if it belongs to the report
+func (s *reportAttachmentService) getReportAttachment(reportID, attachmentID string) (*models.ReportAttachment, error) {
+	attachment, err := s.repo.GetByID(attachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+	if attachment.ReportID != reportID {
+		return nil, fmt.Errorf("failed to get attachment: %w", sql.ErrNoRows)
+	}
+	return attachment, nil
+}
+
+func canSeePrivateAttachments(report *models.Report, viewer *models.User) bool {
+	return viewer != nil && (viewer.ID == report.UserID || viewer.IsModerator())
+}
@@ -1,13 +1,51 @@
 package services
 
 import (
+	"canada-hires/address"
 	"canada-hires/models"
 	"canada-hires/repos"
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
 )
 
+// Verification tiers whose reports are published without moderation, unless
+// REPORT_AUTO_PUBLISH_TIERS is set
+const defaultAutoPublishTiers = "trusted"
+
+// Most reports a single bulk moderation request can change
+const maxBulkModeration = 100
+
+// Days during which a reporter can't file a second report on the same business, unless
+// REPORT_DUPLICATE_WINDOW_DAYS is set
+const defaultDuplicateWindowDays = 30
+
+// What happens to a report on a business its reporter already reported within the window
+const (
+	DuplicateActionMerge  = "merge"  // The earlier report is updated with the new one
+	DuplicateActionReject = "reject" // The new report is refused
+)
+
+// Moderation actions and the status they give a report
+var moderationActions = map[string]string{
+	"publish": models.ReportStatusPublished,
+	"reject":  models.ReportStatusRejected,
+	"flag":    models.ReportStatusFlagged,
+}
+
+// ErrInvalidModeration is returned for a moderation request that can't be applied
+var ErrInvalidModeration = errors.New("invalid moderation request")
+
+// ErrDuplicateReport is returned when a reporter already reported the business recently
+var ErrDuplicateReport = errors.New("you already reported this business recently")
+
 type CreateReportRequest struct {
 	UserID          string  `json:"user_id"`
 	BusinessName    string  `json:"business_name"`
@@ -17,6 +55,20 @@ type CreateReportRequest struct {
 	TFWRatio        *string `json:"tfw_ratio"`
 	AdditionalNotes *string `json:"additional_notes"`
 	IPAddress       *string `json:"ip_address"`
+
+	// Author's standing, which decides whether the report is published right away
+	AuthorTier models.VerificationTier `json:"-"`
+	AuthorRole models.UserRole         `json:"-"`
+}
+
+// ModerateReportsRequest applies one moderation action to one or more reports
+type ModerateReportsRequest struct {
+	ReportIDs       []string
+	Action          string // publish, reject or flag
+	ModeratorID     string
+	RejectionReason *string // Required to reject
+	Notes           *string
+	IPAddress       *string
 }
 
 type ReportFilters struct {
@@ -27,29 +79,124 @@ type ReportFilters struct {
 }
 
 type ReportService interface {
-	CreateReport(req *CreateReportRequest) (*models.Report, error)
+	CreateReport(req *CreateReportRequest) (report *models.Report, merged bool, err error)
 	GetReportByID(id string) (*models.Report, error)
+	GetVisibleReport(id string, viewer *models.User) (*models.Report, error)
 	GetAllReports(limit, offset int) ([]*models.Report, error)
 	GetReportsWithFilters(filters ReportFilters, limit, offset int) ([]*models.Report, error)
 	GetUserReports(userID string, limit, offset int) ([]*models.Report, error)
 	GetBusinessReports(businessName string, limit, offset int) ([]*models.Report, error)
 	GetAddressReports(address string) ([]*models.Report, error)
 	GetReportsGroupedByAddress(filters *ReportFilters, limit, offset int) ([]*models.ReportsByAddress, error)
-	UpdateReport(report *models.Report) error
-	DeleteReport(reportID, userID string, isAdmin bool) error
+	UpdateReport(report *models.Report, editor *models.User, ipAddress *string) error
+	DeleteReport(reportID, userID string, isAdmin bool, ipAddress *string) error
+
+	// History and deleted reports
+	GetReportHistory(reportID string) ([]*models.ReportRevision, error)
+	GetDeletedReports(limit, offset int) ([]*models.Report, int, error)
+	RestoreReport(reportID, adminID string, ipAddress *string) (*models.Report, error)
+
+	// Moderation
+	GetModerationQueue(statuses []string, limit, offset int) ([]*models.Report, int, error)
+	GetModerationSummary() (map[string]int, error)
+	ModerateReports(req *ModerateReportsRequest) ([]string, error)
 }
 
 type reportService struct {
-	repo repos.ReportRepository
+	repo                     repos.ReportRepository
+	nonCompliantMatchService NonCompliantMatchService
+	addressService           AddressService
+	businessRepo             repos.BusinessRepository
+	revisionRepo             repos.ReportRevisionRepository
+	autoPublishTiers         []models.VerificationTier
+	duplicateWindow          time.Duration
+	duplicateAction          string
+}
+
+func NewReportService(repo repos.ReportRepository, nonCompliantMatchService NonCompliantMatchService, addressService AddressService, businessRepo repos.BusinessRepository, revisionRepo repos.ReportRevisionRepository) ReportService {
+	// Comma separated verification tiers whose reports skip the moderation queue, e.g. "enhanced,trusted".
+	// Set it to "none" to hold every report for moderation.
+	tiersConfig := os.Getenv("REPORT_AUTO_PUBLISH_TIERS")
+	if tiersConfig == "" {
+		tiersConfig = defaultAutoPublishTiers
+	}
+
+	var autoPublishTiers []models.VerificationTier
+	for _, tier := range strings.Split(tiersConfig, ",") {
+		switch tier := models.VerificationTier(strings.ToLower(strings.TrimSpace(tier))); tier {
+		case models.VerificationBasic, models.VerificationEnhanced, models.VerificationTrusted:
+			autoPublishTiers = append(autoPublishTiers, tier)
+		case "", "none":
+		default:
+			log.Error("Unknown verification tier in REPORT_AUTO_PUBLISH_TIERS", "tier", tier)
+		}
+	}
+	log.Info("Report auto-publishing configured", "tiers", autoPublishTiers)
+
+	// A reporter, identified by their account or IP address, gets one report per business within
+	// the window. Set it to 0 to allow any number of reports.
+	windowDays := defaultDuplicateWindowDays
+	if value := os.Getenv("REPORT_DUPLICATE_WINDOW_DAYS"); value != "" {
+		days, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || days < 0 {
+			log.Error("Invalid REPORT_DUPLICATE_WINDOW_DAYS, using default", "value", value, "default", defaultDuplicateWindowDays)
+		} else {
+			windowDays = days
+		}
+	}
+
+	duplicateAction := strings.ToLower(strings.TrimSpace(os.Getenv("REPORT_DUPLICATE_ACTION")))
+	switch duplicateAction {
+	case DuplicateActionMerge, DuplicateActionReject:
+	case "":
+		duplicateAction = DuplicateActionMerge
+	default:
+		log.Error("Unknown REPORT_DUPLICATE_ACTION, merging duplicates", "action", duplicateAction)
+		duplicateAction = DuplicateActionMerge
+	}
+	log.Info("Duplicate report detection configured", "window_days", windowDays, "action", duplicateAction)
+
+	return &reportService{
+		repo:                     repo,
+		nonCompliantMatchService: nonCompliantMatchService,
+		addressService:           addressService,
+		businessRepo:             businessRepo,
+		revisionRepo:             revisionRepo,
+		autoPublishTiers:         autoPublishTiers,
+		duplicateWindow:          time.Duration(windowDays) * 24 * time.Hour,
+		duplicateAction:          duplicateAction,
+	}
 }
 
-func NewReportService(repo repos.ReportRepository) ReportService {
-	return &reportService{repo: repo}
+// initialStatus returns the status a new or edited report gets. Reports of moderators and of
+// users in an auto-publish tier are published, the rest wait in the moderation queue.
+func (s *reportService) initialStatus(tier models.VerificationTier, role models.UserRole) string {
+	if role == models.RoleModerator || role == models.RoleAdmin || slices.Contains(s.autoPublishTiers, tier) {
+		return models.ReportStatusPublished
+	}
+	return models.ReportStatusPending
 }
 
-func (s *reportService) CreateReport(req *CreateReportRequest) (*models.Report, error) {
+// editedStatus returns the status of a report its author changed. Pending and published reports go
+// through the auto-publish rules again. A rejected report goes back to the moderation queue and a
+// flagged one stays there, so editing a report never gets around a moderator's decision.
+func (s *reportService) editedStatus(current string, tier models.VerificationTier, role models.UserRole) string {
+	switch current {
+	case models.ReportStatusRejected:
+		return models.ReportStatusPending
+	case models.ReportStatusFlagged:
+		return models.ReportStatusFlagged
+	default:
+		return s.initialStatus(tier, role)
+	}
+}
+
+// CreateReport files a report. A report on a business its reporter already reported within the
+// duplicate window is merged into the earlier report, and merged is true, or refused with
+// ErrDuplicateReport, depending on REPORT_DUPLICATE_ACTION.
+func (s *reportService) CreateReport(req *CreateReportRequest) (*models.Report, bool, error) {
 	if err := s.validateCreateRequest(req); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, false, fmt.Errorf("validation failed: %w", err)
 	}
 
 	report := &models.Report{
@@ -61,13 +208,90 @@ func (s *reportService) CreateReport(req *CreateReportRequest) (*models.Report,
 		TFWRatio:        req.TFWRatio,
 		AdditionalNotes: req.AdditionalNotes,
 		IPAddress:       req.IPAddress,
+		Status:          s.initialStatus(req.AuthorTier, req.AuthorRole),
+	}
+
+	duplicate, err := s.findDuplicate(report)
+	if err != nil {
+		return nil, false, err
+	}
+	if duplicate != nil {
+		// Another account can't change the earlier report, so reports from the same IP address are
+		// always refused
+		if s.duplicateAction == DuplicateActionReject || duplicate.UserID != report.UserID {
+			log.Info("Duplicate report refused",
+				"user_id", report.UserID,
+				"existing_report_id", duplicate.ID,
+				"same_user", duplicate.UserID == report.UserID)
+			return nil, false, ErrDuplicateReport
+		}
+
+		merged, err := s.mergeDuplicate(duplicate, report, req.AuthorTier, req.AuthorRole)
+		if err != nil {
+			return nil, false, err
+		}
+		return merged, true, nil
 	}
 
 	if err := s.repo.Create(report); err != nil {
-		return nil, fmt.Errorf("failed to create report: %w", err)
+		return nil, false, fmt.Errorf("failed to create report: %w", err)
 	}
 
-	return report, nil
+	// The report is saved either way, the address can be parsed again later
+	if err := s.addressService.LinkAddress(models.AddressSourceReports, report.BusinessAddress); err != nil {
+		log.Warn("Failed to parse report address", "report_id", report.ID, "error", err)
+	}
+
+	return report, false, nil
+}
+
+// findDuplicate returns the latest report on the same business, by normalized name and address,
+// filed by the same user or from the same IP address within the duplicate window
+func (s *reportService) findDuplicate(report *models.Report) (*models.Report, error) {
+	if s.duplicateWindow <= 0 {
+		return nil, nil
+	}
+
+	recent, err := s.repo.GetRecentByReporter(report.UserID, report.IPAddress, time.Now().UTC().Add(-s.duplicateWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate reports: %w", err)
+	}
+
+	businessName := models.NormalizeEmployerName(report.BusinessName)
+	businessAddress := address.Normalize(report.BusinessAddress)
+	for _, existing := range recent {
+		if models.NormalizeEmployerName(existing.BusinessName) == businessName &&
+			address.Normalize(existing.BusinessAddress) == businessAddress {
+			return existing, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// mergeDuplicate updates an earlier report with the details of a new report on the same business.
+// The merged report gets the status of an edit by its author.
+func (s *reportService) mergeDuplicate(existing, report *models.Report, tier models.VerificationTier, role models.UserRole) (*models.Report, error) {
+	existing.ReportSource = report.ReportSource
+	if report.ConfidenceLevel != nil {
+		existing.ConfidenceLevel = report.ConfidenceLevel
+	}
+	if report.TFWRatio != nil {
+		existing.TFWRatio = report.TFWRatio
+	}
+	if report.AdditionalNotes != nil && strings.TrimSpace(*report.AdditionalNotes) != "" {
+		existing.AdditionalNotes = report.AdditionalNotes
+	}
+	existing.Status = s.editedStatus(existing.Status, tier, role)
+
+	author := models.ReportActor{UserID: &report.UserID, IPAddress: report.IPAddress}
+	if err := s.repo.Update(existing, author); err != nil {
+		return nil, fmt.Errorf("failed to merge duplicate report: %w", err)
+	}
+
+	log.Info("Duplicate report merged", "report_id", existing.ID, "user_id", existing.UserID)
+
+	return existing, nil
 }
 
 func (s *reportService) validateCreateRequest(req *CreateReportRequest) error {
@@ -118,6 +342,69 @@ func (s *reportService) GetReportByID(id string) (*models.Report, error) {
 	return report, nil
 }
 
+// GetVisibleReport returns a report if the viewer may see it: published reports are public,
+// others are only visible to their author and to moderators. Hidden reports are reported as not
+// found so their existence isn't disclosed.
+func (s *reportService) GetVisibleReport(id string, viewer *models.User) (*models.Report, error) {
+	report, err := s.GetReportByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if report.Status != models.ReportStatusPublished &&
+		(viewer == nil || (viewer.ID != report.UserID && !viewer.IsModerator())) {
+		return nil, fmt.Errorf("failed to get report: %w", sql.ErrNoRows)
+	}
+
+	s.attachBusinessReplies([]*models.Report{report})
+	return report, nil
+}
+
+// attachBusinessReplies adds the published responses of verified businesses to reports. Replies
+// are supplementary, so a lookup failure is only logged.
+func (s *reportService) attachBusinessReplies(reports []*models.Report) {
+	ids := make([]string, 0, len(reports))
+	for _, report := range reports {
+		ids = append(ids, report.ID)
+	}
+
+	replies, err := s.businessRepo.GetPublishedReportReplies(ids)
+	if err != nil {
+		log.Error("Failed to get business replies", "error", err)
+		return
+	}
+	for _, report := range reports {
+		if reply, ok := replies[report.ID]; ok {
+			report.BusinessReply = reply.Public()
+		}
+	}
+}
+
+// attachBusinessStatements adds the published statements of verified businesses to grouped reports
+func (s *reportService) attachBusinessStatements(grouped []*models.ReportsByAddress) {
+	names := make([]string, 0, len(grouped))
+	addresses := make([]string, 0, len(grouped))
+	for _, group := range grouped {
+		names = append(names, models.NormalizeEmployerName(group.BusinessName))
+		addresses = append(addresses, address.Normalize(group.BusinessAddress))
+	}
+
+	statements, err := s.businessRepo.GetPublishedStatements(names, addresses)
+	if err != nil {
+		log.Error("Failed to get business statements", "error", err)
+		return
+	}
+	for _, statement := range statements {
+		name := models.NormalizeEmployerName(statement.BusinessName)
+		businessAddress := address.Normalize(statement.BusinessAddress)
+		for i, group := range grouped {
+			if names[i] == name && addresses[i] == businessAddress {
+				group.BusinessStatement = statement.Public()
+			}
+		}
+	}
+}
+
 func (s *reportService) GetAllReports(limit, offset int) ([]*models.Report, error) {
 	if limit <= 0 {
 		limit = 50 // Default limit
@@ -134,6 +421,7 @@ func (s *reportService) GetAllReports(limit, offset int) ([]*models.Report, erro
 		return nil, fmt.Errorf("failed to get reports: %w", err)
 	}
 
+	s.attachBusinessReplies(reports)
 	return reports, nil
 }
 
@@ -161,6 +449,7 @@ func (s *reportService) GetReportsWithFilters(filters ReportFilters, limit, offs
 		return nil, fmt.Errorf("failed to get reports with filters: %w", err)
 	}
 
+	s.attachBusinessReplies(reports)
 	return reports, nil
 }
 
@@ -183,6 +472,7 @@ func (s *reportService) GetUserReports(userID string, limit, offset int) ([]*mod
 		return nil, fmt.Errorf("failed to get user reports: %w", err)
 	}
 
+	s.attachBusinessReplies(reports)
 	return reports, nil
 }
 
@@ -205,14 +495,21 @@ func (s *reportService) GetBusinessReports(businessName string, limit, offset in
 		return nil, fmt.Errorf("failed to get business reports: %w", err)
 	}
 
+	s.attachBusinessReplies(reports)
 	return reports, nil
 }
 
 
-func (s *reportService) UpdateReport(report *models.Report) error {
+// UpdateReport saves an edited report. Reports edited by their author get the status from
+// editedStatus, so a published report changed by an unverified author is held for moderation.
+// Edits by moderators keep the report's status.
+func (s *reportService) UpdateReport(report *models.Report, editor *models.User, ipAddress *string) error {
 	if report.ID == "" {
 		return fmt.Errorf("report ID is required")
 	}
+	if editor == nil {
+		return fmt.Errorf("editor is required")
+	}
 
 	if err := s.validateReport(report); err != nil {
 		return fmt.Errorf("validation failed: %w", err)
@@ -220,11 +517,19 @@ func (s *reportService) UpdateReport(report *models.Report) error {
 
 	report.BusinessName = strings.TrimSpace(report.BusinessName)
 	report.BusinessAddress = strings.TrimSpace(report.BusinessAddress)
+	if !editor.IsModerator() {
+		report.Status = s.editedStatus(report.Status, editor.VerificationTier, editor.Role)
+	}
 
-	if err := s.repo.Update(report); err != nil {
+	if err := s.repo.Update(report, models.ReportActor{UserID: &editor.ID, IPAddress: ipAddress}); err != nil {
 		return fmt.Errorf("failed to update report: %w", err)
 	}
 
+	// Only links the report when its address changed
+	if err := s.addressService.LinkAddress(models.AddressSourceReports, report.BusinessAddress); err != nil {
+		log.Warn("Failed to parse report address", "report_id", report.ID, "error", err)
+	}
+
 	return nil
 }
 
@@ -251,7 +556,8 @@ func (s *reportService) validateReport(report *models.Report) error {
 }
 
 
-func (s *reportService) DeleteReport(reportID, userID string, isAdmin bool) error {
+// DeleteReport hides a report. It is kept with its history so an admin can restore it.
+func (s *reportService) DeleteReport(reportID, userID string, isAdmin bool, ipAddress *string) error {
 	if reportID == "" {
 		return fmt.Errorf("report ID is required")
 	}
@@ -270,7 +576,7 @@ func (s *reportService) DeleteReport(reportID, userID string, isAdmin bool) erro
 		return fmt.Errorf("unauthorized: can only delete your own reports")
 	}
 
-	err = s.repo.Delete(reportID)
+	err = s.repo.Delete(reportID, models.ReportActor{UserID: &userID, IPAddress: ipAddress})
 	if err != nil {
 		return fmt.Errorf("failed to delete report: %w", err)
 	}
@@ -278,6 +584,32 @@ func (s *reportService) DeleteReport(reportID, userID string, isAdmin bool) erro
 	return nil
 }
 
+// GetReportHistory returns every recorded change to a report, oldest first, including changes
+// to deleted reports
+func (s *reportService) GetReportHistory(reportID string) ([]*models.ReportRevision, error) {
+	if _, err := s.repo.GetByIDIncludingDeleted(reportID); err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+
+	return s.revisionRepo.GetByReportID(reportID)
+}
+
+// GetDeletedReports returns deleted reports, most recently deleted first, and their total
+func (s *reportService) GetDeletedReports(limit, offset int) ([]*models.Report, int, error) {
+	return s.repo.GetDeleted(limit, offset)
+}
+
+// RestoreReport brings back a deleted report with the status it had when it was deleted
+func (s *reportService) RestoreReport(reportID, adminID string, ipAddress *string) (*models.Report, error) {
+	if err := s.repo.Restore(reportID, models.ReportActor{UserID: &adminID, IPAddress: ipAddress}); err != nil {
+		return nil, err
+	}
+
+	log.Info("Report restored", "report_id", reportID, "admin_id", adminID)
+
+	return s.GetReportByID(reportID)
+}
+
 func (s *reportService) GetAddressReports(address string) ([]*models.Report, error) {
 	if strings.TrimSpace(address) == "" {
 		return nil, fmt.Errorf("address is required")
@@ -288,6 +620,7 @@ func (s *reportService) GetAddressReports(address string) ([]*models.Report, err
 		return nil, fmt.Errorf("failed to get reports by address: %w", err)
 	}
 
+	s.attachBusinessReplies(reports)
 	return reports, nil
 }
 
@@ -318,5 +651,97 @@ func (s *reportService) GetReportsGroupedByAddress(filters *ReportFilters, limit
 		return nil, fmt.Errorf("failed to get reports grouped by address: %w", err)
 	}
 
+	// The non-compliant flag is informational, so a lookup failure doesn't fail the request
+	if err := s.nonCompliantMatchService.FlagReportGroups(grouped); err != nil {
+		log.Error("Failed to flag non-compliant businesses", "error", err)
+	}
+	s.attachBusinessStatements(grouped)
+
 	return grouped, nil
 }
+
+// GetModerationQueue returns the reports waiting for moderation, pending and flagged by default,
+// oldest first
+func (s *reportService) GetModerationQueue(statuses []string, limit, offset int) ([]*models.Report, int, error) {
+	if len(statuses) == 0 {
+		statuses = []string{models.ReportStatusPending, models.ReportStatusFlagged}
+	}
+	for _, status := range statuses {
+		if !slices.Contains(models.ReportStatuses, status) {
+			return nil, 0, fmt.Errorf("%w: unknown status %s", ErrInvalidModeration, status)
+		}
+	}
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	reports, total, err := s.repo.GetModerationQueue(statuses, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get moderation queue: %w", err)
+	}
+
+	return reports, total, nil
+}
+
+// GetModerationSummary returns the number of reports in each status
+func (s *reportService) GetModerationSummary() (map[string]int, error) {
+	return s.repo.GetStatusCounts()
+}
+
+// ModerateReports publishes, rejects or flags reports and returns the IDs of the reports that
+// changed. Rejections need one of the models.RejectionReasons.
+func (s *reportService) ModerateReports(req *ModerateReportsRequest) ([]string, error) {
+	status, ok := moderationActions[req.Action]
+	if !ok {
+		return nil, fmt.Errorf("%w: action must be 'publish', 'reject' or 'flag'", ErrInvalidModeration)
+	}
+	if req.ModeratorID == "" {
+		return nil, fmt.Errorf("moderator ID is required")
+	}
+
+	var ids []string
+	for _, id := range req.ReportIDs {
+		if id = strings.TrimSpace(id); id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: at least one report ID is required", ErrInvalidModeration)
+	}
+	if len(ids) > maxBulkModeration {
+		return nil, fmt.Errorf("%w: at most %d reports can be moderated at once", ErrInvalidModeration, maxBulkModeration)
+	}
+
+	moderation := models.ReportModeration{
+		Status:      status,
+		ModeratorID: req.ModeratorID,
+		Notes:       req.Notes,
+		IPAddress:   req.IPAddress,
+	}
+	if status == models.ReportStatusRejected {
+		if req.RejectionReason == nil || !slices.Contains(models.RejectionReasons, *req.RejectionReason) {
+			return nil, fmt.Errorf("%w: a rejection reason is required, one of %s", ErrInvalidModeration, strings.Join(models.RejectionReasons, ", "))
+		}
+		moderation.RejectionReason = req.RejectionReason
+	}
+
+	updated, err := s.repo.Moderate(ids, moderation)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("Reports moderated",
+		"action", req.Action,
+		"moderator_id", req.ModeratorID,
+		"requested", len(ids),
+		"updated", len(updated),
+		"rejection_reason", moderation.RejectionReason)
+
+	return updated, nil
+}
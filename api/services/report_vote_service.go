@@ -0,0 +1,206 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Counted flags that move a published report into the moderation queue, unless
+// REPORT_FLAG_THRESHOLD is set
+const defaultFlagThreshold = 3
+
+var (
+	// ErrOwnReport is returned when users vote on their own report
+	ErrOwnReport = errors.New("you can't vote on your own report")
+	// ErrInvalidVote is returned for a vote that can't be recorded
+	ErrInvalidVote = errors.New("invalid vote")
+)
+
+// CastVoteRequest corroborates or flags a report
+type CastVoteRequest struct {
+	ReportID   string
+	Voter      *models.User
+	VoteType   string  // corroborate or flag
+	FlagReason *string // Required to flag
+	Comment    *string
+	IPAddress  string
+}
+
+type ReportVoteService interface {
+	CastVote(req *CastVoteRequest) (*models.ReportVoteResult, error)
+	WithdrawVote(reportID string, voter *models.User) error
+	GetUserVote(reportID string, voter *models.User) (*models.ReportVote, error)
+	GetReportVotes(reportID string) ([]*models.ReportVote, error)
+}
+
+type reportVoteService struct {
+	repo          repos.ReportVoteRepository
+	reportService ReportService
+	userRepo      repos.UserRepository
+	flagThreshold int
+}
+
+func NewReportVoteService(repo repos.ReportVoteRepository, reportService ReportService, userRepo repos.UserRepository) ReportVoteService {
+	flagThreshold := envPositiveInt("REPORT_FLAG_THRESHOLD", defaultFlagThreshold)
+	log.Info("Report flagging configured", "threshold", flagThreshold)
+
+	return &reportVoteService{
+		repo:          repo,
+		reportService: reportService,
+		userRepo:      userRepo,
+		flagThreshold: flagThreshold,
+	}
+}
+
+// CastVote records a user's corroboration or flag of a published report, replacing their earlier
+// vote. Votes are only counted when the voter doesn't share an IP address with the report author
+// or with another counted voter, so one person can't back their own report or flag it repeatedly
+// from several accounts.
+func (s *reportVoteService) CastVote(req *CastVoteRequest) (*models.ReportVoteResult, error) {
+	if req.Voter == nil {
+		return nil, fmt.Errorf("voter is required")
+	}
+
+	vote := &models.ReportVote{
+		ReportID: req.ReportID,
+		UserID:   req.Voter.ID,
+		VoteType: req.VoteType,
+		Counted:  true,
+	}
+	switch req.VoteType {
+	case models.VoteCorroborate:
+	case models.VoteFlag:
+		if req.FlagReason == nil || !slices.Contains(models.FlagReasons, *req.FlagReason) {
+			return nil, fmt.Errorf("%w: a flag reason is required, one of %s", ErrInvalidVote, strings.Join(models.FlagReasons, ", "))
+		}
+		vote.FlagReason = req.FlagReason
+	default:
+		return nil, fmt.Errorf("%w: vote type must be 'corroborate' or 'flag'", ErrInvalidVote)
+	}
+	if req.Comment != nil {
+		if comment := strings.TrimSpace(*req.Comment); comment != "" {
+			if len(comment) > 1000 {
+				return nil, fmt.Errorf("%w: comment must be at most 1000 characters", ErrInvalidVote)
+			}
+			vote.Comment = &comment
+		}
+	}
+	if net.ParseIP(req.IPAddress) != nil {
+		vote.IPAddress = &req.IPAddress
+	}
+
+	// Only published reports are open to votes, others are reported as not found
+	report, err := s.reportService.GetVisibleReport(req.ReportID, nil)
+	if err != nil {
+		return nil, err
+	}
+	if report.UserID == req.Voter.ID {
+		return nil, ErrOwnReport
+	}
+
+	voterIPs := voterIPAddresses(req.Voter, vote.IPAddress)
+	sharesAuthorIP, err := s.sharesAuthorIP(report, voterIPs)
+	if err != nil {
+		return nil, err
+	}
+	sharedVotes, err := s.repo.CountSharedIPVotes(report.ID, req.Voter.ID, voterIPs)
+	if err != nil {
+		return nil, err
+	}
+	if sharesAuthorIP || sharedVotes > 0 {
+		vote.Counted = false
+		log.Warn("Report vote not counted, IP address shared",
+			"report_id", report.ID,
+			"user_id", req.Voter.ID,
+			"vote_type", vote.VoteType,
+			"shares_author_ip", sharesAuthorIP,
+			"shared_votes", sharedVotes)
+	}
+
+	result, err := s.repo.Upsert(vote, s.flagThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.SentToModeration {
+		log.Info("Report flagged by the community", "report_id", report.ID, "flags", result.FlagCount)
+	}
+
+	return result, nil
+}
+
+// sharesAuthorIP reports whether the voter used an IP address the report was filed from or the
+// author's account was used from
+func (s *reportVoteService) sharesAuthorIP(report *models.Report, voterIPs []string) (bool, error) {
+	authorIPs := []string{}
+	if report.IPAddress != nil {
+		authorIPs = append(authorIPs, *report.IPAddress)
+	}
+
+	author, err := s.userRepo.GetByID(report.UserID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("failed to get report author: %w", err)
+	}
+	if author != nil {
+		authorIPs = append(authorIPs, author.IPAddresses...)
+	}
+
+	for _, ip := range authorIPs {
+		// Reports store the IP address as INET, which can come back with a prefix length
+		ip, _, _ = strings.Cut(ip, "/")
+		if slices.Contains(voterIPs, ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// voterIPAddresses returns the IP addresses the voter's account was used from and the one the
+// vote comes from
+func voterIPAddresses(voter *models.User, current *string) []string {
+	ips := slices.Clone([]string(voter.IPAddresses))
+	if current != nil && !slices.Contains(ips, *current) {
+		ips = append(ips, *current)
+	}
+	return ips
+}
+
+// WithdrawVote removes a user's vote from a report
+func (s *reportVoteService) WithdrawVote(reportID string, voter *models.User) error {
+	if voter == nil {
+		return fmt.Errorf("voter is required")
+	}
+
+	deleted, err := s.repo.Delete(reportID, voter.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("failed to withdraw vote: %w", sql.ErrNoRows)
+	}
+	return nil
+}
+
+// GetUserVote returns the user's vote on a report
+func (s *reportVoteService) GetUserVote(reportID string, voter *models.User) (*models.ReportVote, error) {
+	if voter == nil {
+		return nil, fmt.Errorf("voter is required")
+	}
+	return s.repo.GetByReportAndUser(reportID, voter.ID)
+}
+
+// GetReportVotes returns every vote on a report, for moderators
+func (s *reportVoteService) GetReportVotes(reportID string) ([]*models.ReportVote, error) {
+	if _, err := s.reportService.GetReportByID(reportID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByReportID(reportID)
+}
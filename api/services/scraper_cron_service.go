@@ -11,24 +11,34 @@ import (
 )
 
 type ScraperCronService struct {
-	cron              *cron.Cron
-	logger            *log.Logger
-	scraperService    ScraperService
-	scraperJobRepo    repos.ScraperJobRepository
-	statisticsService LMIAStatisticsService
-	jobType           string
+	cron                     *cron.Cron
+	logger                   *log.Logger
+	scraperService           ScraperService
+	scraperJobRepo           repos.ScraperJobRepository
+	statisticsService        LMIAStatisticsService
+	wageService              WageService
+	lmiaMatchService         LMIAMatchService
+	nonCompliantMatchService NonCompliantMatchService
+	spatialService           SpatialService
+	electoralDistrictService ElectoralDistrictService
+	jobType                  string
 }
 
-func NewScraperCronService(logger *log.Logger, scraperService ScraperService, scraperJobRepo repos.ScraperJobRepository, statisticsService LMIAStatisticsService) *ScraperCronService {
+func NewScraperCronService(logger *log.Logger, scraperService ScraperService, scraperJobRepo repos.ScraperJobRepository, statisticsService LMIAStatisticsService, wageService WageService, lmiaMatchService LMIAMatchService, nonCompliantMatchService NonCompliantMatchService, spatialService SpatialService, electoralDistrictService ElectoralDistrictService) *ScraperCronService {
 	c := cron.New(cron.WithLocation(time.UTC))
 
 	return &ScraperCronService{
-		cron:              c,
-		logger:            logger,
-		scraperService:    scraperService,
-		scraperJobRepo:    scraperJobRepo,
-		statisticsService: statisticsService,
-		jobType:           "lmia_scraper",
+		cron:                     c,
+		logger:                   logger,
+		scraperService:           scraperService,
+		scraperJobRepo:           scraperJobRepo,
+		statisticsService:        statisticsService,
+		wageService:              wageService,
+		lmiaMatchService:         lmiaMatchService,
+		nonCompliantMatchService: nonCompliantMatchService,
+		spatialService:           spatialService,
+		electoralDistrictService: electoralDistrictService,
+		jobType:                  "lmia_scraper",
 	}
 }
 
@@ -93,6 +103,31 @@ func (scs *ScraperCronService) runScraper() {
 		scs.logger.Error("Failed to update next scheduled run", "error", err)
 	}
 
+	// Compare newly scraped wages with the prevailing wage table before aggregating statistics
+	if _, err := scs.wageService.AnnotateAllJobPostings(); err != nil {
+		scs.logger.Error("Failed to run prevailing wage comparison", "error", err)
+	}
+
+	// Link postings to the employer's LMIA approval history
+	if _, err := scs.lmiaMatchService.MatchAllJobPostings(); err != nil {
+		scs.logger.Error("Failed to match job postings to LMIA approvals", "error", err)
+	}
+
+	// Flag postings by employers on the non-compliant list
+	if _, err := scs.nonCompliantMatchService.MatchAllJobPostings(); err != nil {
+		scs.logger.Error("Failed to match job postings to non-compliant employers", "error", err)
+	}
+
+	// Place new postings on the map
+	if _, err := scs.spatialService.GeocodeJobPostings(); err != nil {
+		scs.logger.Error("Failed to geocode job posting locations", "error", err)
+	}
+
+	// Assign newly geocoded postings and addresses to their riding
+	if _, err := scs.electoralDistrictService.AssignDistricts(); err != nil {
+		scs.logger.Error("Failed to assign electoral districts", "error", err)
+	}
+
 	// Run LMIA statistics aggregation after successful scraping
 	scs.logger.Info("Starting LMIA statistics aggregation after successful scraping")
 	if err := scs.statisticsService.RunDailyAggregation(); err != nil {
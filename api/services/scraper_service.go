@@ -27,14 +27,16 @@ type ScraperService interface {
 }
 
 type scraperService struct {
-	jobRepo repos.JobBankRepository
-	logger  *log.Logger
+	jobRepo        repos.JobBankRepository
+	jobBankService JobBankService
+	logger         *log.Logger
 }
 
-func NewScraperService(jobRepo repos.JobBankRepository, logger *log.Logger) ScraperService {
+func NewScraperService(jobRepo repos.JobBankRepository, jobBankService JobBankService, logger *log.Logger) ScraperService {
 	return &scraperService{
-		jobRepo: jobRepo,
-		logger:  logger,
+		jobRepo:        jobRepo,
+		jobBankService: jobBankService,
+		logger:         logger,
 	}
 }
 
@@ -83,6 +85,10 @@ func (s *scraperService) RunScraper(numberOfPages int) (*models.JobScrapingRun,
 		return nil, fmt.Errorf("failed to save jobs to database: %w", err)
 	}
 
+	// Read the NOC code and weekly hours of new postings from their detail pages, the search
+	// results don't show them
+	s.jobBankService.EnrichJobPostings(savedJobs)
+
 	// Clean up orphaned jobs (jobs that existed in previous scrapes but not in current scrape)
 	currentJobBankIDs := make([]string, 0, len(jobs))
 	for _, job := range jobs {
@@ -173,6 +179,10 @@ func (s *scraperService) RunScraperWithConfig(ctx context.Context, config Scrape
 		return nil, fmt.Errorf("failed to save jobs to database: %w", err)
 	}
 
+	// Read the NOC code and weekly hours of new postings from their detail pages, the search
+	// results don't show them
+	s.jobBankService.EnrichJobPostings(savedJobs)
+
 	// Clean up orphaned jobs (jobs that existed in previous scrapes but not in current scrape)
 	currentJobBankIDs := make([]string, 0, len(jobs))
 	for _, job := range jobs {
@@ -0,0 +1,259 @@
+package services
+
+import (
+	"canada-hires/address"
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/charmbracelet/log"
+)
+
+// Number of distinct job locations or report addresses geocoded per batch
+const spatialGeocodeBatchSize = 500
+
+// Map clustering grid, in web map tile pixels
+const (
+	mapTileSize        = 256
+	mapClusterCellSize = 60
+	MaxMapZoom         = 20
+	maxMapClusters     = 5000
+)
+
+type SpatialService interface {
+	Near(latitude, longitude, radiusMeters float64, datasets []string, limit int) (*models.SpatialSearchResponse, error)
+	Within(bbox models.BoundingBox, datasets []string, limit int) (*models.SpatialSearchResponse, error)
+	GeocodeJobPostings() (int, error)
+	GeocodeReports() (int, error)
+	ClusterMapLayer(q models.MapClusterQuery) (*models.FeatureCollection, error)
+}
+
+type spatialService struct {
+	repo             repos.SpatialRepository
+	geocodingService PostalCodeGeocodingService
+}
+
+func NewSpatialService(repo repos.SpatialRepository, geocodingService PostalCodeGeocodingService) SpatialService {
+	return &spatialService{
+		repo:             repo,
+		geocodingService: geocodingService,
+	}
+}
+
+// Near returns the records of each dataset within a radius of a point, nearest first
+func (s *spatialService) Near(latitude, longitude, radiusMeters float64, datasets []string, limit int) (*models.SpatialSearchResponse, error) {
+	q := models.SpatialQuery{
+		Latitude:     latitude,
+		Longitude:    longitude,
+		RadiusMeters: radiusMeters,
+		Limit:        limit,
+	}
+
+	response := &models.SpatialSearchResponse{
+		Latitude:     latitude,
+		Longitude:    longitude,
+		RadiusMeters: radiusMeters,
+	}
+
+	return response, s.search(q, datasets, response)
+}
+
+// Within returns the records of each dataset inside a bounding box, nearest to its center first
+func (s *spatialService) Within(bbox models.BoundingBox, datasets []string, limit int) (*models.SpatialSearchResponse, error) {
+	latitude, longitude := bbox.Center()
+	q := models.SpatialQuery{
+		Latitude:  latitude,
+		Longitude: longitude,
+		BBox:      &bbox,
+		Limit:     limit,
+	}
+
+	response := &models.SpatialSearchResponse{
+		Latitude:  latitude,
+		Longitude: longitude,
+		BBox:      &bbox,
+	}
+
+	return response, s.search(q, datasets, response)
+}
+
+// search fills the response with the requested datasets. Datasets that weren't requested stay nil.
+func (s *spatialService) search(q models.SpatialQuery, datasets []string, response *models.SpatialSearchResponse) error {
+	var err error
+
+	if slices.Contains(datasets, models.SpatialDatasetLMIA) {
+		if response.LMIAEmployers, err = s.repo.FindLMIAEmployers(q); err != nil {
+			return err
+		}
+		if response.LMIAEmployers == nil {
+			response.LMIAEmployers = []*models.NearbyLMIAEmployer{}
+		}
+	}
+
+	if slices.Contains(datasets, models.SpatialDatasetNonCompliant) {
+		if response.NonCompliantEmployers, err = s.repo.FindNonCompliantEmployers(q); err != nil {
+			return err
+		}
+		if response.NonCompliantEmployers == nil {
+			response.NonCompliantEmployers = []*models.NearbyNonCompliantEmployer{}
+		}
+	}
+
+	if slices.Contains(datasets, models.SpatialDatasetJobPostings) {
+		if response.JobPostings, err = s.repo.FindJobPostings(q); err != nil {
+			return err
+		}
+		if response.JobPostings == nil {
+			response.JobPostings = []*models.NearbyJobPosting{}
+		}
+	}
+
+	if slices.Contains(datasets, models.SpatialDatasetReports) {
+		if response.Reports, err = s.repo.FindReports(q); err != nil {
+			return err
+		}
+		if response.Reports == nil {
+			response.Reports = []*models.NearbyReportLocation{}
+		}
+	}
+
+	return nil
+}
+
+// GeocodeJobPostings places job postings at the city of their location, geocoding each distinct
+// location once, and returns the number of postings placed
+func (s *spatialService) GeocodeJobPostings() (int, error) {
+	log.Info("Geocoding job posting locations")
+
+	total := 0
+	failed := 0
+	for {
+		locations, err := s.repo.GetUngeocodedJobLocations(spatialGeocodeBatchSize)
+		if err != nil {
+			return total, err
+		}
+		if len(locations) == 0 {
+			break
+		}
+
+		for _, location := range locations {
+			var latitude, longitude *float64
+			if lat, lng, err := s.geocodeJobLocation(location); err != nil {
+				log.Debug("Failed to geocode job location", "location", location, "error", err)
+				failed++
+			} else {
+				latitude, longitude = &lat, &lng
+			}
+
+			// Failures are recorded too, otherwise the same locations would be fetched forever
+			updated, err := s.repo.SetJobLocationCoordinates(location, latitude, longitude)
+			if err != nil {
+				return total, err
+			}
+			if latitude != nil {
+				total += int(updated)
+			}
+		}
+	}
+
+	log.Info("Job posting locations geocoded", "postings", total, "failed_locations", failed)
+	return total, nil
+}
+
+// geocodeJobLocation geocodes a Job Bank location such as "Toronto (ON)" as "Toronto, ON"
+func (s *spatialService) geocodeJobLocation(location string) (latitude, longitude float64, err error) {
+	municipality, provinceCode := address.ParseLocation(location)
+	if municipality == "" || provinceCode == "" {
+		return 0, 0, fmt.Errorf("location has no city and province: %s", location)
+	}
+
+	components := address.Components{Municipality: municipality, ProvinceCode: provinceCode}
+	return s.geocodingService.GeocodeFullAddress(components.Format())
+}
+
+// GeocodeReports geocodes the addresses of reported businesses that can't be placed yet and
+// returns the number of addresses placed. Their postal code is geocoded as well, so reports can
+// fall back to its centroid when the full address can't be resolved.
+func (s *spatialService) GeocodeReports() (int, error) {
+	addresses, err := s.repo.GetUngeocodedReportAddresses(spatialGeocodeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Info("Geocoding report addresses", "addresses", len(addresses))
+
+	geocoded := 0
+	for _, businessAddress := range addresses {
+		postalCodeErr := fmt.Errorf("no postal code")
+		if postalCode := address.ExtractPostalCode(businessAddress); postalCode != "" {
+			_, _, postalCodeErr = s.geocodingService.GeocodePostalCode(postalCode)
+		}
+		_, _, addressErr := s.geocodingService.GeocodeFullAddress(businessAddress)
+
+		if postalCodeErr != nil && addressErr != nil {
+			log.Debug("Failed to geocode report address", "address", businessAddress, "error", addressErr)
+			continue
+		}
+		geocoded++
+	}
+
+	log.Info("Report addresses geocoded", "geocoded", geocoded, "failed", len(addresses)-geocoded)
+	return geocoded, nil
+}
+
+// ClusterMapLayer groups the points of a layer inside the viewport into clusters sized for the
+// zoom level and returns them as GeoJSON point features. Cells are a fixed number of screen pixels
+// wide, so clusters split up as the map zooms in, and are aligned to a global grid so they don't
+// shift while panning.
+func (s *spatialService) ClusterMapLayer(q models.MapClusterQuery) (*models.FeatureCollection, error) {
+	q.CellLongitude = mapClusterCellSize * 360 / (mapTileSize * math.Exp2(float64(q.Zoom)))
+	// Cells are square in degrees rather than on screen, where Web Mercator stretches them
+	// vertically. Sizing them by latitude would move the grid whenever the viewport does.
+	q.CellLatitude = q.CellLongitude
+	q.Limit = maxMapClusters
+
+	clusters, err := s.repo.ClusterMapLayer(q)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := models.NewFeatureCollection(&q.BBox)
+	for _, cluster := range clusters {
+		properties := map[string]interface{}{
+			"layer":       q.Layer,
+			"cluster":     cluster.PointCount > 1,
+			"point_count": cluster.PointCount,
+		}
+		if cluster.PointCount > 1 {
+			properties["expansion_zoom"] = min(q.Zoom+2, MaxMapZoom)
+		}
+		if cluster.Name != nil {
+			properties["name"] = *cluster.Name
+		}
+		if cluster.RecordID != nil {
+			properties["record_id"] = *cluster.RecordID
+		}
+		if cluster.ApprovedLMIAs != nil {
+			properties["approved_lmias"] = *cluster.ApprovedLMIAs
+		}
+		if cluster.ApprovedPositions != nil {
+			properties["approved_positions"] = *cluster.ApprovedPositions
+		}
+		if cluster.TotalPenaltyAmount != nil {
+			properties["total_penalty_amount"] = *cluster.TotalPenaltyAmount
+		}
+		if cluster.TFWPostings != nil {
+			properties["tfw_postings"] = *cluster.TFWPostings
+		}
+		if cluster.LMIAPostings != nil {
+			properties["lmia_postings"] = *cluster.LMIAPostings
+		}
+
+		id := fmt.Sprintf("%s:%d:%d:%d", q.Layer, q.Zoom, cluster.CellX, cluster.CellY)
+		collection.Features = append(collection.Features, models.NewPointFeature(id, cluster.Latitude, cluster.Longitude, properties))
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"canada-hires/repos"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/robfig/cron/v3"
+)
+
+type VerificationTierCronService struct {
+	cron           *cron.Cron
+	logger         *log.Logger
+	tierService    VerificationTierService
+	scraperJobRepo repos.ScraperJobRepository
+	jobType        string
+}
+
+func NewVerificationTierCronService(logger *log.Logger, tierService VerificationTierService, scraperJobRepo repos.ScraperJobRepository) *VerificationTierCronService {
+	c := cron.New(cron.WithLocation(time.UTC))
+
+	return &VerificationTierCronService{
+		cron:           c,
+		logger:         logger,
+		tierService:    tierService,
+		scraperJobRepo: scraperJobRepo,
+		jobType:        "verification_tiers",
+	}
+}
+
+func (vts *VerificationTierCronService) Start(ctx context.Context) error {
+	// Check for missed execution on startup
+	if err := vts.checkMissedExecution(); err != nil {
+		vts.logger.Error("Failed to check missed verification tier evaluation", "error", err)
+	}
+
+	// Schedule daily execution at 3 AM UTC, after the nightly scrapers
+	_, err := vts.cron.AddFunc("0 3 * * *", vts.runEvaluation)
+	if err != nil {
+		return fmt.Errorf("failed to add cron job: %w", err)
+	}
+
+	vts.cron.Start()
+	vts.logger.Info("Verification tier cron service started - scheduled for daily execution at 3 AM UTC")
+
+	// Keep the service running until context is cancelled
+	<-ctx.Done()
+	vts.Stop()
+	return nil
+}
+
+func (vts *VerificationTierCronService) Stop() {
+	if vts.cron != nil {
+		vts.cron.Stop()
+		vts.logger.Info("Verification tier cron service stopped")
+	}
+}
+
+func (vts *VerificationTierCronService) runEvaluation() {
+	vts.logger.Info("Starting scheduled verification tier evaluation")
+
+	if err := vts.scraperJobRepo.UpdateStatus(vts.jobType, "running"); err != nil {
+		vts.logger.Error("Failed to update verification tier job status to running", "error", err)
+	}
+
+	if _, err := vts.tierService.EvaluateAll(); err != nil {
+		vts.logger.Error("Verification tier evaluation failed", "error", err)
+		if updateErr := vts.scraperJobRepo.UpdateStatus(vts.jobType, "failed"); updateErr != nil {
+			vts.logger.Error("Failed to update verification tier job status to failed", "error", updateErr)
+		}
+		return
+	}
+
+	now := time.Now()
+	if err := vts.scraperJobRepo.UpdateLastRunTime(vts.jobType, now); err != nil {
+		vts.logger.Error("Failed to update last run time", "error", err)
+	}
+
+	if err := vts.scraperJobRepo.UpdateStatus(vts.jobType, "completed"); err != nil {
+		vts.logger.Error("Failed to update verification tier job status to completed", "error", err)
+	}
+
+	nextRun := now.Add(24 * time.Hour)
+	if err := vts.scraperJobRepo.UpdateNextScheduledRun(vts.jobType, nextRun); err != nil {
+		vts.logger.Error("Failed to update next scheduled run", "error", err)
+	}
+
+	vts.logger.Info("Verification tier evaluation completed successfully", "timestamp", now)
+}
+
+func (vts *VerificationTierCronService) checkMissedExecution() error {
+	scraperJob, err := vts.scraperJobRepo.GetScraperJobByType(vts.jobType)
+	if err != nil {
+		return err
+	}
+
+	if scraperJob.ShouldRun() {
+		vts.logger.Info("Verification tier evaluation is due, running catch-up evaluation")
+		go vts.runEvaluation() // Run asynchronously to not block startup
+	}
+
+	return nil
+}
+
+// RunNow manually triggers the evaluation (useful for testing/admin)
+func (vts *VerificationTierCronService) RunNow() error {
+	vts.logger.Info("Manual verification tier evaluation triggered")
+	_, err := vts.tierService.EvaluateAll()
+	return err
+}
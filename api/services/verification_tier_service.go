@@ -0,0 +1,396 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Requirements of the enhanced and trusted tiers. Accounts on institutional email domains need
+// less account age since the domain already vouches for them.
+const (
+	enhancedMinAccountDays              = 30
+	enhancedMinInstitutionalAccountDays = 7
+	enhancedMaxRejectedShare            = 1.0 / 3
+	enhancedMaxSharedIPAccounts         = 2
+
+	trustedMinAccountDays              = 180
+	trustedMinInstitutionalAccountDays = 90
+	trustedMinPublishedReports         = 5
+	trustedMinCorroboratedReports      = 3
+	trustedMaxRejectedShare            = 0.1
+	trustedMaxIPAddresses              = 20
+
+	tierEvaluationBatchSize = 500
+)
+
+// nilUUID sorts before every user id, it starts paging through users
+const nilUUID = "00000000-0000-0000-0000-000000000000"
+
+// institutionalEmailSuffixes are the domains of the federal, provincial and territorial
+// governments, and the US government and education top level domains. A domain matches itself and
+// its subdomains, so gov.on.ca covers the ministries under it but not gov.attacker.com.
+var institutionalEmailSuffixes = []string{
+	// Federal
+	"canada.ca", "gc.ca",
+	// Provinces and territories
+	"gov.ab.ca", "gov.bc.ca", "gov.mb.ca", "gnb.ca", "gov.nl.ca", "gov.ns.ca", "gov.on.ca",
+	"gov.pe.ca", "gouv.qc.ca", "gov.sk.ca", "gov.yk.ca", "gov.nt.ca", "gov.nu.ca",
+	"alberta.ca", "ontario.ca", "novascotia.ca", "quebec.ca", "yukon.ca",
+	// United States
+	"gov", "edu",
+}
+
+// Throwaway inbox services, accounts on them never get past the basic tier
+var disposableEmailDomains = map[string]bool{
+	"10minutemail.com":    true,
+	"burnermail.io":       true,
+	"discard.email":       true,
+	"dispostable.com":     true,
+	"emailondeck.com":     true,
+	"emailtemporanea.net": true,
+	"fakeinbox.com":       true,
+	"getnada.com":         true,
+	"guerrillamail.com":   true,
+	"guerrillamail.net":   true,
+	"mailcatch.com":       true,
+	"maildrop.cc":         true,
+	"mailinator.com":      true,
+	"mailnesia.com":       true,
+	"mintemail.com":       true,
+	"moakt.com":           true,
+	"mohmal.com":          true,
+	"sharklasers.com":     true,
+	"spamgourmet.com":     true,
+	"temp-mail.org":       true,
+	"tempinbox.com":       true,
+	"tempmail.com":        true,
+	"throwawaymail.com":   true,
+	"trashmail.com":       true,
+	"yopmail.com":         true,
+}
+
+type VerificationTierService interface {
+	EvaluateAll() (*models.TierEvaluationSummary, error)
+	EvaluateUser(userID string) (*models.TierEvaluation, error)
+	GetTierStatus(userID string) (*models.TierEvaluation, error)
+	GetTierHistory(userID string) ([]*models.UserTierChange, error)
+}
+
+type verificationTierService struct {
+	repo repos.UserTierRepository
+}
+
+func NewVerificationTierService(repo repos.UserTierRepository) VerificationTierService {
+	return &verificationTierService{repo: repo}
+}
+
+// tierRequirement is one condition of a tier, described whether it's met or not
+type tierRequirement struct {
+	met    bool
+	reason string
+}
+
+// EvaluateAll computes the tier of every user and applies the changes. A user that fails is
+// counted and skipped so one bad record doesn't stop the run.
+func (s *verificationTierService) EvaluateAll() (*models.TierEvaluationSummary, error) {
+	summary := &models.TierEvaluationSummary{}
+	now := time.Now()
+	after := nilUUID
+
+	for {
+		batch, err := s.repo.GetSignals(after, tierEvaluationBatchSize)
+		if err != nil {
+			return summary, err
+		}
+
+		for _, signals := range batch {
+			evaluation := evaluateTier(signals, now)
+			summary.Evaluated++
+
+			changed, err := s.applyEvaluation(evaluation)
+			if err != nil {
+				log.Error("Failed to apply verification tier", "user_id", signals.UserID, "error", err)
+				summary.Failed++
+				continue
+			}
+			if !changed {
+				continue
+			}
+			if evaluation.QualifiedTier.Rank() > evaluation.CurrentTier.Rank() {
+				summary.Promoted++
+			} else {
+				summary.Demoted++
+			}
+		}
+
+		if len(batch) < tierEvaluationBatchSize {
+			break
+		}
+		after = batch[len(batch)-1].UserID
+	}
+
+	log.Info("Verification tiers evaluated",
+		"evaluated", summary.Evaluated,
+		"promoted", summary.Promoted,
+		"demoted", summary.Demoted,
+		"failed", summary.Failed)
+
+	return summary, nil
+}
+
+// EvaluateUser computes the tier of one user and applies it
+func (s *verificationTierService) EvaluateUser(userID string) (*models.TierEvaluation, error) {
+	signals, err := s.repo.GetSignalsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluation := evaluateTier(signals, time.Now())
+	if _, err := s.applyEvaluation(evaluation); err != nil {
+		return nil, err
+	}
+
+	return evaluation, nil
+}
+
+// GetTierStatus computes the tier a user qualifies for without applying it. Tiers change when
+// the scheduled evaluation runs.
+func (s *verificationTierService) GetTierStatus(userID string) (*models.TierEvaluation, error) {
+	signals, err := s.repo.GetSignalsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return evaluateTier(signals, time.Now()), nil
+}
+
+// GetTierHistory returns the tier changes of a user, most recent first
+func (s *verificationTierService) GetTierHistory(userID string) ([]*models.UserTierChange, error) {
+	changes, err := s.repo.GetTierHistory(userID)
+	if err != nil {
+		return nil, err
+	}
+	if changes == nil {
+		changes = []*models.UserTierChange{}
+	}
+
+	return changes, nil
+}
+
+// applyEvaluation saves the qualified tier of a user when it differs from the current one
+func (s *verificationTierService) applyEvaluation(evaluation *models.TierEvaluation) (bool, error) {
+	if evaluation.QualifiedTier == evaluation.CurrentTier {
+		return false, nil
+	}
+
+	change := &models.UserTierChange{
+		UserID:  evaluation.UserID,
+		OldTier: evaluation.CurrentTier,
+		NewTier: evaluation.QualifiedTier,
+		Reasons: evaluation.Reasons,
+		Signals: evaluation.Signals,
+	}
+	changed, err := s.repo.ApplyTierChange(change)
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		log.Info("Verification tier changed",
+			"user_id", change.UserID,
+			"old_tier", change.OldTier,
+			"new_tier", change.NewTier,
+			"reasons", strings.Join(change.Reasons, "; "))
+	}
+
+	return changed, nil
+}
+
+// evaluateTier computes the highest tier whose requirements the user meets. Promotions are
+// explained by the requirements the user met, demotions by the ones they no longer meet.
+func evaluateTier(signals *models.UserTierSignals, now time.Time) *models.TierEvaluation {
+	requirements := map[models.VerificationTier][]tierRequirement{
+		models.VerificationEnhanced: enhancedRequirements(signals, now),
+		models.VerificationTrusted:  trustedRequirements(signals, now),
+	}
+
+	qualified := models.VerificationBasic
+	if allRequirementsMet(requirements[models.VerificationEnhanced]) {
+		qualified = models.VerificationEnhanced
+		if allRequirementsMet(requirements[models.VerificationTrusted]) {
+			qualified = models.VerificationTrusted
+		}
+	}
+
+	current := signals.CurrentTier
+	if current == "" {
+		current = models.VerificationBasic
+	}
+
+	evaluation := &models.TierEvaluation{
+		UserID:        signals.UserID,
+		CurrentTier:   current,
+		QualifiedTier: qualified,
+		Reasons:       []string{},
+		Signals:       *signals,
+	}
+
+	next, hasNext := nextTier(qualified)
+	if hasNext {
+		evaluation.NextTier = &next
+		evaluation.NextTierMissing = requirementReasons(requirements[next], false)
+	}
+
+	switch {
+	case qualified.Rank() < current.Rank():
+		evaluation.Reasons = evaluation.NextTierMissing
+	case qualified != models.VerificationBasic:
+		evaluation.Reasons = requirementReasons(requirements[qualified], true)
+	}
+
+	return evaluation
+}
+
+func enhancedRequirements(signals *models.UserTierSignals, now time.Time) []tierRequirement {
+	reputation := emailDomainReputation(signals.EmailDomain)
+	minDays := enhancedMinAccountDays
+	if reputation == models.EmailDomainInstitutional {
+		minDays = enhancedMinInstitutionalAccountDays
+	}
+
+	return []tierRequirement{
+		emailDomainRequirement(reputation),
+		accountAgeRequirement(signals, now, minDays),
+		rejectedShareRequirement(signals, enhancedMaxRejectedShare),
+		sharedIPRequirement(signals, enhancedMaxSharedIPAccounts),
+	}
+}
+
+func trustedRequirements(signals *models.UserTierSignals, now time.Time) []tierRequirement {
+	reputation := emailDomainReputation(signals.EmailDomain)
+	minDays := trustedMinAccountDays
+	if reputation == models.EmailDomainInstitutional {
+		minDays = trustedMinInstitutionalAccountDays
+	}
+
+	return []tierRequirement{
+		emailDomainRequirement(reputation),
+		accountAgeRequirement(signals, now, minDays),
+		{
+			met:    signals.PublishedReports >= trustedMinPublishedReports,
+			reason: fmt.Sprintf("%d published reports, %d required", signals.PublishedReports, trustedMinPublishedReports),
+		},
+		{
+			met:    signals.CorroboratedReports >= trustedMinCorroboratedReports,
+			reason: fmt.Sprintf("%d reports corroborated by the community, %d required", signals.CorroboratedReports, trustedMinCorroboratedReports),
+		},
+		rejectedShareRequirement(signals, trustedMaxRejectedShare),
+		{
+			met:    signals.FlaggedReports == 0,
+			reason: fmt.Sprintf("%d reports currently flagged, none allowed", signals.FlaggedReports),
+		},
+		sharedIPRequirement(signals, 0),
+		{
+			met:    signals.IPAddressCount <= trustedMaxIPAddresses,
+			reason: fmt.Sprintf("signed in from %d IP addresses, at most %d allowed", signals.IPAddressCount, trustedMaxIPAddresses),
+		},
+	}
+}
+
+func emailDomainRequirement(reputation string) tierRequirement {
+	return tierRequirement{
+		met:    reputation != models.EmailDomainDisposable,
+		reason: fmt.Sprintf("email domain is %s, disposable domains are not allowed", reputation),
+	}
+}
+
+func accountAgeRequirement(signals *models.UserTierSignals, now time.Time, minDays int) tierRequirement {
+	days := int(now.Sub(signals.AccountCreatedAt).Hours() / 24)
+	return tierRequirement{
+		met:    days >= minDays,
+		reason: fmt.Sprintf("account is %d days old, %d required", days, minDays),
+	}
+}
+
+// rejectedShareRequirement limits the share of moderated reports that were rejected
+func rejectedShareRequirement(signals *models.UserTierSignals, maxShare float64) tierRequirement {
+	moderated := signals.PublishedReports + signals.RejectedReports
+	share := 0.0
+	if moderated > 0 {
+		share = float64(signals.RejectedReports) / float64(moderated)
+	}
+	return tierRequirement{
+		met:    share <= maxShare,
+		reason: fmt.Sprintf("%d of %d moderated reports rejected, at most %.0f%% allowed", signals.RejectedReports, moderated, maxShare*100),
+	}
+}
+
+func sharedIPRequirement(signals *models.UserTierSignals, maxAccounts int) tierRequirement {
+	return tierRequirement{
+		met:    signals.SharedIPAccounts <= maxAccounts,
+		reason: fmt.Sprintf("%d other accounts share IP addresses with this one, at most %d allowed", signals.SharedIPAccounts, maxAccounts),
+	}
+}
+
+func allRequirementsMet(requirements []tierRequirement) bool {
+	for _, requirement := range requirements {
+		if !requirement.met {
+			return false
+		}
+	}
+	return true
+}
+
+// requirementReasons returns the descriptions of the requirements that are met or not
+func requirementReasons(requirements []tierRequirement, met bool) []string {
+	reasons := []string{}
+	for _, requirement := range requirements {
+		if requirement.met == met {
+			reasons = append(reasons, requirement.reason)
+		}
+	}
+	return reasons
+}
+
+func nextTier(tier models.VerificationTier) (models.VerificationTier, bool) {
+	switch tier {
+	case models.VerificationBasic:
+		return models.VerificationEnhanced, true
+	case models.VerificationEnhanced:
+		return models.VerificationTrusted, true
+	default:
+		return "", false
+	}
+}
+
+// emailDomainReputation classifies an email domain as disposable, institutional (one of the
+// institutionalEmailSuffixes) or standard
+func emailDomainReputation(domain *string) string {
+	if domain == nil {
+		return models.EmailDomainStandard
+	}
+	name := strings.ToLower(strings.TrimSpace(*domain))
+
+	for candidate := name; candidate != ""; {
+		if disposableEmailDomains[candidate] {
+			return models.EmailDomainDisposable
+		}
+		_, parent, found := strings.Cut(candidate, ".")
+		if !found {
+			break
+		}
+		candidate = parent
+	}
+
+	for _, suffix := range institutionalEmailSuffixes {
+		if name == suffix || strings.HasSuffix(name, "."+suffix) {
+			return models.EmailDomainInstitutional
+		}
+	}
+
+	return models.EmailDomainStandard
+}
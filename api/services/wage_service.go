@@ -0,0 +1,331 @@
+package services
+
+import (
+	"canada-hires/models"
+	"canada-hires/repos"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// Number of job postings loaded at a time when annotating every posting
+const wageAnnotationBatchSize = 500
+
+type WageService interface {
+	ImportWageTableFromCSV(filePath string) (int, error)
+	AnnotateJobPosting(posting *models.JobPosting) error
+	AnnotateAllJobPostings() (int, error)
+}
+
+type wageService struct {
+	wageRepo    repos.PrevailingWageRepository
+	jobBankRepo repos.JobBankRepository
+}
+
+func NewWageService(wageRepo repos.PrevailingWageRepository, jobBankRepo repos.JobBankRepository) WageService {
+	return &wageService{
+		wageRepo:    wageRepo,
+		jobBankRepo: jobBankRepo,
+	}
+}
+
+// ImportWageTableFromCSV loads the ESDC/Job Bank wage table from a local CSV file and upserts it
+func (s *wageService) ImportWageTableFromCSV(filePath string) (int, error) {
+	log.Info("Importing prevailing wage table", "file_path", filePath)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open wage table: %w", err)
+	}
+	defer file.Close()
+
+	wages, err := parseWageTable(file)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := s.wageRepo.UpsertWagesBatch(wages); err != nil {
+		return 0, fmt.Errorf("failed to store wage table: %w", err)
+	}
+
+	log.Info("Prevailing wage table imported", "rows", len(wages))
+	return len(wages), nil
+}
+
+// AnnotateJobPosting resolves the posting's NOC code and compares its offered wage with the local median
+func (s *wageService) AnnotateJobPosting(posting *models.JobPosting) error {
+	return s.annotate(posting, newWageLookupCache())
+}
+
+// wageLookupCache remembers NOC title matches and wage rows during a run, since many postings
+// share a title and location
+type wageLookupCache struct {
+	nocCodes map[string]string
+	wages    map[string]*models.PrevailingWage
+}
+
+func newWageLookupCache() *wageLookupCache {
+	return &wageLookupCache{
+		nocCodes: make(map[string]string),
+		wages:    make(map[string]*models.PrevailingWage),
+	}
+}
+
+func (s *wageService) annotate(posting *models.JobPosting, cache *wageLookupCache) error {
+	if posting.NOCCode == nil || *posting.NOCCode == "" {
+		// An empty title would match every NOC title
+		title := strings.ToLower(strings.TrimSpace(posting.Title))
+		if title == "" {
+			return nil
+		}
+
+		nocCode, ok := cache.nocCodes[title]
+		if !ok {
+			var err error
+			nocCode, err = s.wageRepo.FindNOCCodeByTitle(title)
+			if err != nil {
+				return err
+			}
+			cache.nocCodes[title] = nocCode
+		}
+		if nocCode == "" {
+			return nil
+		}
+		posting.NOCCode = &nocCode
+	}
+
+	province, city := "", ""
+	if posting.Province != nil {
+		province = models.NormalizeProvince(*posting.Province)
+	}
+	if posting.City != nil {
+		city = strings.TrimSpace(*posting.City)
+	}
+
+	key := *posting.NOCCode + "|" + province + "|" + strings.ToLower(city)
+	wage, ok := cache.wages[key]
+	if !ok {
+		var err error
+		wage, err = s.wageRepo.FindWageForLocation(*posting.NOCCode, province, city)
+		if err != nil {
+			return err
+		}
+		cache.wages[key] = wage
+	}
+
+	posting.ApplyPrevailingWage(wage)
+
+	if err := s.jobBankRepo.UpdateJobPostingWageComparison(posting); err != nil {
+		return fmt.Errorf("failed to update wage comparison: %w", err)
+	}
+
+	return nil
+}
+
+// AnnotateAllJobPostings recomputes the wage comparison for every job posting, typically after a
+// new wage table import or a scrape. Postings are loaded in batches.
+func (s *wageService) AnnotateAllJobPostings() (int, error) {
+	count, err := s.wageRepo.GetWagesCount()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count prevailing wages: %w", err)
+	}
+	if count == 0 {
+		log.Warn("No prevailing wages loaded, skipping wage comparison")
+		return 0, nil
+	}
+
+	cache := newWageLookupCache()
+	processed, annotated := 0, 0
+	after := nilUUID
+
+	for {
+		postings, err := s.jobBankRepo.GetJobPostingsAfter(after, wageAnnotationBatchSize)
+		if err != nil {
+			return annotated, fmt.Errorf("failed to get job postings: %w", err)
+		}
+
+		for _, posting := range postings {
+			processed++
+			if err := s.annotate(posting, cache); err != nil {
+				log.Error("Failed to annotate job posting with prevailing wage", "job_id", posting.ID, "error", err)
+				continue
+			}
+			if posting.MedianWage != nil {
+				annotated++
+			}
+		}
+
+		if len(postings) < wageAnnotationBatchSize {
+			break
+		}
+		after = postings[len(postings)-1].ID
+	}
+
+	log.Info("Prevailing wage comparison completed", "postings", processed, "annotated", annotated)
+	return annotated, nil
+}
+
+// parseWageTable reads the ESDC wage CSV. Column names vary between releases (and are bilingual),
+// so columns are located by keyword.
+func parseWageTable(r io.Reader) ([]*models.PrevailingWage, error) {
+	reader := csv.NewReader(r)
+	reader.LazyQuotes = true
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	headers, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wage table header: %w", err)
+	}
+
+	columns := mapWageColumns(headers)
+	for _, required := range []string{"noc_code", "region_code", "median"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("wage table is missing a %s column", required)
+		}
+	}
+
+	var wages []*models.PrevailingWage
+	lineNumber := 1
+	for {
+		record, err := reader.Read()
+		lineNumber++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Warn("Skipping malformed wage table line", "line", lineNumber, "error", err.Error())
+			continue
+		}
+
+		wage := parseWageRecord(record, columns)
+		if wage != nil {
+			wages = append(wages, wage)
+		}
+	}
+
+	if len(wages) == 0 {
+		return nil, fmt.Errorf("wage table contains no usable rows")
+	}
+
+	return wages, nil
+}
+
+// mapWageColumns finds the column index of each field in the wage table header
+func mapWageColumns(headers []string) map[string]int {
+	columns := make(map[string]int)
+	for i, header := range headers {
+		h := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header, "\ufeff")))
+		switch {
+		case strings.Contains(h, "noc") && strings.Contains(h, "title"):
+			// Prefer the English title when the table is bilingual
+			if _, ok := columns["noc_title"]; !ok || strings.Contains(h, "eng") {
+				columns["noc_title"] = i
+			}
+		case strings.HasPrefix(h, "noc"):
+			columns["noc_code"] = i
+		case strings.Contains(h, "er_code") || strings.Contains(h, "code_re"):
+			columns["region_code"] = i
+		case strings.Contains(h, "er_name") || strings.Contains(h, "nom_re"):
+			columns["region_name"] = i
+		case strings.HasPrefix(h, "prov"):
+			columns["province"] = i
+		case strings.HasPrefix(h, "low"):
+			columns["low"] = i
+		case strings.HasPrefix(h, "median"):
+			columns["median"] = i
+		case strings.HasPrefix(h, "high"):
+			columns["high"] = i
+		case strings.HasPrefix(h, "annual"):
+			columns["annual"] = i
+		case strings.HasPrefix(h, "reference"):
+			columns["reference_period"] = i
+		}
+	}
+	return columns
+}
+
+// parseWageRecord converts a single wage table row, returning nil for rows without a NOC or region
+func parseWageRecord(record []string, columns map[string]int) *models.PrevailingWage {
+	field := func(name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	nocCode := field("noc_code")
+	regionCode := strings.ToUpper(field("region_code"))
+	if nocCode == "" || regionCode == "" {
+		return nil
+	}
+
+	// The NOC column sometimes carries a prefix such as "NOC_65201"
+	nocCode = strings.TrimPrefix(strings.ToUpper(nocCode), "NOC_")
+
+	wage := &models.PrevailingWage{
+		NOCCode:            nocCode,
+		EconomicRegionCode: regionCode,
+		RegionLevel:        wageRegionLevel(regionCode),
+		LowWage:            parseWageAmount(field("low")),
+		MedianWage:         parseWageAmount(field("median")),
+		HighWage:           parseWageAmount(field("high")),
+		IsAnnual:           field("annual") == "1" || strings.EqualFold(field("annual"), "true") || strings.EqualFold(field("annual"), "y"),
+	}
+
+	if title := field("noc_title"); title != "" {
+		wage.NOCTitle = &title
+	}
+	if name := field("region_name"); name != "" {
+		wage.EconomicRegionName = &name
+	}
+	if period := field("reference_period"); period != "" {
+		wage.ReferencePeriod = &period
+	}
+
+	province := field("province")
+	if province == "" && wage.RegionLevel == models.WageRegionProvince {
+		province = regionCode
+	}
+	if province != "" && !strings.EqualFold(province, "NAT") {
+		code := models.NormalizeProvince(province)
+		if len(code) == 2 {
+			wage.Province = &code
+		}
+	}
+
+	return wage
+}
+
+// wageRegionLevel classifies an economic region code. National rows use "NAT", province rows use the
+// two letter province code, and economic regions use four digit codes.
+func wageRegionLevel(regionCode string) string {
+	switch {
+	case regionCode == "NAT":
+		return models.WageRegionNational
+	case len(regionCode) == 2:
+		return models.WageRegionProvince
+	default:
+		return models.WageRegionEconomicRegion
+	}
+}
+
+// parseWageAmount parses a wage value, treating blanks and "N/A" as unknown
+func parseWageAmount(value string) *float64 {
+	value = strings.ReplaceAll(strings.ReplaceAll(value, "$", ""), ",", "")
+	if value == "" || strings.EqualFold(value, "n/a") {
+		return nil
+	}
+
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil || amount <= 0 {
+		return nil
+	}
+	return &amount
+}